@@ -1,14 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/zheli/validator-key-manager-backend/internal/api"
 	"github.com/zheli/validator-key-manager-backend/internal/db"
+	"github.com/zheli/validator-key-manager-backend/internal/db/repo"
+	"github.com/zheli/validator-key-manager-backend/pkg/alerting"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/detector"
+	"github.com/zheli/validator-key-manager-backend/pkg/lido"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/outbox"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+	"github.com/zheli/validator-key-manager-backend/pkg/web3signer"
+	"github.com/zheli/validator-key-manager-backend/pkg/webhook"
 )
 
 func main() {
@@ -22,6 +39,230 @@ func main() {
 	}
 	defer database.Close()
 
+	validatorRepo := repo.NewValidatorRepository(database)
+	clientKeyRepo := repo.NewClientKeyRepository(database)
+	alertRepo := repo.NewAlertRepository(database)
+	auditRepo := repo.NewAuditRepository(database)
+
+	blsChangeRepo := repo.NewBLSChangeRepository(database)
+	voluntaryExitRepo := repo.NewVoluntaryExitRepository(database)
+	exitRequestRepo := repo.NewExitRequestRepository(database)
+	withdrawalRequestRepo := repo.NewWithdrawalRequestRepository(database)
+	consolidationRepo := repo.NewConsolidationRepository(database)
+	keyMigrationRepo := repo.NewKeyMigrationRepository(database)
+	doppelgangerCheckRepo := repo.NewDoppelgangerCheckRepository(database)
+	slashingProtectionRepo := repo.NewSlashingProtectionRepository(database)
+	keystoreRepo := repo.NewKeystoreRepository(database)
+	signerKeyRepo := repo.NewSignerKeyRepository(database)
+	webhookDeliveryRepo := repo.NewWebhookDeliveryRepository(database)
+	syncRunRepo := repo.NewSyncRunRepository(database)
+	alertSilenceRepo := repo.NewAlertSilenceRepository(database)
+	outboxRepo := repo.NewOutboxRepository(database)
+
+	// "validator-key-manager reconcile" prints the reconciliation report and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		code := runReconcile(validatorRepo)
+		database.Close()
+		os.Exit(code)
+	}
+
+	validatorService := service.NewValidatorService(validatorRepo, auditRepo)
+	withdrawalService := service.NewWithdrawalService(validatorRepo)
+	doubleLoadService := service.NewDoubleLoadService(clientKeyRepo, alertRepo, auditRepo)
+	slashingProtectionService := service.NewSlashingProtectionService(slashingProtectionRepo, auditRepo)
+
+	// Send webhook notifications if any webhook is configured
+	var webhookService *service.WebhookService
+	if path := os.Getenv("WEBHOOKS_CONFIG"); path != "" {
+		webhookConfig, err := webhook.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load webhook config: %v", err)
+		}
+		webhookService = service.NewWebhookService(webhookDeliveryRepo, auditRepo, webhookConfig.Webhooks, webhook.NewClient())
+		doubleLoadService.OnDetect(webhookService.DoubleLoaded)
+	}
+
+	// Validator changes are written to the outbox with the change itself and published to these subscribers
+	outboxDispatcher := outbox.NewDispatcher(outboxRepo)
+	if webhookService != nil {
+		outboxDispatcher.Subscribe("webhooks", webhookService.HandleEvent)
+	}
+
+	// "validator-key-manager replay" publishes the outbox events of a time range again and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		code := runReplay(outboxDispatcher, os.Args[2:])
+		database.Close()
+		os.Exit(code)
+	}
+
+	outboxInterval := 5 * time.Second
+	if v := os.Getenv("OUTBOX_DISPATCH_INTERVAL"); v != "" {
+		if outboxInterval, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid OUTBOX_DISPATCH_INTERVAL: %v", err)
+		}
+	}
+	go outboxDispatcher.Start(context.Background(), outboxInterval)
+
+	if webhookService != nil {
+		interval := 10 * time.Second
+		if v := os.Getenv("WEBHOOK_DISPATCH_INTERVAL"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil {
+				log.Fatalf("Invalid WEBHOOK_DISPATCH_INTERVAL: %v", err)
+			}
+		}
+		go webhookService.Start(context.Background(), interval)
+	}
+
+	beaconConfig, err := beacon.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load beacon config: %v", err)
+	}
+	beaconNodes := beacon.NewNodes(beaconConfig)
+
+	// Evaluate alert rules if any are configured
+	var alertRuleService *service.AlertRuleService
+	if path := os.Getenv("ALERT_RULES_CONFIG"); path != "" {
+		alertingConfig, err := alerting.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load alerting config: %v", err)
+		}
+		notifiers := make([]alerting.Notifier, 0, len(alertingConfig.Notifiers))
+		for _, cfg := range alertingConfig.Notifiers {
+			n, err := alerting.NewNotifier(cfg)
+			if err != nil {
+				log.Fatalf("Failed to create notifier: %v", err)
+			}
+			notifiers = append(notifiers, n)
+		}
+		alertRuleService = service.NewAlertRuleService(validatorRepo, alertRepo, alertSilenceRepo, syncRunRepo, auditRepo,
+			beaconNodes, alertingConfig.Rules, notifiers)
+		interval := time.Minute
+		if v := os.Getenv("ALERT_EVAL_INTERVAL"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil {
+				log.Fatalf("Invalid ALERT_EVAL_INTERVAL: %v", err)
+			}
+		}
+		go alertRuleService.Start(context.Background(), interval)
+	}
+
+	// Every background sync reports the outcome of its runs to these hooks, by sync name
+	var syncHooks []func(source string) func(ctx context.Context, err error)
+	if webhookService != nil {
+		syncHooks = append(syncHooks, webhookService.SyncFailed)
+	}
+	if alertRuleService != nil {
+		syncHooks = append(syncHooks, alertRuleService.SyncCompleted)
+	}
+
+	// Start validator client detection if instances are configured
+	var detectorConfig *detector.Config
+	if path := os.Getenv("VALIDATOR_CLIENTS_CONFIG"); path != "" {
+		detectorConfig, err = detector.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load validator client config: %v", err)
+		}
+		det, err := detector.New(validatorRepo, clientKeyRepo, detectorConfig)
+		if err != nil {
+			log.Fatalf("Failed to create validator client detector: %v", err)
+		}
+		det.OnRun(func(ctx context.Context) error {
+			_, err := doubleLoadService.Check(ctx)
+			return err
+		})
+		for _, hook := range syncHooks {
+			det.OnComplete(hook(models.SyncSourceDetector))
+		}
+		interval := time.Hour
+		if v := os.Getenv("DETECTOR_INTERVAL"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil {
+				log.Fatalf("Invalid DETECTOR_INTERVAL: %v", err)
+			}
+		}
+		go det.Start(context.Background(), interval)
+	}
+
+	// Start Lido registry sync if any network is configured
+	lidoConfig, err := lido.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load Lido config: %v", err)
+	}
+	lidoSyncer := lido.NewSyncer(validatorRepo, lidoConfig)
+	if len(lidoConfig.Networks) > 0 {
+		for _, hook := range syncHooks {
+			lidoSyncer.OnComplete(hook(models.SyncSourceLido))
+		}
+		go lidoSyncer.Start(context.Background(), lidoConfig.CacheTTL)
+	}
+
+	// Start beacon metadata sync if any beacon node is configured
+	withdrawalRequestService := service.NewWithdrawalRequestService(validatorService, withdrawalRequestRepo, alertRepo, auditRepo, beaconNodes)
+	consolidationService := service.NewConsolidationService(validatorService, consolidationRepo, auditRepo, beaconNodes)
+	doppelgangerEpochs := int64(3)
+	if v := os.Getenv("DOPPELGANGER_EPOCHS"); v != "" {
+		if doppelgangerEpochs, err = strconv.ParseInt(v, 10, 64); err != nil || doppelgangerEpochs < 1 {
+			log.Fatalf("Invalid DOPPELGANGER_EPOCHS: %q", v)
+		}
+	}
+	doppelgangerService := service.NewDoppelgangerService(doppelgangerCheckRepo, validatorRepo, alertRepo, auditRepo, beaconNodes, doppelgangerEpochs)
+	if len(beaconConfig.Nodes) > 0 {
+		beaconSyncer := beacon.NewSyncer(validatorRepo, beaconConfig)
+		// Consolidating sources must leave active before exits are checked
+		beaconSyncer.OnRun(consolidationService.Check)
+		beaconSyncer.OnRun(withdrawalRequestService.Check)
+		for _, hook := range syncHooks {
+			beaconSyncer.OnComplete(hook(models.SyncSourceBeacon))
+		}
+		go beaconSyncer.Start(context.Background(), beaconConfig.Interval)
+		go doppelgangerService.Start(context.Background(), time.Minute)
+	}
+
+	// Remote keys, deployments and key migrations load and remove keys on the configured validator clients
+	var keyManagers []vclient.KeyManager
+	if detectorConfig != nil {
+		for _, inst := range detectorConfig.Instances {
+			c, err := vclient.New(inst)
+			if err != nil {
+				log.Fatalf("Failed to create validator client: %v", err)
+			}
+			km, ok := c.(vclient.KeyManager)
+			if !ok {
+				log.Fatalf("Validator client %q cannot import or delete keystores", inst.Name)
+			}
+			keyManagers = append(keyManagers, km)
+		}
+	}
+
+	// Start remote signer sync if any Web3Signer instance is configured
+	var remoteSignerService *service.RemoteSignerService
+	if detectorConfig != nil && len(detectorConfig.Signers) > 0 {
+		signers := make([]*web3signer.Client, 0, len(detectorConfig.Signers))
+		for _, cfg := range detectorConfig.Signers {
+			signer, err := web3signer.New(cfg)
+			if err != nil {
+				log.Fatalf("Failed to create remote signer client: %v", err)
+			}
+			signers = append(signers, signer)
+		}
+		remoteSignerService = service.NewRemoteSignerService(validatorRepo, keyMigrationRepo, signerKeyRepo, clientKeyRepo,
+			doppelgangerService, auditRepo, signers, keyManagers)
+		for _, hook := range syncHooks {
+			remoteSignerService.OnComplete(hook(models.SyncSourceSigners))
+		}
+		interval := 10 * time.Minute
+		if v := os.Getenv("SIGNER_SYNC_INTERVAL"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil {
+				log.Fatalf("Invalid SIGNER_SYNC_INTERVAL: %v", err)
+			}
+		}
+		go remoteSignerService.Start(context.Background(), interval)
+	}
+
+	// Secrets stored in the database are only accepted once an encryption key is configured
+	cipher, err := vault.NewCipherFromEnv()
+	if err != nil && !errors.Is(err, vault.ErrNotConfigured) {
+		log.Fatalf("Failed to load vault encryption key: %v", err)
+	}
+
 	// Initialize chi router
 	r := chi.NewRouter()
 
@@ -29,6 +270,21 @@ func main() {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
+	// Operators identify themselves with bearer tokens on endpoints that record who acted
+	if path := os.Getenv("OPERATORS_CONFIG"); path != "" {
+		operators, err := api.LoadOperators(path)
+		if err != nil {
+			log.Fatalf("Failed to load operators config: %v", err)
+		}
+		auth, err := api.NewAuthenticator(operators)
+		if err != nil {
+			log.Fatalf("Invalid operators config: %v", err)
+		}
+		r.Use(auth.Middleware)
+	} else {
+		log.Printf("OPERATORS_CONFIG is not set, exit requests cannot be created or reviewed")
+	}
+
 	// Health check endpoint
 	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		if err := database.Ping(); err != nil {
@@ -40,6 +296,79 @@ func main() {
 		fmt.Fprintf(w, "ok")
 	})
 
+	api.NewValidatorHandler(validatorService).Routes(r)
+	api.NewWithdrawalHandler(withdrawalService).Routes(r)
+	api.NewAlertHandler(doubleLoadService).Routes(r)
+	api.NewWithdrawalRequestHandler(withdrawalRequestService).Routes(r)
+	api.NewConsolidationHandler(consolidationService).Routes(r)
+	api.NewSlashingProtectionHandler(slashingProtectionService).Routes(r)
+	api.NewDoppelgangerHandler(doppelgangerService).Routes(r)
+	validatorClients := make([]vclient.ValidatorClient, 0, len(keyManagers))
+	for _, km := range keyManagers {
+		validatorClients = append(validatorClients, km)
+	}
+	api.NewReportHandler(service.NewReconciliationService(validatorRepo, validatorClients, beaconNodes, lidoSyncer)).Routes(r)
+	if remoteSignerService != nil {
+		api.NewSignerHandler(remoteSignerService).Routes(r)
+	}
+	if webhookService != nil {
+		api.NewWebhookHandler(webhookService).Routes(r)
+	}
+	if alertRuleService != nil {
+		api.NewAlertRuleHandler(alertRuleService).Routes(r)
+	}
+	if cipher != nil {
+		blsChangeService := service.NewBLSChangeService(validatorRepo, blsChangeRepo, auditRepo, cipher, beaconNodes)
+		api.NewBLSChangeHandler(blsChangeService).Routes(r)
+		voluntaryExitService := service.NewVoluntaryExitService(validatorRepo, voluntaryExitRepo, auditRepo, cipher, beaconNodes)
+		api.NewVoluntaryExitHandler(voluntaryExitService).Routes(r)
+
+		exitService := service.NewExitService(exitRequestRepo, voluntaryExitRepo, validatorService, auditRepo, cipher, beaconNodes)
+		api.NewExitHandler(exitService).Routes(r)
+		keystoreService := service.NewKeystoreService(keystoreRepo, validatorRepo, auditRepo, cipher)
+		api.NewKeystoreHandler(keystoreService).Routes(r)
+		if len(beaconConfig.Nodes) > 0 {
+			interval := 10 * time.Minute
+			if v := os.Getenv("EXIT_TRACK_INTERVAL"); v != "" {
+				if interval, err = time.ParseDuration(v); err != nil {
+					log.Fatalf("Invalid EXIT_TRACK_INTERVAL: %v", err)
+				}
+			}
+			go exitService.Start(context.Background(), interval)
+		}
+
+		// Deployments and key migrations load and remove keys on the configured validator clients
+		if len(keyManagers) > 0 {
+			deploymentService := service.NewDeploymentService(validatorRepo, keyMigrationRepo, keystoreService,
+				slashingProtectionService, doppelgangerService, auditRepo, keyManagers)
+			api.NewDeploymentHandler(deploymentService).Routes(r)
+		}
+
+		// Key migrations also need a beacon node to watch attestations
+		if len(keyManagers) > 0 && len(beaconConfig.Nodes) > 0 {
+			waitEpochs := int64(2)
+			if v := os.Getenv("MIGRATION_WAIT_EPOCHS"); v != "" {
+				if waitEpochs, err = strconv.ParseInt(v, 10, 64); err != nil || waitEpochs < 1 {
+					log.Fatalf("Invalid MIGRATION_WAIT_EPOCHS: %q", v)
+				}
+			}
+			interval := time.Minute
+			if v := os.Getenv("MIGRATION_INTERVAL"); v != "" {
+				if interval, err = time.ParseDuration(v); err != nil {
+					log.Fatalf("Invalid MIGRATION_INTERVAL: %v", err)
+				}
+			}
+
+			keyMigrationService := service.NewKeyMigrationService(keyMigrationRepo, validatorRepo, slashingProtectionService,
+				doppelgangerService, auditRepo, cipher, beaconNodes, keyManagers, waitEpochs)
+			api.NewKeyMigrationHandler(keyMigrationService).Routes(r)
+			go keyMigrationService.Start(context.Background(), interval)
+		}
+	} else {
+		log.Printf("VAULT_ENCRYPTION_KEY is not set, BLS-to-execution change, exit, keystore, deployment and key migration endpoints are disabled")
+	}
+	api.NewLidoHandler(lidoSyncer).Routes(r)
+
 	// Root endpoint
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "Validator Key Manager Service")
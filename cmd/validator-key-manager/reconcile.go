@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/detector"
+	"github.com/zheli/validator-key-manager-backend/pkg/lido"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// Exit codes of the reconcile command
+const (
+	exitReconciled    = 0
+	exitDiscrepancies = 1
+	exitSourceErrors  = 2
+)
+
+// runReconcile prints the reconciliation report as JSON on stdout and returns the
+// exit code: 0 when every source agrees, 1 when discrepancies exist and 2 when a
+// source could not be read. It uses the same configuration as the server.
+func runReconcile(validators models.ValidatorRepo) int {
+	var clients []vclient.ValidatorClient
+	if path := os.Getenv("VALIDATOR_CLIENTS_CONFIG"); path != "" {
+		cfg, err := detector.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load validator client config: %v", err)
+		}
+		for _, inst := range cfg.Instances {
+			c, err := vclient.New(inst)
+			if err != nil {
+				log.Fatalf("Failed to create validator client: %v", err)
+			}
+			clients = append(clients, c)
+		}
+	}
+	beaconConfig, err := beacon.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load beacon config: %v", err)
+	}
+	lidoConfig, err := lido.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load Lido config: %v", err)
+	}
+
+	reconciliation := service.NewReconciliationService(validators, clients, beacon.NewNodes(beaconConfig), lido.NewSyncer(validators, lidoConfig))
+	report, err := reconciliation.Reconcile(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to build reconciliation report: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatalf("Failed to write reconciliation report: %v", err)
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		log.Printf("Reconciliation incomplete: %d sources could not be read", len(report.Errors))
+		return exitSourceErrors
+	case report.Discrepancies() > 0:
+		log.Printf("Reconciliation found %d discrepancies", report.Discrepancies())
+		return exitDiscrepancies
+	default:
+		return exitReconciled
+	}
+}
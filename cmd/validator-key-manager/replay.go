@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/outbox"
+)
+
+// runReplay publishes the outbox events created in a time range again to the
+// in-process subscribers and returns the exit code: 0 on success, 1 when an
+// event could not be published and 2 on invalid arguments. Subscribers only
+// record their side effects, e.g. webhook deliveries are sent by the server.
+//
+//	validator-key-manager replay --from 2026-01-01T00:00:00Z [--to 2026-01-02T00:00:00Z] [--subscriber webhooks]
+func runReplay(dispatcher *outbox.Dispatcher, args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "replay events created at or after this RFC 3339 time (required)")
+	toFlag := fs.String("to", "", "replay events created before this RFC 3339 time (default now)")
+	subscriber := fs.String("subscriber", "", "only replay to this subscriber (default every subscriber)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *fromFlag == "" {
+		log.Printf("--from is required")
+		return 2
+	}
+	from, err := time.Parse(time.RFC3339, *fromFlag)
+	if err != nil {
+		log.Printf("Invalid --from: %v", err)
+		return 2
+	}
+	to := time.Now()
+	if *toFlag != "" {
+		if to, err = time.Parse(time.RFC3339, *toFlag); err != nil {
+			log.Printf("Invalid --to: %v", err)
+			return 2
+		}
+	}
+	if !from.Before(to) {
+		log.Printf("--from must be before --to")
+		return 2
+	}
+
+	n, err := dispatcher.Replay(context.Background(), from, to, *subscriber)
+	if err != nil {
+		log.Printf("Replay stopped after %d events: %v", n, err)
+		return 1
+	}
+	log.Printf("Replayed %d events created from %s to %s", n, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	return 0
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/alerting"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxSilenceSize bounds the size of an alert silence body
+const maxSilenceSize = 64 << 10
+
+// silenceBody is the body of a new alert silence
+type silenceBody struct {
+	Rule      string    `json:"rule"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+}
+
+// AlertRuleHandler serves the alert rule and silence endpoints
+type AlertRuleHandler struct {
+	rules *service.AlertRuleService
+}
+
+// NewAlertRuleHandler creates a new alert rule handler
+func NewAlertRuleHandler(rules *service.AlertRuleService) *AlertRuleHandler {
+	return &AlertRuleHandler{rules: rules}
+}
+
+// Routes mounts the alert rule endpoints on r
+func (h *AlertRuleHandler) Routes(r chi.Router) {
+	r.Get("/alert-rules", h.ListRules)
+	r.Get("/alert-rules/sync-runs", h.ListSyncRuns)
+	r.Get("/alert-silences", h.ListSilences)
+	r.Post("/alert-silences", h.CreateSilence)
+	r.Delete("/alert-silences/{id}", h.DeleteSilence)
+}
+
+// ListRules returns the configured alert rules
+func (h *AlertRuleHandler) ListRules(w http.ResponseWriter, _ *http.Request) {
+	rules := h.rules.Rules()
+	if rules == nil {
+		rules = []alerting.Rule{}
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// ListSyncRuns returns the last success and failure of every background sync
+func (h *AlertRuleHandler) ListSyncRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.rules.SyncRuns(r.Context())
+	if err != nil {
+		log.Printf("Failed to list sync runs: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list sync runs")
+		return
+	}
+	if runs == nil {
+		runs = []models.SyncRun{}
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// ListSilences returns the silences that have not ended
+func (h *AlertRuleHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	silences, err := h.rules.ListSilences(r.Context())
+	if err != nil {
+		log.Printf("Failed to list alert silences: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list alert silences")
+		return
+	}
+	if silences == nil {
+		silences = []models.AlertSilence{}
+	}
+	writeJSON(w, http.StatusOK, silences)
+}
+
+// CreateSilence stops a rule, or every rule if no rule is given, from raising
+// alerts until ends_at
+func (h *AlertRuleHandler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var body silenceBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSilenceSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	silence := &models.AlertSilence{Rule: body.Rule, StartsAt: body.StartsAt, EndsAt: body.EndsAt, Reason: body.Reason, CreatedBy: body.CreatedBy}
+	err := h.rules.CreateSilence(r.Context(), silence, sourceIP(r))
+	if errors.Is(err, service.ErrInvalidSilence) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create alert silence: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to create alert silence")
+		return
+	}
+	writeJSON(w, http.StatusCreated, silence)
+}
+
+// DeleteSilence ends a silence immediately
+func (h *AlertRuleHandler) DeleteSilence(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid silence id")
+		return
+	}
+
+	err = h.rules.DeleteSilence(r.Context(), id, sourceIP(r))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "silence not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete alert silence %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to delete alert silence")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/alerting"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestAlertRuleHandler(t *testing.T) {
+	rules := []alerting.Rule{{Name: "mainnet-slashed", Type: alerting.RuleStatus, Severity: models.SeverityCritical, Status: models.StatusSlashed}}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockAlertSilenceRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "list rules",
+			method:         "GET",
+			path:           "/alert-rules",
+			mockSetup:      func(*mocks.MockAlertSilenceRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"mainnet-slashed","type":"status","severity":"critical","status":"slashed"}]`,
+		},
+		{
+			name:   "list silences",
+			method: "GET",
+			path:   "/alert-silences",
+			mockSetup: func(s *mocks.MockAlertSilenceRepo, _ *mocks.MockAuditRepo) {
+				s.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "create silence",
+			method: "POST",
+			path:   "/alert-silences",
+			body:   `{"rule":"mainnet-slashed","ends_at":"2999-01-01T00:00:00Z","reason":"key rotation","created_by":"alice"}`,
+			mockSetup: func(s *mocks.MockAlertSilenceRepo, a *mocks.MockAuditRepo) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create silence for unknown rule",
+			method:         "POST",
+			path:           "/alert-silences",
+			body:           `{"rule":"inactive","ends_at":"2999-01-01T00:00:00Z","reason":"key rotation"}`,
+			mockSetup:      func(*mocks.MockAlertSilenceRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid alert silence: unknown rule \"inactive\""}`,
+		},
+		{
+			name:   "delete missing silence",
+			method: "DELETE",
+			path:   "/alert-silences/9",
+			mockSetup: func(s *mocks.MockAlertSilenceRepo, _ *mocks.MockAuditRepo) {
+				s.EXPECT().Delete(gomock.Any(), int64(9)).Return(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "delete silence",
+			method: "DELETE",
+			path:   "/alert-silences/4",
+			mockSetup: func(s *mocks.MockAlertSilenceRepo, a *mocks.MockAuditRepo) {
+				s.EXPECT().Delete(gomock.Any(), int64(4)).Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSilences := mocks.NewMockAlertSilenceRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockSilences, mockAudit)
+
+			r := chi.NewRouter()
+			svc := service.NewAlertRuleService(mocks.NewMockValidatorRepo(ctrl), mocks.NewMockAlertRepo(ctrl), mockSilences,
+				mocks.NewMockSyncRunRepo(ctrl), mockAudit, beacon.Nodes{}, rules, nil)
+			NewAlertRuleHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// AlertHandler serves the alert endpoints
+type AlertHandler struct {
+	doubleLoad *service.DoubleLoadService
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(doubleLoad *service.DoubleLoadService) *AlertHandler {
+	return &AlertHandler{doubleLoad: doubleLoad}
+}
+
+// Routes mounts the alert endpoints on r
+func (h *AlertHandler) Routes(r chi.Router) {
+	r.Get("/alerts/double-loaded", h.ListDoubleLoaded)
+}
+
+// ListDoubleLoaded returns every key currently loaded on more than one validator client instance
+func (h *AlertHandler) ListDoubleLoaded(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.doubleLoad.ListDoubleLoaded(r.Context())
+	if err != nil {
+		log.Printf("Failed to list double-loaded keys: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list double-loaded keys")
+		return
+	}
+	if keys == nil {
+		keys = []models.DoubleLoadedKey{}
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
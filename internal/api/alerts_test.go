@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestAlertHandler_ListDoubleLoaded(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockClientKeyRepo)
+		expectedStatus int
+		expectedLen    int
+	}{
+		{
+			name: "double-loaded keys",
+			mockSetup: func(k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return([]models.DoubleLoadedKey{
+					{
+						Pubkey: "0xaa",
+						Instances: []models.ClientKey{
+							{Pubkey: "0xaa", Client: "lighthouse", ClientInstance: "lh-1"},
+							{Pubkey: "0xaa", Client: "teku", ClientInstance: "teku-1"},
+						},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedLen:    1,
+		},
+		{
+			name: "none",
+			mockSetup: func(k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedLen:    0,
+		},
+		{
+			name: "database error",
+			mockSetup: func(k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockKeys := mocks.NewMockClientKeyRepo(ctrl)
+			tt.mockSetup(mockKeys)
+
+			svc := service.NewDoubleLoadService(mockKeys, mocks.NewMockAlertRepo(ctrl), mocks.NewMockAuditRepo(ctrl))
+			r := chi.NewRouter()
+			NewAlertHandler(svc).Routes(r)
+
+			req := httptest.NewRequest("GET", "/alerts/double-loaded", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var keys []models.DoubleLoadedKey
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+			assert.Len(t, keys, tt.expectedLen)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Operator is a person allowed to act on endpoints that record who acted, such as
+// exit requests. Only the SHA-256 of the operator's bearer token is configured.
+type Operator struct {
+	Name        string `json:"name"`
+	TokenSHA256 string `json:"token_sha256"`
+}
+
+// operatorsConfig is the JSON operators configuration
+type operatorsConfig struct {
+	Operators []Operator `json:"operators"`
+}
+
+// LoadOperators reads a JSON operators configuration from path
+func LoadOperators(path string) ([]Operator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operators config: %w", err)
+	}
+	var cfg operatorsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse operators config: %w", err)
+	}
+	return cfg.Operators, nil
+}
+
+type operatorKey struct{}
+
+// Authenticator resolves bearer tokens to the operators they belong to
+type Authenticator struct {
+	operators map[[sha256.Size]byte]string
+}
+
+// NewAuthenticator creates an authenticator for the configured operators.
+// Names are compared case-insensitively, so they must be unique regardless of case.
+func NewAuthenticator(operators []Operator) (*Authenticator, error) {
+	a := &Authenticator{operators: make(map[[sha256.Size]byte]string, len(operators))}
+	names := make(map[string]bool, len(operators))
+	for _, op := range operators {
+		name := strings.TrimSpace(op.Name)
+		if name == "" {
+			return nil, fmt.Errorf("operator name is required")
+		}
+		if names[strings.ToLower(name)] {
+			return nil, fmt.Errorf("duplicate operator name %q", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		raw, err := hex.DecodeString(op.TokenSHA256)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("operator %q: token_sha256 must be a hex SHA-256 digest", name)
+		}
+		var hash [sha256.Size]byte
+		copy(hash[:], raw)
+		if _, ok := a.operators[hash]; ok {
+			return nil, fmt.Errorf("operator %q: token is shared with another operator", name)
+		}
+		a.operators[hash] = name
+	}
+	return a, nil
+}
+
+// Middleware attaches the operator of the request's bearer token to its context.
+// Requests without a token pass through unauthenticated; an unknown token is rejected.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid authorization header")
+			return
+		}
+		name, ok := a.operators[sha256.Sum256([]byte(token))]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithOperator(r.Context(), name)))
+	})
+}
+
+// WithOperator returns a copy of ctx carrying the authenticated operator name
+func WithOperator(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, name)
+}
+
+// OperatorFromContext returns the authenticated operator name carried by ctx
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(operatorKey{}).(string)
+	return name, ok
+}
+
+// requireOperator writes 401 and returns false unless the request is authenticated
+func requireOperator(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name, ok := OperatorFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "operator authentication required")
+	}
+	return name, ok
+}
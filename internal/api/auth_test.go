@@ -0,0 +1,101 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	auth, err := NewAuthenticator([]Operator{
+		{Name: "alice", TokenSHA256: tokenHash("alice-token")},
+		{Name: "bob", TokenSHA256: tokenHash("bob-token")},
+	})
+	require.NoError(t, err)
+
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := OperatorFromContext(r.Context())
+		if !ok {
+			name = "anonymous"
+		}
+		w.Write([]byte(name))
+	}))
+
+	tests := []struct {
+		name           string
+		header         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "operator token", header: "Bearer bob-token", expectedStatus: http.StatusOK, expectedBody: "bob"},
+		{name: "no token", expectedStatus: http.StatusOK, expectedBody: "anonymous"},
+		{name: "unknown token", header: "Bearer mallory-token", expectedStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", header: "Basic YWxpY2U6eA==", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		operators []Operator
+	}{
+		{name: "missing name", operators: []Operator{{TokenSHA256: tokenHash("a")}}},
+		{name: "names differing in case", operators: []Operator{
+			{Name: "alice", TokenSHA256: tokenHash("a")},
+			{Name: "Alice", TokenSHA256: tokenHash("b")},
+		}},
+		{name: "shared token", operators: []Operator{
+			{Name: "alice", TokenSHA256: tokenHash("a")},
+			{Name: "bob", TokenSHA256: tokenHash("a")},
+		}},
+		{name: "plaintext token", operators: []Operator{{Name: "alice", TokenSHA256: "alice-token"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthenticator(tt.operators)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadOperators(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operators.json")
+	content := `{"operators":[{"name":"alice","token_sha256":"` + tokenHash("alice-token") + `"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	operators, err := LoadOperators(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Operator{{Name: "alice", TokenSHA256: tokenHash("alice-token")}}, operators)
+
+	_, err = LoadOperators(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
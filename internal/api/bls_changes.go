@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxBLSChangesSize bounds the size of an uploaded bls_to_execution_change file
+const maxBLSChangesSize = 10 << 20
+
+// BLSChangeHandler serves the BLS-to-execution change endpoints
+type BLSChangeHandler struct {
+	changes *service.BLSChangeService
+}
+
+// NewBLSChangeHandler creates a new BLS-to-execution change handler
+func NewBLSChangeHandler(changes *service.BLSChangeService) *BLSChangeHandler {
+	return &BLSChangeHandler{changes: changes}
+}
+
+// Routes mounts the BLS-to-execution change endpoints on r
+func (h *BLSChangeHandler) Routes(r chi.Router) {
+	r.Get("/bls-changes", h.List)
+	r.Post("/bls-changes", h.Upload)
+	r.Post("/bls-changes/submit", h.Submit)
+}
+
+// List returns the stored changes, optionally filtered by the status query parameter
+func (h *BLSChangeHandler) List(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.changes.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Failed to list BLS changes: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list BLS changes")
+		return
+	}
+	if changes == nil {
+		changes = []models.BLSChange{}
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// Upload verifies and stores the signed changes of a bls_to_execution_change-*.json file.
+// The network query parameter applies to entries without metadata.
+func (h *BLSChangeHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	var uploads []service.BLSChangeUpload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBLSChangesSize)).Decode(&uploads); err != nil {
+		writeError(w, http.StatusBadRequest, "request body must be a JSON array of signed BLS-to-execution changes")
+		return
+	}
+
+	results, err := h.changes.Upload(r.Context(), r.URL.Query().Get("network"), uploads, sourceIP(r))
+	if err != nil {
+		log.Printf("Failed to upload BLS changes: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to upload BLS changes")
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// Submit sends all uploaded and previously failed changes to the beacon node pool
+func (h *BLSChangeHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	results, err := h.changes.Submit(r.Context(), sourceIP(r))
+	if err != nil {
+		log.Printf("Failed to submit BLS changes: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to submit BLS changes")
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestBLSChangeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockBLSChangeRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/bls-changes?status=failed",
+			mockSetup: func(_ *mocks.MockValidatorRepo, c *mocks.MockBLSChangeRepo) {
+				c.EXPECT().ConfirmApplied(gomock.Any()).Return(int64(0), nil)
+				c.EXPECT().List(gomock.Any(), models.BLSChangeStatusFailed).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "upload rejects unknown validator",
+			method: "POST",
+			path:   "/bls-changes?network=holesky",
+			body: `[{"message":{"validator_index":"7","from_bls_pubkey":"0x` + strings.Repeat("00", 48) +
+				`","to_execution_address":"0x` + strings.Repeat("11", 20) + `"},"signature":"0x00"}]`,
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockBLSChangeRepo) {
+				v.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"validator_index":"7","error":"validator 7 is not managed on holesky"}]`,
+		},
+		{
+			name:           "upload invalid body",
+			method:         "POST",
+			path:           "/bls-changes",
+			body:           `{}`,
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockBLSChangeRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "submit database error",
+			method: "POST",
+			path:   "/bls-changes/submit",
+			mockSetup: func(_ *mocks.MockValidatorRepo, c *mocks.MockBLSChangeRepo) {
+				c.EXPECT().ConfirmApplied(gomock.Any()).Return(int64(0), errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockChanges := mocks.NewMockBLSChangeRepo(ctrl)
+			tt.mockSetup(mockValidators, mockChanges)
+
+			r := chi.NewRouter()
+			svc := service.NewBLSChangeService(mockValidators, mockChanges, mocks.NewMockAuditRepo(ctrl), nil, beacon.Nodes{})
+			NewBLSChangeHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
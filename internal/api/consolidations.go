@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxConsolidationSize bounds the size of a consolidation request body
+const maxConsolidationSize = 1 << 20
+
+// consolidationBody is the body of a planned consolidation
+type consolidationBody struct {
+	SourcePubkey string `json:"source_pubkey"`
+	TargetPubkey string `json:"target_pubkey"`
+}
+
+// ConsolidationHandler serves the consolidation endpoints
+type ConsolidationHandler struct {
+	consolidations *service.ConsolidationService
+}
+
+// NewConsolidationHandler creates a new consolidation handler
+func NewConsolidationHandler(consolidations *service.ConsolidationService) *ConsolidationHandler {
+	return &ConsolidationHandler{consolidations: consolidations}
+}
+
+// Routes mounts the consolidation endpoints on r
+func (h *ConsolidationHandler) Routes(r chi.Router) {
+	r.Get("/consolidations", h.List)
+	r.Post("/consolidations", h.Plan)
+	r.Get("/consolidations/targets", h.Targets)
+}
+
+// List returns the consolidations, optionally filtered by the status query parameter
+func (h *ConsolidationHandler) List(w http.ResponseWriter, r *http.Request) {
+	consolidations, err := h.consolidations.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Failed to list consolidations: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list consolidations")
+		return
+	}
+	if consolidations == nil {
+		consolidations = []models.Consolidation{}
+	}
+	writeJSON(w, http.StatusOK, consolidations)
+}
+
+// Plan records a source validator to be consolidated into a target validator
+func (h *ConsolidationHandler) Plan(w http.ResponseWriter, r *http.Request) {
+	var body consolidationBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConsolidationSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.consolidations.Plan(r.Context(), body.SourcePubkey, body.TargetPubkey, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, c)
+	case errors.Is(err, service.ErrInvalidConsolidation):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrConsolidationInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Failed to plan consolidation: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to plan consolidation")
+	}
+}
+
+// Targets returns the current and projected effective balance of every consolidation target
+func (h *ConsolidationHandler) Targets(w http.ResponseWriter, r *http.Request) {
+	targets, err := h.consolidations.Targets(r.Context())
+	if err != nil {
+		log.Printf("Failed to list consolidation targets: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list consolidation targets")
+		return
+	}
+	if targets == nil {
+		targets = []models.ConsolidationTarget{}
+	}
+	writeJSON(w, http.StatusOK, targets)
+}
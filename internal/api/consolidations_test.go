@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestConsolidationHandler(t *testing.T) {
+	compounding := "0x020000000000000000000000" + strings.Repeat("d8", 20)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockConsolidationRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/consolidations?status=pending",
+			mockSetup: func(_ *mocks.MockValidatorRepo, c *mocks.MockConsolidationRepo) {
+				c.EXPECT().List(gomock.Any(), models.ConsolidationStatusPending).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "targets",
+			method: "GET",
+			path:   "/consolidations/targets",
+			mockSetup: func(v *mocks.MockValidatorRepo, c *mocks.MockConsolidationRepo) {
+				c.EXPECT().List(gomock.Any(), "").Return([]models.Consolidation{
+					{SourcePubkey: "0xaa", TargetPubkey: "0xbb", Status: models.ConsolidationStatusPlanned},
+				}, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xbb").Return(&models.Validator{EffectiveBalance: 32000000000}, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.Validator{EffectiveBalance: 32000000000}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "plan unknown source",
+			method: "POST",
+			path:   "/consolidations",
+			body:   `{"source_pubkey":"0xaa","target_pubkey":"0xbb"}`,
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockConsolidationRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(nil, models.ErrNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid consolidation: validator 0xaa is not managed"}`,
+		},
+		{
+			name:   "plan in progress",
+			method: "POST",
+			path:   "/consolidations",
+			body:   `{"source_pubkey":"0xaa","target_pubkey":"0xbb"}`,
+			mockSetup: func(v *mocks.MockValidatorRepo, c *mocks.MockConsolidationRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.Validator{Status: models.StatusActive, WithdrawalCredentials: compounding}, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xbb").Return(&models.Validator{Status: models.StatusActive, WithdrawalCredentials: compounding}, nil)
+				c.EXPECT().Plan(gomock.Any(), gomock.Any()).Return(models.ErrConsolidationInProgress)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "plan database error",
+			method: "POST",
+			path:   "/consolidations",
+			body:   `{"source_pubkey":"0xaa","target_pubkey":"0xbb"}`,
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockConsolidationRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockConsolidations := mocks.NewMockConsolidationRepo(ctrl)
+			tt.mockSetup(mockValidators, mockConsolidations)
+
+			r := chi.NewRouter()
+			svc := service.NewConsolidationService(service.NewValidatorService(mockValidators, nil), mockConsolidations,
+				mocks.NewMockAuditRepo(ctrl), beacon.Nodes{})
+			NewConsolidationHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
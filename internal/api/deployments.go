@@ -0,0 +1,73 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// DeploymentHandler serves the endpoints that load keys on validator clients and remove them
+type DeploymentHandler struct {
+	deployments *service.DeploymentService
+}
+
+// NewDeploymentHandler creates a new deployment handler
+func NewDeploymentHandler(deployments *service.DeploymentService) *DeploymentHandler {
+	return &DeploymentHandler{deployments: deployments}
+}
+
+// Routes mounts the deployment endpoints on r
+func (h *DeploymentHandler) Routes(r chi.Router) {
+	r.Post("/validators/{pubkey}/deploy", h.Deploy)
+	r.Post("/validators/{pubkey}/undeploy", h.Undeploy)
+}
+
+// Deploy imports the stored keystore of a validator on the validator client
+// instance named by the client query parameter
+func (h *DeploymentHandler) Deploy(w http.ResponseWriter, r *http.Request) {
+	client := r.URL.Query().Get("client")
+	if client == "" {
+		writeError(w, http.StatusBadRequest, "client query parameter is required")
+		return
+	}
+
+	v, err := h.deployments.Deploy(r.Context(), chi.URLParam(r, "pubkey"), client, sourceIP(r))
+	if err != nil {
+		writeDeploymentError(w, "deploy", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// Undeploy deletes the keystore of a validator from the instance named by the
+// client query parameter, or from the instance it is loaded on
+func (h *DeploymentHandler) Undeploy(w http.ResponseWriter, r *http.Request) {
+	v, err := h.deployments.Undeploy(r.Context(), chi.URLParam(r, "pubkey"), r.URL.Query().Get("client"), sourceIP(r))
+	if err != nil {
+		writeDeploymentError(w, "undeploy", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// writeDeploymentError maps a deployment error to its response
+func writeDeploymentError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "validator not found")
+	case errors.Is(err, service.ErrInvalidDeployment):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrAlreadyDeployed), errors.Is(err, service.ErrKeyMigrating), errors.Is(err, service.ErrNotDeployable):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, vclient.ErrRejected):
+		writeError(w, http.StatusBadGateway, err.Error())
+	default:
+		log.Printf("Failed to %s validator: %v", action, err)
+		writeError(w, http.StatusInternalServerError, "failed to "+action+" validator")
+	}
+}
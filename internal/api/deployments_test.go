@@ -0,0 +1,118 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+func TestDeploymentHandler(t *testing.T) {
+	pubkey := "0x" + strings.Repeat("a1", 48)
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "deploy without client",
+			path:           "/validators/" + pubkey + "/deploy",
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "deploy to unknown client",
+			path:           "/validators/" + pubkey + "/deploy?client=prysm-1",
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid deployment: validator client instance \"prysm-1\" is not configured"}`,
+		},
+		{
+			name: "deploy unknown validator",
+			path: "/validators/" + pubkey + "/deploy?client=lh-1",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "deploy already deployed",
+			path: "/validators/" + pubkey + "/deploy?client=lh-1",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, ClientInstance: "lh-1"}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "undeploy not deployed",
+			path: "/validators/" + pubkey + "/undeploy",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "undeploy key the client does not have",
+			path: "/validators/" + pubkey + "/undeploy",
+			mockSetup: func(v *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Client: vclient.TypeLodestar,
+					ClientInstance: "lh-1"}, nil)
+				v.EXPECT().UpdateClient(gomock.Any(), pubkey, "", "").Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	keymanager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/eth/v1/keystores" {
+			w.Write([]byte(`{"data":[{"status":"not_found"}],"slashing_protection":""}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer keymanager.Close()
+	c, err := vclient.New(vclient.Config{Name: "lh-1", Client: vclient.TypeLodestar, URL: keymanager.URL})
+	require.NoError(t, err)
+	clients := []vclient.KeyManager{c.(vclient.KeyManager)}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+			mockMigrations.EXPECT().Holding(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+			tt.mockSetup(mockValidators, mockAudit)
+
+			r := chi.NewRouter()
+			doppelganger := service.NewDoppelgangerService(mocks.NewMockDoppelgangerCheckRepo(ctrl), mockValidators, nil, mockAudit, beacon.Nodes{}, 3)
+			NewDeploymentHandler(service.NewDeploymentService(mockValidators, mockMigrations, nil, nil, doppelganger, mockAudit, clients)).Routes(r)
+
+			req := httptest.NewRequest("POST", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
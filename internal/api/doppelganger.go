@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxDoppelgangerCheckSize bounds the size of a doppelganger check body
+const maxDoppelgangerCheckSize = 1 << 20
+
+// doppelgangerCheckBody is the body of a new doppelganger check
+type doppelgangerCheckBody struct {
+	Pubkey string `json:"pubkey"`
+	// Epochs overrides the default number of quiet epochs when set
+	Epochs int64 `json:"epochs"`
+}
+
+// deployableResponse tells whether a validator may be enabled on a validator client
+type deployableResponse struct {
+	Pubkey     string `json:"pubkey"`
+	Deployable bool   `json:"deployable"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// DoppelgangerHandler serves the doppelganger check endpoints
+type DoppelgangerHandler struct {
+	doppelganger *service.DoppelgangerService
+}
+
+// NewDoppelgangerHandler creates a new doppelganger check handler
+func NewDoppelgangerHandler(doppelganger *service.DoppelgangerService) *DoppelgangerHandler {
+	return &DoppelgangerHandler{doppelganger: doppelganger}
+}
+
+// Routes mounts the doppelganger check endpoints on r
+func (h *DoppelgangerHandler) Routes(r chi.Router) {
+	r.Get("/doppelganger-checks", h.List)
+	r.Post("/doppelganger-checks", h.Start)
+	r.Get("/validators/{pubkey}/deployable", h.Deployable)
+}
+
+// List returns the doppelganger checks, optionally of the validator in the pubkey query parameter
+func (h *DoppelgangerHandler) List(w http.ResponseWriter, r *http.Request) {
+	checks, err := h.doppelganger.List(r.Context(), r.URL.Query().Get("pubkey"))
+	if err != nil {
+		log.Printf("Failed to list doppelganger checks: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list doppelganger checks")
+		return
+	}
+	if checks == nil {
+		checks = []models.DoppelgangerCheck{}
+	}
+	writeJSON(w, http.StatusOK, checks)
+}
+
+// Start begins watching a validator for activity. The check runs in the
+// background; a running check of the same key is returned instead of a new one.
+func (h *DoppelgangerHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var body doppelgangerCheckBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDoppelgangerCheckSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.doppelganger.StartCheck(r.Context(), body.Pubkey, body.Epochs, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusAccepted, c)
+	case errors.Is(err, service.ErrInvalidDoppelgangerCheck):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Failed to start doppelganger check: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to start doppelganger check")
+	}
+}
+
+// Deployable tells whether a validator may be enabled on a validator client now,
+// and if not, why
+func (h *DoppelgangerHandler) Deployable(w http.ResponseWriter, r *http.Request) {
+	resp := deployableResponse{Pubkey: chi.URLParam(r, "pubkey"), Deployable: true}
+
+	err := h.doppelganger.Deployable(r.Context(), resp.Pubkey)
+	if errors.Is(err, service.ErrNotDeployable) {
+		resp.Deployable, resp.Reason = false, err.Error()
+	} else if err != nil {
+		log.Printf("Failed to check whether %s is deployable: %v", resp.Pubkey, err)
+		writeError(w, http.StatusInternalServerError, "failed to check whether the validator is deployable")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
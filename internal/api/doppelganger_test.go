@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestDoppelgangerHandler(t *testing.T) {
+	pubkey := "0x" + strings.Repeat("a1", 48)
+	index := int64(7)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/doppelganger-checks?pubkey=" + pubkey,
+			mockSetup: func(c *mocks.MockDoppelgangerCheckRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				c.EXPECT().List(gomock.Any(), pubkey).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "list database error",
+			method: "GET",
+			path:   "/doppelganger-checks",
+			mockSetup: func(c *mocks.MockDoppelgangerCheckRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				c.EXPECT().List(gomock.Any(), "").Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "start",
+			method: "POST",
+			path:   "/doppelganger-checks",
+			body:   `{"pubkey":"` + pubkey + `","epochs":4}`,
+			mockSetup: func(c *mocks.MockDoppelgangerCheckRepo, v *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Status: models.StatusActive,
+					ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}, nil)
+				c.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, check *models.DoppelgangerCheck) error {
+					check.ID, check.Status = 1, models.DoppelgangerStatusRunning
+					return nil
+				})
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:   "start unmanaged validator",
+			method: "POST",
+			path:   "/doppelganger-checks",
+			body:   `{"pubkey":"` + pubkey + `"}`,
+			mockSetup: func(_ *mocks.MockDoppelgangerCheckRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "start invalid body",
+			method:         "POST",
+			path:           "/doppelganger-checks",
+			body:           `[]`,
+			mockSetup:      func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "deployable",
+			method: "GET",
+			path:   "/validators/" + pubkey + "/deployable",
+			mockSetup: func(_ *mocks.MockDoppelgangerCheckRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Status: models.StatusPending}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"pubkey":"` + pubkey + `","deployable":true}`,
+		},
+		{
+			name:   "not deployable",
+			method: "GET",
+			path:   "/validators/" + pubkey + "/deployable",
+			mockSetup: func(c *mocks.MockDoppelgangerCheckRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Status: models.StatusActive}, nil)
+				c.EXPECT().Latest(gomock.Any(), pubkey).Return(&models.DoppelgangerCheck{ID: 2, Status: models.DoppelgangerStatusRunning}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"pubkey":"` + pubkey + `","deployable":false,
+				"reason":"validator is not deployable: doppelganger check 2 is still running"}`,
+		},
+		{
+			name:   "deployable database error",
+			method: "GET",
+			path:   "/validators/" + pubkey + "/deployable",
+			mockSetup: func(_ *mocks.MockDoppelgangerCheckRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/config/spec":
+			w.Write([]byte(`{"data":{"SLOTS_PER_EPOCH":"32"}}`))
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"header":{"message":{"slot":"3200"}}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer node.Close()
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: node.URL}}})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockChecks, mockValidators, mockAudit)
+
+			r := chi.NewRouter()
+			NewDoppelgangerHandler(service.NewDoppelgangerService(mockChecks, mockValidators, mocks.NewMockAlertRepo(ctrl), mockAudit, nodes, 3)).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
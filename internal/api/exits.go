@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxExitRequestSize bounds the size of an exit request body
+const maxExitRequestSize = 1 << 20
+
+// exitRequestBody is the body of a new exit request
+type exitRequestBody struct {
+	Pubkeys []string `json:"pubkeys"`
+	Reason  string   `json:"reason"`
+}
+
+// ExitHandler serves the exit request endpoints. Requesting and reviewing an
+// exit require an authenticated operator, who is recorded as the requester or
+// reviewer, so the two-person rule holds between distinct operators.
+type ExitHandler struct {
+	exits *service.ExitService
+}
+
+// NewExitHandler creates a new exit request handler
+func NewExitHandler(exits *service.ExitService) *ExitHandler {
+	return &ExitHandler{exits: exits}
+}
+
+// Routes mounts the exit request endpoints on r
+func (h *ExitHandler) Routes(r chi.Router) {
+	r.Get("/exit-requests", h.List)
+	r.Post("/exit-requests", h.Create)
+	r.Get("/exit-requests/{id}", h.Get)
+	r.Post("/exit-requests/{id}/approve", h.Approve)
+	r.Post("/exit-requests/{id}/reject", h.Reject)
+}
+
+// List returns the exit requests, optionally filtered by the status query parameter
+func (h *ExitHandler) List(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.exits.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Failed to list exit requests: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list exit requests")
+		return
+	}
+	if requests == nil {
+		requests = []models.ExitRequest{}
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// Create requests the exit of a set of validators, pending approval by a second operator
+func (h *ExitHandler) Create(w http.ResponseWriter, r *http.Request) {
+	operator, ok := requireOperator(w, r)
+	if !ok {
+		return
+	}
+	var body exitRequestBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExitRequestSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req, err := h.exits.Request(r.Context(), body.Pubkeys, operator, body.Reason, sourceIP(r))
+	if errors.Is(err, service.ErrInvalidExitRequest) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create exit request: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to create exit request")
+		return
+	}
+	writeJSON(w, http.StatusCreated, req)
+}
+
+// Get returns an exit request with the progress of each validator
+func (h *ExitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid exit request id")
+		return
+	}
+
+	req, err := h.exits.Get(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "exit request not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get exit request %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to get exit request")
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
+
+// Approve approves a pending exit request and submits its exits to the beacon node
+func (h *ExitHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.exits.Approve)
+}
+
+// Reject declines a pending exit request
+func (h *ExitHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.exits.Reject)
+}
+
+func (h *ExitHandler) review(w http.ResponseWriter, r *http.Request,
+	review func(ctx context.Context, id int64, reviewer, sourceIP string) (*models.ExitRequest, error)) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid exit request id")
+		return
+	}
+	reviewer, ok := requireOperator(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := review(r.Context(), id, reviewer, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, req)
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "exit request not found")
+	case errors.Is(err, service.ErrInvalidExitRequest):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSelfReview):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrRequestNotPending):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Failed to review exit request %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to review exit request")
+	}
+}
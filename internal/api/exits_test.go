@@ -0,0 +1,137 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestExitHandler(t *testing.T) {
+	pending := &models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusPending}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		operator       string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockExitRequestRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/exit-requests?status=pending",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockExitRequestRepo) {
+				e.EXPECT().List(gomock.Any(), models.ExitRequestStatusPending).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:     "create unknown validator",
+			method:   "POST",
+			path:     "/exit-requests",
+			body:     `{"pubkeys":["0xaa"]}`,
+			operator: "alice",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockExitRequestRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid exit request: validator 0xaa is not managed"}`,
+		},
+		{
+			name:   "create unauthenticated",
+			method: "POST",
+			path:   "/exit-requests",
+			// A requester named in the body is ignored
+			body:           `{"pubkeys":["0xaa"],"requested_by":"alice"}`,
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockExitRequestRepo) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "get not found",
+			method: "GET",
+			path:   "/exit-requests/9",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockExitRequestRepo) {
+				e.EXPECT().Get(gomock.Any(), int64(9)).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "self approval",
+			method:   "POST",
+			path:     "/exit-requests/1/approve",
+			operator: "alice",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockExitRequestRepo) {
+				e.EXPECT().Get(gomock.Any(), int64(1)).Return(pending, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "reject reviewed request",
+			method:   "POST",
+			path:     "/exit-requests/1/reject",
+			operator: "bob",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockExitRequestRepo) {
+				e.EXPECT().Get(gomock.Any(), int64(1)).Return(&models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusSubmitted}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "approve unauthenticated",
+			method:         "POST",
+			path:           "/exit-requests/1/approve",
+			body:           `{"reviewer":"bob"}`,
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockExitRequestRepo) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid id",
+			method:         "POST",
+			path:           "/exit-requests/abc/approve",
+			operator:       "bob",
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockExitRequestRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+			tt.mockSetup(mockValidators, mockRequests)
+
+			r := chi.NewRouter()
+			svc := service.NewExitService(mockRequests, mocks.NewMockVoluntaryExitRepo(ctrl),
+				service.NewValidatorService(mockValidators, nil), mocks.NewMockAuditRepo(ctrl), nil, beacon.Nodes{})
+			NewExitHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.operator != "" {
+				req = req.WithContext(WithOperator(req.Context(), tt.operator))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxKeyMigrationSize bounds the size of a key migration body, which carries a keystore
+const maxKeyMigrationSize = 1 << 20
+
+// KeyMigrationHandler serves the key migration endpoints
+type KeyMigrationHandler struct {
+	migrations *service.KeyMigrationService
+}
+
+// NewKeyMigrationHandler creates a new key migration handler
+func NewKeyMigrationHandler(migrations *service.KeyMigrationService) *KeyMigrationHandler {
+	return &KeyMigrationHandler{migrations: migrations}
+}
+
+// Routes mounts the key migration endpoints on r
+func (h *KeyMigrationHandler) Routes(r chi.Router) {
+	r.Get("/key-migrations", h.List)
+	r.Post("/key-migrations", h.Create)
+	r.Get("/key-migrations/{id}", h.Get)
+	r.Post("/key-migrations/{id}/release", h.Release)
+}
+
+// List returns the key migrations, optionally filtered by the status query parameter
+func (h *KeyMigrationHandler) List(w http.ResponseWriter, r *http.Request) {
+	migrations, err := h.migrations.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Failed to list key migrations: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list key migrations")
+		return
+	}
+	if migrations == nil {
+		migrations = []models.KeyMigration{}
+	}
+	writeJSON(w, http.StatusOK, migrations)
+}
+
+// Create starts moving a key between validator client instances
+func (h *KeyMigrationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var body service.KeyMigrationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxKeyMigrationSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	m, err := h.migrations.Create(r.Context(), body, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, m)
+	case errors.Is(err, service.ErrInvalidKeyMigration):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrMigrationInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Failed to create key migration: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to create key migration")
+	}
+}
+
+// Get returns a key migration with the step it reached
+func (h *KeyMigrationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid key migration id")
+		return
+	}
+
+	m, err := h.migrations.Get(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "key migration not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get key migration %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to get key migration")
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// Release discards the keystore a failed migration kept, so the key can be deployed again
+func (h *KeyMigrationHandler) Release(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid key migration id")
+		return
+	}
+
+	m, err := h.migrations.Release(r.Context(), id, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, m)
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "key migration not found")
+	case errors.Is(err, service.ErrInvalidKeyMigration):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Failed to release key migration %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to release key migration")
+	}
+}
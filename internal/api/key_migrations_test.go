@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+func TestKeyMigrationHandler(t *testing.T) {
+	pubkey := "0x" + strings.Repeat("a1", 48)
+	index := int64(7)
+	body := `{"pubkey":"` + pubkey + `","source_instance":"lh-1","target_instance":"teku-1","requested_by":"alice",
+		"keystore":{"pubkey":"` + pubkey[2:] + `"},"password":"pw"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockKeyMigrationRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/key-migrations?status=failed",
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				m.EXPECT().List(gomock.Any(), models.KeyMigrationStatusFailed).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "get not found",
+			method: "GET",
+			path:   "/key-migrations/3",
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				m.EXPECT().Get(gomock.Any(), int64(3)).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/key-migrations",
+			body:   body,
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, v *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{
+					Pubkey: pubkey, Status: models.StatusActive, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+				}, nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, km *models.KeyMigration) error {
+					km.ID, km.Status = 1, models.KeyMigrationStatusPending
+					return nil
+				})
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "create in progress",
+			method: "POST",
+			path:   "/key-migrations",
+			body:   body,
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{
+					Pubkey: pubkey, Status: models.StatusActive, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+				}, nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.ErrMigrationInProgress)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "release",
+			method: "POST",
+			path:   "/key-migrations/4/release",
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, _ *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				m.EXPECT().Get(gomock.Any(), int64(4)).Return(&models.KeyMigration{
+					ID: 4, Pubkey: pubkey, Status: models.KeyMigrationStatusFailed, Keystore: []byte("sealed"),
+				}, nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, km *models.KeyMigration) error {
+					assert.Nil(t, km.Keystore)
+					return nil
+				})
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "release without keystore",
+			method: "POST",
+			path:   "/key-migrations/5/release",
+			mockSetup: func(m *mocks.MockKeyMigrationRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				m.EXPECT().Get(gomock.Any(), int64(5)).Return(&models.KeyMigration{
+					ID: 5, Pubkey: pubkey, Status: models.KeyMigrationStatusSourceDeleted, Keystore: []byte("sealed"),
+				}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "create unknown instance",
+			method:         "POST",
+			path:           "/key-migrations",
+			body:           `{"pubkey":"` + pubkey + `","source_instance":"lh-1","target_instance":"nimbus-1","requested_by":"alice"}`,
+			mockSetup:      func(*mocks.MockKeyMigrationRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid key migration: validator client instance \"nimbus-1\" is not configured"}`,
+		},
+	}
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: "http://localhost"}}})
+	var clients []vclient.KeyManager
+	for _, cfg := range []vclient.Config{
+		{Name: "lh-1", Client: vclient.TypeLodestar, URL: "http://localhost"},
+		{Name: "teku-1", Client: vclient.TypeLodestar, URL: "http://localhost"},
+	} {
+		c, err := vclient.New(cfg)
+		require.NoError(t, err)
+		clients = append(clients, c.(vclient.KeyManager))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockMigrations, mockValidators, mockAudit)
+
+			r := chi.NewRouter()
+			svc := service.NewKeyMigrationService(mockMigrations, mockValidators, nil, nil, mockAudit, cipher, nodes, clients, 2)
+			NewKeyMigrationHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			assert.NotContains(t, w.Body.String(), "pw")
+		})
+	}
+}
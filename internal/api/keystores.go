@@ -0,0 +1,102 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/keystore"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxKeystoreSize bounds the size of a keystore upload
+const maxKeystoreSize = 1 << 20
+
+// KeystoreHandler serves the keystore storage endpoints. Responses only carry
+// keystore metadata; the encrypted keystore and password are never returned.
+type KeystoreHandler struct {
+	keystores *service.KeystoreService
+}
+
+// NewKeystoreHandler creates a new keystore handler
+func NewKeystoreHandler(keystores *service.KeystoreService) *KeystoreHandler {
+	return &KeystoreHandler{keystores: keystores}
+}
+
+// Routes mounts the keystore endpoints on r
+func (h *KeystoreHandler) Routes(r chi.Router) {
+	r.Get("/keystores", h.List)
+	r.Post("/keystores", h.Store)
+	r.Get("/keystores/{pubkey}", h.Get)
+	r.Delete("/keystores/{pubkey}", h.Delete)
+}
+
+// List returns the metadata of all stored keystores
+func (h *KeystoreHandler) List(w http.ResponseWriter, r *http.Request) {
+	keystores, err := h.keystores.List(r.Context())
+	if err != nil {
+		log.Printf("Failed to list keystores: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list keystores")
+		return
+	}
+	if keystores == nil {
+		keystores = []models.StoredKeystore{}
+	}
+	writeJSON(w, http.StatusOK, keystores)
+}
+
+// Store uploads an EIP-2335 keystore and its password
+func (h *KeystoreHandler) Store(w http.ResponseWriter, r *http.Request) {
+	var body service.KeystoreRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxKeystoreSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	k, err := h.keystores.Store(r.Context(), body, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, k)
+	case errors.Is(err, keystore.ErrInvalid), errors.Is(err, keystore.ErrWrongPassword), errors.Is(err, service.ErrInvalidKeystore):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Failed to store keystore: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to store keystore")
+	}
+}
+
+// Get returns the metadata of the keystore stored for a validator
+func (h *KeystoreHandler) Get(w http.ResponseWriter, r *http.Request) {
+	pubkey := chi.URLParam(r, "pubkey")
+	k, err := h.keystores.Get(r.Context(), pubkey)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "keystore not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get keystore of %s: %v", pubkey, err)
+		writeError(w, http.StatusInternalServerError, "failed to get keystore")
+		return
+	}
+	writeJSON(w, http.StatusOK, k)
+}
+
+// Delete removes the keystore stored for a validator
+func (h *KeystoreHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	pubkey := chi.URLParam(r, "pubkey")
+	err := h.keystores.Delete(r.Context(), pubkey, sourceIP(r))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "keystore not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete keystore of %s: %v", pubkey, err)
+		writeError(w, http.StatusInternalServerError, "failed to delete keystore")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+func TestKeystoreHandler(t *testing.T) {
+	pubkey := "0x9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+	keystore := `{"crypto":{` +
+		`"kdf":{"function":"pbkdf2","params":{"dklen":32,"c":262144,"prf":"hmac-sha256","salt":"d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},"message":""},` +
+		`"checksum":{"function":"sha256","params":{},"message":"8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"},` +
+		`"cipher":{"function":"aes-128-ctr","params":{"iv":"264daa3f303d7259501c93d997d84fe6"},"message":"cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"}},` +
+		`"pubkey":"` + pubkey[2:] + `","path":"m/12381/60/0/0","uuid":"64625def-3331-4eea-ab6f-782f3ed16a83","version":4}`
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockKeystoreRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "store",
+			method: "POST",
+			path:   "/keystores",
+			body:   `{"keystore":` + keystore + `,"password":"secret"}`,
+			mockSetup: func(k *mocks.MockKeystoreRepo, v *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey}, nil)
+				k.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *models.StoredKeystore) error {
+					s.ID, s.CreatedAt, s.UpdatedAt = 1, created, created
+					return nil
+				})
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":1,"pubkey":"` + pubkey + `","path":"m/12381/60/0/0","uuid":"64625def-3331-4eea-ab6f-782f3ed16a83",
+				"kdf":"pbkdf2","password_verified":false,"created_at":"2025-01-02T03:04:05Z","updated_at":"2025-01-02T03:04:05Z"}`,
+		},
+		{
+			name:           "store invalid keystore",
+			method:         "POST",
+			path:           "/keystores",
+			body:           `{"keystore":{"version":3},"password":"secret"}`,
+			mockSetup:      func(*mocks.MockKeystoreRepo, *mocks.MockValidatorRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid keystore: unsupported version 3"}`,
+		},
+		{
+			name:   "store unmanaged validator",
+			method: "POST",
+			path:   "/keystores",
+			body:   `{"keystore":` + keystore + `,"password":"secret"}`,
+			mockSetup: func(_ *mocks.MockKeystoreRepo, v *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/keystores",
+			mockSetup: func(k *mocks.MockKeystoreRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				k.EXPECT().List(gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   "/keystores/" + pubkey,
+			mockSetup: func(k *mocks.MockKeystoreRepo, _ *mocks.MockValidatorRepo, _ *mocks.MockAuditRepo) {
+				k.EXPECT().Get(gomock.Any(), pubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/keystores/" + pubkey,
+			mockSetup: func(k *mocks.MockKeystoreRepo, _ *mocks.MockValidatorRepo, a *mocks.MockAuditRepo) {
+				k.EXPECT().Delete(gomock.Any(), pubkey).Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockKeystores := mocks.NewMockKeystoreRepo(ctrl)
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockKeystores, mockValidators, mockAudit)
+
+			r := chi.NewRouter()
+			NewKeystoreHandler(service.NewKeystoreService(mockKeystores, mockValidators, mockAudit, cipher)).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
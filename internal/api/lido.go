@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/lido"
+)
+
+// lidoSummarizer reports the key usage of our Lido node operators
+type lidoSummarizer interface {
+	Summaries(ctx context.Context) ([]lido.OperatorSummary, error)
+}
+
+// LidoHandler serves the Lido endpoints
+type LidoHandler struct {
+	lido lidoSummarizer
+}
+
+// NewLidoHandler creates a new Lido handler
+func NewLidoHandler(l lidoSummarizer) *LidoHandler {
+	return &LidoHandler{lido: l}
+}
+
+// Routes mounts the Lido endpoints on r
+func (h *LidoHandler) Routes(r chi.Router) {
+	r.Get("/lido/operators", h.ListOperators)
+}
+
+// ListOperators returns how many deposited, vetted and unvetted keys each configured operator has
+func (h *LidoHandler) ListOperators(w http.ResponseWriter, r *http.Request) {
+	summaries, err := h.lido.Summaries(r.Context())
+	if err != nil {
+		log.Printf("Failed to read Lido operators: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "failed to read Lido node operators")
+		return
+	}
+	if summaries == nil {
+		summaries = []lido.OperatorSummary{}
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
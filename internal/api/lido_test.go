@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/lido"
+)
+
+// fakeLido returns fixed operator summaries
+type fakeLido struct {
+	summaries []lido.OperatorSummary
+	err       error
+}
+
+func (f *fakeLido) Summaries(_ context.Context) ([]lido.OperatorSummary, error) {
+	return f.summaries, f.err
+}
+
+func TestLidoHandler_ListOperators(t *testing.T) {
+	tests := []struct {
+		name           string
+		lido           *fakeLido
+		expectedStatus int
+		expectedLen    int
+	}{
+		{
+			name: "operators",
+			lido: &fakeLido{summaries: []lido.OperatorSummary{
+				{Network: "mainnet", OperatorID: 7, TotalAdded: 10, TotalVetted: 8, TotalDeposited: 5, Available: 3, Unvetted: 2},
+			}},
+			expectedStatus: http.StatusOK,
+			expectedLen:    1,
+		},
+		{
+			name:           "not configured",
+			lido:           &fakeLido{},
+			expectedStatus: http.StatusOK,
+			expectedLen:    0,
+		},
+		{
+			name:           "execution node unavailable",
+			lido:           &fakeLido{err: errors.New("connection refused")},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			NewLidoHandler(tt.lido).Routes(r)
+
+			req := httptest.NewRequest("GET", "/lido/operators", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var summaries []lido.OperatorSummary
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summaries))
+			assert.Len(t, summaries, tt.expectedLen)
+		})
+	}
+}
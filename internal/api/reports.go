@@ -0,0 +1,36 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// ReportHandler serves the report endpoints
+type ReportHandler struct {
+	reconciliation *service.ReconciliationService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reconciliation *service.ReconciliationService) *ReportHandler {
+	return &ReportHandler{reconciliation: reconciliation}
+}
+
+// Routes mounts the report endpoints on r
+func (h *ReportHandler) Routes(r chi.Router) {
+	r.Get("/reports/reconciliation", h.Reconciliation)
+}
+
+// Reconciliation compares the database with the validator clients, the beacon
+// chain and the Lido registries and returns the keys they disagree on
+func (h *ReportHandler) Reconciliation(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reconciliation.Reconcile(r.Context())
+	if err != nil {
+		log.Printf("Failed to build reconciliation report: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to build reconciliation report")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestReportHandler_Reconciliation(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockValidatorRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "stored key not loaded",
+			mockSetup: func(v *mocks.MockValidatorRepo) {
+				v.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{
+					{Pubkey: "0xaa", BlockchainNetwork: "mainnet", Status: models.StatusActive},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "database error",
+			mockSetup: func(v *mocks.MockValidatorRepo) {
+				v.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to build reconciliation report"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			tt.mockSetup(mockValidators)
+
+			r := chi.NewRouter()
+			NewReportHandler(service.NewReconciliationService(mockValidators, nil, beacon.Nodes{}, nil)).Routes(r)
+
+			req := httptest.NewRequest("GET", "/reports/reconciliation", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"not_loaded":[{"pubkey":"0xaa","network":"mainnet","status":"active"}]`)
+			}
+		})
+	}
+}
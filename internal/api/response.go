@@ -0,0 +1,37 @@
+// Package api provides the HTTP handlers of the validator key manager service
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+)
+
+// errorResponse is the body returned for failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON encodes v as the JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// writeError writes a JSON error body with the given status code
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// sourceIP returns the client address of r for the audit log
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// SignerHandler serves the remote signer endpoints
+type SignerHandler struct {
+	signers *service.RemoteSignerService
+}
+
+// NewSignerHandler creates a new remote signer handler
+func NewSignerHandler(signers *service.RemoteSignerService) *SignerHandler {
+	return &SignerHandler{signers: signers}
+}
+
+// Routes mounts the remote signer endpoints on r
+func (h *SignerHandler) Routes(r chi.Router) {
+	r.Get("/signers", h.Health)
+	r.Get("/validators/{pubkey}/signers", h.Locate)
+	r.Post("/validators/{pubkey}/remote-key", h.Register)
+	r.Delete("/validators/{pubkey}/remote-key", h.Unregister)
+}
+
+// Health reports the reachability and health of every configured signer
+func (h *SignerHandler) Health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.signers.Health(r.Context()))
+}
+
+// Locate lists the signers holding a key and the clients signing for it through a signer
+func (h *SignerHandler) Locate(w http.ResponseWriter, r *http.Request) {
+	loc, err := h.signers.Locate(r.Context(), chi.URLParam(r, "pubkey"))
+	if err != nil {
+		log.Printf("Failed to locate key: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to locate key")
+		return
+	}
+	writeJSON(w, http.StatusOK, loc)
+}
+
+// Register makes the instance named by the client query parameter sign for a
+// validator through the signer named by the signer query parameter
+func (h *SignerHandler) Register(w http.ResponseWriter, r *http.Request) {
+	signer, client := r.URL.Query().Get("signer"), r.URL.Query().Get("client")
+	if signer == "" || client == "" {
+		writeError(w, http.StatusBadRequest, "signer and client query parameters are required")
+		return
+	}
+
+	v, err := h.signers.Register(r.Context(), chi.URLParam(r, "pubkey"), signer, client, sourceIP(r))
+	if err != nil {
+		writeDeploymentError(w, "register", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// Unregister removes the remote key of a validator from the instance named by
+// the client query parameter, or from the instance it is loaded on
+func (h *SignerHandler) Unregister(w http.ResponseWriter, r *http.Request) {
+	v, err := h.signers.Unregister(r.Context(), chi.URLParam(r, "pubkey"), r.URL.Query().Get("client"), sourceIP(r))
+	if err != nil {
+		writeDeploymentError(w, "unregister", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+	"github.com/zheli/validator-key-manager-backend/pkg/web3signer"
+)
+
+func TestSignerHandler(t *testing.T) {
+	pubkey := "0x" + strings.Repeat("a1", 48)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockSignerKeyRepo, *mocks.MockClientKeyRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "health",
+			method: "GET",
+			path:   "/signers",
+			mockSetup: func(*mocks.MockValidatorRepo, *mocks.MockSignerKeyRepo, *mocks.MockClientKeyRepo, *mocks.MockAuditRepo) {
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"w3s-1","url":"http://web3signer:9000","up":true,"status":"UP","checks":[{"id":"default-check","status":"UP"}]}]`,
+		},
+		{
+			name:   "locate",
+			method: "GET",
+			path:   "/validators/" + pubkey + "/signers",
+			mockSetup: func(_ *mocks.MockValidatorRepo, s *mocks.MockSignerKeyRepo, c *mocks.MockClientKeyRepo, _ *mocks.MockAuditRepo) {
+				s.EXPECT().ListByPubkey(gomock.Any(), pubkey).Return(nil, nil)
+				c.EXPECT().ListByPubkey(gomock.Any(), pubkey).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"pubkey":"` + pubkey + `","signers":[],"clients":[]}`,
+		},
+		{
+			name:   "register without signer",
+			method: "POST",
+			path:   "/validators/" + pubkey + "/remote-key?client=lh-1",
+			mockSetup: func(*mocks.MockValidatorRepo, *mocks.MockSignerKeyRepo, *mocks.MockClientKeyRepo, *mocks.MockAuditRepo) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "register key the signer does not hold",
+			method: "POST",
+			path:   "/validators/" + pubkey + "/remote-key?signer=w3s-1&client=lh-1",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockSignerKeyRepo, _ *mocks.MockClientKeyRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Status: models.StatusPending}, nil).Times(2)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid deployment: signer \"w3s-1\" does not hold ` + pubkey + `"}`,
+		},
+		{
+			name:   "register unknown validator",
+			method: "POST",
+			path:   "/validators/" + pubkey + "/remote-key?signer=w3s-1&client=lh-1",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockSignerKeyRepo, _ *mocks.MockClientKeyRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "unregister",
+			method: "DELETE",
+			path:   "/validators/" + pubkey + "/remote-key",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockSignerKeyRepo, _ *mocks.MockClientKeyRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), pubkey).Return(&models.Validator{Pubkey: pubkey, Client: vclient.TypeLodestar,
+					ClientInstance: "lh-1"}, nil)
+				v.EXPECT().UpdateClient(gomock.Any(), pubkey, "", "").Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	signerSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/eth2/publicKeys":
+			w.Write([]byte(`[]`))
+		case "/upcheck":
+			w.Write([]byte("OK"))
+		case "/healthcheck":
+			w.Write([]byte(`{"status":"UP","checks":[{"id":"default-check","status":"UP"}],"outcome":"UP"}`))
+		}
+	}))
+	defer signerSrv.Close()
+	signer, err := web3signer.New(web3signer.Config{Name: "w3s-1", URL: signerSrv.URL, ClientURL: "http://web3signer:9000"})
+	require.NoError(t, err)
+
+	keymanager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/eth/v1/remotekeys" {
+			w.Write([]byte(`{"data":[{"status":"deleted"}]}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer keymanager.Close()
+	c, err := vclient.New(vclient.Config{Name: "lh-1", Client: vclient.TypeLodestar, URL: keymanager.URL})
+	require.NoError(t, err)
+	clients := []vclient.KeyManager{c.(vclient.KeyManager)}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockSignerKeys := mocks.NewMockSignerKeyRepo(ctrl)
+			mockClientKeys := mocks.NewMockClientKeyRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+			mockMigrations.EXPECT().Holding(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+			tt.mockSetup(mockValidators, mockSignerKeys, mockClientKeys, mockAudit)
+
+			r := chi.NewRouter()
+			doppelganger := service.NewDoppelgangerService(mocks.NewMockDoppelgangerCheckRepo(ctrl), mockValidators, nil, mockAudit, beacon.Nodes{}, 3)
+			NewSignerHandler(service.NewRemoteSignerService(mockValidators, mockMigrations, mockSignerKeys, mockClientKeys, doppelganger,
+				mockAudit, []*web3signer.Client{signer}, clients)).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/interchange"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxInterchangeSize bounds the size of an uploaded interchange file. Complete
+// interchanges list every signed block and attestation, so they can be large.
+const maxInterchangeSize = 64 << 20
+
+// exportBody is the body of a slashing protection export request
+type exportBody struct {
+	Pubkeys []string `json:"pubkeys"`
+}
+
+// SlashingProtectionHandler serves the slashing protection interchange endpoints
+type SlashingProtectionHandler struct {
+	protection *service.SlashingProtectionService
+}
+
+// NewSlashingProtectionHandler creates a new slashing protection handler
+func NewSlashingProtectionHandler(protection *service.SlashingProtectionService) *SlashingProtectionHandler {
+	return &SlashingProtectionHandler{protection: protection}
+}
+
+// Routes mounts the slashing protection endpoints on r
+func (h *SlashingProtectionHandler) Routes(r chi.Router) {
+	r.Post("/slashing-protection/import", h.Import)
+	r.Post("/slashing-protection/export", h.Export)
+}
+
+// Import merges an EIP-3076 interchange file into the stored slashing protection
+// data. The source query parameter names the validator client it came from.
+func (h *SlashingProtectionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var i interchange.Interchange
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxInterchangeSize)).Decode(&i); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.protection.Import(r.Context(), &i, r.URL.Query().Get("source"), sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, result)
+	case errors.Is(err, interchange.ErrInvalid):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Failed to import slashing protection: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to import slashing protection")
+	}
+}
+
+// Export returns a minimal EIP-3076 interchange for the requested keys, to be
+// imported by the validator client that takes them over
+func (h *SlashingProtectionHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var body exportBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxInterchangeSize)).Decode(&body); err != nil || len(body.Pubkeys) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	i, err := h.protection.Export(r.Context(), body.Pubkeys, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, i)
+	case errors.Is(err, service.ErrMissingSlashingProtection):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrMixedGenesis):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Failed to export slashing protection: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to export slashing protection")
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestSlashingProtectionHandler(t *testing.T) {
+	root := "0x" + strings.Repeat("4b", 32)
+	pubkey := "0x" + strings.Repeat("a1", 48)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockSlashingProtectionRepo, *mocks.MockAuditRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "import",
+			path: "/slashing-protection/import?source=teku-1",
+			body: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + root + `"},
+				"data":[{"pubkey":"` + pubkey + `","signed_blocks":[{"slot":"100"}],"signed_attestations":[]}]}`,
+			mockSetup: func(p *mocks.MockSlashingProtectionRepo, a *mocks.MockAuditRepo) {
+				p.EXPECT().Merge(gomock.Any(), gomock.Any()).Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"imported":1,"rejected":[]}`,
+		},
+		{
+			name:           "import unsupported version",
+			path:           "/slashing-protection/import",
+			body:           `{"metadata":{"interchange_format_version":"4","genesis_validators_root":"` + root + `"},"data":[]}`,
+			mockSetup:      func(*mocks.MockSlashingProtectionRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "import invalid body",
+			path:           "/slashing-protection/import",
+			body:           `[]`,
+			mockSetup:      func(*mocks.MockSlashingProtectionRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "export",
+			path: "/slashing-protection/export",
+			body: `{"pubkeys":["` + pubkey + `"]}`,
+			mockSetup: func(p *mocks.MockSlashingProtectionRepo, a *mocks.MockAuditRepo) {
+				p.EXPECT().List(gomock.Any(), []string{pubkey}).Return([]models.SlashingProtection{
+					{Pubkey: pubkey, GenesisValidatorsRoot: root},
+				}, nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + root + `"},
+				"data":[{"pubkey":"` + pubkey + `","signed_blocks":[],"signed_attestations":[]}]}`,
+		},
+		{
+			name: "export missing key",
+			path: "/slashing-protection/export",
+			body: `{"pubkeys":["` + pubkey + `"]}`,
+			mockSetup: func(p *mocks.MockSlashingProtectionRepo, _ *mocks.MockAuditRepo) {
+				p.EXPECT().List(gomock.Any(), []string{pubkey}).Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "export no keys",
+			path:           "/slashing-protection/export",
+			body:           `{"pubkeys":[]}`,
+			mockSetup:      func(*mocks.MockSlashingProtectionRepo, *mocks.MockAuditRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "export database error",
+			path: "/slashing-protection/export",
+			body: `{"pubkeys":["` + pubkey + `"]}`,
+			mockSetup: func(p *mocks.MockSlashingProtectionRepo, _ *mocks.MockAuditRepo) {
+				p.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProtection := mocks.NewMockSlashingProtectionRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockProtection, mockAudit)
+
+			r := chi.NewRouter()
+			NewSlashingProtectionHandler(service.NewSlashingProtectionService(mockProtection, mockAudit)).Routes(r)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// ValidatorHandler serves the validator endpoints
+type ValidatorHandler struct {
+	validators *service.ValidatorService
+}
+
+// NewValidatorHandler creates a new validator handler
+func NewValidatorHandler(validators *service.ValidatorService) *ValidatorHandler {
+	return &ValidatorHandler{validators: validators}
+}
+
+// Routes mounts the validator endpoints on r
+func (h *ValidatorHandler) Routes(r chi.Router) {
+	r.Get("/validators/search", h.Search)
+	r.Post("/validators/deposit-data", h.ImportDepositData)
+}
+
+// maxDepositDataSize bounds the size of an uploaded deposit data file
+const maxDepositDataSize = 10 << 20
+
+// ImportDepositData records the withdrawal credentials from a deposit_data-*.json file,
+// adding keys that are not yet known as unused validators
+func (h *ValidatorHandler) ImportDepositData(w http.ResponseWriter, r *http.Request) {
+	var entries []models.DepositData
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDepositDataSize)).Decode(&entries); err != nil {
+		writeError(w, http.StatusBadRequest, "request body must be a deposit data JSON array")
+		return
+	}
+
+	result, err := h.validators.ImportDepositData(r.Context(), entries)
+	if errors.Is(err, service.ErrInvalidDepositData) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to import deposit data: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to import deposit data")
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Search finds validators by the validator index or pubkey prefix given in q.
+// The optional network parameter restricts index lookups to one network.
+func (h *ValidatorHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "missing query parameter q")
+		return
+	}
+
+	result, err := h.validators.SearchValidators(r.Context(), q, r.URL.Query().Get("network"))
+	if errors.Is(err, service.ErrInvalidQuery) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to search validators for %q: %v", q, err)
+		writeError(w, http.StatusInternalServerError, "failed to search validators")
+		return
+	}
+	if result.Validators == nil {
+		result.Validators = []models.Validator{}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestValidatorHandler_Search(t *testing.T) {
+	fullPubkey := "0x" + strings.Repeat("ab", 48)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*mocks.MockValidatorRepo)
+		expectedStatus int
+		expectedKind   string
+		expectedLen    int
+	}{
+		{
+			name:  "index on a network",
+			query: "?q=42&network=mainnet",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().GetByIndex(gomock.Any(), "mainnet", int64(42)).Return(&models.Validator{Pubkey: fullPubkey}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedKind:   service.SearchKindIndex,
+			expectedLen:    1,
+		},
+		{
+			name:  "index on any network",
+			query: "?q=42",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().List(gomock.Any(), map[string]interface{}{"validator_index": int64(42)}).Return([]models.Validator{
+					{Pubkey: fullPubkey, Blockchain: "ethereum"},
+					{Pubkey: "0xcd", Blockchain: "gnosis"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedKind:   service.SearchKindIndex,
+			expectedLen:    2,
+		},
+		{
+			name:  "unknown index",
+			query: "?q=7&network=holesky",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().GetByIndex(gomock.Any(), "holesky", int64(7)).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusOK,
+			expectedKind:   service.SearchKindIndex,
+			expectedLen:    0,
+		},
+		{
+			name:  "pubkey prefix without 0x",
+			query: "?q=ABAB",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().SearchByPubkeyPrefix(gomock.Any(), "0xabab", 50).Return([]models.Validator{{Pubkey: fullPubkey}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedKind:   service.SearchKindPrefix,
+			expectedLen:    1,
+		},
+		{
+			name:  "full pubkey",
+			query: "?q=" + fullPubkey,
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().GetByPubkey(gomock.Any(), fullPubkey).Return(&models.Validator{Pubkey: fullPubkey}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedKind:   service.SearchKindPubkey,
+			expectedLen:    1,
+		},
+		{
+			name:           "prefix too short",
+			query:          "?q=0xab",
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not hex",
+			query:          "?q=0xzzzz",
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing query",
+			query:          "",
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "database error",
+			query: "?q=0xabcd",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().SearchByPubkeyPrefix(gomock.Any(), "0xabcd", 50).Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockValidatorRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			r := chi.NewRouter()
+			NewValidatorHandler(service.NewValidatorService(mockRepo, nil)).Routes(r)
+
+			req := httptest.NewRequest("GET", "/validators/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result service.SearchResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, tt.expectedKind, result.Kind)
+			assert.Len(t, result.Validators, tt.expectedLen)
+			assert.NotNil(t, result.Validators)
+		})
+	}
+}
+
+func TestValidatorHandler_ImportDepositData(t *testing.T) {
+	pubkey := strings.Repeat("a1", 48)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*mocks.MockValidatorRepo)
+		expectedStatus int
+	}{
+		{
+			name: "new key",
+			body: `[{"pubkey":"` + pubkey + `","withdrawal_credentials":"00` + strings.Repeat("cd", 31) +
+				`","amount":32000000000,"network_name":"holesky"}]`,
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().GetByPubkey(gomock.Any(), "0x"+pubkey).Return(nil, sql.ErrNoRows)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().UpdateWithdrawalCredentials(gomock.Any(), "0x"+pubkey, gomock.Any(), models.CredentialsSourceDepositData).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unsupported network",
+			body:           `[{"pubkey":"` + pubkey + `","withdrawal_credentials":"00` + strings.Repeat("cd", 31) + `","network_name":"goerli"}]`,
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not an array",
+			body:           `{"pubkey":"` + pubkey + `"}`,
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockValidatorRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			r := chi.NewRouter()
+			NewValidatorHandler(service.NewValidatorService(mockRepo, nil)).Routes(r)
+
+			req := httptest.NewRequest("POST", "/validators/deposit-data", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, `{"created":1,"updated":0}`, w.Body.String())
+			}
+		})
+	}
+}
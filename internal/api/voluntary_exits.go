@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// maxVoluntaryExitsSize bounds the size of an uploaded set of signed exits
+const maxVoluntaryExitsSize = 10 << 20
+
+// VoluntaryExitHandler serves the pre-signed voluntary exit endpoints
+type VoluntaryExitHandler struct {
+	exits *service.VoluntaryExitService
+}
+
+// NewVoluntaryExitHandler creates a new voluntary exit handler
+func NewVoluntaryExitHandler(exits *service.VoluntaryExitService) *VoluntaryExitHandler {
+	return &VoluntaryExitHandler{exits: exits}
+}
+
+// Routes mounts the voluntary exit endpoints on r
+func (h *VoluntaryExitHandler) Routes(r chi.Router) {
+	r.Get("/voluntary-exits", h.List)
+	r.Post("/voluntary-exits", h.Upload)
+	r.Get("/voluntary-exits/missing", h.Missing)
+}
+
+// List returns the stored pre-signed exits without their signed messages
+func (h *VoluntaryExitHandler) List(w http.ResponseWriter, r *http.Request) {
+	exits, err := h.exits.List(r.Context())
+	if err != nil {
+		log.Printf("Failed to list voluntary exits: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list voluntary exits")
+		return
+	}
+	if exits == nil {
+		exits = []models.VoluntaryExit{}
+	}
+	writeJSON(w, http.StatusOK, exits)
+}
+
+// Upload verifies and stores signed voluntary exits for the validators on the network
+// query parameter. The body is a single SignedVoluntaryExit or a JSON array of them.
+func (h *VoluntaryExitHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	network := r.URL.Query().Get("network")
+	if network == "" {
+		writeError(w, http.StatusBadRequest, "network query parameter is required")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxVoluntaryExitsSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	var exits []beacon.SignedVoluntaryExit
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var exit beacon.SignedVoluntaryExit
+		err = json.Unmarshal(trimmed, &exit)
+		exits = append(exits, exit)
+	} else {
+		err = json.Unmarshal(trimmed, &exits)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "request body must be a signed voluntary exit or a JSON array of them")
+		return
+	}
+
+	results, err := h.exits.Upload(r.Context(), network, exits, sourceIP(r))
+	if err != nil {
+		log.Printf("Failed to upload voluntary exits: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to upload voluntary exits")
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// Missing returns the active validators without a valid pre-signed exit
+func (h *VoluntaryExitHandler) Missing(w http.ResponseWriter, r *http.Request) {
+	validators, err := h.exits.Missing(r.Context())
+	if err != nil {
+		log.Printf("Failed to list validators missing an exit: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list validators missing an exit")
+		return
+	}
+	if validators == nil {
+		validators = []models.Validator{}
+	}
+	writeJSON(w, http.StatusOK, validators)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestVoluntaryExitHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*mocks.MockValidatorRepo, *mocks.MockVoluntaryExitRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/voluntary-exits",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockVoluntaryExitRepo) {
+				e.EXPECT().List(gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "missing",
+			method: "GET",
+			path:   "/voluntary-exits/missing",
+			mockSetup: func(_ *mocks.MockValidatorRepo, e *mocks.MockVoluntaryExitRepo) {
+				e.EXPECT().ListMissing(gomock.Any()).Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "upload single exit",
+			method: "POST",
+			path:   "/voluntary-exits?network=holesky",
+			body:   `{"message":{"epoch":"1","validator_index":"7"},"signature":"0x00"}`,
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockVoluntaryExitRepo) {
+				v.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"validator_index":"7","error":"validator 7 is not managed on holesky"}]`,
+		},
+		{
+			name:   "upload array",
+			method: "POST",
+			path:   "/voluntary-exits?network=holesky",
+			body: `[{"message":{"epoch":"1","validator_index":"7"},"signature":"0x00"},
+				{"message":{"epoch":"1","validator_index":"x"},"signature":"0x00"}]`,
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockVoluntaryExitRepo) {
+				v.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"validator_index":"7","error":"validator 7 is not managed on holesky"},
+				{"validator_index":"x","error":"invalid validator index: strconv.ParseUint: parsing \"x\": invalid syntax"}]`,
+		},
+		{
+			name:           "upload without network",
+			method:         "POST",
+			path:           "/voluntary-exits",
+			body:           `[]`,
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockVoluntaryExitRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "upload invalid body",
+			method:         "POST",
+			path:           "/voluntary-exits?network=holesky",
+			body:           `"exit"`,
+			mockSetup:      func(*mocks.MockValidatorRepo, *mocks.MockVoluntaryExitRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockExits := mocks.NewMockVoluntaryExitRepo(ctrl)
+			tt.mockSetup(mockValidators, mockExits)
+
+			r := chi.NewRouter()
+			svc := service.NewVoluntaryExitService(mockValidators, mockExits, mocks.NewMockAuditRepo(ctrl), nil, beacon.Nodes{})
+			NewVoluntaryExitHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// WebhookHandler serves the webhook delivery log endpoints
+type WebhookHandler struct {
+	webhooks *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhooks *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// Routes mounts the webhook endpoints on r
+func (h *WebhookHandler) Routes(r chi.Router) {
+	r.Get("/webhooks/deliveries", h.List)
+	r.Get("/webhooks/deliveries/{id}", h.Get)
+	r.Post("/webhooks/deliveries/{id}/redeliver", h.Redeliver)
+}
+
+// List returns the delivery log, newest first. The webhook, event and status
+// query parameters narrow the list.
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := map[string]interface{}{}
+	for _, key := range []string{"webhook", "event", "status"} {
+		if v := query.Get(key); v != "" {
+			filters[key] = v
+		}
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), filters)
+	if err != nil {
+		log.Printf("Failed to list webhook deliveries: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list webhook deliveries")
+		return
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// Get returns a delivery with its payload and the outcome of its last attempt
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid delivery id")
+		return
+	}
+
+	d, err := h.webhooks.GetDelivery(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "delivery not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get webhook delivery %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to get webhook delivery")
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// Redeliver sends the payload of a delivery again as a new delivery and returns it
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid delivery id")
+		return
+	}
+
+	d, err := h.webhooks.Redeliver(r.Context(), id, sourceIP(r))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, d)
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "delivery not found")
+	case errors.Is(err, service.ErrWebhookNotConfigured):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Failed to redeliver webhook delivery %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to redeliver webhook delivery")
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+	"github.com/zheli/validator-key-manager-backend/pkg/webhook"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		mockSetup      func(*mocks.MockWebhookDeliveryRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list with filters",
+			method: "GET",
+			path:   "/webhooks/deliveries?webhook=ops&status=failed",
+			mockSetup: func(d *mocks.MockWebhookDeliveryRepo) {
+				d.EXPECT().List(gomock.Any(), map[string]interface{}{"webhook": "ops", "status": "failed"}).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:   "get not found",
+			method: "GET",
+			path:   "/webhooks/deliveries/9",
+			mockSetup: func(d *mocks.MockWebhookDeliveryRepo) {
+				d.EXPECT().Get(gomock.Any(), int64(9)).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			method:         "GET",
+			path:           "/webhooks/deliveries/abc",
+			mockSetup:      func(*mocks.MockWebhookDeliveryRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "redeliver to removed webhook",
+			method: "POST",
+			path:   "/webhooks/deliveries/3/redeliver",
+			mockSetup: func(d *mocks.MockWebhookDeliveryRepo) {
+				d.EXPECT().Get(gomock.Any(), int64(3)).Return(&models.WebhookDelivery{ID: 3, Webhook: "old"}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"webhook is no longer configured: old"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDeliveries := mocks.NewMockWebhookDeliveryRepo(ctrl)
+			tt.mockSetup(mockDeliveries)
+
+			r := chi.NewRouter()
+			endpoints := []webhook.Endpoint{{Name: "ops", URL: "https://hooks.example", Secret: "s", Events: webhook.Events}}
+			svc := service.NewWebhookService(mockDeliveries, mocks.NewMockAuditRepo(ctrl), endpoints, webhook.NewClient())
+			NewWebhookHandler(svc).Routes(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
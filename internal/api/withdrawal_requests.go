@@ -0,0 +1,40 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// WithdrawalRequestHandler serves the detected execution layer request endpoints
+type WithdrawalRequestHandler struct {
+	requests *service.WithdrawalRequestService
+}
+
+// NewWithdrawalRequestHandler creates a new withdrawal request handler
+func NewWithdrawalRequestHandler(requests *service.WithdrawalRequestService) *WithdrawalRequestHandler {
+	return &WithdrawalRequestHandler{requests: requests}
+}
+
+// Routes mounts the withdrawal request endpoints on r
+func (h *WithdrawalRequestHandler) Routes(r chi.Router) {
+	r.Get("/withdrawal-requests", h.List)
+}
+
+// List returns the exits and partial withdrawals detected outside this service,
+// optionally filtered by the pubkey query parameter
+func (h *WithdrawalRequestHandler) List(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.requests.List(r.Context(), r.URL.Query().Get("pubkey"))
+	if err != nil {
+		log.Printf("Failed to list withdrawal requests: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list withdrawal requests")
+		return
+	}
+	if requests == nil {
+		requests = []models.WithdrawalRequest{}
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestWithdrawalRequestHandler_List(t *testing.T) {
+	detected := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockWithdrawalRequestRepo)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "by pubkey",
+			path: "/withdrawal-requests?pubkey=0xaa",
+			mockSetup: func(r *mocks.MockWithdrawalRequestRepo) {
+				r.EXPECT().List(gomock.Any(), "0xaa").Return([]models.WithdrawalRequest{
+					{ID: 1, Pubkey: "0xaa", ValidatorIndex: 42, Epoch: 300, SourceAddress: "0xbb", DetectedAt: detected},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":1,"pubkey":"0xaa","validator_index":42,"amount":0,"epoch":300,"source_address":"0xbb",
+				"detected_at":"2025-06-01T00:00:00Z"}]`,
+		},
+		{
+			name: "none",
+			path: "/withdrawal-requests",
+			mockSetup: func(r *mocks.MockWithdrawalRequestRepo) {
+				r.EXPECT().List(gomock.Any(), "").Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "database error",
+			path: "/withdrawal-requests",
+			mockSetup: func(r *mocks.MockWithdrawalRequestRepo) {
+				r.EXPECT().List(gomock.Any(), "").Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRequests := mocks.NewMockWithdrawalRequestRepo(ctrl)
+			tt.mockSetup(mockRequests)
+
+			svc := service.NewWithdrawalRequestService(nil, mockRequests, nil, nil, beacon.Nodes{})
+			r := chi.NewRouter()
+			NewWithdrawalRequestHandler(svc).Routes(r)
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+// WithdrawalHandler serves the withdrawal credentials endpoints
+type WithdrawalHandler struct {
+	withdrawals *service.WithdrawalService
+}
+
+// NewWithdrawalHandler creates a new withdrawal credentials handler
+func NewWithdrawalHandler(withdrawals *service.WithdrawalService) *WithdrawalHandler {
+	return &WithdrawalHandler{withdrawals: withdrawals}
+}
+
+// Routes mounts the withdrawal credentials endpoints on r
+func (h *WithdrawalHandler) Routes(r chi.Router) {
+	r.Get("/reports/withdrawal-credentials", h.Report)
+	r.Get("/validators/{pubkey}/withdrawal-credentials/history", h.History)
+}
+
+// Report lists our keys grouped by withdrawal credential type and withdrawal address.
+// The blockchain, network and type query parameters narrow the report.
+func (h *WithdrawalHandler) Report(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := map[string]interface{}{}
+	if v := query.Get("blockchain"); v != "" {
+		filters["blockchain"] = v
+	}
+	if v := query.Get("network"); v != "" {
+		filters["blockchain_network"] = v
+	}
+	if v := query.Get("type"); v != "" {
+		switch v {
+		case models.WithdrawalCredentialsBLS, models.WithdrawalCredentialsExecution, models.WithdrawalCredentialsCompounding:
+			filters["withdrawal_credentials_type"] = v
+		default:
+			writeError(w, http.StatusBadRequest, "type must be bls, execution or compounding")
+			return
+		}
+	}
+
+	report, err := h.withdrawals.Report(r.Context(), filters)
+	if err != nil {
+		log.Printf("Failed to build withdrawal credentials report: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to build withdrawal credentials report")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// History returns the recorded withdrawal credential changes of a validator
+func (h *WithdrawalHandler) History(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.withdrawals.History(r.Context(), chi.URLParam(r, "pubkey"))
+	if err != nil {
+		log.Printf("Failed to list withdrawal credential changes: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list withdrawal credential changes")
+		return
+	}
+	if changes == nil {
+		changes = []models.WithdrawalCredentialChange{}
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/service"
+)
+
+func TestWithdrawalHandler_Report(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*mocks.MockValidatorRepo)
+		expectedStatus int
+	}{
+		{
+			name:  "bls keys on mainnet",
+			query: "?network=mainnet&type=bls",
+			mockSetup: func(m *mocks.MockValidatorRepo) {
+				m.EXPECT().List(gomock.Any(), map[string]interface{}{
+					"blockchain_network":          "mainnet",
+					"withdrawal_credentials_type": models.WithdrawalCredentialsBLS,
+				}).Return([]models.Validator{{Pubkey: "0xaa", WithdrawalCredentials: "0x00" + strings.Repeat("ab", 31)}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown type",
+			query:          "?type=0x03",
+			mockSetup:      func(*mocks.MockValidatorRepo) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockValidatorRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			r := chi.NewRouter()
+			NewWithdrawalHandler(service.NewWithdrawalService(mockRepo)).Routes(r)
+
+			req := httptest.NewRequest("GET", "/reports/withdrawal-credentials"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var report service.WithdrawalCredentialsReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, 1, report.Totals[models.WithdrawalCredentialsBLS])
+			require.Len(t, report.Groups, 1)
+			assert.Equal(t, []string{"0xaa"}, report.Groups[0].Pubkeys)
+		})
+	}
+}
+
+func TestWithdrawalHandler_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	mockRepo.EXPECT().ListCredentialChanges(gomock.Any(), "0xaa").Return(nil, nil)
+
+	r := chi.NewRouter()
+	NewWithdrawalHandler(service.NewWithdrawalService(mockRepo)).Routes(r)
+
+	req := httptest.NewRequest("GET", "/validators/0xaa/withdrawal-credentials/history", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// AlertRepository implements the AlertRepo interface using SQL
+type AlertRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// Raise stores a new alert unless an unresolved one of the same type exists for the pubkey
+func (r *AlertRepository) Raise(ctx context.Context, a *models.Alert) (bool, error) {
+	query := `
+		INSERT INTO alerts (type, severity, pubkey, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (type, pubkey) WHERE resolved_at IS NULL DO NOTHING
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		a.Type,
+		a.Severity,
+		a.Pubkey,
+		a.Message,
+		time.Now(),
+	).Scan(&a.ID, &a.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to raise alert: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListOpen returns the unresolved alerts of the given type
+func (r *AlertRepository) ListOpen(ctx context.Context, alertType string) ([]models.Alert, error) {
+	query := `
+		SELECT id, type, severity, pubkey, message, created_at, resolved_at, notified_at
+		FROM alerts
+		WHERE type = $1 AND resolved_at IS NULL
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, alertType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var a models.Alert
+		err := rows.Scan(
+			&a.ID,
+			&a.Type,
+			&a.Severity,
+			&a.Pubkey,
+			&a.Message,
+			&a.CreatedAt,
+			&a.ResolvedAt,
+			&a.NotifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// Resolve marks the unresolved alert of the given type for a pubkey as resolved
+func (r *AlertRepository) Resolve(ctx context.Context, alertType, pubkey string) error {
+	query := `
+		UPDATE alerts
+		SET resolved_at = $1
+		WHERE type = $2 AND pubkey = $3 AND resolved_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), alertType, pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to resolve alert: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MarkNotified records that an alert was sent to its notifiers
+func (r *AlertRepository) MarkNotified(ctx context.Context, id int64) error {
+	query := `
+		UPDATE alerts
+		SET notified_at = $1
+		WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark alert notified: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
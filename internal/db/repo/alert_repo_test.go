@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestAlertRepository_Raise(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		mockSetup       func()
+		expectedCreated bool
+	}{
+		{
+			name: "new alert",
+			mockSetup: func() {
+				mock.ExpectQuery("INSERT INTO alerts").
+					WithArgs("double_loaded", "critical", "0xaa", "loaded twice", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+			},
+			expectedCreated: true,
+		},
+		{
+			name: "already open",
+			mockSetup: func() {
+				mock.ExpectQuery("INSERT INTO alerts").
+					WithArgs("double_loaded", "critical", "0xaa", "loaded twice", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+			},
+			expectedCreated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			alert := &models.Alert{
+				Type:     models.AlertTypeDoubleLoaded,
+				Severity: models.SeverityCritical,
+				Pubkey:   "0xaa",
+				Message:  "loaded twice",
+			}
+			created, err := repo.Raise(ctx, alert)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCreated, created)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAlertRepository_ListOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAlertRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "type", "severity", "pubkey", "message", "created_at", "resolved_at", "notified_at"}).
+		AddRow(1, "double_loaded", "critical", "0xaa", "loaded twice", time.Now(), nil, nil)
+	mock.ExpectQuery("SELECT (.+) FROM alerts WHERE type = \\$1 AND resolved_at IS NULL").
+		WithArgs("double_loaded").
+		WillReturnRows(rows)
+
+	alerts, err := repo.ListOpen(context.Background(), models.AlertTypeDoubleLoaded)
+	assert.NoError(t, err)
+	if assert.Len(t, alerts, 1) {
+		assert.Nil(t, alerts[0].ResolvedAt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAlertRepository_Resolve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE alerts SET resolved_at = \\$1").
+		WithArgs(sqlmock.AnyArg(), "double_loaded", "0xaa").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Resolve(ctx, models.AlertTypeDoubleLoaded, "0xaa"))
+
+	mock.ExpectExec("UPDATE alerts SET resolved_at = \\$1").
+		WithArgs(sqlmock.AnyArg(), "double_loaded", "0xbb").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.Resolve(ctx, models.AlertTypeDoubleLoaded, "0xbb"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAlertRepository_MarkNotified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE alerts SET notified_at = \\$1 WHERE id = \\$2").
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.MarkNotified(ctx, 1))
+
+	mock.ExpectExec("UPDATE alerts SET notified_at = \\$1 WHERE id = \\$2").
+		WithArgs(sqlmock.AnyArg(), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.MarkNotified(ctx, 2))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
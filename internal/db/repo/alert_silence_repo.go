@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// AlertSilenceRepository implements the AlertSilenceRepo interface using SQL
+type AlertSilenceRepository struct {
+	db *sql.DB
+}
+
+// NewAlertSilenceRepository creates a new alert silence repository
+func NewAlertSilenceRepository(db *sql.DB) *AlertSilenceRepository {
+	return &AlertSilenceRepository{db: db}
+}
+
+// Create stores a new silence
+func (r *AlertSilenceRepository) Create(ctx context.Context, s *models.AlertSilence) error {
+	query := `
+		INSERT INTO alert_silences (rule, starts_at, ends_at, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		s.Rule,
+		s.StartsAt,
+		s.EndsAt,
+		s.Reason,
+		s.CreatedBy,
+		time.Now(),
+	).Scan(&s.ID, &s.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create alert silence: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the silences that have not ended at now, by start time
+func (r *AlertSilenceRepository) List(ctx context.Context, now time.Time) ([]models.AlertSilence, error) {
+	query := `
+		SELECT id, rule, starts_at, ends_at, reason, created_by, created_at
+		FROM alert_silences
+		WHERE ends_at > $1
+		ORDER BY starts_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []models.AlertSilence
+	for rows.Next() {
+		var s models.AlertSilence
+		if err := rows.Scan(&s.ID, &s.Rule, &s.StartsAt, &s.EndsAt, &s.Reason, &s.CreatedBy, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert silence: %w", err)
+		}
+		silences = append(silences, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert silences: %w", err)
+	}
+
+	return silences, nil
+}
+
+// Delete removes a silence
+func (r *AlertSilenceRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_silences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert silence: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
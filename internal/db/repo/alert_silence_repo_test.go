@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestAlertSilenceRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAlertSilenceRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	s := &models.AlertSilence{Rule: "inactive", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "client upgrade", CreatedBy: "alice"}
+	mock.ExpectQuery("INSERT INTO alert_silences").
+		WithArgs("inactive", s.StartsAt, s.EndsAt, "client upgrade", "alice", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	require.NoError(t, repo.Create(ctx, s))
+	assert.Equal(t, int64(3), s.ID)
+
+	mock.ExpectQuery("SELECT (.+) FROM alert_silences WHERE ends_at > \\$1").
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rule", "starts_at", "ends_at", "reason", "created_by", "created_at"}).
+			AddRow(3, "inactive", now, now.Add(time.Hour), "client upgrade", "alice", now))
+	silences, err := repo.List(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, "inactive", silences[0].Rule)
+
+	mock.ExpectExec("DELETE FROM alert_silences WHERE id = \\$1").
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Delete(ctx, 3))
+
+	mock.ExpectExec("DELETE FROM alert_silences WHERE id = \\$1").
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete(ctx, 4), sql.ErrNoRows)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
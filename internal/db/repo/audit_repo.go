@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// AuditRepository implements the AuditRepo interface using SQL
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record appends an entry to the audit log
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (timestamp, action, source_ip, details)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.Timestamp,
+		entry.Action,
+		entry.SourceIP,
+		entry.Details,
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+
+	return nil
+}
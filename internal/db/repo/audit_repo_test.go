@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestAuditRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("INSERT INTO audit_logs").
+		WithArgs(sqlmock.AnyArg(), "alert.double_loaded", "", "0xaa on lh-1, teku-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	entry := &models.AuditLog{Action: "alert.double_loaded", Details: "0xaa on lh-1, teku-1"}
+	assert.NoError(t, repo.Record(ctx, entry))
+	assert.Equal(t, int64(7), entry.ID)
+	assert.False(t, entry.Timestamp.IsZero())
+
+	mock.ExpectQuery("INSERT INTO audit_logs").
+		WillReturnError(errors.New("database error"))
+	assert.Error(t, repo.Record(ctx, &models.AuditLog{Action: "import"}))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
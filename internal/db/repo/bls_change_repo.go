@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// blsChangeColumns lists the bls_changes columns in the order scanBLSChange reads them
+const blsChangeColumns = `id, pubkey, validator_index, from_bls_pubkey, to_execution_address, message, status, error,
+		submitted_at, created_at, updated_at`
+
+func scanBLSChange(row rowScanner, c *models.BLSChange) error {
+	return row.Scan(
+		&c.ID,
+		&c.Pubkey,
+		&c.ValidatorIndex,
+		&c.FromBLSPubkey,
+		&c.ToExecutionAddress,
+		&c.Message,
+		&c.Status,
+		&c.Error,
+		&c.SubmittedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+// BLSChangeRepository implements the BLSChangeRepo interface using SQL
+type BLSChangeRepository struct {
+	db *sql.DB
+}
+
+// NewBLSChangeRepository creates a new BLS-to-execution change repository
+func NewBLSChangeRepository(db *sql.DB) *BLSChangeRepository {
+	return &BLSChangeRepository{db: db}
+}
+
+// Save stores a verified change, replacing one for the same key that was not yet submitted.
+// It returns models.ErrAlreadySubmitted if the existing change was submitted or confirmed.
+func (r *BLSChangeRepository) Save(ctx context.Context, c *models.BLSChange) error {
+	query := `
+		INSERT INTO bls_changes (pubkey, validator_index, from_bls_pubkey, to_execution_address, message, status,
+			error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, '', $7, $7)
+		ON CONFLICT (pubkey) DO UPDATE
+		SET validator_index = EXCLUDED.validator_index, from_bls_pubkey = EXCLUDED.from_bls_pubkey,
+			to_execution_address = EXCLUDED.to_execution_address, message = EXCLUDED.message,
+			status = EXCLUDED.status, error = '', updated_at = EXCLUDED.updated_at
+		WHERE bls_changes.status IN ('uploaded', 'failed')
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		c.Pubkey,
+		c.ValidatorIndex,
+		c.FromBLSPubkey,
+		c.ToExecutionAddress,
+		c.Message,
+		c.Status,
+		time.Now(),
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrAlreadySubmitted
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save BLS change: %w", err)
+	}
+
+	return nil
+}
+
+// GetByPubkey retrieves the change of a validator
+func (r *BLSChangeRepository) GetByPubkey(ctx context.Context, pubkey string) (*models.BLSChange, error) {
+	query := `
+		SELECT ` + blsChangeColumns + `
+		FROM bls_changes
+		WHERE pubkey = $1`
+
+	c := &models.BLSChange{}
+	err := scanBLSChange(r.db.QueryRowContext(ctx, query, pubkey), c)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get BLS change: %w", err)
+	}
+
+	return c, nil
+}
+
+// List returns the changes with the given status, or all changes if status is empty
+func (r *BLSChangeRepository) List(ctx context.Context, status string) ([]models.BLSChange, error) {
+	query := `
+		SELECT ` + blsChangeColumns + `
+		FROM bls_changes
+		WHERE $1 = '' OR status = $1
+		ORDER BY validator_index`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BLS changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.BLSChange
+	for rows.Next() {
+		var c models.BLSChange
+		if err := scanBLSChange(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan BLS change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating BLS changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// UpdateStatus records the submission outcome of a change
+func (r *BLSChangeRepository) UpdateStatus(ctx context.Context, pubkey, status, errMsg string) error {
+	query := `
+		UPDATE bls_changes
+		SET status = $1, error = $2, updated_at = $3,
+			submitted_at = CASE WHEN $1 = 'submitted' THEN $3 ELSE submitted_at END
+		WHERE pubkey = $4`
+
+	result, err := r.db.ExecContext(ctx, query, status, errMsg, time.Now(), pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to update BLS change status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ConfirmApplied marks submitted changes whose validators now have 0x01 credentials as confirmed
+func (r *BLSChangeRepository) ConfirmApplied(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE bls_changes
+		SET status = 'confirmed', updated_at = $1
+		FROM validators
+		WHERE validators.pubkey = bls_changes.pubkey
+			AND bls_changes.status = 'submitted'
+			AND validators.withdrawal_credentials LIKE '0x01%'`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to confirm BLS changes: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestBLSChangeRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewBLSChangeRepository(db)
+	ctx := context.Background()
+
+	change := &models.BLSChange{
+		Pubkey:             "0xaa",
+		ValidatorIndex:     42,
+		FromBLSPubkey:      "0xbb",
+		ToExecutionAddress: "0xcc",
+		Message:            []byte("sealed"),
+		Status:             models.BLSChangeStatusUploaded,
+	}
+
+	mock.ExpectQuery("INSERT INTO bls_changes (.+) ON CONFLICT \\(pubkey\\) DO UPDATE").
+		WithArgs("0xaa", int64(42), "0xbb", "0xcc", []byte("sealed"), "uploaded", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, time.Now(), time.Now()))
+	assert.NoError(t, repo.Save(ctx, change))
+	assert.Equal(t, int64(1), change.ID)
+
+	// An already submitted change is not replaced
+	mock.ExpectQuery("INSERT INTO bls_changes").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}))
+	assert.Equal(t, models.ErrAlreadySubmitted, repo.Save(ctx, change))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBLSChangeRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewBLSChangeRepository(db)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "pubkey", "validator_index", "from_bls_pubkey", "to_execution_address", "message", "status", "error",
+		"submitted_at", "created_at", "updated_at",
+	}).AddRow(1, "0xaa", 42, "0xbb", "0xcc", []byte("sealed"), "uploaded", "", nil, time.Now(), time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM bls_changes WHERE \\$1 = '' OR status = \\$1").
+		WithArgs("uploaded").
+		WillReturnRows(rows)
+
+	changes, err := repo.List(context.Background(), models.BLSChangeStatusUploaded)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, []byte("sealed"), changes[0].Message)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBLSChangeRepository_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewBLSChangeRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE bls_changes SET status = \\$1, error = \\$2").
+		WithArgs("failed", "invalid signature", sqlmock.AnyArg(), "0xaa").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateStatus(ctx, "0xaa", models.BLSChangeStatusFailed, "invalid signature"))
+
+	mock.ExpectExec("UPDATE bls_changes").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.UpdateStatus(ctx, "0xdd", models.BLSChangeStatusSubmitted, ""))
+
+	mock.ExpectExec("UPDATE bls_changes SET status = 'confirmed'").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	confirmed, err := repo.ConfirmApplied(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), confirmed)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ClientKeyRepository implements the ClientKeyRepo interface using SQL
+type ClientKeyRepository struct {
+	db *sql.DB
+}
+
+// NewClientKeyRepository creates a new client key repository
+func NewClientKeyRepository(db *sql.DB) *ClientKeyRepository {
+	return &ClientKeyRepository{db: db}
+}
+
+// ReplaceInstanceKeys replaces all keys recorded for a client instance
+func (r *ClientKeyRepository) ReplaceInstanceKeys(ctx context.Context, instance string, keys []models.ClientKey) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM client_keys WHERE client_instance = $1`, instance); err != nil {
+		return fmt.Errorf("failed to delete client keys: %w", err)
+	}
+
+	query := `
+		INSERT INTO client_keys (pubkey, client, client_instance, remote, signer_url, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pubkey, client_instance) DO NOTHING`
+
+	now := time.Now()
+	for _, k := range keys {
+		if _, err := tx.ExecContext(ctx, query, k.Pubkey, k.Client, instance, k.Remote, k.SignerURL, now); err != nil {
+			return fmt.Errorf("failed to insert client key: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit client keys: %w", err)
+	}
+
+	return nil
+}
+
+// ListDoubleLoaded returns the pubkeys loaded on more than one client instance
+func (r *ClientKeyRepository) ListDoubleLoaded(ctx context.Context) ([]models.DoubleLoadedKey, error) {
+	query := `
+		SELECT pubkey, client, client_instance, remote, signer_url, last_seen_at
+		FROM client_keys
+		WHERE pubkey IN (
+			SELECT pubkey FROM client_keys GROUP BY pubkey HAVING COUNT(*) > 1
+		)
+		ORDER BY pubkey, client_instance`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list double-loaded keys: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.DoubleLoadedKey
+	for rows.Next() {
+		var k models.ClientKey
+		err := rows.Scan(
+			&k.Pubkey,
+			&k.Client,
+			&k.ClientInstance,
+			&k.Remote,
+			&k.SignerURL,
+			&k.LastSeenAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client key: %w", err)
+		}
+
+		if n := len(result); n == 0 || result[n-1].Pubkey != k.Pubkey {
+			result = append(result, models.DoubleLoadedKey{Pubkey: k.Pubkey})
+		}
+		last := &result[len(result)-1]
+		last.Instances = append(last.Instances, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating client keys: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListByPubkey returns the client instances that have a key loaded
+func (r *ClientKeyRepository) ListByPubkey(ctx context.Context, pubkey string) ([]models.ClientKey, error) {
+	query := `
+		SELECT pubkey, client, client_instance, remote, signer_url, last_seen_at
+		FROM client_keys
+		WHERE pubkey = $1
+		ORDER BY client_instance`
+
+	rows, err := r.db.QueryContext(ctx, query, pubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.ClientKey
+	for rows.Next() {
+		var k models.ClientKey
+		err := rows.Scan(
+			&k.Pubkey,
+			&k.Client,
+			&k.ClientInstance,
+			&k.Remote,
+			&k.SignerURL,
+			&k.LastSeenAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating client keys: %w", err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestClientKeyRepository_ReplaceInstanceKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewClientKeyRepository(db)
+	ctx := context.Background()
+
+	keys := []models.ClientKey{
+		{Pubkey: "0xaa", Client: "teku"},
+		{Pubkey: "0xbb", Client: "teku", Remote: true, SignerURL: "https://signer:9000"},
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func()
+		expectError bool
+	}{
+		{
+			name: "successful replace",
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM client_keys WHERE client_instance = \\$1").
+					WithArgs("teku-1").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("INSERT INTO client_keys").
+					WithArgs("0xaa", "teku", "teku-1", false, "", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO client_keys").
+					WithArgs("0xbb", "teku", "teku-1", true, "https://signer:9000", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "insert error rolls back",
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM client_keys").
+					WithArgs("teku-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO client_keys").
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := repo.ReplaceInstanceKeys(ctx, "teku-1", keys)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestClientKeyRepository_ListDoubleLoaded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewClientKeyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"pubkey", "client", "client_instance", "remote", "signer_url", "last_seen_at"}).
+		AddRow("0xaa", "lighthouse", "lh-1", false, "", time.Now()).
+		AddRow("0xaa", "teku", "teku-1", true, "https://signer:9000", time.Now()).
+		AddRow("0xcc", "prysm", "prysm-1", false, "", time.Now()).
+		AddRow("0xcc", "teku", "teku-1", false, "", time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM client_keys WHERE pubkey IN").
+		WillReturnRows(rows)
+
+	keys, err := repo.ListDoubleLoaded(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "0xaa", keys[0].Pubkey)
+		assert.Len(t, keys[0].Instances, 2)
+		assert.Equal(t, "0xcc", keys[1].Pubkey)
+		assert.Equal(t, "prysm-1", keys[1].Instances[0].ClientInstance)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClientKeyRepository_ListByPubkey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewClientKeyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"pubkey", "client", "client_instance", "remote", "signer_url", "last_seen_at"}).
+		AddRow("0xaa", "teku", "teku-1", true, "https://signer:9000", time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM client_keys WHERE pubkey = \\$1").
+		WithArgs("0xaa").
+		WillReturnRows(rows)
+
+	keys, err := repo.ListByPubkey(context.Background(), "0xaa")
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.True(t, keys[0].Remote)
+		assert.Equal(t, "https://signer:9000", keys[0].SignerURL)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
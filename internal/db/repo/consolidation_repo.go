@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ConsolidationRepository implements the ConsolidationRepo interface using SQL
+type ConsolidationRepository struct {
+	db *sql.DB
+}
+
+// NewConsolidationRepository creates a new consolidation repository
+func NewConsolidationRepository(db *sql.DB) *ConsolidationRepository {
+	return &ConsolidationRepository{db: db}
+}
+
+// Plan records a planned consolidation, replacing a planned one for the same source.
+// It returns models.ErrConsolidationInProgress if the source is already pending or completed.
+func (r *ConsolidationRepository) Plan(ctx context.Context, c *models.Consolidation) error {
+	query := `
+		INSERT INTO consolidations (source_pubkey, target_pubkey, status, created_at, updated_at)
+		VALUES ($1, $2, 'planned', $3, $3)
+		ON CONFLICT (source_pubkey) DO UPDATE
+		SET target_pubkey = EXCLUDED.target_pubkey, updated_at = EXCLUDED.updated_at
+		WHERE consolidations.status = 'planned'
+		RETURNING id, status, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, c.SourcePubkey, c.TargetPubkey, time.Now()).
+		Scan(&c.ID, &c.Status, &c.CreatedAt, &c.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrConsolidationInProgress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to plan consolidation: %w", err)
+	}
+
+	return nil
+}
+
+// SetPending records a consolidation seen in the beacon state queue and reports whether it changed.
+// A planned consolidation of the source becomes pending, with the target the chain reports.
+func (r *ConsolidationRepository) SetPending(ctx context.Context, source, target string) (bool, error) {
+	query := `
+		INSERT INTO consolidations (source_pubkey, target_pubkey, status, created_at, updated_at)
+		VALUES ($1, $2, 'pending', $3, $3)
+		ON CONFLICT (source_pubkey) DO UPDATE
+		SET target_pubkey = EXCLUDED.target_pubkey, status = 'pending', updated_at = EXCLUDED.updated_at
+		WHERE consolidations.status = 'planned'`
+
+	result, err := r.db.ExecContext(ctx, query, source, target, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to record pending consolidation: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// UpdateStatus sets the status of the consolidation of a source validator
+func (r *ConsolidationRepository) UpdateStatus(ctx context.Context, source, status string) error {
+	query := `
+		UPDATE consolidations
+		SET status = $1, updated_at = $2,
+			completed_at = CASE WHEN $1 = 'completed' THEN $2 ELSE completed_at END
+		WHERE source_pubkey = $3`
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), source)
+	if err != nil {
+		return fmt.Errorf("failed to update consolidation status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// List returns the consolidations with the given status, or all consolidations if status is empty
+func (r *ConsolidationRepository) List(ctx context.Context, status string) ([]models.Consolidation, error) {
+	query := `
+		SELECT id, source_pubkey, target_pubkey, status, created_at, updated_at, completed_at
+		FROM consolidations
+		WHERE $1 = '' OR status = $1
+		ORDER BY target_pubkey, id`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consolidations: %w", err)
+	}
+	defer rows.Close()
+
+	var consolidations []models.Consolidation
+	for rows.Next() {
+		var c models.Consolidation
+		err := rows.Scan(
+			&c.ID,
+			&c.SourcePubkey,
+			&c.TargetPubkey,
+			&c.Status,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consolidation: %w", err)
+		}
+		consolidations = append(consolidations, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consolidations: %w", err)
+	}
+
+	return consolidations, nil
+}
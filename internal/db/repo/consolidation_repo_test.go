@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestConsolidationRepository_Plan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewConsolidationRepository(db)
+	ctx := context.Background()
+
+	c := &models.Consolidation{SourcePubkey: "0xaa", TargetPubkey: "0xbb"}
+
+	mock.ExpectQuery("INSERT INTO consolidations (.+) ON CONFLICT \\(source_pubkey\\) DO UPDATE (.+) WHERE consolidations.status = 'planned'").
+		WithArgs("0xaa", "0xbb", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}).AddRow(1, "planned", time.Now(), time.Now()))
+	require.NoError(t, repo.Plan(ctx, c))
+	assert.Equal(t, models.ConsolidationStatusPlanned, c.Status)
+
+	// A consolidation the chain already processes is not replaced
+	mock.ExpectQuery("INSERT INTO consolidations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}))
+	assert.Equal(t, models.ErrConsolidationInProgress, repo.Plan(ctx, c))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConsolidationRepository_SetPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewConsolidationRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO consolidations (.+) 'pending'").
+		WithArgs("0xaa", "0xbb", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	changed, err := repo.SetPending(ctx, "0xaa", "0xbb")
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// Already pending
+	mock.ExpectExec("INSERT INTO consolidations").
+		WithArgs("0xaa", "0xbb", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	changed, err = repo.SetPending(ctx, "0xaa", "0xbb")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConsolidationRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewConsolidationRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM consolidations WHERE \\$1 = '' OR status = \\$1").
+		WithArgs("completed").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source_pubkey", "target_pubkey", "status", "created_at", "updated_at", "completed_at"}).
+			AddRow(1, "0xaa", "0xbb", "completed", now, now, now))
+
+	consolidations, err := repo.List(context.Background(), models.ConsolidationStatusCompleted)
+	require.NoError(t, err)
+	require.Len(t, consolidations, 1)
+	assert.NotNil(t, consolidations[0].CompletedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// doppelgangerCheckColumns lists the doppelganger_checks columns in the order scanDoppelgangerCheck reads them
+const doppelgangerCheckColumns = `id, pubkey, validator_index, epochs, start_epoch, checked_epochs, last_checked_epoch,
+	status, detected_epoch, created_at, updated_at, finished_at`
+
+func scanDoppelgangerCheck(row rowScanner, c *models.DoppelgangerCheck) error {
+	return row.Scan(
+		&c.ID,
+		&c.Pubkey,
+		&c.ValidatorIndex,
+		&c.Epochs,
+		&c.StartEpoch,
+		&c.CheckedEpochs,
+		&c.LastCheckedEpoch,
+		&c.Status,
+		&c.DetectedEpoch,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.FinishedAt,
+	)
+}
+
+// DoppelgangerCheckRepository implements the DoppelgangerCheckRepo interface using SQL
+type DoppelgangerCheckRepository struct {
+	db *sql.DB
+}
+
+// NewDoppelgangerCheckRepository creates a new doppelganger check repository
+func NewDoppelgangerCheckRepository(db *sql.DB) *DoppelgangerCheckRepository {
+	return &DoppelgangerCheckRepository{db: db}
+}
+
+// Create stores a new running check. It returns models.ErrCheckInProgress if
+// the key already has a running check.
+func (r *DoppelgangerCheckRepository) Create(ctx context.Context, c *models.DoppelgangerCheck) error {
+	query := `
+		INSERT INTO doppelganger_checks (pubkey, validator_index, epochs, start_epoch, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'running', $5, $5)
+		ON CONFLICT (pubkey) WHERE status = 'running' DO NOTHING
+		RETURNING id, status, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, c.Pubkey, c.ValidatorIndex, c.Epochs, c.StartEpoch, time.Now()).
+		Scan(&c.ID, &c.Status, &c.CreatedAt, &c.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrCheckInProgress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create doppelganger check: %w", err)
+	}
+
+	return nil
+}
+
+// Latest returns the most recent check of a key
+func (r *DoppelgangerCheckRepository) Latest(ctx context.Context, pubkey string) (*models.DoppelgangerCheck, error) {
+	query := `
+		SELECT ` + doppelgangerCheckColumns + `
+		FROM doppelganger_checks
+		WHERE pubkey = $1
+		ORDER BY id DESC
+		LIMIT 1`
+
+	c := &models.DoppelgangerCheck{}
+	err := scanDoppelgangerCheck(r.db.QueryRowContext(ctx, query, pubkey), c)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get doppelganger check: %w", err)
+	}
+
+	return c, nil
+}
+
+// List returns the checks of a key, or of all keys if pubkey is empty, newest first
+func (r *DoppelgangerCheckRepository) List(ctx context.Context, pubkey string) ([]models.DoppelgangerCheck, error) {
+	query := `
+		SELECT ` + doppelgangerCheckColumns + `
+		FROM doppelganger_checks
+		WHERE $1 = '' OR pubkey = $1
+		ORDER BY id DESC`
+
+	return r.list(ctx, query, pubkey)
+}
+
+// ListRunning returns the checks that are still running
+func (r *DoppelgangerCheckRepository) ListRunning(ctx context.Context) ([]models.DoppelgangerCheck, error) {
+	query := `
+		SELECT ` + doppelgangerCheckColumns + `
+		FROM doppelganger_checks
+		WHERE status = 'running'
+		ORDER BY id`
+
+	return r.list(ctx, query)
+}
+
+func (r *DoppelgangerCheckRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.DoppelgangerCheck, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list doppelganger checks: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []models.DoppelgangerCheck
+	for rows.Next() {
+		var c models.DoppelgangerCheck
+		if err := scanDoppelgangerCheck(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan doppelganger check: %w", err)
+		}
+		checks = append(checks, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating doppelganger checks: %w", err)
+	}
+
+	return checks, nil
+}
+
+// Update stores the progress of a check. The finish time is set once it passes or fails.
+func (r *DoppelgangerCheckRepository) Update(ctx context.Context, c *models.DoppelgangerCheck) error {
+	query := `
+		UPDATE doppelganger_checks
+		SET checked_epochs = $1, last_checked_epoch = $2, status = $3, detected_epoch = $4, updated_at = $5,
+			finished_at = CASE WHEN $3 = 'running' THEN NULL ELSE $5 END
+		WHERE id = $6
+		RETURNING updated_at, finished_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		c.CheckedEpochs,
+		c.LastCheckedEpoch,
+		c.Status,
+		c.DetectedEpoch,
+		time.Now(),
+		c.ID,
+	).Scan(&c.UpdatedAt, &c.FinishedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("failed to update doppelganger check: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func doppelgangerCheckRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "pubkey", "validator_index", "epochs", "start_epoch", "checked_epochs", "last_checked_epoch",
+		"status", "detected_epoch", "created_at", "updated_at", "finished_at",
+	})
+}
+
+func TestDoppelgangerCheckRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewDoppelgangerCheckRepository(db)
+	ctx := context.Background()
+
+	c := &models.DoppelgangerCheck{Pubkey: "0xaa", ValidatorIndex: 7, Epochs: 3, StartEpoch: 100}
+
+	mock.ExpectQuery("INSERT INTO doppelganger_checks (.+) ON CONFLICT \\(pubkey\\) WHERE status = 'running' DO NOTHING").
+		WithArgs("0xaa", int64(7), int64(3), int64(100), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}).AddRow(1, "running", time.Now(), time.Now()))
+	require.NoError(t, repo.Create(ctx, c))
+	assert.Equal(t, models.DoppelgangerStatusRunning, c.Status)
+
+	mock.ExpectQuery("INSERT INTO doppelganger_checks").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}))
+	assert.Equal(t, models.ErrCheckInProgress, repo.Create(ctx, c))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDoppelgangerCheckRepository_Read(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewDoppelgangerCheckRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM doppelganger_checks WHERE pubkey = \\$1 ORDER BY id DESC LIMIT 1").
+		WithArgs("0xaa").
+		WillReturnRows(doppelgangerCheckRows().AddRow(2, "0xaa", 7, 3, 100, 3, int64(103), "passed", nil, now, now, now))
+	c, err := repo.Latest(ctx, "0xaa")
+	require.NoError(t, err)
+	assert.Equal(t, models.DoppelgangerStatusPassed, c.Status)
+	assert.Equal(t, int64(103), *c.LastCheckedEpoch)
+
+	mock.ExpectQuery("SELECT (.+) FROM doppelganger_checks WHERE pubkey = \\$1").
+		WithArgs("0xbb").
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.Latest(ctx, "0xbb")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM doppelganger_checks WHERE \\$1 = '' OR pubkey = \\$1").
+		WithArgs("").
+		WillReturnRows(doppelgangerCheckRows())
+	checks, err := repo.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+
+	mock.ExpectQuery("SELECT (.+) FROM doppelganger_checks WHERE status = 'running'").
+		WillReturnRows(doppelgangerCheckRows().AddRow(3, "0xcc", 8, 2, 100, 0, nil, "running", nil, now, now, nil))
+	checks, err = repo.ListRunning(ctx)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Nil(t, checks[0].LastCheckedEpoch)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDoppelgangerCheckRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewDoppelgangerCheckRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	detected := int64(101)
+
+	c := &models.DoppelgangerCheck{ID: 1, Status: models.DoppelgangerStatusFailed, DetectedEpoch: &detected}
+
+	mock.ExpectQuery("UPDATE doppelganger_checks SET (.+) finished_at = CASE WHEN \\$3 = 'running' THEN NULL ELSE \\$5 END").
+		WithArgs(int64(0), nil, "failed", &detected, sqlmock.AnyArg(), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "finished_at"}).AddRow(now, now))
+	require.NoError(t, repo.Update(ctx, c))
+	require.NotNil(t, c.FinishedAt)
+
+	mock.ExpectQuery("UPDATE doppelganger_checks").
+		WillReturnError(sql.ErrNoRows)
+	assert.Equal(t, sql.ErrNoRows, repo.Update(ctx, c))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
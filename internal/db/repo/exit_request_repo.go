@@ -0,0 +1,262 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// exitRequestColumns lists the exit_requests columns in the order scanExitRequest reads them
+const exitRequestColumns = `id, requested_by, reason, status, reviewed_by, reviewed_at, created_at, updated_at`
+
+// exitItemColumns lists the exit_request_items columns in the order scanExitItem reads them
+const exitItemColumns = `request_id, pubkey, status, error, submitted_at, exited_at`
+
+func scanExitRequest(row rowScanner, req *models.ExitRequest) error {
+	return row.Scan(
+		&req.ID,
+		&req.RequestedBy,
+		&req.Reason,
+		&req.Status,
+		&req.ReviewedBy,
+		&req.ReviewedAt,
+		&req.CreatedAt,
+		&req.UpdatedAt,
+	)
+}
+
+func scanExitItem(row rowScanner, item *models.ExitRequestItem) error {
+	return row.Scan(&item.RequestID, &item.Pubkey, &item.Status, &item.Error, &item.SubmittedAt, &item.ExitedAt)
+}
+
+// ExitRequestRepository implements the ExitRequestRepo interface using SQL
+type ExitRequestRepository struct {
+	db *sql.DB
+}
+
+// NewExitRequestRepository creates a new exit request repository
+func NewExitRequestRepository(db *sql.DB) *ExitRequestRepository {
+	return &ExitRequestRepository{db: db}
+}
+
+// Create stores a new pending request together with its items
+func (r *ExitRequestRepository) Create(ctx context.Context, req *models.ExitRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO exit_requests (requested_by, reason, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, created_at, updated_at`
+
+	req.Status = models.ExitRequestStatusPending
+	err = tx.QueryRowContext(ctx, query, req.RequestedBy, req.Reason, req.Status, time.Now()).
+		Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create exit request: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO exit_request_items (request_id, pubkey, status)
+		VALUES ($1, $2, $3)`
+
+	for i := range req.Items {
+		item := &req.Items[i]
+		item.RequestID = req.ID
+		item.Status = models.ExitItemStatusPending
+		if _, err := tx.ExecContext(ctx, itemQuery, item.RequestID, item.Pubkey, item.Status); err != nil {
+			return fmt.Errorf("failed to create exit request item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit exit request: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a request with its items
+func (r *ExitRequestRepository) Get(ctx context.Context, id int64) (*models.ExitRequest, error) {
+	query := `
+		SELECT ` + exitRequestColumns + `
+		FROM exit_requests
+		WHERE id = $1`
+
+	req := &models.ExitRequest{}
+	err := scanExitRequest(r.db.QueryRowContext(ctx, query, id), req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get exit request: %w", err)
+	}
+
+	itemQuery := `
+		SELECT ` + exitItemColumns + `
+		FROM exit_request_items
+		WHERE request_id = $1
+		ORDER BY pubkey`
+
+	rows, err := r.db.QueryContext(ctx, itemQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exit request items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ExitRequestItem
+		if err := scanExitItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan exit request item: %w", err)
+		}
+		req.Items = append(req.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exit request items: %w", err)
+	}
+
+	return req, nil
+}
+
+// List returns the requests with the given status, or all requests if status is empty.
+// Items are not loaded; use Get for the details of one request.
+func (r *ExitRequestRepository) List(ctx context.Context, status string) ([]models.ExitRequest, error) {
+	query := `
+		SELECT ` + exitRequestColumns + `
+		FROM exit_requests
+		WHERE $1 = '' OR status = $1
+		ORDER BY id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exit requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []models.ExitRequest
+	for rows.Next() {
+		var req models.ExitRequest
+		if err := scanExitRequest(rows, &req); err != nil {
+			return nil, fmt.Errorf("failed to scan exit request: %w", err)
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exit requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// Review moves a pending request to status on behalf of reviewer. It returns
+// sql.ErrNoRows if the request is not pending or reviewer is its requester.
+func (r *ExitRequestRepository) Review(ctx context.Context, id int64, status, reviewer string) error {
+	query := `
+		UPDATE exit_requests
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, updated_at = $3
+		WHERE id = $4 AND status = 'pending' AND requested_by <> $2`
+
+	result, err := r.db.ExecContext(ctx, query, status, reviewer, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to review exit request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateStatus sets the status of a request
+func (r *ExitRequestRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	query := `
+		UPDATE exit_requests
+		SET status = $1, updated_at = $2
+		WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update exit request status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateItem records the progress of one validator of a request
+func (r *ExitRequestRepository) UpdateItem(ctx context.Context, id int64, pubkey, status, errMsg string) error {
+	query := `
+		UPDATE exit_request_items
+		SET status = $1, error = $2,
+			submitted_at = CASE WHEN $1 = 'submitted' THEN $3 ELSE submitted_at END,
+			exited_at = CASE WHEN $1 = 'exited' THEN $3 ELSE exited_at END
+		WHERE request_id = $4 AND pubkey = $5`
+
+	result, err := r.db.ExecContext(ctx, query, status, errMsg, time.Now(), id, pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to update exit request item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListItems returns the items with the given status across all requests
+func (r *ExitRequestRepository) ListItems(ctx context.Context, status string) ([]models.ExitRequestItem, error) {
+	query := `
+		SELECT ` + exitItemColumns + `
+		FROM exit_request_items
+		WHERE status = $1
+		ORDER BY request_id, pubkey`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exit request items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.ExitRequestItem
+	for rows.Next() {
+		var item models.ExitRequestItem
+		if err := scanExitItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan exit request item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exit request items: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestExitRequestRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewExitRequestRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO exit_requests").
+		WithArgs("alice", "decommission", "pending", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO exit_request_items").
+		WithArgs(int64(7), "0xaa", "pending").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO exit_request_items").
+		WithArgs(int64(7), "0xbb", "pending").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	req := &models.ExitRequest{
+		RequestedBy: "alice",
+		Reason:      "decommission",
+		Items:       []models.ExitRequestItem{{Pubkey: "0xaa"}, {Pubkey: "0xbb"}},
+	}
+	require.NoError(t, repo.Create(context.Background(), req))
+	assert.Equal(t, int64(7), req.ID)
+	assert.Equal(t, models.ExitRequestStatusPending, req.Status)
+	assert.Equal(t, int64(7), req.Items[1].RequestID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExitRequestRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewExitRequestRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM exit_requests WHERE id = \\$1").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "requested_by", "reason", "status", "reviewed_by", "reviewed_at", "created_at", "updated_at",
+		}).AddRow(7, "alice", "", "submitted", "bob", now, now, now))
+	mock.ExpectQuery("SELECT (.+) FROM exit_request_items WHERE request_id = \\$1").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"request_id", "pubkey", "status", "error", "submitted_at", "exited_at"}).
+			AddRow(7, "0xaa", "submitted", "", now, nil))
+
+	req, err := repo.Get(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", req.ReviewedBy)
+	require.Len(t, req.Items, 1)
+	assert.Equal(t, models.ExitItemStatusSubmitted, req.Items[0].Status)
+	assert.Nil(t, req.Items[0].ExitedAt)
+
+	mock.ExpectQuery("SELECT (.+) FROM exit_requests").
+		WithArgs(int64(8)).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.Get(context.Background(), 8)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExitRequestRepository_Review(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewExitRequestRepository(db)
+
+	mock.ExpectExec("UPDATE exit_requests (.+) WHERE id = \\$4 AND status = 'pending' AND requested_by <> \\$2").
+		WithArgs("submitted", "bob", sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Review(context.Background(), 7, models.ExitRequestStatusSubmitted, "bob"))
+
+	// Already reviewed, or reviewed by the requester
+	mock.ExpectExec("UPDATE exit_requests").
+		WithArgs("submitted", "alice", sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.Review(context.Background(), 7, models.ExitRequestStatusSubmitted, "alice"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
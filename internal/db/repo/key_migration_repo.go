@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// keyMigrationColumns lists the key_migrations columns in the order scanKeyMigration reads them
+const keyMigrationColumns = `id, pubkey, source_instance, target_instance, requested_by, wait_epochs, status, error,
+	deleted_epoch, imported_epoch, keystore, slashing_protection, created_at, updated_at, finished_at`
+
+func scanKeyMigration(row rowScanner, m *models.KeyMigration) error {
+	return row.Scan(
+		&m.ID,
+		&m.Pubkey,
+		&m.SourceInstance,
+		&m.TargetInstance,
+		&m.RequestedBy,
+		&m.WaitEpochs,
+		&m.Status,
+		&m.Error,
+		&m.DeletedEpoch,
+		&m.ImportedEpoch,
+		&m.Keystore,
+		&m.SlashingProtection,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.FinishedAt,
+	)
+}
+
+// KeyMigrationRepository implements the KeyMigrationRepo interface using SQL
+type KeyMigrationRepository struct {
+	db *sql.DB
+}
+
+// NewKeyMigrationRepository creates a new key migration repository
+func NewKeyMigrationRepository(db *sql.DB) *KeyMigrationRepository {
+	return &KeyMigrationRepository{db: db}
+}
+
+// Create stores a new pending migration. It returns models.ErrMigrationInProgress
+// if the key already has an unfinished migration.
+func (r *KeyMigrationRepository) Create(ctx context.Context, m *models.KeyMigration) error {
+	query := `
+		INSERT INTO key_migrations (pubkey, source_instance, target_instance, requested_by, wait_epochs, status, keystore, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', $6, $7, $7)
+		ON CONFLICT (pubkey) WHERE status NOT IN ('completed', 'failed') DO NOTHING
+		RETURNING id, status, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		m.Pubkey,
+		m.SourceInstance,
+		m.TargetInstance,
+		m.RequestedBy,
+		m.WaitEpochs,
+		m.Keystore,
+		time.Now(),
+	).Scan(&m.ID, &m.Status, &m.CreatedAt, &m.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrMigrationInProgress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create key migration: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a migration by ID
+func (r *KeyMigrationRepository) Get(ctx context.Context, id int64) (*models.KeyMigration, error) {
+	query := `
+		SELECT ` + keyMigrationColumns + `
+		FROM key_migrations
+		WHERE id = $1`
+
+	m := &models.KeyMigration{}
+	err := scanKeyMigration(r.db.QueryRowContext(ctx, query, id), m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get key migration: %w", err)
+	}
+
+	return m, nil
+}
+
+// List returns the migrations with the given status, or all migrations if status is empty
+func (r *KeyMigrationRepository) List(ctx context.Context, status string) ([]models.KeyMigration, error) {
+	query := `
+		SELECT ` + keyMigrationColumns + `
+		FROM key_migrations
+		WHERE $1 = '' OR status = $1
+		ORDER BY id DESC`
+
+	return r.list(ctx, query, status)
+}
+
+// ListUnfinished returns the migrations that have neither completed nor failed, oldest first
+func (r *KeyMigrationRepository) ListUnfinished(ctx context.Context) ([]models.KeyMigration, error) {
+	query := `
+		SELECT ` + keyMigrationColumns + `
+		FROM key_migrations
+		WHERE status NOT IN ('completed', 'failed')
+		ORDER BY id`
+
+	return r.list(ctx, query)
+}
+
+// Holding returns the latest migration that holds a key: one that has not
+// finished, or one that failed after the key left its source and kept the
+// keystore. It returns sql.ErrNoRows if no migration holds the key.
+func (r *KeyMigrationRepository) Holding(ctx context.Context, pubkey string) (*models.KeyMigration, error) {
+	query := `
+		SELECT ` + keyMigrationColumns + `
+		FROM key_migrations
+		WHERE pubkey = $1
+			AND (status NOT IN ('completed', 'failed') OR (status = 'failed' AND keystore IS NOT NULL))
+		ORDER BY id DESC
+		LIMIT 1`
+
+	m := &models.KeyMigration{}
+	err := scanKeyMigration(r.db.QueryRowContext(ctx, query, pubkey), m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get key migration: %w", err)
+	}
+
+	return m, nil
+}
+
+func (r *KeyMigrationRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.KeyMigration, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []models.KeyMigration
+	for rows.Next() {
+		var m models.KeyMigration
+		if err := scanKeyMigration(rows, &m); err != nil {
+			return nil, fmt.Errorf("failed to scan key migration: %w", err)
+		}
+		migrations = append(migrations, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating key migrations: %w", err)
+	}
+
+	return migrations, nil
+}
+
+// Update stores the progress of a migration. The finish time is set when it
+// reaches a final status.
+func (r *KeyMigrationRepository) Update(ctx context.Context, m *models.KeyMigration) error {
+	query := `
+		UPDATE key_migrations
+		SET status = $1, error = $2, deleted_epoch = $3, imported_epoch = $4, keystore = $5,
+			slashing_protection = $6, updated_at = $7,
+			finished_at = CASE WHEN $1 IN ('completed', 'failed') THEN $7 END
+		WHERE id = $8
+		RETURNING updated_at, finished_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		m.Status,
+		m.Error,
+		m.DeletedEpoch,
+		m.ImportedEpoch,
+		m.Keystore,
+		m.SlashingProtection,
+		time.Now(),
+		m.ID,
+	).Scan(&m.UpdatedAt, &m.FinishedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("failed to update key migration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func keyMigrationRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "pubkey", "source_instance", "target_instance", "requested_by", "wait_epochs", "status", "error",
+		"deleted_epoch", "imported_epoch", "keystore", "slashing_protection", "created_at", "updated_at", "finished_at",
+	})
+}
+
+func TestKeyMigrationRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeyMigrationRepository(db)
+	ctx := context.Background()
+
+	m := &models.KeyMigration{Pubkey: "0xaa", SourceInstance: "lh-1", TargetInstance: "teku-1", RequestedBy: "alice", WaitEpochs: 2, Keystore: []byte("sealed")}
+
+	mock.ExpectQuery("INSERT INTO key_migrations (.+) ON CONFLICT \\(pubkey\\) WHERE status NOT IN \\('completed', 'failed'\\) DO NOTHING").
+		WithArgs("0xaa", "lh-1", "teku-1", "alice", int64(2), []byte("sealed"), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}).AddRow(1, "pending", time.Now(), time.Now()))
+	require.NoError(t, repo.Create(ctx, m))
+	assert.Equal(t, int64(1), m.ID)
+	assert.Equal(t, models.KeyMigrationStatusPending, m.Status)
+
+	// The key is already being migrated
+	mock.ExpectQuery("INSERT INTO key_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}))
+	assert.Equal(t, models.ErrMigrationInProgress, repo.Create(ctx, m))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestKeyMigrationRepository_GetList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeyMigrationRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE id = \\$1").
+		WithArgs(int64(1)).
+		WillReturnRows(keyMigrationRows().AddRow(1, "0xaa", "lh-1", "teku-1", "alice", 2, "source_deleted", "",
+			int64(100), nil, []byte("sealed"), []byte("{}"), now, now, nil))
+	m, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyMigrationStatusSourceDeleted, m.Status)
+	assert.Equal(t, int64(100), *m.DeletedEpoch)
+	assert.Nil(t, m.ImportedEpoch)
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE id = \\$1").
+		WithArgs(int64(2)).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.Get(ctx, 2)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE \\$1 = '' OR status = \\$1").
+		WithArgs("failed").
+		WillReturnRows(keyMigrationRows())
+	migrations, err := repo.List(ctx, models.KeyMigrationStatusFailed)
+	require.NoError(t, err)
+	assert.Empty(t, migrations)
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE status NOT IN \\('completed', 'failed'\\) ORDER BY id").
+		WillReturnRows(keyMigrationRows().AddRow(1, "0xaa", "lh-1", "teku-1", "alice", 2, "pending", "",
+			nil, nil, []byte("sealed"), nil, now, now, nil))
+	migrations, err = repo.ListUnfinished(ctx)
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	assert.Equal(t, "0xaa", migrations[0].Pubkey)
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE pubkey = \\$1 AND \\(status NOT IN \\('completed', 'failed'\\) " +
+		"OR \\(status = 'failed' AND keystore IS NOT NULL\\)\\) ORDER BY id DESC LIMIT 1").
+		WithArgs("0xaa").
+		WillReturnRows(keyMigrationRows().AddRow(4, "0xaa", "lh-1", "teku-1", "alice", 2, "failed", "rejected",
+			int64(100), nil, []byte("sealed"), []byte("{}"), now, now, now))
+	m, err = repo.Holding(ctx, "0xaa")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), m.ID)
+
+	mock.ExpectQuery("SELECT (.+) FROM key_migrations WHERE pubkey = \\$1").
+		WithArgs("0xbb").
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.Holding(ctx, "0xbb")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestKeyMigrationRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeyMigrationRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	deleted := int64(100)
+
+	m := &models.KeyMigration{ID: 1, Status: models.KeyMigrationStatusSourceDeleted, DeletedEpoch: &deleted, SlashingProtection: []byte("{}")}
+
+	mock.ExpectQuery("UPDATE key_migrations SET (.+) finished_at = CASE WHEN \\$1 IN \\('completed', 'failed'\\)").
+		WithArgs("source_deleted", "", &deleted, nil, []byte(nil), []byte("{}"), sqlmock.AnyArg(), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at", "finished_at"}).AddRow(now, nil))
+	require.NoError(t, repo.Update(ctx, m))
+	assert.Nil(t, m.FinishedAt)
+
+	mock.ExpectQuery("UPDATE key_migrations").
+		WillReturnError(sql.ErrNoRows)
+	assert.Equal(t, sql.ErrNoRows, repo.Update(ctx, m))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// keystoreColumns lists the keystores columns in the order scanKeystore reads them
+const keystoreColumns = `id, pubkey, path, uuid, kdf, password_verified, data_key, keystore, password, created_at, updated_at`
+
+func scanKeystore(row rowScanner, k *models.StoredKeystore) error {
+	return row.Scan(
+		&k.ID,
+		&k.Pubkey,
+		&k.Path,
+		&k.UUID,
+		&k.KDF,
+		&k.PasswordVerified,
+		&k.DataKey,
+		&k.Keystore,
+		&k.Password,
+		&k.CreatedAt,
+		&k.UpdatedAt,
+	)
+}
+
+// KeystoreRepository implements the KeystoreRepo interface using SQL
+type KeystoreRepository struct {
+	db *sql.DB
+}
+
+// NewKeystoreRepository creates a new keystore repository
+func NewKeystoreRepository(db *sql.DB) *KeystoreRepository {
+	return &KeystoreRepository{db: db}
+}
+
+// Save stores a keystore, replacing the one stored for the same key
+func (r *KeystoreRepository) Save(ctx context.Context, k *models.StoredKeystore) error {
+	query := `
+		INSERT INTO keystores (pubkey, path, uuid, kdf, password_verified, data_key, keystore, password, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (pubkey) DO UPDATE
+		SET path = EXCLUDED.path, uuid = EXCLUDED.uuid, kdf = EXCLUDED.kdf,
+			password_verified = EXCLUDED.password_verified, data_key = EXCLUDED.data_key,
+			keystore = EXCLUDED.keystore, password = EXCLUDED.password, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		k.Pubkey,
+		k.Path,
+		k.UUID,
+		k.KDF,
+		k.PasswordVerified,
+		k.DataKey,
+		k.Keystore,
+		k.Password,
+		time.Now(),
+	).Scan(&k.ID, &k.CreatedAt, &k.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save keystore: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the keystore of a key
+func (r *KeystoreRepository) Get(ctx context.Context, pubkey string) (*models.StoredKeystore, error) {
+	query := `
+		SELECT ` + keystoreColumns + `
+		FROM keystores
+		WHERE pubkey = $1`
+
+	k := &models.StoredKeystore{}
+	err := scanKeystore(r.db.QueryRowContext(ctx, query, pubkey), k)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get keystore: %w", err)
+	}
+
+	return k, nil
+}
+
+// List returns all stored keystores
+func (r *KeystoreRepository) List(ctx context.Context) ([]models.StoredKeystore, error) {
+	query := `
+		SELECT ` + keystoreColumns + `
+		FROM keystores
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keystores: %w", err)
+	}
+	defer rows.Close()
+
+	var keystores []models.StoredKeystore
+	for rows.Next() {
+		var k models.StoredKeystore
+		if err := scanKeystore(rows, &k); err != nil {
+			return nil, fmt.Errorf("failed to scan keystore: %w", err)
+		}
+		keystores = append(keystores, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating keystores: %w", err)
+	}
+
+	return keystores, nil
+}
+
+// Delete removes the keystore of a key
+func (r *KeystoreRepository) Delete(ctx context.Context, pubkey string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM keystores WHERE pubkey = $1`, pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to delete keystore: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
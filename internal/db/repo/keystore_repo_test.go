@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestKeystoreRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeystoreRepository(db)
+
+	k := &models.StoredKeystore{
+		Pubkey:           "0xaa",
+		Path:             "m/12381/3600/0/0/0",
+		UUID:             "64625def-3331-4eea-ab6f-782f3ed16a83",
+		KDF:              "scrypt",
+		PasswordVerified: true,
+		DataKey:          []byte("sealed key"),
+		Keystore:         []byte("sealed keystore"),
+		Password:         []byte("sealed password"),
+	}
+
+	mock.ExpectQuery("INSERT INTO keystores (.+) ON CONFLICT \\(pubkey\\) DO UPDATE").
+		WithArgs("0xaa", "m/12381/3600/0/0/0", "64625def-3331-4eea-ab6f-782f3ed16a83", "scrypt", true,
+			[]byte("sealed key"), []byte("sealed keystore"), []byte("sealed password"), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, time.Now(), time.Now()))
+
+	assert.NoError(t, repo.Save(context.Background(), k))
+	assert.Equal(t, int64(1), k.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestKeystoreRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeystoreRepository(db)
+	ctx := context.Background()
+
+	columns := []string{"id", "pubkey", "path", "uuid", "kdf", "password_verified", "data_key", "keystore", "password",
+		"created_at", "updated_at"}
+	mock.ExpectQuery("SELECT (.+) FROM keystores WHERE pubkey = \\$1").
+		WithArgs("0xaa").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "0xaa", "", "uuid", "pbkdf2", false,
+			[]byte("sealed key"), []byte("sealed keystore"), []byte("sealed password"), time.Now(), time.Now()))
+
+	k, err := repo.Get(ctx, "0xaa")
+	assert.NoError(t, err)
+	assert.Equal(t, "pbkdf2", k.KDF)
+	assert.Equal(t, []byte("sealed keystore"), k.Keystore)
+
+	mock.ExpectQuery("SELECT (.+) FROM keystores WHERE pubkey = \\$1").
+		WithArgs("0xbb").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = repo.Get(ctx, "0xbb")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestKeystoreRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewKeystoreRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("DELETE FROM keystores WHERE pubkey = \\$1").
+		WithArgs("0xaa").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Delete(ctx, "0xaa"))
+
+	mock.ExpectExec("DELETE FROM keystores WHERE pubkey = \\$1").
+		WithArgs("0xbb").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.Delete(ctx, "0xbb"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// OutboxRepository implements the OutboxRepo interface using SQL
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertOutboxEvent writes an event in the transaction making the change it describes
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType, pubkey string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	query := `
+		INSERT INTO outbox_events (type, pubkey, payload, created_at)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := tx.ExecContext(ctx, query, eventType, pubkey, data, time.Now()); err != nil {
+		return fmt.Errorf("failed to write %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+const outboxColumns = `id, type, pubkey, payload, created_at, published_at`
+
+// ListUnpublished returns up to limit events that have not been published, oldest first
+func (r *OutboxRepository) ListUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT ` + outboxColumns + `
+		FROM outbox_events
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1`
+
+	return r.list(ctx, query, limit)
+}
+
+// MarkPublished records that an event was delivered to every subscriber
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = $1
+		WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListRange returns the events created in [from, to), oldest first
+func (r *OutboxRepository) ListRange(ctx context.Context, from, to time.Time) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT ` + outboxColumns + `
+		FROM outbox_events
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY id`
+
+	return r.list(ctx, query, from, to)
+}
+
+func (r *OutboxRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.Pubkey, &e.Payload, &e.CreatedAt, &e.PublishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	return events, nil
+}
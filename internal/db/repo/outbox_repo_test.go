@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	columns := []string{"id", "type", "pubkey", "payload", "created_at", "published_at"}
+
+	mock.ExpectQuery("SELECT (.+) FROM outbox_events WHERE published_at IS NULL ORDER BY id LIMIT \\$1").
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, models.EventValidatorCreated, "0x123", []byte(`{"pubkey":"0x123"}`), now, nil).
+			AddRow(2, models.EventValidatorStatusChanged, "0x123", []byte(`{"from":"unused","to":"pending"}`), now, nil))
+	events, err := repo.ListUnpublished(ctx, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventValidatorStatusChanged, events[1].Type)
+	assert.JSONEq(t, `{"from":"unused","to":"pending"}`, string(events[1].Payload))
+	assert.Nil(t, events[0].PublishedAt)
+
+	mock.ExpectExec("UPDATE outbox_events SET published_at").
+		WithArgs(now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.MarkPublished(ctx, 1, now))
+
+	mock.ExpectExec("UPDATE outbox_events SET published_at").
+		WithArgs(now, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, sql.ErrNoRows, repo.MarkPublished(ctx, 9, now))
+
+	from := now.Add(-time.Hour)
+	mock.ExpectQuery("SELECT (.+) FROM outbox_events WHERE created_at >= \\$1 AND created_at < \\$2 ORDER BY id").
+		WithArgs(from, now).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, models.EventValidatorCreated, "0x123", []byte(`{}`), from, now))
+	events, err = repo.ListRange(ctx, from, now)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.NotNil(t, events[0].PublishedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// SignerKeyRepository implements the SignerKeyRepo interface using SQL
+type SignerKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSignerKeyRepository creates a new signer key repository
+func NewSignerKeyRepository(db *sql.DB) *SignerKeyRepository {
+	return &SignerKeyRepository{db: db}
+}
+
+// ReplaceSignerKeys replaces all keys recorded for a remote signer
+func (r *SignerKeyRepository) ReplaceSignerKeys(ctx context.Context, signer string, keys []models.SignerKey) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM signer_keys WHERE signer = $1`, signer); err != nil {
+		return fmt.Errorf("failed to delete signer keys: %w", err)
+	}
+
+	query := `
+		INSERT INTO signer_keys (pubkey, signer, signer_url, last_seen_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pubkey, signer) DO NOTHING`
+
+	now := time.Now()
+	for _, k := range keys {
+		if _, err := tx.ExecContext(ctx, query, k.Pubkey, signer, k.SignerURL, now); err != nil {
+			return fmt.Errorf("failed to insert signer key: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit signer keys: %w", err)
+	}
+
+	return nil
+}
+
+// ListByPubkey returns the remote signers that hold a key
+func (r *SignerKeyRepository) ListByPubkey(ctx context.Context, pubkey string) ([]models.SignerKey, error) {
+	query := `
+		SELECT pubkey, signer, signer_url, last_seen_at
+		FROM signer_keys
+		WHERE pubkey = $1
+		ORDER BY signer`
+
+	rows, err := r.db.QueryContext(ctx, query, pubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signer keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.SignerKey
+	for rows.Next() {
+		var k models.SignerKey
+		if err := rows.Scan(&k.Pubkey, &k.Signer, &k.SignerURL, &k.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signer key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating signer keys: %w", err)
+	}
+
+	return keys, nil
+}
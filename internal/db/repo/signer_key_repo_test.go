@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestSignerKeyRepository_ReplaceSignerKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSignerKeyRepository(db)
+	ctx := context.Background()
+
+	keys := []models.SignerKey{
+		{Pubkey: "0xaa", SignerURL: "https://signer:9000"},
+		{Pubkey: "0xbb", SignerURL: "https://signer:9000"},
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func()
+		expectError bool
+	}{
+		{
+			name: "successful replace",
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM signer_keys WHERE signer = \\$1").
+					WithArgs("w3s-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO signer_keys").
+					WithArgs("0xaa", "w3s-1", "https://signer:9000", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO signer_keys").
+					WithArgs("0xbb", "w3s-1", "https://signer:9000", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "insert error rolls back",
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM signer_keys").
+					WithArgs("w3s-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO signer_keys").
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := repo.ReplaceSignerKeys(ctx, "w3s-1", keys)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestSignerKeyRepository_ListByPubkey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSignerKeyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"pubkey", "signer", "signer_url", "last_seen_at"}).
+		AddRow("0xaa", "w3s-1", "https://signer-1:9000", time.Now()).
+		AddRow("0xaa", "w3s-2", "https://signer-2:9000", time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM signer_keys WHERE pubkey = \\$1").
+		WithArgs("0xaa").
+		WillReturnRows(rows)
+
+	keys, err := repo.ListByPubkey(context.Background(), "0xaa")
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "w3s-2", keys[1].Signer)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
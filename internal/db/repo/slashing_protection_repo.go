@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// SlashingProtectionRepository implements the SlashingProtectionRepo interface using SQL
+type SlashingProtectionRepository struct {
+	db *sql.DB
+}
+
+// NewSlashingProtectionRepository creates a new slashing protection repository
+func NewSlashingProtectionRepository(db *sql.DB) *SlashingProtectionRepository {
+	return &SlashingProtectionRepository{db: db}
+}
+
+// Merge raises the stored watermarks of a key to those of p, never lowering them.
+// It returns models.ErrGenesisMismatch if the key is stored for another chain.
+func (r *SlashingProtectionRepository) Merge(ctx context.Context, p *models.SlashingProtection) error {
+	// GREATEST ignores NULLs, so a missing value never clears a stored one
+	query := `
+		INSERT INTO slashing_protection (pubkey, genesis_validators_root, block_slot, source_epoch, target_epoch, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pubkey) DO UPDATE
+		SET block_slot = GREATEST(slashing_protection.block_slot, EXCLUDED.block_slot),
+			source_epoch = GREATEST(slashing_protection.source_epoch, EXCLUDED.source_epoch),
+			target_epoch = GREATEST(slashing_protection.target_epoch, EXCLUDED.target_epoch),
+			updated_at = EXCLUDED.updated_at
+		WHERE slashing_protection.genesis_validators_root = EXCLUDED.genesis_validators_root
+		RETURNING block_slot, source_epoch, target_epoch, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		p.Pubkey,
+		p.GenesisValidatorsRoot,
+		p.BlockSlot,
+		p.SourceEpoch,
+		p.TargetEpoch,
+		time.Now(),
+	).Scan(&p.BlockSlot, &p.SourceEpoch, &p.TargetEpoch, &p.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrGenesisMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge slashing protection: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the watermarks of the given keys that have any
+func (r *SlashingProtectionRepository) List(ctx context.Context, pubkeys []string) ([]models.SlashingProtection, error) {
+	if len(pubkeys) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(pubkeys))
+	args := make([]interface{}, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = pubkey
+	}
+
+	query := `
+		SELECT pubkey, genesis_validators_root, block_slot, source_epoch, target_epoch, updated_at
+		FROM slashing_protection
+		WHERE pubkey IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY pubkey`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slashing protection: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.SlashingProtection
+	for rows.Next() {
+		var p models.SlashingProtection
+		err := rows.Scan(
+			&p.Pubkey,
+			&p.GenesisValidatorsRoot,
+			&p.BlockSlot,
+			&p.SourceEpoch,
+			&p.TargetEpoch,
+			&p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan slashing protection: %w", err)
+		}
+		result = append(result, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating slashing protection: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestSlashingProtectionRepository_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSlashingProtectionRepository(db)
+	ctx := context.Background()
+
+	slot := int64(100)
+	p := &models.SlashingProtection{Pubkey: "0xaa", GenesisValidatorsRoot: "0x4b", BlockSlot: &slot}
+
+	// The stored attestation epochs are kept and the higher stored slot wins
+	mock.ExpectQuery("INSERT INTO slashing_protection (.+) GREATEST(.+) WHERE slashing_protection.genesis_validators_root = EXCLUDED.genesis_validators_root").
+		WithArgs("0xaa", "0x4b", &slot, nil, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"block_slot", "source_epoch", "target_epoch", "updated_at"}).
+			AddRow(120, 5, 6, time.Now()))
+	require.NoError(t, repo.Merge(ctx, p))
+	assert.Equal(t, int64(120), *p.BlockSlot)
+	assert.Equal(t, int64(6), *p.TargetEpoch)
+
+	// Another chain's data is not merged
+	mock.ExpectQuery("INSERT INTO slashing_protection").
+		WillReturnRows(sqlmock.NewRows([]string{"block_slot", "source_epoch", "target_epoch", "updated_at"}))
+	assert.Equal(t, models.ErrGenesisMismatch, repo.Merge(ctx, p))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSlashingProtectionRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSlashingProtectionRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM slashing_protection WHERE pubkey IN \\(\\$1, \\$2\\)").
+		WithArgs("0xaa", "0xbb").
+		WillReturnRows(sqlmock.NewRows([]string{"pubkey", "genesis_validators_root", "block_slot", "source_epoch", "target_epoch", "updated_at"}).
+			AddRow("0xaa", "0x4b", nil, 5, 6, time.Now()))
+
+	result, err := repo.List(context.Background(), []string{"0xaa", "0xbb"})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Nil(t, result[0].BlockSlot)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// SyncRunRepository implements the SyncRunRepo interface using SQL
+type SyncRunRepository struct {
+	db *sql.DB
+}
+
+// NewSyncRunRepository creates a new sync run repository
+func NewSyncRunRepository(db *sql.DB) *SyncRunRepository {
+	return &SyncRunRepository{db: db}
+}
+
+// RecordSuccess stores a successful run of a sync at the given time
+func (r *SyncRunRepository) RecordSuccess(ctx context.Context, source string, at time.Time) error {
+	query := `
+		INSERT INTO sync_runs (source, last_success_at, updated_at)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (source) DO UPDATE
+		SET last_success_at = EXCLUDED.last_success_at, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query, source, at); err != nil {
+		return fmt.Errorf("failed to record sync success: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure stores a failed run of a sync at the given time and its error
+func (r *SyncRunRepository) RecordFailure(ctx context.Context, source string, at time.Time, errMsg string) error {
+	query := `
+		INSERT INTO sync_runs (source, last_failure_at, last_error, updated_at)
+		VALUES ($1, $2, $3, $2)
+		ON CONFLICT (source) DO UPDATE
+		SET last_failure_at = EXCLUDED.last_failure_at, last_error = EXCLUDED.last_error, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query, source, at, errMsg); err != nil {
+		return fmt.Errorf("failed to record sync failure: %w", err)
+	}
+	return nil
+}
+
+// List returns the recorded outcome of every sync that has run
+func (r *SyncRunRepository) List(ctx context.Context) ([]models.SyncRun, error) {
+	query := `
+		SELECT source, last_success_at, last_failure_at, last_error, updated_at
+		FROM sync_runs
+		ORDER BY source`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.SyncRun
+	for rows.Next() {
+		var run models.SyncRun
+		if err := rows.Scan(&run.Source, &run.LastSuccessAt, &run.LastFailureAt, &run.LastError, &run.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sync run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sync runs: %w", err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncRunRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSyncRunRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectExec("INSERT INTO sync_runs (.+) ON CONFLICT \\(source\\) DO UPDATE SET last_success_at").
+		WithArgs("beacon", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RecordSuccess(ctx, "beacon", now))
+
+	mock.ExpectExec("INSERT INTO sync_runs (.+) ON CONFLICT \\(source\\) DO UPDATE SET last_failure_at").
+		WithArgs("lido", now, "rpc unavailable").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RecordFailure(ctx, "lido", now, "rpc unavailable"))
+
+	mock.ExpectQuery("SELECT (.+) FROM sync_runs ORDER BY source").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "last_success_at", "last_failure_at", "last_error", "updated_at"}).
+			AddRow("beacon", now, nil, "", now).
+			AddRow("lido", nil, now, "rpc unavailable", now))
+	runs, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.NotNil(t, runs[0].LastSuccessAt)
+	assert.Nil(t, runs[1].LastSuccessAt)
+	assert.Equal(t, "rpc unavailable", runs[1].LastError)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -4,11 +4,57 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/zheli/validator-key-manager-backend/pkg/models"
 )
 
+// validatorColumns lists the validators columns in the order scanValidator reads them
+const validatorColumns = `id, pubkey, blockchain, blockchain_network, status, client, client_instance,
+		lido_uploaded, lido_module, lido_operator_id, lido_key_index, lido_key_state,
+		validator_index, effective_balance, activation_epoch, exit_epoch, withdrawable_epoch, slashed,
+		withdrawal_credentials, created_at, updated_at`
+
+// credentialPrefixes maps withdrawal credential types to their 0x prefix
+var credentialPrefixes = map[string]string{
+	models.WithdrawalCredentialsBLS:         "0x00",
+	models.WithdrawalCredentialsExecution:   "0x01",
+	models.WithdrawalCredentialsCompounding: "0x02",
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanValidator reads a row selected with validatorColumns
+func scanValidator(row rowScanner, v *models.Validator) error {
+	return row.Scan(
+		&v.ID,
+		&v.Pubkey,
+		&v.Blockchain,
+		&v.BlockchainNetwork,
+		&v.Status,
+		&v.Client,
+		&v.ClientInstance,
+		&v.LidoUploaded,
+		&v.LidoModule,
+		&v.LidoOperatorID,
+		&v.LidoKeyIndex,
+		&v.LidoKeyState,
+		&v.ValidatorIndex,
+		&v.EffectiveBalance,
+		&v.ActivationEpoch,
+		&v.ExitEpoch,
+		&v.WithdrawableEpoch,
+		&v.Slashed,
+		&v.WithdrawalCredentials,
+		&v.CreatedAt,
+		&v.UpdatedAt,
+	)
+}
+
 // ValidatorRepository implements the ValidatorRepo interface using SQL
 type ValidatorRepository struct {
 	db *sql.DB
@@ -21,18 +67,25 @@ func NewValidatorRepository(db *sql.DB) *ValidatorRepository {
 
 // Create adds a new validator to the repository
 func (r *ValidatorRepository) Create(ctx context.Context, v *models.Validator) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO validators (pubkey, blockchain, blockchain_network, status, client, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO validators (pubkey, blockchain, blockchain_network, status, client, client_instance, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at, updated_at`
 
 	now := time.Now()
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		v.Pubkey,
 		v.Blockchain,
 		v.BlockchainNetwork,
 		v.Status,
 		v.Client,
+		v.ClientInstance,
 		now,
 		now,
 	).Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
@@ -41,27 +94,26 @@ func (r *ValidatorRepository) Create(ctx context.Context, v *models.Validator) e
 		return err
 	}
 
+	if err := insertOutboxEvent(ctx, tx, models.EventValidatorCreated, v.Pubkey, v); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit validator: %w", err)
+	}
+
 	return nil
 }
 
 // GetByPubkey retrieves a validator by its public key
 func (r *ValidatorRepository) GetByPubkey(ctx context.Context, pubkey string) (*models.Validator, error) {
 	query := `
-		SELECT id, pubkey, blockchain, blockchain_network, status, client, created_at, updated_at
+		SELECT ` + validatorColumns + `
 		FROM validators
 		WHERE pubkey = $1`
 
 	v := &models.Validator{}
-	err := r.db.QueryRowContext(ctx, query, pubkey).Scan(
-		&v.ID,
-		&v.Pubkey,
-		&v.Blockchain,
-		&v.BlockchainNetwork,
-		&v.Status,
-		&v.Client,
-		&v.CreatedAt,
-		&v.UpdatedAt,
-	)
+	err := scanValidator(r.db.QueryRowContext(ctx, query, pubkey), v)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -73,10 +125,69 @@ func (r *ValidatorRepository) GetByPubkey(ctx context.Context, pubkey string) (*
 	return v, nil
 }
 
+// GetByIndex retrieves a validator by its beacon chain index on a network.
+// Indices are only unique per chain, so the oldest matching row is returned
+// should two blockchains share a network name.
+func (r *ValidatorRepository) GetByIndex(ctx context.Context, network string, index int64) (*models.Validator, error) {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE blockchain_network = $1 AND validator_index = $2
+		ORDER BY id
+		LIMIT 1`
+
+	v := &models.Validator{}
+	err := scanValidator(r.db.QueryRowContext(ctx, query, network, index), v)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get validator by index: %w", err)
+	}
+
+	return v, nil
+}
+
+// SearchByPubkeyPrefix returns up to limit validators whose public key starts with prefix
+func (r *ValidatorRepository) SearchByPubkeyPrefix(ctx context.Context, prefix string, limit int) ([]models.Validator, error) {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE pubkey LIKE $1
+		ORDER BY pubkey
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search validators: %w", err)
+	}
+	defer rows.Close()
+
+	var validators []models.Validator
+	for rows.Next() {
+		var v models.Validator
+		if err := scanValidator(rows, &v); err != nil {
+			return nil, fmt.Errorf("failed to scan validator: %w", err)
+		}
+		validators = append(validators, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating validators: %w", err)
+	}
+
+	return validators, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // List returns a list of validators based on the provided filters
 func (r *ValidatorRepository) List(ctx context.Context, filters map[string]interface{}) ([]models.Validator, error) {
 	query := `
-		SELECT id, pubkey, blockchain, blockchain_network, status, client, created_at, updated_at
+		SELECT ` + validatorColumns + `
 		FROM validators
 		WHERE 1=1`
 	args := []interface{}{}
@@ -107,6 +218,64 @@ func (r *ValidatorRepository) List(ctx context.Context, filters map[string]inter
 		argCount++
 	}
 
+	if instance, ok := filters["client_instance"].(string); ok && instance != "" {
+		query += fmt.Sprintf(" AND client_instance = $%d", argCount)
+		args = append(args, instance)
+		argCount++
+	}
+
+	if uploaded, ok := filters["lido_uploaded"].(bool); ok {
+		query += fmt.Sprintf(" AND lido_uploaded = $%d", argCount)
+		args = append(args, uploaded)
+		argCount++
+	}
+
+	if module, ok := filters["lido_module"].(string); ok && module != "" {
+		query += fmt.Sprintf(" AND lido_module = $%d", argCount)
+		args = append(args, module)
+		argCount++
+	}
+
+	if state, ok := filters["lido_key_state"].(string); ok && state != "" {
+		query += fmt.Sprintf(" AND lido_key_state = $%d", argCount)
+		args = append(args, state)
+		argCount++
+	}
+
+	if index, ok := filters["validator_index"].(int64); ok {
+		query += fmt.Sprintf(" AND validator_index = $%d", argCount)
+		args = append(args, index)
+		argCount++
+	}
+
+	if slashed, ok := filters["slashed"].(bool); ok {
+		query += fmt.Sprintf(" AND slashed = $%d", argCount)
+		args = append(args, slashed)
+		argCount++
+	}
+
+	if creds, ok := filters["withdrawal_credentials"].(string); ok && creds != "" {
+		query += fmt.Sprintf(" AND withdrawal_credentials = $%d", argCount)
+		args = append(args, creds)
+		argCount++
+	}
+
+	if credsType, ok := filters["withdrawal_credentials_type"].(string); ok && credsType != "" {
+		prefix, known := credentialPrefixes[credsType]
+		if !known {
+			return nil, fmt.Errorf("unknown withdrawal credentials type %q", credsType)
+		}
+		query += fmt.Sprintf(" AND withdrawal_credentials LIKE $%d", argCount)
+		args = append(args, prefix+"%")
+		argCount++
+	}
+
+	if balance, ok := filters["min_effective_balance"].(int64); ok {
+		query += fmt.Sprintf(" AND effective_balance >= $%d", argCount)
+		args = append(args, balance)
+		argCount++
+	}
+
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list validators: %w", err)
@@ -116,17 +285,7 @@ func (r *ValidatorRepository) List(ctx context.Context, filters map[string]inter
 	var validators []models.Validator
 	for rows.Next() {
 		var v models.Validator
-		err := rows.Scan(
-			&v.ID,
-			&v.Pubkey,
-			&v.Blockchain,
-			&v.BlockchainNetwork,
-			&v.Status,
-			&v.Client,
-			&v.CreatedAt,
-			&v.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanValidator(rows, &v); err != nil {
 			return nil, fmt.Errorf("failed to scan validator: %w", err)
 		}
 		validators = append(validators, v)
@@ -139,18 +298,79 @@ func (r *ValidatorRepository) List(ctx context.Context, filters map[string]inter
 	return validators, nil
 }
 
-// UpdateStatus updates the status of a validator by its public key
-func (r *ValidatorRepository) UpdateStatus(ctx context.Context, pubkey, status string) error {
+// UpdateStatus updates the status of a validator by its public key. A change
+// to a different status is recorded as an outbox event in the same transaction.
+func (r *ValidatorRepository) UpdateStatus(ctx context.Context, pubkey string, status models.Status) error {
+	return r.updateStatus(ctx, pubkey, status, false)
+}
+
+// TransitionStatus moves a validator to status if the lifecycle allows it from
+// the status the validator holds once its row is locked, and returns a
+// *models.TransitionError otherwise
+func (r *ValidatorRepository) TransitionStatus(ctx context.Context, pubkey string, status models.Status) error {
+	return r.updateStatus(ctx, pubkey, status, true)
+}
+
+func (r *ValidatorRepository) updateStatus(ctx context.Context, pubkey string, status models.Status, checkTransition bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	change := models.StatusChange{Pubkey: pubkey, To: status}
+	err = tx.QueryRowContext(ctx, `
+		SELECT blockchain, blockchain_network, status
+		FROM validators
+		WHERE pubkey = $1
+		FOR UPDATE`, pubkey).Scan(&change.Blockchain, &change.Network, &change.From)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("failed to get validator status: %w", err)
+	}
+
+	if checkTransition {
+		if err := change.From.CheckTransition(status); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE validators
 		SET status = $1, updated_at = $2
 		WHERE pubkey = $3`
 
-	result, err := r.db.ExecContext(ctx, query, status, time.Now(), pubkey)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, status, time.Now(), pubkey); err != nil {
 		return fmt.Errorf("failed to update validator status: %w", err)
 	}
 
+	if change.From != status {
+		if err := insertOutboxEvent(ctx, tx, models.EventValidatorStatusChanged, pubkey, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit validator status: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateClient records the validator client type and instance that has the key loaded
+func (r *ValidatorRepository) UpdateClient(ctx context.Context, pubkey, client, instance string) error {
+	query := `
+		UPDATE validators
+		SET client = $1, client_instance = $2, updated_at = $3
+		WHERE pubkey = $4`
+
+	result, err := r.db.ExecContext(ctx, query, client, instance, time.Now(), pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to update validator client: %w", err)
+	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
@@ -162,3 +382,180 @@ func (r *ValidatorRepository) UpdateStatus(ctx context.Context, pubkey, status s
 
 	return nil
 }
+
+// UpdateLido records the Lido registry status of a validator by its public key
+func (r *ValidatorRepository) UpdateLido(ctx context.Context, pubkey string, status models.LidoStatus) error {
+	query := `
+		UPDATE validators
+		SET lido_uploaded = $1, lido_module = $2, lido_operator_id = $3, lido_key_index = $4, lido_key_state = $5,
+			updated_at = $6
+		WHERE pubkey = $7`
+
+	result, err := r.db.ExecContext(ctx, query,
+		status.Uploaded,
+		status.Module,
+		status.OperatorID,
+		status.KeyIndex,
+		status.KeyState,
+		time.Now(),
+		pubkey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update validator lido status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateBeacon records the beacon chain metadata of a validator by its public key.
+// A change of previously known withdrawal credentials is added to their history.
+func (r *ValidatorRepository) UpdateBeacon(ctx context.Context, pubkey string, meta models.BeaconMetadata) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	oldCreds, err := lockWithdrawalCredentials(ctx, tx, pubkey)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE validators
+		SET validator_index = $1, effective_balance = $2, activation_epoch = $3, exit_epoch = $4,
+			withdrawable_epoch = $5, slashed = $6, withdrawal_credentials = $7, updated_at = $8
+		WHERE pubkey = $9`
+
+	_, err = tx.ExecContext(ctx, query,
+		meta.Index,
+		meta.EffectiveBalance,
+		meta.ActivationEpoch,
+		meta.ExitEpoch,
+		meta.WithdrawableEpoch,
+		meta.Slashed,
+		meta.WithdrawalCredentials,
+		time.Now(),
+		pubkey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update validator beacon metadata: %w", err)
+	}
+
+	if err := recordCredentialChange(ctx, tx, pubkey, oldCreds, meta.WithdrawalCredentials, models.CredentialsSourceBeacon); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit beacon metadata: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWithdrawalCredentials records the withdrawal credentials of a validator,
+// adding a change of previously known credentials to their history
+func (r *ValidatorRepository) UpdateWithdrawalCredentials(ctx context.Context, pubkey, creds, source string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	oldCreds, err := lockWithdrawalCredentials(ctx, tx, pubkey)
+	if err != nil {
+		return err
+	}
+	if oldCreds == creds {
+		return nil
+	}
+
+	query := `
+		UPDATE validators
+		SET withdrawal_credentials = $1, updated_at = $2
+		WHERE pubkey = $3`
+
+	if _, err := tx.ExecContext(ctx, query, creds, time.Now(), pubkey); err != nil {
+		return fmt.Errorf("failed to update withdrawal credentials: %w", err)
+	}
+
+	if err := recordCredentialChange(ctx, tx, pubkey, oldCreds, creds, source); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit withdrawal credentials: %w", err)
+	}
+
+	return nil
+}
+
+// ListCredentialChanges returns the withdrawal credential history of a validator, oldest first
+func (r *ValidatorRepository) ListCredentialChanges(ctx context.Context, pubkey string) ([]models.WithdrawalCredentialChange, error) {
+	query := `
+		SELECT id, pubkey, old_credentials, new_credentials, source, changed_at
+		FROM withdrawal_credential_changes
+		WHERE pubkey = $1
+		ORDER BY changed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, pubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawal credential changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.WithdrawalCredentialChange
+	for rows.Next() {
+		var c models.WithdrawalCredentialChange
+		if err := rows.Scan(&c.ID, &c.Pubkey, &c.OldValue, &c.NewValue, &c.Source, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan withdrawal credential change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating withdrawal credential changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// lockWithdrawalCredentials reads a validator's withdrawal credentials and locks its row until tx ends
+func lockWithdrawalCredentials(ctx context.Context, tx *sql.Tx, pubkey string) (string, error) {
+	var creds string
+	err := tx.QueryRowContext(ctx,
+		`SELECT withdrawal_credentials FROM validators WHERE pubkey = $1 FOR UPDATE`, pubkey).Scan(&creds)
+	if err == sql.ErrNoRows {
+		return "", sql.ErrNoRows
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read withdrawal credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// recordCredentialChange adds a history entry when known credentials are replaced.
+// Credentials seen for the first time are not a change.
+func recordCredentialChange(ctx context.Context, tx *sql.Tx, pubkey, oldCreds, newCreds, source string) error {
+	if oldCreds == "" || newCreds == "" || oldCreds == newCreds {
+		return nil
+	}
+
+	query := `
+		INSERT INTO withdrawal_credential_changes (pubkey, old_credentials, new_credentials, source, changed_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := tx.ExecContext(ctx, query, pubkey, oldCreds, newCreds, source, time.Now()); err != nil {
+		return fmt.Errorf("failed to record withdrawal credential change: %w", err)
+	}
+	return nil
+}
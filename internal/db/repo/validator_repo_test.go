@@ -3,14 +3,36 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/zheli/validator-key-manager-backend/pkg/models"
 )
 
+// newValidatorRows returns an empty result set with the columns scanValidator reads
+func newValidatorRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "pubkey", "blockchain", "blockchain_network", "status", "client", "client_instance",
+		"lido_uploaded", "lido_module", "lido_operator_id", "lido_key_index", "lido_key_state",
+		"validator_index", "effective_balance", "activation_epoch", "exit_epoch", "withdrawable_epoch", "slashed",
+		"withdrawal_credentials", "created_at", "updated_at",
+	})
+}
+
+// validatorRow returns the column values of an ethereum mainnet validator
+func validatorRow(id int64, pubkey, status, client, instance string) []driver.Value {
+	return []driver.Value{
+		id, pubkey, "ethereum", "mainnet", status, client, instance,
+		false, "", nil, nil, "",
+		nil, 0, nil, nil, nil, false, "", time.Now(), time.Now(),
+	}
+}
+
 func TestValidatorRepository_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -37,10 +59,15 @@ func TestValidatorRepository_Create(t *testing.T) {
 				Client:            "lighthouse",
 			},
 			mockSetup: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO validators").
-					WithArgs("0x123", "ethereum", "mainnet", "active", "lighthouse", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs("0x123", "ethereum", "mainnet", "active", "lighthouse", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
 						AddRow(1, time.Now(), time.Now()))
+				mock.ExpectExec("INSERT INTO outbox_events").
+					WithArgs(models.EventValidatorCreated, "0x123", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 		},
@@ -54,9 +81,11 @@ func TestValidatorRepository_Create(t *testing.T) {
 				Client:            "lighthouse",
 			},
 			mockSetup: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO validators").
-					WithArgs("0x123", "ethereum", "mainnet", "active", "lighthouse", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs("0x123", "ethereum", "mainnet", "active", "lighthouse", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
 			expectedError: sql.ErrNoRows,
 		},
@@ -104,8 +133,8 @@ func TestValidatorRepository_GetByPubkey(t *testing.T) {
 			name:   "successful retrieval",
 			pubkey: "0x123",
 			mockSetup: func() {
-				rows := sqlmock.NewRows([]string{"id", "pubkey", "blockchain", "blockchain_network", "status", "client", "created_at", "updated_at"}).
-					AddRow(1, "0x123", "ethereum", "mainnet", "active", "lighthouse", time.Now(), time.Now())
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...)
 				mock.ExpectQuery("SELECT (.+) FROM validators WHERE pubkey = \\$1").
 					WithArgs("0x123").
 					WillReturnRows(rows)
@@ -166,9 +195,9 @@ func TestValidatorRepository_List(t *testing.T) {
 		{
 			name: "list all",
 			mockSetup: func() {
-				rows := sqlmock.NewRows([]string{"id", "pubkey", "blockchain", "blockchain_network", "status", "client", "created_at", "updated_at"}).
-					AddRow(1, "0x123", "ethereum", "mainnet", "active", "lighthouse", time.Now(), time.Now()).
-					AddRow(2, "0x456", "ethereum", "mainnet", "active", "teku", time.Now(), time.Now())
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...).
+					AddRow(validatorRow(2, "0x456", "active", "teku", "teku-1")...)
 				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1").
 					WillReturnRows(rows)
 			},
@@ -181,8 +210,8 @@ func TestValidatorRepository_List(t *testing.T) {
 				"blockchain": "ethereum",
 			},
 			mockSetup: func() {
-				rows := sqlmock.NewRows([]string{"id", "pubkey", "blockchain", "blockchain_network", "status", "client", "created_at", "updated_at"}).
-					AddRow(1, "0x123", "ethereum", "mainnet", "active", "lighthouse", time.Now(), time.Now())
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...)
 				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1 AND blockchain = \\$1").
 					WithArgs("ethereum").
 					WillReturnRows(rows)
@@ -190,6 +219,66 @@ func TestValidatorRepository_List(t *testing.T) {
 			expectedCount: 1,
 			expectedError: nil,
 		},
+		{
+			name: "filter by lido upload",
+			filters: map[string]interface{}{
+				"blockchain_network": "mainnet",
+				"lido_uploaded":      false,
+			},
+			mockSetup: func() {
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...)
+				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1 AND blockchain_network = \\$1 AND lido_uploaded = \\$2").
+					WithArgs("mainnet", false).
+					WillReturnRows(rows)
+			},
+			expectedCount: 1,
+			expectedError: nil,
+		},
+		{
+			name: "filter by lido module",
+			filters: map[string]interface{}{
+				"lido_module": "csm",
+			},
+			mockSetup: func() {
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...)
+				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1 AND lido_module = \\$1").
+					WithArgs("csm").
+					WillReturnRows(rows)
+			},
+			expectedCount: 1,
+			expectedError: nil,
+		},
+		{
+			name: "filter by withdrawal credentials type",
+			filters: map[string]interface{}{
+				"withdrawal_credentials_type": models.WithdrawalCredentialsBLS,
+			},
+			mockSetup: func() {
+				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1 AND withdrawal_credentials LIKE \\$1").
+					WithArgs("0x00%").
+					WillReturnRows(newValidatorRows())
+			},
+			expectedCount: 0,
+			expectedError: nil,
+		},
+		{
+			name: "filter by beacon metadata",
+			filters: map[string]interface{}{
+				"validator_index": int64(42),
+				"slashed":         false,
+			},
+			mockSetup: func() {
+				rows := newValidatorRows().
+					AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...)
+				mock.ExpectQuery("SELECT (.+) FROM validators WHERE 1=1 AND validator_index = \\$1 AND slashed = \\$2").
+					WithArgs(int64(42), false).
+					WillReturnRows(rows)
+			},
+			expectedCount: 1,
+			expectedError: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -226,7 +315,7 @@ func TestValidatorRepository_UpdateStatus(t *testing.T) {
 	tests := []struct {
 		name          string
 		pubkey        string
-		status        string
+		status        models.Status
 		mockSetup     func()
 		expectedError error
 	}{
@@ -235,9 +324,37 @@ func TestValidatorRepository_UpdateStatus(t *testing.T) {
 			pubkey: "0x123",
 			status: "inactive",
 			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT blockchain, blockchain_network, status").
+					WithArgs("0x123").
+					WillReturnRows(sqlmock.NewRows([]string{"blockchain", "blockchain_network", "status"}).
+						AddRow("ethereum", "mainnet", "active"))
 				mock.ExpectExec("UPDATE validators").
 					WithArgs("inactive", sqlmock.AnyArg(), "0x123").
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO outbox_events").
+					WithArgs(models.EventValidatorStatusChanged, "0x123",
+						[]byte(`{"pubkey":"0x123","blockchain":"ethereum","network":"mainnet","from":"active","to":"inactive"}`),
+						sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "unchanged status writes no event",
+			pubkey: "0x123",
+			status: "active",
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT blockchain, blockchain_network, status").
+					WithArgs("0x123").
+					WillReturnRows(sqlmock.NewRows([]string{"blockchain", "blockchain_network", "status"}).
+						AddRow("ethereum", "mainnet", "active"))
+				mock.ExpectExec("UPDATE validators").
+					WithArgs("active", sqlmock.AnyArg(), "0x123").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 		},
@@ -246,9 +363,11 @@ func TestValidatorRepository_UpdateStatus(t *testing.T) {
 			pubkey: "0x123",
 			status: "inactive",
 			mockSetup: func() {
-				mock.ExpectExec("UPDATE validators").
-					WithArgs("inactive", sqlmock.AnyArg(), "0x123").
-					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT blockchain, blockchain_network, status").
+					WithArgs("0x123").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
 			expectedError: sql.ErrNoRows,
 		},
@@ -272,3 +391,318 @@ func TestValidatorRepository_UpdateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorRepository_TransitionStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+	columns := []string{"blockchain", "blockchain_network", "status"}
+
+	// The locked status allows the transition
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT blockchain, blockchain_network, status (.+) FOR UPDATE").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("ethereum", "mainnet", "active"))
+	mock.ExpectExec("UPDATE validators").
+		WithArgs("exiting", sqlmock.AnyArg(), "0x123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(models.EventValidatorStatusChanged, "0x123", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.TransitionStatus(ctx, "0x123", models.StatusExiting))
+
+	// A concurrent change moved the validator on before the row was locked
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT blockchain, blockchain_network, status (.+) FOR UPDATE").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("ethereum", "mainnet", "exited"))
+	mock.ExpectRollback()
+	err = repo.TransitionStatus(ctx, "0x123", models.StatusExiting)
+	var transitionErr *models.TransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, models.StatusExited, transitionErr.From)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_UpdateClient(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		pubkey        string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "successful update",
+			pubkey: "0x123",
+			mockSetup: func() {
+				mock.ExpectExec("UPDATE validators SET client = \\$1, client_instance = \\$2").
+					WithArgs("teku", "teku-1", sqlmock.AnyArg(), "0x123").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "not found",
+			pubkey: "0x123",
+			mockSetup: func() {
+				mock.ExpectExec("UPDATE validators SET client = \\$1, client_instance = \\$2").
+					WithArgs("teku", "teku-1", sqlmock.AnyArg(), "0x123").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := repo.UpdateClient(ctx, tt.pubkey, "teku", "teku-1")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidatorRepository_UpdateLido(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	operatorID, keyIndex := int64(12), int64(345)
+
+	mock.ExpectExec("UPDATE validators SET lido_uploaded = \\$1, lido_module = \\$2, lido_operator_id = \\$3").
+		WithArgs(true, "csm", &operatorID, &keyIndex, "vetted", sqlmock.AnyArg(), "0x123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = repo.UpdateLido(ctx, "0x123", models.LidoStatus{
+		Uploaded:   true,
+		Module:     models.LidoModuleCSM,
+		OperatorID: &operatorID,
+		KeyIndex:   &keyIndex,
+		KeyState:   models.LidoKeyStateVetted,
+	})
+	assert.NoError(t, err)
+
+	mock.ExpectExec("UPDATE validators SET lido_uploaded = \\$1").
+		WithArgs(false, "", nil, nil, "", sqlmock.AnyArg(), "0x456").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.UpdateLido(ctx, "0x456", models.LidoStatus{})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_UpdateBeacon(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	index, activation := int64(42), int64(100)
+	blsCreds := "0x00" + strings.Repeat("ab", 31)
+	creds := "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+
+	// 0x00 credentials rotated to 0x01 are recorded in the history
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT withdrawal_credentials FROM validators WHERE pubkey = \\$1 FOR UPDATE").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows([]string{"withdrawal_credentials"}).AddRow(blsCreds))
+	mock.ExpectExec("UPDATE validators SET validator_index = \\$1, effective_balance = \\$2").
+		WithArgs(&index, int64(32000000000), &activation, nil, nil, false, creds, sqlmock.AnyArg(), "0x123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO withdrawal_credential_changes").
+		WithArgs("0x123", blsCreds, creds, models.CredentialsSourceBeacon, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err = repo.UpdateBeacon(ctx, "0x123", models.BeaconMetadata{
+		Index:                 &index,
+		EffectiveBalance:      32000000000,
+		ActivationEpoch:       &activation,
+		WithdrawalCredentials: creds,
+	})
+	assert.NoError(t, err)
+
+	// Credentials seen for the first time are not a change
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT withdrawal_credentials FROM validators").
+		WithArgs("0x789").
+		WillReturnRows(sqlmock.NewRows([]string{"withdrawal_credentials"}).AddRow(""))
+	mock.ExpectExec("UPDATE validators SET validator_index = \\$1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err = repo.UpdateBeacon(ctx, "0x789", models.BeaconMetadata{WithdrawalCredentials: creds})
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT withdrawal_credentials FROM validators").
+		WithArgs("0x456").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+	err = repo.UpdateBeacon(ctx, "0x456", models.BeaconMetadata{})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_UpdateWithdrawalCredentials(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	blsCreds := "0x00" + strings.Repeat("ab", 31)
+	creds := "0x020000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT withdrawal_credentials FROM validators WHERE pubkey = \\$1 FOR UPDATE").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows([]string{"withdrawal_credentials"}).AddRow(blsCreds))
+	mock.ExpectExec("UPDATE validators SET withdrawal_credentials = \\$1").
+		WithArgs(creds, sqlmock.AnyArg(), "0x123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO withdrawal_credential_changes").
+		WithArgs("0x123", blsCreds, creds, models.CredentialsSourceDepositData, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.UpdateWithdrawalCredentials(ctx, "0x123", creds, models.CredentialsSourceDepositData))
+
+	// Unchanged credentials are left alone
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT withdrawal_credentials FROM validators").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows([]string{"withdrawal_credentials"}).AddRow(creds))
+	mock.ExpectRollback()
+	assert.NoError(t, repo.UpdateWithdrawalCredentials(ctx, "0x123", creds, models.CredentialsSourceDepositData))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_ListCredentialChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM withdrawal_credential_changes WHERE pubkey = \\$1").
+		WithArgs("0x123").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pubkey", "old_credentials", "new_credentials", "source", "changed_at"}).
+			AddRow(1, "0x123", "0x00aa", "0x01bb", "beacon", time.Now()))
+
+	changes, err := repo.ListCredentialChanges(context.Background(), "0x123")
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "0x01bb", changes[0].NewValue)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_GetByIndex(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT (.+) FROM validators WHERE blockchain_network = \\$1 AND validator_index = \\$2").
+		WithArgs("mainnet", int64(42)).
+		WillReturnRows(newValidatorRows().AddRow(validatorRow(1, "0x123", "active", "lighthouse", "")...))
+	v, err := repo.GetByIndex(ctx, "mainnet", 42)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x123", v.Pubkey)
+
+	mock.ExpectQuery("SELECT (.+) FROM validators WHERE blockchain_network = \\$1 AND validator_index = \\$2").
+		WithArgs("holesky", int64(42)).
+		WillReturnError(sql.ErrNoRows)
+	v, err = repo.GetByIndex(ctx, "holesky", 42)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Nil(t, v)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidatorRepository_SearchByPubkeyPrefix(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewValidatorRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT (.+) FROM validators WHERE pubkey LIKE \\$1 ORDER BY pubkey LIMIT \\$2").
+		WithArgs("0xab12%", 50).
+		WillReturnRows(newValidatorRows().
+			AddRow(validatorRow(1, "0xab1200", "active", "lighthouse", "")...).
+			AddRow(validatorRow(2, "0xab1299", "active", "teku", "teku-1")...))
+	validators, err := repo.SearchByPubkeyPrefix(ctx, "0xab12", 50)
+	assert.NoError(t, err)
+	assert.Len(t, validators, 2)
+
+	// Wildcards in the prefix are matched literally
+	mock.ExpectQuery("SELECT (.+) FROM validators WHERE pubkey LIKE \\$1").
+		WithArgs(`0x\_\%%`, 50).
+		WillReturnRows(newValidatorRows())
+	validators, err = repo.SearchByPubkeyPrefix(ctx, "0x_%", 50)
+	assert.NoError(t, err)
+	assert.Empty(t, validators)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// voluntaryExitColumns lists the voluntary_exits columns in the order scanVoluntaryExit reads them
+const voluntaryExitColumns = `id, pubkey, validator_index, epoch, message, created_at, updated_at`
+
+func scanVoluntaryExit(row rowScanner, e *models.VoluntaryExit) error {
+	return row.Scan(&e.ID, &e.Pubkey, &e.ValidatorIndex, &e.Epoch, &e.Message, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// VoluntaryExitRepository implements the VoluntaryExitRepo interface using SQL
+type VoluntaryExitRepository struct {
+	db *sql.DB
+}
+
+// NewVoluntaryExitRepository creates a new voluntary exit repository
+func NewVoluntaryExitRepository(db *sql.DB) *VoluntaryExitRepository {
+	return &VoluntaryExitRepository{db: db}
+}
+
+// Save stores a verified exit, replacing any earlier one for the same key
+func (r *VoluntaryExitRepository) Save(ctx context.Context, e *models.VoluntaryExit) error {
+	query := `
+		INSERT INTO voluntary_exits (pubkey, validator_index, epoch, message, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (pubkey) DO UPDATE
+		SET validator_index = EXCLUDED.validator_index, epoch = EXCLUDED.epoch, message = EXCLUDED.message,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, e.Pubkey, e.ValidatorIndex, e.Epoch, e.Message, time.Now()).
+		Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save voluntary exit: %w", err)
+	}
+
+	return nil
+}
+
+// GetByPubkey retrieves the pre-signed exit of a validator
+func (r *VoluntaryExitRepository) GetByPubkey(ctx context.Context, pubkey string) (*models.VoluntaryExit, error) {
+	query := `
+		SELECT ` + voluntaryExitColumns + `
+		FROM voluntary_exits
+		WHERE pubkey = $1`
+
+	e := &models.VoluntaryExit{}
+	err := scanVoluntaryExit(r.db.QueryRowContext(ctx, query, pubkey), e)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get voluntary exit: %w", err)
+	}
+
+	return e, nil
+}
+
+// List returns every stored exit
+func (r *VoluntaryExitRepository) List(ctx context.Context) ([]models.VoluntaryExit, error) {
+	query := `
+		SELECT ` + voluntaryExitColumns + `
+		FROM voluntary_exits
+		ORDER BY validator_index`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voluntary exits: %w", err)
+	}
+	defer rows.Close()
+
+	var exits []models.VoluntaryExit
+	for rows.Next() {
+		var e models.VoluntaryExit
+		if err := scanVoluntaryExit(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan voluntary exit: %w", err)
+		}
+		exits = append(exits, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating voluntary exits: %w", err)
+	}
+
+	return exits, nil
+}
+
+// ListMissing returns the active validators without a stored exit for their current index
+func (r *VoluntaryExitRepository) ListMissing(ctx context.Context) ([]models.Validator, error) {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE status = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM voluntary_exits e
+				WHERE e.pubkey = validators.pubkey AND e.validator_index = validators.validator_index
+			)
+		ORDER BY validator_index, pubkey`
+
+	rows, err := r.db.QueryContext(ctx, query, models.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validators missing an exit: %w", err)
+	}
+	defer rows.Close()
+
+	var validators []models.Validator
+	for rows.Next() {
+		var v models.Validator
+		if err := scanValidator(rows, &v); err != nil {
+			return nil, fmt.Errorf("failed to scan validator: %w", err)
+		}
+		validators = append(validators, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating validators: %w", err)
+	}
+
+	return validators, nil
+}
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestVoluntaryExitRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewVoluntaryExitRepository(db)
+
+	mock.ExpectQuery("INSERT INTO voluntary_exits (.+) ON CONFLICT \\(pubkey\\) DO UPDATE").
+		WithArgs("0xaa", int64(42), int64(194048), []byte("sealed"), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, time.Now(), time.Now()))
+
+	exit := &models.VoluntaryExit{Pubkey: "0xaa", ValidatorIndex: 42, Epoch: 194048, Message: []byte("sealed")}
+	assert.NoError(t, repo.Save(context.Background(), exit))
+	assert.Equal(t, int64(1), exit.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestVoluntaryExitRepository_ListMissing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewVoluntaryExitRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM validators WHERE status = \\$1 AND NOT EXISTS").
+		WithArgs(models.StatusActive).
+		WillReturnRows(newValidatorRows().AddRow(validatorRow(1, "0xaa", "active", "lighthouse", "")...))
+
+	validators, err := repo.ListMissing(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, validators, 1)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// webhookDeliveryListLimit caps the number of deliveries returned by List
+const webhookDeliveryListLimit = 500
+
+// webhookDeliveryColumns lists the webhook_deliveries columns in the order scanWebhookDelivery reads them
+const webhookDeliveryColumns = `id, webhook, event, url, payload, status, attempts, response_code, last_error,
+	next_attempt_at, redelivery_of, created_at, updated_at, delivered_at`
+
+func scanWebhookDelivery(row rowScanner, d *models.WebhookDelivery) error {
+	return row.Scan(
+		&d.ID,
+		&d.Webhook,
+		&d.Event,
+		&d.URL,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseCode,
+		&d.LastError,
+		&d.NextAttemptAt,
+		&d.RedeliveryOf,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&d.DeliveredAt,
+	)
+}
+
+// WebhookDeliveryRepository implements the WebhookDeliveryRepo interface using SQL
+type WebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *sql.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Create stores a new pending delivery, due at d.NextAttemptAt or immediately if it is not set
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook, event, url, payload, status, next_attempt_at, redelivery_of, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'pending', $5, $6, $7, $7)
+		RETURNING id, status, next_attempt_at, created_at, updated_at`
+
+	now := time.Now()
+	next := now
+	if d.NextAttemptAt != nil {
+		next = *d.NextAttemptAt
+	}
+	err := r.db.QueryRowContext(ctx, query, d.Webhook, d.Event, d.URL, []byte(d.Payload), next, d.RedeliveryOf, now).
+		Scan(&d.ID, &d.Status, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a delivery by its ID
+func (r *WebhookDeliveryRepository) Get(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE id = $1`
+
+	d := &models.WebhookDelivery{}
+	err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id), d)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return d, nil
+}
+
+// List returns the deliveries matching the provided filters, newest first
+func (r *WebhookDeliveryRepository) List(ctx context.Context, filters map[string]interface{}) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE 1=1`
+	args := []interface{}{}
+	argCount := 1
+
+	for _, column := range []string{"webhook", "event", "status"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query += fmt.Sprintf(" AND %s = $%d", column, argCount)
+			args = append(args, value)
+			argCount++
+		}
+	}
+
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT %d", webhookDeliveryListLimit)
+
+	return r.list(ctx, query, args...)
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due at now,
+// longest waiting first, by moving their next attempt to leaseUntil. Rows another
+// dispatcher is claiming are skipped, so every delivery is attempted by one caller;
+// a claim whose attempt is never stored runs out at leaseUntil.
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	return r.list(ctx, query, now, leaseUntil, limit)
+}
+
+func (r *WebhookDeliveryRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateAttempt stores the outcome of a delivery attempt
+func (r *WebhookDeliveryRepository) UpdateAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_code = $3, last_error = $4, next_attempt_at = $5,
+			delivered_at = $6, updated_at = $7
+		WHERE id = $8
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		d.NextAttemptAt,
+		d.DeliveredAt,
+		time.Now(),
+		d.ID,
+	).Scan(&d.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.ErrNoRows
+		}
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
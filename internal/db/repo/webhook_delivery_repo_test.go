@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func webhookDeliveryRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "webhook", "event", "url", "payload", "status", "attempts", "response_code", "last_error",
+		"next_attempt_at", "redelivery_of", "created_at", "updated_at", "delivered_at",
+	})
+}
+
+func TestWebhookDeliveryRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepository(db)
+	now := time.Now()
+
+	d := &models.WebhookDelivery{Webhook: "ops", Event: "validator.slashed", URL: "https://hooks.example", Payload: []byte(`{}`)}
+	mock.ExpectQuery("INSERT INTO webhook_deliveries").
+		WithArgs("ops", "validator.slashed", "https://hooks.example", []byte(`{}`), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "next_attempt_at", "created_at", "updated_at"}).
+			AddRow(1, "pending", now, now, now))
+	require.NoError(t, repo.Create(context.Background(), d))
+	assert.Equal(t, int64(1), d.ID)
+	assert.Equal(t, models.WebhookDeliveryPending, d.Status)
+	assert.NotNil(t, d.NextAttemptAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookDeliveryRepository_Read(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_deliveries WHERE id = \\$1").
+		WithArgs(int64(2)).
+		WillReturnRows(webhookDeliveryRows().AddRow(2, "ops", "sync.failed", "https://hooks.example", []byte(`{"event":"sync.failed"}`),
+			"failed", 8, 500, "webhook \"ops\": returned status 500", nil, nil, now, now, nil))
+	d, err := repo.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 500, *d.ResponseCode)
+	assert.JSONEq(t, `{"event":"sync.failed"}`, string(d.Payload))
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_deliveries WHERE id = \\$1").
+		WithArgs(int64(3)).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.Get(ctx, 3)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_deliveries WHERE 1=1 AND webhook = \\$1 AND status = \\$2 ORDER BY id DESC").
+		WithArgs("ops", "failed").
+		WillReturnRows(webhookDeliveryRows())
+	deliveries, err := repo.List(ctx, map[string]interface{}{"webhook": "ops", "status": "failed"})
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	lease := now.Add(time.Minute)
+	mock.ExpectQuery("UPDATE webhook_deliveries SET next_attempt_at = \\$2 WHERE id IN \\( SELECT id FROM webhook_deliveries "+
+		"WHERE status = 'pending' AND next_attempt_at <= \\$1 (.+) FOR UPDATE SKIP LOCKED \\) RETURNING").
+		WithArgs(now, lease, 100).
+		WillReturnRows(webhookDeliveryRows().AddRow(4, "ops", "sync.failed", "https://hooks.example", []byte(`{}`),
+			"pending", 1, nil, "", lease, nil, now, now, nil))
+	deliveries, err = repo.ClaimDue(ctx, now, lease, 100)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, lease, *deliveries[0].NextAttemptAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookDeliveryRepository_UpdateAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepository(db)
+	code := 204
+	d := &models.WebhookDelivery{ID: 5, Status: models.WebhookDeliveryDelivered, Attempts: 1, ResponseCode: &code}
+
+	mock.ExpectQuery("UPDATE webhook_deliveries SET (.+) WHERE id = \\$8").
+		WithArgs("delivered", 1, &code, "", nil, nil, sqlmock.AnyArg(), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	require.NoError(t, repo.UpdateAttempt(context.Background(), d))
+
+	mock.ExpectQuery("UPDATE webhook_deliveries").
+		WillReturnError(sql.ErrNoRows)
+	assert.Equal(t, sql.ErrNoRows, repo.UpdateAttempt(context.Background(), d))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// WithdrawalRequestRepository implements the WithdrawalRequestRepo interface using SQL
+type WithdrawalRequestRepository struct {
+	db *sql.DB
+}
+
+// NewWithdrawalRequestRepository creates a new withdrawal request repository
+func NewWithdrawalRequestRepository(db *sql.DB) *WithdrawalRequestRepository {
+	return &WithdrawalRequestRepository{db: db}
+}
+
+// Record stores a detected request and reports whether it was new.
+// A request already recorded for the same key, amount and epoch is left unchanged.
+func (r *WithdrawalRequestRepository) Record(ctx context.Context, req *models.WithdrawalRequest) (bool, error) {
+	query := `
+		INSERT INTO withdrawal_requests (pubkey, validator_index, amount, epoch, source_address, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pubkey, amount, epoch) DO NOTHING
+		RETURNING id, detected_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		req.Pubkey,
+		req.ValidatorIndex,
+		req.Amount,
+		req.Epoch,
+		req.SourceAddress,
+		time.Now(),
+	).Scan(&req.ID, &req.DetectedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record withdrawal request: %w", err)
+	}
+
+	return true, nil
+}
+
+// List returns the detected requests of a validator, or of all validators if pubkey is empty
+func (r *WithdrawalRequestRepository) List(ctx context.Context, pubkey string) ([]models.WithdrawalRequest, error) {
+	query := `
+		SELECT id, pubkey, validator_index, amount, epoch, source_address, detected_at
+		FROM withdrawal_requests
+		WHERE $1 = '' OR pubkey = $1
+		ORDER BY detected_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, pubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawal requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []models.WithdrawalRequest
+	for rows.Next() {
+		var req models.WithdrawalRequest
+		err := rows.Scan(
+			&req.ID,
+			&req.Pubkey,
+			&req.ValidatorIndex,
+			&req.Amount,
+			&req.Epoch,
+			&req.SourceAddress,
+			&req.DetectedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan withdrawal request: %w", err)
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating withdrawal requests: %w", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestWithdrawalRequestRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewWithdrawalRequestRepository(db)
+	ctx := context.Background()
+
+	req := &models.WithdrawalRequest{Pubkey: "0xaa", ValidatorIndex: 42, Epoch: 364000, SourceAddress: "0xbb"}
+
+	mock.ExpectQuery("INSERT INTO withdrawal_requests (.+) ON CONFLICT \\(pubkey, amount, epoch\\) DO NOTHING").
+		WithArgs("0xaa", int64(42), int64(0), int64(364000), "0xbb", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "detected_at"}).AddRow(1, time.Now()))
+	created, err := repo.Record(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(1), req.ID)
+
+	// The same request is only reported once
+	mock.ExpectQuery("INSERT INTO withdrawal_requests").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "detected_at"}))
+	created, err = repo.Record(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWithdrawalRequestRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewWithdrawalRequestRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM withdrawal_requests WHERE \\$1 = '' OR pubkey = \\$1").
+		WithArgs("0xaa").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pubkey", "validator_index", "amount", "epoch", "source_address", "detected_at"}).
+			AddRow(1, "0xaa", 42, 1000000000, 364000, "0xbb", time.Now()))
+
+	requests, err := repo.List(context.Background(), "0xaa")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.False(t, requests[0].IsExit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
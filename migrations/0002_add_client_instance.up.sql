@@ -0,0 +1,2 @@
+-- +migrate Up
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS client_instance TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- +migrate Down
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS client_keys;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS client_keys (
+    pubkey TEXT NOT NULL,
+    client TEXT NOT NULL,
+    client_instance TEXT NOT NULL,
+    remote BOOLEAN NOT NULL DEFAULT FALSE,
+    signer_url TEXT NOT NULL DEFAULT '',
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (pubkey, client_instance)
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id SERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    pubkey TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS alerts_open_type_pubkey_idx ON alerts (type, pubkey) WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    action TEXT NOT NULL,
+    source_ip TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,4 @@
+-- +migrate Down
+ALTER TABLE validators DROP COLUMN IF EXISTS lido_key_index;
+ALTER TABLE validators DROP COLUMN IF EXISTS lido_operator_id;
+ALTER TABLE validators DROP COLUMN IF EXISTS lido_uploaded;
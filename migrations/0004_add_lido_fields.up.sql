@@ -0,0 +1,4 @@
+-- +migrate Up
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS lido_uploaded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS lido_operator_id BIGINT;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS lido_key_index BIGINT;
@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE validators DROP COLUMN IF EXISTS lido_key_state;
@@ -0,0 +1,2 @@
+-- +migrate Up
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS lido_key_state TEXT NOT NULL DEFAULT '';
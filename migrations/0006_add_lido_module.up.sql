@@ -0,0 +1,3 @@
+-- +migrate Up
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS lido_module TEXT NOT NULL DEFAULT '';
+UPDATE validators SET lido_module = 'curated' WHERE lido_uploaded;
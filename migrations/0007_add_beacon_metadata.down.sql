@@ -0,0 +1,8 @@
+-- +migrate Down
+ALTER TABLE validators DROP COLUMN IF EXISTS withdrawal_credentials;
+ALTER TABLE validators DROP COLUMN IF EXISTS slashed;
+ALTER TABLE validators DROP COLUMN IF EXISTS withdrawable_epoch;
+ALTER TABLE validators DROP COLUMN IF EXISTS exit_epoch;
+ALTER TABLE validators DROP COLUMN IF EXISTS activation_epoch;
+ALTER TABLE validators DROP COLUMN IF EXISTS effective_balance;
+ALTER TABLE validators DROP COLUMN IF EXISTS validator_index;
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS validator_index BIGINT;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS effective_balance BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS activation_epoch BIGINT;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS exit_epoch BIGINT;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS withdrawable_epoch BIGINT;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS slashed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS withdrawal_credentials TEXT NOT NULL DEFAULT '';
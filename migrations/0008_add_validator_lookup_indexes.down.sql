@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP INDEX IF EXISTS validators_pubkey_pattern_idx;
+DROP INDEX IF EXISTS validators_network_index_idx;
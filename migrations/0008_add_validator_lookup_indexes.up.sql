@@ -0,0 +1,6 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS validators_network_index_idx
+    ON validators (blockchain_network, validator_index)
+    WHERE validator_index IS NOT NULL;
+-- text_pattern_ops lets LIKE 'prefix%' use the index regardless of collation
+CREATE INDEX IF NOT EXISTS validators_pubkey_pattern_idx ON validators (pubkey text_pattern_ops);
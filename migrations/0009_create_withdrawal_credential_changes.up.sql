@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS withdrawal_credential_changes (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT NOT NULL,
+    old_credentials TEXT NOT NULL,
+    new_credentials TEXT NOT NULL,
+    source TEXT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS withdrawal_credential_changes_pubkey_idx ON withdrawal_credential_changes (pubkey, changed_at);
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS bls_changes (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT UNIQUE NOT NULL,
+    validator_index BIGINT NOT NULL,
+    from_bls_pubkey TEXT NOT NULL,
+    to_execution_address TEXT NOT NULL,
+    message BYTEA NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS bls_changes_status_idx ON bls_changes (status);
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS voluntary_exits (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT UNIQUE NOT NULL,
+    validator_index BIGINT NOT NULL,
+    epoch BIGINT NOT NULL,
+    message BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
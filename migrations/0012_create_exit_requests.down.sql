@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP TABLE IF EXISTS exit_request_items;
+DROP TABLE IF EXISTS exit_requests;
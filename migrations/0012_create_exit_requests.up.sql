@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS exit_requests (
+    id SERIAL PRIMARY KEY,
+    requested_by TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (reviewed_by = '' OR reviewed_by <> requested_by)
+);
+
+CREATE TABLE IF NOT EXISTS exit_request_items (
+    request_id INTEGER NOT NULL REFERENCES exit_requests (id) ON DELETE CASCADE,
+    pubkey TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    exited_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (request_id, pubkey)
+);
+
+CREATE INDEX IF NOT EXISTS exit_request_items_status_idx ON exit_request_items (status);
@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS withdrawal_requests (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT NOT NULL,
+    validator_index BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    epoch BIGINT NOT NULL,
+    source_address TEXT NOT NULL DEFAULT '',
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (pubkey, amount, epoch)
+);
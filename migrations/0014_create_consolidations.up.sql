@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS consolidations (
+    id SERIAL PRIMARY KEY,
+    source_pubkey TEXT UNIQUE NOT NULL,
+    target_pubkey TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'planned',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CHECK (source_pubkey <> target_pubkey)
+);
+
+CREATE INDEX IF NOT EXISTS consolidations_target_pubkey_idx ON consolidations (target_pubkey);
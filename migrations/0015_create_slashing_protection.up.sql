@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS slashing_protection (
+    pubkey TEXT PRIMARY KEY,
+    genesis_validators_root TEXT NOT NULL,
+    block_slot BIGINT,
+    source_epoch BIGINT,
+    target_epoch BIGINT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
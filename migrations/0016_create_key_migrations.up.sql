@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS key_migrations (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT NOT NULL,
+    source_instance TEXT NOT NULL,
+    target_instance TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    wait_epochs INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    deleted_epoch BIGINT,
+    imported_epoch BIGINT,
+    keystore BYTEA,
+    slashing_protection BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    CHECK (source_instance <> target_instance)
+);
+
+-- A key can only be in one unfinished migration at a time
+CREATE UNIQUE INDEX IF NOT EXISTS key_migrations_active_pubkey_idx
+    ON key_migrations (pubkey) WHERE status NOT IN ('completed', 'failed');
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS doppelganger_checks (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT NOT NULL,
+    validator_index BIGINT NOT NULL,
+    epochs INTEGER NOT NULL,
+    start_epoch BIGINT NOT NULL,
+    checked_epochs INTEGER NOT NULL DEFAULT 0,
+    last_checked_epoch BIGINT,
+    status TEXT NOT NULL DEFAULT 'running',
+    detected_epoch BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS doppelganger_checks_pubkey_idx ON doppelganger_checks (pubkey, id);
+
+-- A key can only have one running check at a time
+CREATE UNIQUE INDEX IF NOT EXISTS doppelganger_checks_running_pubkey_idx
+    ON doppelganger_checks (pubkey) WHERE status = 'running';
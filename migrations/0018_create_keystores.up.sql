@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS keystores (
+    id SERIAL PRIMARY KEY,
+    pubkey TEXT UNIQUE NOT NULL REFERENCES validators (pubkey) ON DELETE CASCADE,
+    path TEXT NOT NULL DEFAULT '',
+    uuid TEXT NOT NULL,
+    kdf TEXT NOT NULL,
+    password_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    data_key BYTEA NOT NULL,
+    keystore BYTEA NOT NULL,
+    password BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
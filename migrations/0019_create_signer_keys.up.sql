@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS signer_keys (
+    pubkey TEXT NOT NULL,
+    signer TEXT NOT NULL,
+    signer_url TEXT NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (pubkey, signer)
+);
+
+CREATE INDEX IF NOT EXISTS signer_keys_signer_idx ON signer_keys (signer);
@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP TABLE IF EXISTS alert_silences;
+DROP TABLE IF EXISTS sync_runs;
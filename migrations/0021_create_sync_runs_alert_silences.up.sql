@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync_runs (
+    source TEXT PRIMARY KEY,
+    last_success_at TIMESTAMP WITH TIME ZONE,
+    last_failure_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id SERIAL PRIMARY KEY,
+    rule TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alert_silences_ends_at_idx ON alert_silences (ends_at);
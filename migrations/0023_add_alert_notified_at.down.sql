@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE alerts DROP COLUMN IF EXISTS notified_at;
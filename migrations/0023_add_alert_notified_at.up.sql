@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS notified_at TIMESTAMP WITH TIME ZONE;
+
+-- Alerts raised so far were notified when they were raised
+UPDATE alerts SET notified_at = created_at WHERE notified_at IS NULL;
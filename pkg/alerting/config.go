@@ -0,0 +1,228 @@
+// Package alerting defines alert rules and the channels their alerts are sent to
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// Rule types
+const (
+	// RuleStatus fires for every key in a given status, e.g. every slashed mainnet key
+	RuleStatus = "status"
+	// RuleInactive fires when more than Threshold active keys of a network were
+	// not seen live by the beacon node in any of the last Epochs epochs
+	RuleInactive = "inactive"
+	// RuleSyncStale fires when a background sync has not succeeded within MaxAge
+	RuleSyncStale = "sync_stale"
+)
+
+// Notifier types
+const (
+	// NotifierSlack posts to a Slack-compatible incoming webhook
+	NotifierSlack = "slack"
+	// NotifierSMTP sends email through an SMTP server
+	NotifierSMTP = "smtp"
+)
+
+// Duration is a time.Duration read from and written to JSON as a string such as "26h"
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Rule is a condition that raises an alert while it holds
+type Rule struct {
+	// Name uniquely identifies the rule; its alerts have type "rule:<name>"
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Severity of the raised alerts, warning unless set
+	Severity string `json:"severity,omitempty"`
+	// Blockchain and Network narrow status rules and select the beacon node of
+	// inactive rules. Blockchain defaults to ethereum for inactive rules.
+	Blockchain string `json:"blockchain,omitempty"`
+	Network    string `json:"network,omitempty"`
+	// Status is the validator status a status rule fires for
+	Status models.Status `json:"status,omitempty"`
+	// Threshold is the number of inactive keys an inactive rule tolerates
+	Threshold int `json:"threshold,omitempty"`
+	// Epochs is the number of most recent finished epochs an inactive key missed
+	Epochs int64 `json:"epochs,omitempty"`
+	// Source is the background sync a sync_stale rule watches
+	Source string `json:"source,omitempty"`
+	// MaxAge is the time a sync_stale rule allows since the last successful run
+	MaxAge Duration `json:"max_age,omitzero"`
+	// Notify names the notifiers the rule's alerts are sent to, or every notifier if empty
+	Notify []string `json:"notify,omitempty"`
+}
+
+// AlertType returns the type of the alerts raised by the rule
+func (r Rule) AlertType() string {
+	return models.AlertTypeRulePrefix + r.Name
+}
+
+func (r *Rule) validate() error {
+	switch r.Severity {
+	case "":
+		r.Severity = models.SeverityWarning
+	case models.SeverityWarning, models.SeverityCritical:
+	default:
+		return fmt.Errorf("severity must be %s or %s", models.SeverityWarning, models.SeverityCritical)
+	}
+
+	switch r.Type {
+	case RuleStatus:
+		if !r.Status.Valid() {
+			return fmt.Errorf("%w: %q", models.ErrInvalidStatus, r.Status)
+		}
+	case RuleInactive:
+		if r.Blockchain == "" {
+			r.Blockchain = "ethereum"
+		}
+		if r.Network == "" {
+			return fmt.Errorf("network is required")
+		}
+		if r.Threshold < 0 {
+			return fmt.Errorf("threshold must not be negative")
+		}
+		if r.Epochs < 1 {
+			return fmt.Errorf("epochs must be at least 1")
+		}
+	case RuleSyncStale:
+		if !slices.Contains(models.SyncSources, r.Source) {
+			return fmt.Errorf("source must be one of %s", strings.Join(models.SyncSources, ", "))
+		}
+		if r.MaxAge.Duration <= 0 {
+			return fmt.Errorf("max_age must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// NotifierConfig configures a channel alerts are sent to
+type NotifierConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// URL is the incoming webhook of a slack notifier
+	URL string `json:"url,omitempty"`
+	// SMTP server of an smtp notifier. Authentication is only used if
+	// Username is set; the password is read from PasswordFile if Password is empty.
+	Host         string   `json:"host,omitempty"`
+	Port         int      `json:"port,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	PasswordFile string   `json:"password_file,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
+
+func (n *NotifierConfig) validate() error {
+	switch n.Type {
+	case NotifierSlack:
+		if n.URL == "" {
+			return fmt.Errorf("url is required")
+		}
+	case NotifierSMTP:
+		if n.Host == "" {
+			return fmt.Errorf("host is required")
+		}
+		if n.Port == 0 {
+			n.Port = 587
+		}
+		if n.From == "" || len(n.To) == 0 {
+			return fmt.Errorf("from and to are required")
+		}
+		if n.Password == "" && n.PasswordFile != "" {
+			password, err := os.ReadFile(n.PasswordFile)
+			if err != nil {
+				return fmt.Errorf("failed to read password file: %w", err)
+			}
+			n.Password = strings.TrimSpace(string(password))
+		}
+	default:
+		return fmt.Errorf("unknown notifier type %q", n.Type)
+	}
+	return nil
+}
+
+// Config holds the alert rules and notifiers
+type Config struct {
+	Rules     []Rule           `json:"rules"`
+	Notifiers []NotifierConfig `json:"notifiers"`
+}
+
+// LoadConfig reads a JSON alerting configuration from path
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerting config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alerting config: %w", err)
+	}
+
+	notifiers := make(map[string]bool, len(cfg.Notifiers))
+	for i := range cfg.Notifiers {
+		n := &cfg.Notifiers[i]
+		if n.Name == "" {
+			return nil, fmt.Errorf("notifier name is required")
+		}
+		if notifiers[n.Name] {
+			return nil, fmt.Errorf("duplicate notifier name %q", n.Name)
+		}
+		notifiers[n.Name] = true
+		if err := n.validate(); err != nil {
+			return nil, fmt.Errorf("notifier %q: %w", n.Name, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(cfg.Rules))
+	for i := range cfg.Rules {
+		r := &cfg.Rules[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule name is required")
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", r.Name)
+		}
+		seen[r.Name] = true
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		for _, name := range r.Notify {
+			if !notifiers[name] {
+				return nil, fmt.Errorf("rule %q: unknown notifier %q", r.Name, name)
+			}
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,83 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectError bool
+	}{
+		{
+			name: "valid config",
+			content: `{"rules":[
+				{"name":"mainnet-slashed","type":"status","network":"mainnet","status":"slashed","severity":"critical","notify":["ops"]},
+				{"name":"inactive","type":"inactive","network":"mainnet","threshold":5,"epochs":2},
+				{"name":"beacon-stale","type":"sync_stale","source":"beacon","max_age":"26h"}],
+			"notifiers":[{"name":"ops","type":"slack","url":"https://hooks.example"}]}`,
+		},
+		{
+			name:        "unknown status",
+			content:     `{"rules":[{"name":"r","type":"status","status":"gone"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "inactive without epochs",
+			content:     `{"rules":[{"name":"r","type":"inactive","network":"mainnet","threshold":5}]}`,
+			expectError: true,
+		},
+		{
+			name:        "unknown sync source",
+			content:     `{"rules":[{"name":"r","type":"sync_stale","source":"execution","max_age":"1h"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid max age",
+			content:     `{"rules":[{"name":"r","type":"sync_stale","source":"lido","max_age":"a day"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "unknown notifier",
+			content:     `{"rules":[{"name":"r","type":"status","status":"slashed","notify":["pager"]}]}`,
+			expectError: true,
+		},
+		{
+			name:        "smtp without recipients",
+			content:     `{"notifiers":[{"name":"mail","type":"smtp","host":"smtp.example","from":"vkm@example.com"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "duplicate rule",
+			content:     `{"rules":[{"name":"r","type":"status","status":"slashed"},{"name":"r","type":"status","status":"exited"}]}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "alerting.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			cfg, err := LoadConfig(path)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.Rules, 3)
+			assert.Equal(t, "rule:mainnet-slashed", cfg.Rules[0].AlertType())
+			assert.Equal(t, models.SeverityWarning, cfg.Rules[1].Severity)
+			assert.Equal(t, "ethereum", cfg.Rules[1].Blockchain)
+			assert.Equal(t, 26*time.Hour, cfg.Rules[2].MaxAge.Duration)
+		})
+	}
+}
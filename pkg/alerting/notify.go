@@ -0,0 +1,169 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notification is an alert raised or resolved by a rule
+type Notification struct {
+	Rule     string
+	Severity string
+	Message  string
+	Resolved bool
+	Time     time.Time
+}
+
+// Title summarizes the notification in one line, e.g. "[CRITICAL] mainnet-slashed"
+func (n Notification) Title() string {
+	if n.Resolved {
+		return "[RESOLVED] " + n.Rule
+	}
+	return "[" + strings.ToUpper(n.Severity) + "] " + n.Rule
+}
+
+// Notifier sends notifications to a channel
+type Notifier interface {
+	// Name returns the configured notifier name
+	Name() string
+
+	// Notify sends a notification
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifier creates the notifier described by a validated configuration
+func NewNotifier(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case NotifierSlack:
+		return &SlackNotifier{name: cfg.Name, url: cfg.URL, httpClient: &http.Client{Timeout: 10 * time.Second}}, nil
+	case NotifierSMTP:
+		return &SMTPNotifier{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+}
+
+// SlackNotifier posts notifications to a Slack-compatible incoming webhook
+type SlackNotifier struct {
+	name       string
+	url        string
+	httpClient *http.Client
+}
+
+// Name returns the configured notifier name
+func (s *SlackNotifier) Name() string { return s.name }
+
+// Notify posts the notification as a text message
+func (s *SlackNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(map[string]string{"text": n.Title() + "\n" + n.Message})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifier %q: request failed: %w", s.name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notifier %q: returned status %d", s.name, resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier emails notifications through an SMTP server
+type SMTPNotifier struct {
+	cfg NotifierConfig
+}
+
+// Name returns the configured notifier name
+func (s *SMTPNotifier) Name() string { return s.cfg.Name }
+
+// Notify emails the notification to every recipient. The connection is
+// upgraded with STARTTLS whenever the server offers it.
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	if err := s.send(ctx, n); err != nil {
+		return fmt.Errorf("notifier %q: %w", s.cfg.Name, err)
+	}
+	return nil
+}
+
+func (s *SMTPNotifier) send(ctx context.Context, n Notification) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("starttls failed: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	if err := c.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *SMTPNotifier) message(n Notification) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.cfg.From + "\r\n")
+	b.WriteString("To: " + strings.Join(s.cfg.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + n.Title() + "\r\n")
+	b.WriteString("Date: " + n.Time.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Message, "\n", "\r\n") + "\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,118 @@
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackNotifier(t *testing.T) {
+	status := http.StatusOK
+	var text string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		text = body["text"]
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(NotifierConfig{Name: "ops", Type: NotifierSlack, URL: srv.URL})
+	require.NoError(t, err)
+
+	err = n.Notify(context.Background(), Notification{Rule: "mainnet-slashed", Severity: "critical", Message: "Validator 0xaa is slashed"})
+	require.NoError(t, err)
+	assert.Equal(t, "[CRITICAL] mainnet-slashed\nValidator 0xaa is slashed", text)
+
+	status = http.StatusForbidden
+	err = n.Notify(context.Background(), Notification{Rule: "mainnet-slashed", Resolved: true})
+	assert.EqualError(t, err, `notifier "ops": returned status 403`)
+	assert.True(t, strings.HasPrefix(text, "[RESOLVED] mainnet-slashed"))
+}
+
+// smtpStandIn accepts one SMTP session and returns the commands and message it received
+func smtpStandIn(t *testing.T) (net.Listener, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(line, "AUTH"):
+				reply("235 authenticated")
+			case strings.HasPrefix(line, "DATA"):
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 queued")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+		received <- lines
+	}()
+	return ln, received
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	ln, received := smtpStandIn(t)
+	defer ln.Close()
+	addr := ln.Addr().(*net.TCPAddr)
+
+	n, err := NewNotifier(NotifierConfig{
+		Name: "mail", Type: NotifierSMTP, Host: "127.0.0.1", Port: addr.Port,
+		Username: "vkm", Password: "secret", From: "vkm@example.com", To: []string{"ops@example.com", "oncall@example.com"},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = n.Notify(ctx, Notification{Rule: "inactive", Severity: "warning", Message: "6 keys inactive", Time: time.Now()})
+	require.NoError(t, err)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<vkm@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<ops@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<oncall@example.com>")
+	assert.Contains(t, lines, "Subject: [WARNING] inactive")
+	assert.Contains(t, lines, "6 keys inactive")
+	assert.Contains(t, strings.Join(lines, "\n"), "AUTH PLAIN")
+}
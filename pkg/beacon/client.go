@@ -0,0 +1,408 @@
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// farFutureEpoch is the epoch value used for events that have not been scheduled
+const farFutureEpoch = math.MaxUint64
+
+// ValidatorState is a validator as returned by the beacon node
+type ValidatorState struct {
+	Index  string `json:"index"`
+	Status string `json:"status"`
+	Data   struct {
+		Pubkey                string `json:"pubkey"`
+		WithdrawalCredentials string `json:"withdrawal_credentials"`
+		EffectiveBalance      string `json:"effective_balance"`
+		Slashed               bool   `json:"slashed"`
+		ActivationEpoch       string `json:"activation_epoch"`
+		ExitEpoch             string `json:"exit_epoch"`
+		WithdrawableEpoch     string `json:"withdrawable_epoch"`
+	} `json:"validator"`
+}
+
+// Metadata converts the beacon node representation to the stored metadata
+func (s ValidatorState) Metadata() (models.BeaconMetadata, error) {
+	var meta models.BeaconMetadata
+	var err error
+
+	if meta.Index, err = parseEpoch(s.Index); err != nil {
+		return meta, fmt.Errorf("invalid index: %w", err)
+	}
+	balance, err := strconv.ParseInt(s.Data.EffectiveBalance, 10, 64)
+	if err != nil {
+		return meta, fmt.Errorf("invalid effective balance: %w", err)
+	}
+	meta.EffectiveBalance = balance
+	if meta.ActivationEpoch, err = parseEpoch(s.Data.ActivationEpoch); err != nil {
+		return meta, fmt.Errorf("invalid activation epoch: %w", err)
+	}
+	if meta.ExitEpoch, err = parseEpoch(s.Data.ExitEpoch); err != nil {
+		return meta, fmt.Errorf("invalid exit epoch: %w", err)
+	}
+	if meta.WithdrawableEpoch, err = parseEpoch(s.Data.WithdrawableEpoch); err != nil {
+		return meta, fmt.Errorf("invalid withdrawable epoch: %w", err)
+	}
+	meta.Slashed = s.Data.Slashed
+	meta.WithdrawalCredentials = strings.ToLower(s.Data.WithdrawalCredentials)
+
+	return meta, nil
+}
+
+// parseEpoch parses a decimal uint64, returning nil for FAR_FUTURE_EPOCH
+func parseEpoch(s string) (*int64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if v == farFutureEpoch {
+		return nil, nil
+	}
+	if v > math.MaxInt64 {
+		return nil, fmt.Errorf("%d out of range", v)
+	}
+	epoch := int64(v)
+	return &epoch, nil
+}
+
+// Client is a minimal beacon node REST API client
+type Client struct {
+	url        string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	genesis *Genesis
+	spec    map[string]string
+}
+
+// NewClient creates a client for the beacon node at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		url:        strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Validators returns the head state of the validators with the given pubkeys
+// or indices. Keys the beacon node has not seen are omitted from the result.
+func (c *Client) Validators(ctx context.Context, ids []string) ([]ValidatorState, error) {
+	query := url.Values{}
+	query.Set("id", strings.Join(ids, ","))
+	endpoint := c.url + "/eth/v1/beacon/states/head/validators?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data []ValidatorState `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Data, nil
+}
+
+// PendingPartialWithdrawal is a partial withdrawal requested from the execution
+// layer (EIP-7002) and queued in the beacon state until it becomes withdrawable
+type PendingPartialWithdrawal struct {
+	ValidatorIndex    string `json:"validator_index"`
+	Amount            string `json:"amount"`
+	WithdrawableEpoch string `json:"withdrawable_epoch"`
+}
+
+// PendingPartialWithdrawals returns the partial withdrawal queue of the head state.
+// The queue only exists from the Electra fork on.
+func (c *Client) PendingPartialWithdrawals(ctx context.Context) ([]PendingPartialWithdrawal, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.url+"/eth/v1/beacon/states/head/pending_partial_withdrawals", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data []PendingPartialWithdrawal `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Data, nil
+}
+
+// PendingConsolidation is a consolidation (EIP-7251) queued in the beacon state.
+// The source balance moves to the target once the source becomes withdrawable.
+type PendingConsolidation struct {
+	SourceIndex string `json:"source_index"`
+	TargetIndex string `json:"target_index"`
+}
+
+// PendingConsolidations returns the consolidation queue of the head state.
+// The queue only exists from the Electra fork on.
+func (c *Client) PendingConsolidations(ctx context.Context) ([]PendingConsolidation, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.url+"/eth/v1/beacon/states/head/pending_consolidations", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data []PendingConsolidation `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Data, nil
+}
+
+// HeadEpoch returns the epoch of the beacon node's head block
+func (c *Client) HeadEpoch(ctx context.Context) (uint64, error) {
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	slotsPerEpoch, err := strconv.ParseUint(spec["SLOTS_PER_EPOCH"], 10, 64)
+	if err != nil || slotsPerEpoch == 0 {
+		return 0, fmt.Errorf("invalid SLOTS_PER_EPOCH %q", spec["SLOTS_PER_EPOCH"])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/eth/v1/beacon/headers/head", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data struct {
+			Header struct {
+				Message struct {
+					Slot string `json:"slot"`
+				} `json:"message"`
+			} `json:"header"`
+		} `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return 0, err
+	}
+
+	slot, err := strconv.ParseUint(body.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid head slot: %w", err)
+	}
+	return slot / slotsPerEpoch, nil
+}
+
+// Liveness reports which of the validators with the given indices the beacon
+// node saw attesting or proposing in epoch. Nodes only answer for the current
+// and previous epoch.
+func (c *Client) Liveness(ctx context.Context, epoch uint64, indices []string) (map[string]bool, error) {
+	payload, err := json.Marshal(indices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode indices: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.url+"/eth/v1/validator/liveness/"+strconv.FormatUint(epoch, 10), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data []struct {
+			Index  string `json:"index"`
+			IsLive bool   `json:"is_live"`
+		} `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]bool, len(body.Data))
+	for _, d := range body.Data {
+		live[d.Index] = d.IsLive
+	}
+	return live, nil
+}
+
+// Genesis returns the chain's genesis validators root and fork version.
+// The result is cached once fetched successfully.
+func (c *Client) Genesis(ctx context.Context) (Genesis, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.genesis != nil {
+		return *c.genesis, nil
+	}
+	g, err := c.fetchGenesis(ctx)
+	if err != nil {
+		return Genesis{}, err
+	}
+	c.genesis = &g
+	return g, nil
+}
+
+func (c *Client) fetchGenesis(ctx context.Context) (Genesis, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/eth/v1/beacon/genesis", nil)
+	if err != nil {
+		return Genesis{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data struct {
+			GenesisValidatorsRoot string `json:"genesis_validators_root"`
+			GenesisForkVersion    string `json:"genesis_fork_version"`
+		} `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return Genesis{}, err
+	}
+
+	var g Genesis
+	root, err := decodeHex(body.Data.GenesisValidatorsRoot, 32)
+	if err != nil {
+		return Genesis{}, fmt.Errorf("invalid genesis validators root: %w", err)
+	}
+	version, err := decodeHex(body.Data.GenesisForkVersion, 4)
+	if err != nil {
+		return Genesis{}, fmt.Errorf("invalid genesis fork version: %w", err)
+	}
+	copy(g.ValidatorsRoot[:], root)
+	copy(g.ForkVersion[:], version)
+	return g, nil
+}
+
+// CapellaForkVersion returns the chain's Capella fork version. Since Deneb
+// (EIP-7044) voluntary exits are always signed with this version, so exits
+// signed once stay valid across later forks.
+func (c *Client) CapellaForkVersion(ctx context.Context) ([4]byte, error) {
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return [4]byte{}, err
+	}
+	version, err := decodeHex(spec["CAPELLA_FORK_VERSION"], 4)
+	if err != nil {
+		return [4]byte{}, fmt.Errorf("invalid CAPELLA_FORK_VERSION: %w", err)
+	}
+	return [4]byte(version), nil
+}
+
+// Spec returns the chain configuration of the beacon node, cached once fetched successfully
+func (c *Client) Spec(ctx context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.spec != nil {
+		return c.spec, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/eth/v1/config/spec", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := c.do(req, &body); err != nil {
+		return nil, err
+	}
+
+	// Most values are strings; the few nested ones are not needed
+	spec := make(map[string]string, len(body.Data))
+	for k, v := range body.Data {
+		if s, ok := v.(string); ok {
+			spec[k] = s
+		}
+	}
+	c.spec = spec
+	return spec, nil
+}
+
+// SubmitBLSToExecutionChange submits a signed change to the beacon node operation pool
+func (c *Client) SubmitBLSToExecutionChange(ctx context.Context, change SignedBLSToExecutionChange) error {
+	payload, err := json.Marshal([]SignedBLSToExecutionChange{change})
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/eth/v1/beacon/pool/bls_to_execution_changes",
+		bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, nil)
+}
+
+// SubmitVoluntaryExit submits a signed voluntary exit to the beacon node operation pool
+func (c *Client) SubmitVoluntaryExit(ctx context.Context, exit SignedVoluntaryExit) error {
+	payload, err := json.Marshal(exit)
+	if err != nil {
+		return fmt.Errorf("failed to encode exit: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/eth/v1/beacon/pool/voluntary_exits",
+		bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, nil)
+}
+
+// do sends req and decodes a successful JSON response into out, if not nil
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("beacon node request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message  string `json:"message"`
+			Failures []struct {
+				Message string `json:"message"`
+			} `json:"failures"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		msg := apiErr.Message
+		for _, f := range apiErr.Failures {
+			msg += "; " + f.Message
+		}
+		return fmt.Errorf("beacon node returned status %d: %s", resp.StatusCode, msg)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode beacon node response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+const farFuture = "18446744073709551615"
+
+// testPubkey returns a deterministic 48-byte pubkey
+func testPubkey(n int) string {
+	return fmt.Sprintf("0x%096x", n)
+}
+
+// validatorJSON builds a beacon API validator entry
+func validatorJSON(index int, pubkey string, exitEpoch string) map[string]interface{} {
+	return map[string]interface{}{
+		"index":   strconv.Itoa(index),
+		"balance": "32001000000",
+		"status":  "active_ongoing",
+		"validator": map[string]interface{}{
+			"pubkey":                       pubkey,
+			"withdrawal_credentials":       "0x010000000000000000000000D8DA6BF26964AF9D7EED9E03E53415D37AA96045",
+			"effective_balance":            "32000000000",
+			"slashed":                      false,
+			"activation_eligibility_epoch": "10",
+			"activation_epoch":             "20",
+			"exit_epoch":                   exitEpoch,
+			"withdrawable_epoch":           farFuture,
+		},
+	}
+}
+
+// newBeaconStub serves /eth/v1/beacon/states/head/validators from the given entries
+func newBeaconStub(t *testing.T, entries map[string]map[string]interface{}, requests *int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/beacon/states/head/validators", r.URL.Path)
+		if requests != nil {
+			*requests++
+		}
+
+		data := []map[string]interface{}{}
+		for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+			if entry, ok := entries[id]; ok {
+				data = append(data, entry)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestClient_Validators(t *testing.T) {
+	srv := newBeaconStub(t, map[string]map[string]interface{}{
+		testPubkey(1): validatorJSON(42, testPubkey(1), "300"),
+	}, nil)
+
+	states, err := NewClient(srv.URL+"/").Validators(t.Context(), []string{testPubkey(1), testPubkey(2)})
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, testPubkey(1), states[0].Data.Pubkey)
+
+	meta, err := states[0].Metadata()
+	require.NoError(t, err)
+	index, activation, exit := int64(42), int64(20), int64(300)
+	assert.Equal(t, models.BeaconMetadata{
+		Index:                 &index,
+		EffectiveBalance:      32000000000,
+		ActivationEpoch:       &activation,
+		ExitEpoch:             &exit,
+		WithdrawalCredentials: "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045",
+	}, meta)
+}
+
+func TestClient_ValidatorsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"code":503,"message":"Beacon node is currently syncing"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL).Validators(t.Context(), []string{testPubkey(1)})
+	assert.ErrorContains(t, err, "status 503: Beacon node is currently syncing")
+}
+
+func TestClient_PendingPartialWithdrawals(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/beacon/states/head/pending_partial_withdrawals", r.URL.Path)
+		w.Write([]byte(`{"version":"electra","execution_optimistic":false,"finalized":false,"data":[
+			{"validator_index":"42","amount":"1000000000","withdrawable_epoch":"364000"}]}`))
+	}))
+	defer srv.Close()
+
+	withdrawals, err := NewClient(srv.URL).PendingPartialWithdrawals(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []PendingPartialWithdrawal{
+		{ValidatorIndex: "42", Amount: "1000000000", WithdrawableEpoch: "364000"},
+	}, withdrawals)
+}
+
+func TestClient_PendingConsolidations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/beacon/states/head/pending_consolidations", r.URL.Path)
+		w.Write([]byte(`{"version":"electra","execution_optimistic":false,"finalized":false,"data":[
+			{"source_index":"1","target_index":"2"}]}`))
+	}))
+	defer srv.Close()
+
+	consolidations, err := NewClient(srv.URL).PendingConsolidations(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []PendingConsolidation{{SourceIndex: "1", TargetIndex: "2"}}, consolidations)
+}
+
+func TestClient_HeadEpoch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/config/spec":
+			w.Write([]byte(`{"data":{"SLOTS_PER_EPOCH":"32"}}`))
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"root":"0x00","canonical":true,"header":{"message":{"slot":"3231"}}}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	epoch, err := NewClient(srv.URL).HeadEpoch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), epoch)
+}
+
+func TestClient_Liveness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/validator/liveness/100", r.URL.Path)
+		var indices []string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&indices))
+		assert.Equal(t, []string{"1", "2"}, indices)
+		w.Write([]byte(`{"data":[{"index":"1","is_live":true},{"index":"2","is_live":false}]}`))
+	}))
+	defer srv.Close()
+
+	live, err := NewClient(srv.URL).Liveness(t.Context(), 100, []string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"1": true, "2": false}, live)
+}
+
+func TestValidatorState_MetadataInvalid(t *testing.T) {
+	var st ValidatorState
+	st.Index = "1"
+	st.Data.EffectiveBalance = "not a number"
+
+	_, err := st.Metadata()
+	assert.ErrorContains(t, err, "invalid effective balance")
+}
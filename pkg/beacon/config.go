@@ -0,0 +1,77 @@
+// Package beacon syncs validator metadata from beacon node REST APIs
+package beacon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// chains lists the blockchain networks a beacon node can be configured for
+var chains = []struct {
+	blockchain string
+	network    string
+}{
+	{"ethereum", "mainnet"},
+	{"ethereum", "holesky"},
+	{"ethereum", "hoodi"},
+	{"gnosis", "mainnet"},
+	{"gnosis", "chiado"},
+}
+
+// NodeConfig is the beacon node serving one blockchain network
+type NodeConfig struct {
+	Blockchain string
+	Network    string
+	URL        string
+}
+
+// Config holds the beacon node configuration
+type Config struct {
+	Nodes    []NodeConfig
+	Interval time.Duration
+}
+
+// NewConfig creates a beacon configuration from environment variables.
+// A network is enabled when BEACON_<BLOCKCHAIN>_<NETWORK>_URL is set, e.g.
+// BEACON_ETHEREUM_MAINNET_URL. BEACON_SYNC_INTERVAL sets how often to sync.
+func NewConfig() (*Config, error) {
+	cfg := &Config{Interval: 24 * time.Hour}
+
+	if v := os.Getenv("BEACON_SYNC_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BEACON_SYNC_INTERVAL: %w", err)
+		}
+		cfg.Interval = interval
+	}
+
+	for _, c := range chains {
+		url := os.Getenv("BEACON_" + strings.ToUpper(c.blockchain) + "_" + strings.ToUpper(c.network) + "_URL")
+		if url == "" {
+			continue
+		}
+		cfg.Nodes = append(cfg.Nodes, NodeConfig{Blockchain: c.blockchain, Network: c.network, URL: url})
+	}
+
+	return cfg, nil
+}
+
+// Nodes holds a beacon node client per configured blockchain network
+type Nodes map[string]*Client
+
+// NewNodes creates a client for every configured beacon node
+func NewNodes(cfg *Config) Nodes {
+	nodes := make(Nodes)
+	for _, nc := range cfg.Nodes {
+		nodes[nc.Blockchain+"/"+nc.Network] = NewClient(nc.URL)
+	}
+	return nodes
+}
+
+// Get returns the client of the beacon node serving a blockchain network
+func (n Nodes) Get(blockchain, network string) (*Client, bool) {
+	c, ok := n[blockchain+"/"+network]
+	return c, ok
+}
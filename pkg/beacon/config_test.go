@@ -0,0 +1,27 @@
+package beacon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Setenv("BEACON_ETHEREUM_MAINNET_URL", "http://lighthouse:5052")
+	t.Setenv("BEACON_GNOSIS_MAINNET_URL", "http://teku:5051")
+	t.Setenv("BEACON_SYNC_INTERVAL", "6h")
+
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, cfg.Interval)
+	assert.Equal(t, []NodeConfig{
+		{Blockchain: "ethereum", Network: "mainnet", URL: "http://lighthouse:5052"},
+		{Blockchain: "gnosis", Network: "mainnet", URL: "http://teku:5051"},
+	}, cfg.Nodes)
+
+	t.Setenv("BEACON_SYNC_INTERVAL", "daily")
+	_, err = NewConfig()
+	assert.ErrorContains(t, err, "invalid BEACON_SYNC_INTERVAL")
+}
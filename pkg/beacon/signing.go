@@ -0,0 +1,156 @@
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Signature domain types
+var (
+	DomainVoluntaryExit        = [4]byte{0x04, 0x00, 0x00, 0x00}
+	DomainBLSToExecutionChange = [4]byte{0x0a, 0x00, 0x00, 0x00}
+)
+
+// Genesis holds the chain parameters used to compute signature domains
+type Genesis struct {
+	ValidatorsRoot [32]byte
+	ForkVersion    [4]byte
+}
+
+// ComputeDomain returns the signature domain of domainType for forkVersion on the chain
+func ComputeDomain(domainType, forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	// hash_tree_root(ForkData{current_version, genesis_validators_root})
+	var versionChunk [32]byte
+	copy(versionChunk[:], forkVersion[:])
+	forkDataRoot := hashPair(versionChunk, genesisValidatorsRoot)
+
+	var domain [32]byte
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain
+}
+
+// SigningRoot returns hash_tree_root(SigningData{object_root, domain})
+func SigningRoot(objectRoot, domain [32]byte) [32]byte {
+	return hashPair(objectRoot, domain)
+}
+
+// BLSToExecutionChange is the message moving a validator from 0x00 to 0x01 withdrawal credentials
+type BLSToExecutionChange struct {
+	ValidatorIndex     string `json:"validator_index"`
+	FromBLSPubkey      string `json:"from_bls_pubkey"`
+	ToExecutionAddress string `json:"to_execution_address"`
+}
+
+// SignedBLSToExecutionChange is a BLSToExecutionChange signed by the withdrawal key
+type SignedBLSToExecutionChange struct {
+	Message   BLSToExecutionChange `json:"message"`
+	Signature string               `json:"signature"`
+}
+
+// Index returns the validator index of the change
+func (c BLSToExecutionChange) Index() (uint64, error) {
+	index, err := strconv.ParseUint(c.ValidatorIndex, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid validator index: %w", err)
+	}
+	return index, nil
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the change
+func (c BLSToExecutionChange) HashTreeRoot() ([32]byte, error) {
+	index, err := c.Index()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pubkey, err := decodeHex(c.FromBLSPubkey, 48)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid from_bls_pubkey: %w", err)
+	}
+	address, err := decodeHex(c.ToExecutionAddress, 20)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid to_execution_address: %w", err)
+	}
+
+	var indexChunk, pubkeyLow, pubkeyHigh, addressChunk [32]byte
+	binary.LittleEndian.PutUint64(indexChunk[:], index)
+	copy(pubkeyLow[:], pubkey[:32])
+	copy(pubkeyHigh[:], pubkey[32:])
+	copy(addressChunk[:], address)
+
+	// Three fields are merkleized as four leaves, the last one zero
+	return hashPair(
+		hashPair(indexChunk, hashPair(pubkeyLow, pubkeyHigh)),
+		hashPair(addressChunk, [32]byte{}),
+	), nil
+}
+
+// VoluntaryExit is the message asking for a validator to exit at or after Epoch
+type VoluntaryExit struct {
+	Epoch          string `json:"epoch"`
+	ValidatorIndex string `json:"validator_index"`
+}
+
+// SignedVoluntaryExit is a VoluntaryExit signed by the validator key
+type SignedVoluntaryExit struct {
+	Message   VoluntaryExit `json:"message"`
+	Signature string        `json:"signature"`
+}
+
+// Index returns the validator index of the exit
+func (e VoluntaryExit) Index() (uint64, error) {
+	index, err := strconv.ParseUint(e.ValidatorIndex, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid validator index: %w", err)
+	}
+	return index, nil
+}
+
+// ExitEpoch parses the epoch of the exit
+func (e VoluntaryExit) ExitEpoch() (uint64, error) {
+	epoch, err := strconv.ParseUint(e.Epoch, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid epoch: %w", err)
+	}
+	return epoch, nil
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the exit
+func (e VoluntaryExit) HashTreeRoot() ([32]byte, error) {
+	epoch, err := e.ExitEpoch()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	index, err := e.Index()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	var epochChunk, indexChunk [32]byte
+	binary.LittleEndian.PutUint64(epochChunk[:], epoch)
+	binary.LittleEndian.PutUint64(indexChunk[:], index)
+	return hashPair(epochChunk, indexChunk), nil
+}
+
+func hashPair(a, b [32]byte) [32]byte {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
+
+// decodeHex decodes a 0x-prefixed hex string of exactly size bytes
+func decodeHex(s string, size int) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("%q must start with 0x", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return b, nil
+}
@@ -0,0 +1,39 @@
+package beacon
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeDomain(t *testing.T) {
+	// The mainnet deposit domain uses the genesis fork version and a zero validators root
+	domain := ComputeDomain([4]byte{0x03}, [4]byte{}, [32]byte{})
+	assert.Equal(t, "03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9", hex.EncodeToString(domain[:]))
+}
+
+func TestBLSToExecutionChange_HashTreeRoot(t *testing.T) {
+	change := BLSToExecutionChange{
+		ValidatorIndex:     "1",
+		FromBLSPubkey:      "0x" + hex.EncodeToString(make([]byte, 48)),
+		ToExecutionAddress: "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+	}
+	root, err := change.HashTreeRoot()
+	require.NoError(t, err)
+
+	other := change
+	other.ValidatorIndex = "2"
+	otherRoot, err := other.HashTreeRoot()
+	require.NoError(t, err)
+	assert.NotEqual(t, root, otherRoot)
+
+	change.ToExecutionAddress = "0xd8da"
+	_, err = change.HashTreeRoot()
+	assert.ErrorContains(t, err, "invalid to_execution_address")
+
+	change.ValidatorIndex = "-1"
+	_, err = change.HashTreeRoot()
+	assert.ErrorContains(t, err, "invalid validator index")
+}
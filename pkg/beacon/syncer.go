@@ -0,0 +1,160 @@
+package beacon
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// batchSize is the number of pubkeys requested per beacon node call,
+// keeping the query string well below common URL length limits
+const batchSize = 50
+
+// node is the beacon node of one blockchain network
+type node struct {
+	blockchain string
+	network    string
+	client     *Client
+}
+
+// Syncer stores the beacon chain metadata of every validator on the configured networks
+type Syncer struct {
+	repo          models.ValidatorRepo
+	nodes         []node
+	hooks         []func(ctx context.Context) error
+	completeHooks []func(ctx context.Context, err error)
+}
+
+// NewSyncer creates a syncer for the configured beacon nodes
+func NewSyncer(repo models.ValidatorRepo, cfg *Config) *Syncer {
+	s := &Syncer{repo: repo}
+	for _, nc := range cfg.Nodes {
+		s.nodes = append(s.nodes, node{
+			blockchain: nc.Blockchain,
+			network:    nc.Network,
+			client:     NewClient(nc.URL),
+		})
+	}
+	return s
+}
+
+// OnRun registers a function called after every run, once the beacon metadata is stored
+func (s *Syncer) OnRun(f func(ctx context.Context) error) {
+	s.hooks = append(s.hooks, f)
+}
+
+// OnComplete registers a function called after every run started by Start, with
+// the error of the run or nil if it succeeded
+func (s *Syncer) OnComplete(f func(ctx context.Context, err error)) {
+	s.completeHooks = append(s.completeHooks, f)
+}
+
+// Run refreshes the beacon metadata of every stored validator on the configured networks.
+// A failing network does not stop the others from being processed.
+func (s *Syncer) Run(ctx context.Context) error {
+	var errs []error
+	for _, n := range s.nodes {
+		if err := s.syncNode(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("beacon %s/%s: %w", n.blockchain, n.network, err))
+		}
+	}
+	for _, hook := range s.hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start runs the syncer immediately and then on every interval until ctx is done
+func (s *Syncer) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Run(ctx)
+		if err != nil {
+			log.Printf("Beacon sync failed: %v", err)
+		}
+		for _, f := range s.completeHooks {
+			f(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Syncer) syncNode(ctx context.Context, n node) error {
+	validators, err := s.repo.List(ctx, map[string]interface{}{
+		"blockchain":         n.blockchain,
+		"blockchain_network": n.network,
+	})
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(validators); start += batchSize {
+		batch := validators[start:min(start+batchSize, len(validators))]
+
+		pubkeys := make([]string, len(batch))
+		for i, v := range batch {
+			pubkeys[i] = v.Pubkey
+		}
+		states, err := n.client.Validators(ctx, pubkeys)
+		if err != nil {
+			return err
+		}
+
+		byPubkey := make(map[string]ValidatorState, len(states))
+		for _, st := range states {
+			byPubkey[st.Data.Pubkey] = st
+		}
+
+		for _, v := range batch {
+			// Keys not yet deposited are unknown to the beacon node
+			st, ok := byPubkey[v.Pubkey]
+			if !ok {
+				continue
+			}
+			meta, err := st.Metadata()
+			if err != nil {
+				return fmt.Errorf("validator %s: %w", v.Pubkey, err)
+			}
+			if sameMetadata(v, meta) {
+				continue
+			}
+			err = s.repo.UpdateBeacon(ctx, v.Pubkey, meta)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sameMetadata(v models.Validator, meta models.BeaconMetadata) bool {
+	return v.EffectiveBalance == meta.EffectiveBalance &&
+		v.Slashed == meta.Slashed &&
+		v.WithdrawalCredentials == meta.WithdrawalCredentials &&
+		equalInt64Ptr(v.ValidatorIndex, meta.Index) &&
+		equalInt64Ptr(v.ActivationEpoch, meta.ActivationEpoch) &&
+		equalInt64Ptr(v.ExitEpoch, meta.ExitEpoch) &&
+		equalInt64Ptr(v.WithdrawableEpoch, meta.WithdrawableEpoch)
+}
+
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,76 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestSyncer_Run(t *testing.T) {
+	var requests int
+	srv := newBeaconStub(t, map[string]map[string]interface{}{
+		testPubkey(1): validatorJSON(1, testPubkey(1), farFuture),
+		testPubkey(2): validatorJSON(2, testPubkey(2), farFuture),
+	}, &requests)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upToDate := models.Validator{
+		Pubkey:                testPubkey(2),
+		ValidatorIndex:        int64Ptr(2),
+		EffectiveBalance:      32000000000,
+		ActivationEpoch:       int64Ptr(20),
+		WithdrawalCredentials: "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045",
+	}
+
+	// More validators than a single batch, most of them not yet deposited
+	validators := []models.Validator{{Pubkey: testPubkey(1)}, upToDate}
+	for i := 0; i < batchSize; i++ {
+		validators = append(validators, models.Validator{Pubkey: testPubkey(100 + i)})
+	}
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), map[string]interface{}{
+		"blockchain":         "gnosis",
+		"blockchain_network": "chiado",
+	}).Return(validators, nil)
+	mockRepo.EXPECT().UpdateBeacon(gomock.Any(), testPubkey(1), models.BeaconMetadata{
+		Index:                 int64Ptr(1),
+		EffectiveBalance:      32000000000,
+		ActivationEpoch:       int64Ptr(20),
+		WithdrawalCredentials: "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045",
+	}).Return(nil)
+
+	syncer := NewSyncer(mockRepo, &Config{
+		Nodes: []NodeConfig{{Blockchain: "gnosis", Network: "chiado", URL: srv.URL}},
+	})
+	require.NoError(t, syncer.Run(t.Context()))
+	assert.Equal(t, 2, requests)
+}
+
+func TestSyncer_OnRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := NewSyncer(mocks.NewMockValidatorRepo(ctrl), &Config{})
+
+	var calls int
+	s.OnRun(func(_ context.Context) error {
+		calls++
+		return errors.New("check failed")
+	})
+
+	assert.Error(t, s.Run(t.Context()))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,82 @@
+// Package bls verifies Ethereum consensus layer BLS12-381 signatures
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Sizes of secret keys and compressed BLS public keys and signatures
+const (
+	SecretKeySize = fr.Bytes
+	PubkeySize    = bls12381.SizeOfG1AffineCompressed
+	SignatureSize = bls12381.SizeOfG2AffineCompressed
+)
+
+// dst is the domain separation tag of the proof-of-possession scheme used by Ethereum
+var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// ErrInvalidSignature is returned when a signature does not verify against the public key
+var ErrInvalidSignature = errors.New("invalid BLS signature")
+
+// Verify checks that sig is a valid signature of msg by the compressed public key pubkey
+func Verify(pubkey, msg, sig []byte) error {
+	if len(pubkey) != PubkeySize {
+		return fmt.Errorf("public key must be %d bytes, got %d", PubkeySize, len(pubkey))
+	}
+	if len(sig) != SignatureSize {
+		return fmt.Errorf("signature must be %d bytes, got %d", SignatureSize, len(sig))
+	}
+
+	var pk bls12381.G1Affine
+	if _, err := pk.SetBytes(pubkey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	if pk.IsInfinity() {
+		return errors.New("invalid public key: point at infinity")
+	}
+
+	var s bls12381.G2Affine
+	if _, err := s.SetBytes(sig); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	h, err := bls12381.HashToG2(msg, dst)
+	if err != nil {
+		return fmt.Errorf("failed to hash message: %w", err)
+	}
+
+	// e(pk, H(msg)) == e(g1, sig)  <=>  e(pk, H(msg)) * e(-g1, sig) == 1
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1)
+
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{pk, negG1}, []bls12381.G2Affine{h, s})
+	if err != nil {
+		return fmt.Errorf("pairing check failed: %w", err)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// PublicKey returns the compressed public key of the big-endian secret key secret
+func PublicKey(secret []byte) ([]byte, error) {
+	if len(secret) != SecretKeySize {
+		return nil, fmt.Errorf("secret key must be %d bytes, got %d", SecretKeySize, len(secret))
+	}
+	s := new(big.Int).SetBytes(secret)
+	if s.Sign() == 0 || s.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("secret key is out of range")
+	}
+
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplicationBase(s)
+	b := pk.Bytes()
+	return b[:], nil
+}
@@ -0,0 +1,48 @@
+package bls
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVerify(t *testing.T) {
+	// consensus-spec-tests bls/verify/verify_valid_case_195246ee3bd3b6ec
+	pubkey := mustDecode(t, "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a")
+	msg := make([]byte, 32)
+	sig := mustDecode(t, "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55")
+
+	assert.NoError(t, Verify(pubkey, msg, sig))
+
+	tampered := append([]byte{}, msg...)
+	tampered[0] = 1
+	assert.ErrorIs(t, Verify(pubkey, tampered, sig), ErrInvalidSignature)
+
+	infinity := make([]byte, PubkeySize)
+	infinity[0] = 0xc0
+	assert.Error(t, Verify(infinity, msg, sig))
+	assert.Error(t, Verify(pubkey[:47], msg, sig))
+}
+
+func TestPublicKey(t *testing.T) {
+	// Secret key of the EIP-2335 test vectors
+	secret := mustDecode(t, "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f")
+
+	pubkey, err := PublicKey(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07", hex.EncodeToString(pubkey))
+
+	_, err = PublicKey(make([]byte, SecretKeySize))
+	assert.EqualError(t, err, "secret key is out of range")
+	_, err = PublicKey(secret[:31])
+	assert.Error(t, err)
+}
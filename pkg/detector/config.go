@@ -0,0 +1,60 @@
+// Package detector discovers which validator client instances have each key loaded
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+	"github.com/zheli/validator-key-manager-backend/pkg/web3signer"
+)
+
+// Config holds the validator client instances to query and the remote signers they use
+type Config struct {
+	Instances []vclient.Config    `json:"instances"`
+	Signers   []web3signer.Config `json:"signers"`
+}
+
+// LoadConfig reads a JSON detector configuration from path
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read detector config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse detector config: %w", err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Instances))
+	for _, inst := range cfg.Instances {
+		if inst.Name == "" {
+			return nil, fmt.Errorf("instance name is required")
+		}
+		if seen[inst.Name] {
+			return nil, fmt.Errorf("duplicate instance name %q", inst.Name)
+		}
+		seen[inst.Name] = true
+		if inst.URL == "" {
+			return nil, fmt.Errorf("instance %q: url is required", inst.Name)
+		}
+	}
+
+	seen = make(map[string]bool, len(cfg.Signers))
+	for _, signer := range cfg.Signers {
+		if signer.Name == "" {
+			return nil, fmt.Errorf("signer name is required")
+		}
+		if seen[signer.Name] {
+			return nil, fmt.Errorf("duplicate signer name %q", signer.Name)
+		}
+		seen[signer.Name] = true
+		if signer.URL == "" {
+			return nil, fmt.Errorf("signer %q: url is required", signer.Name)
+		}
+	}
+
+	return &cfg, nil
+}
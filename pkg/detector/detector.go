@@ -0,0 +1,156 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// Detector records which validator client instance has each stored key loaded
+type Detector struct {
+	repo          models.ValidatorRepo
+	keys          models.ClientKeyRepo
+	clients       []vclient.ValidatorClient
+	hooks         []func(ctx context.Context) error
+	completeHooks []func(ctx context.Context, err error)
+}
+
+// New creates a detector for the configured validator client instances
+func New(repo models.ValidatorRepo, keys models.ClientKeyRepo, cfg *Config) (*Detector, error) {
+	clients := make([]vclient.ValidatorClient, 0, len(cfg.Instances))
+	for _, inst := range cfg.Instances {
+		c, err := vclient.New(inst)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return NewWithClients(repo, keys, clients), nil
+}
+
+// NewWithClients creates a detector for already constructed validator clients
+func NewWithClients(repo models.ValidatorRepo, keys models.ClientKeyRepo, clients []vclient.ValidatorClient) *Detector {
+	return &Detector{repo: repo, keys: keys, clients: clients}
+}
+
+// OnRun registers a function called after every run, once the discovery data is stored
+func (d *Detector) OnRun(f func(ctx context.Context) error) {
+	d.hooks = append(d.hooks, f)
+}
+
+// OnComplete registers a function called after every run started by Start, with
+// the error of the run or nil if it succeeded
+func (d *Detector) OnComplete(f func(ctx context.Context, err error)) {
+	d.completeHooks = append(d.completeHooks, f)
+}
+
+// Run queries every configured instance once, stores the keys each one reports and
+// updates the client of each known key. A failing instance does not stop the others
+// from being processed; its previously stored keys and assignments are kept.
+func (d *Detector) Run(ctx context.Context) error {
+	var errs []error
+	loaded := make(map[string][]vclient.ValidatorClient)
+	failed := make(map[string]bool)
+	for _, c := range d.clients {
+		keys, err := d.detect(ctx, c)
+		if err != nil {
+			errs = append(errs, err)
+			failed[c.Name()] = true
+			continue
+		}
+		for _, k := range keys {
+			loaded[k.Pubkey] = append(loaded[k.Pubkey], c)
+		}
+	}
+	if err := d.assign(ctx, loaded, failed); err != nil {
+		errs = append(errs, err)
+	}
+	for _, hook := range d.hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start runs the detector immediately and then on every interval until ctx is done
+func (d *Detector) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := d.Run(ctx)
+		if err != nil {
+			log.Printf("Validator client detection failed: %v", err)
+		}
+		for _, f := range d.completeHooks {
+			f(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// detect stores the keys an instance reports and returns them
+func (d *Detector) detect(ctx context.Context, c vclient.ValidatorClient) ([]vclient.Key, error) {
+	keys, err := c.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clientKeys := make([]models.ClientKey, 0, len(keys))
+	for _, k := range keys {
+		clientKeys = append(clientKeys, models.ClientKey{
+			Pubkey:    k.Pubkey,
+			Client:    c.Type(),
+			Remote:    k.Remote,
+			SignerURL: k.SignerURL,
+		})
+	}
+	if err := d.keys.ReplaceInstanceKeys(ctx, c.Name(), clientKeys); err != nil {
+		return nil, fmt.Errorf("instance %q: %w", c.Name(), err)
+	}
+	return keys, nil
+}
+
+// assign records the instance of every stored key loaded on exactly one instance
+// and clears the assignment of stored keys no instance reports. Keys loaded on
+// several instances keep their assignment and are left to the double-load check;
+// keys assigned to an instance that could not be queried are left unchanged.
+func (d *Detector) assign(ctx context.Context, loaded map[string][]vclient.ValidatorClient, failed map[string]bool) error {
+	validators, err := d.repo.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list validators: %w", err)
+	}
+
+	var errs []error
+	for _, v := range validators {
+		var client, instance string
+		switch clients := loaded[v.Pubkey]; len(clients) {
+		case 0:
+			if failed[v.ClientInstance] {
+				continue
+			}
+		case 1:
+			client, instance = clients[0].Type(), clients[0].Name()
+		default:
+			continue
+		}
+		if v.Client == client && v.ClientInstance == instance {
+			continue
+		}
+		if err := d.repo.UpdateClient(ctx, v.Pubkey, client, instance); err != nil {
+			errs = append(errs, fmt.Errorf("validator %s: %w", v.Pubkey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,222 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// fakeClient is an in-memory validator client
+type fakeClient struct {
+	name       string
+	clientType string
+	keys       []vclient.Key
+	err        error
+}
+
+func (f *fakeClient) Name() string { return f.name }
+
+func (f *fakeClient) Type() string { return f.clientType }
+
+func (f *fakeClient) ListKeys(_ context.Context) ([]vclient.Key, error) {
+	return f.keys, f.err
+}
+
+func TestDetector_Run(t *testing.T) {
+	teku := &fakeClient{
+		name:       "teku-1",
+		clientType: vclient.TypeTeku,
+		keys:       []vclient.Key{{Pubkey: "0xaa"}, {Pubkey: "0xbb", Remote: true}},
+	}
+	prysm := &fakeClient{
+		name:       "prysm-1",
+		clientType: vclient.TypePrysm,
+		keys:       []vclient.Key{{Pubkey: "0xcc"}},
+	}
+	broken := &fakeClient{name: "nimbus-1", clientType: vclient.TypeNimbus, err: errors.New("connection refused")}
+	tekuBackup := &fakeClient{name: "teku-2", clientType: vclient.TypeTeku, keys: []vclient.Key{{Pubkey: "0xaa"}}}
+
+	tests := []struct {
+		name        string
+		clients     []vclient.ValidatorClient
+		mockSetup   func(*mocks.MockValidatorRepo, *mocks.MockClientKeyRepo)
+		expectError bool
+	}{
+		{
+			name:    "updates keys from every client",
+			clients: []vclient.ValidatorClient{teku, prysm},
+			mockSetup: func(m *mocks.MockValidatorRepo, k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "teku-1", []models.ClientKey{
+					{Pubkey: "0xaa", Client: vclient.TypeTeku},
+					{Pubkey: "0xbb", Client: vclient.TypeTeku, Remote: true},
+				}).Return(nil)
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "prysm-1", []models.ClientKey{
+					{Pubkey: "0xcc", Client: vclient.TypePrysm},
+				}).Return(nil)
+				// 0xbb is already assigned and keys not in the database are skipped
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{
+					{Pubkey: "0xaa"},
+					{Pubkey: "0xbb", Client: vclient.TypeTeku, ClientInstance: "teku-1"},
+				}, nil)
+				m.EXPECT().UpdateClient(gomock.Any(), "0xaa", vclient.TypeTeku, "teku-1").Return(nil)
+			},
+		},
+		{
+			name:    "clears keys no instance reports",
+			clients: []vclient.ValidatorClient{prysm},
+			mockSetup: func(m *mocks.MockValidatorRepo, k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "prysm-1", gomock.Any()).Return(nil)
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{
+					{Pubkey: "0xcc", Client: vclient.TypePrysm, ClientInstance: "prysm-1"},
+					{Pubkey: "0xdd", Client: vclient.TypePrysm, ClientInstance: "prysm-1"},
+					{Pubkey: "0xee", Client: vclient.TypeTeku, ClientInstance: "teku-9"},
+					{Pubkey: "0xff"},
+				}, nil)
+				m.EXPECT().UpdateClient(gomock.Any(), "0xdd", "", "").Return(nil)
+				m.EXPECT().UpdateClient(gomock.Any(), "0xee", "", "").Return(nil)
+			},
+		},
+		{
+			name:    "keeps the assignment of double-loaded keys",
+			clients: []vclient.ValidatorClient{teku, tekuBackup},
+			mockSetup: func(m *mocks.MockValidatorRepo, k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "teku-1", gomock.Any()).Return(nil)
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "teku-2", gomock.Any()).Return(nil)
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{
+					{Pubkey: "0xaa", Client: vclient.TypeTeku, ClientInstance: "teku-2"},
+				}, nil)
+			},
+		},
+		{
+			name:    "failing client does not stop others",
+			clients: []vclient.ValidatorClient{broken, prysm},
+			mockSetup: func(m *mocks.MockValidatorRepo, k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "prysm-1", gomock.Any()).Return(nil)
+				// Keys of the failing instance keep their assignment
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{
+					{Pubkey: "0xcc"},
+					{Pubkey: "0xdd", Client: vclient.TypeNimbus, ClientInstance: "nimbus-1"},
+				}, nil)
+				m.EXPECT().UpdateClient(gomock.Any(), "0xcc", vclient.TypePrysm, "prysm-1").Return(nil)
+			},
+			expectError: true,
+		},
+		{
+			name:    "database error",
+			clients: []vclient.ValidatorClient{teku},
+			mockSetup: func(m *mocks.MockValidatorRepo, k *mocks.MockClientKeyRepo) {
+				k.EXPECT().ReplaceInstanceKeys(gomock.Any(), "teku-1", gomock.Any()).Return(nil)
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Validator{{Pubkey: "0xaa"}, {Pubkey: "0xbb"}}, nil)
+				m.EXPECT().UpdateClient(gomock.Any(), "0xaa", vclient.TypeTeku, "teku-1").Return(errors.New("database error"))
+				m.EXPECT().UpdateClient(gomock.Any(), "0xbb", vclient.TypeTeku, "teku-1").Return(nil)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockValidatorRepo(ctrl)
+			mockKeys := mocks.NewMockClientKeyRepo(ctrl)
+			tt.mockSetup(mockRepo, mockKeys)
+
+			err := NewWithClients(mockRepo, mockKeys, tt.clients).Run(context.Background())
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDetector_OnRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+	d := NewWithClients(mockRepo, mocks.NewMockClientKeyRepo(ctrl), nil)
+
+	var calls int
+	d.OnRun(func(_ context.Context) error {
+		calls++
+		return errors.New("check failed")
+	})
+
+	assert.Error(t, d.Run(context.Background()))
+	assert.Equal(t, 1, calls)
+}
+
+func TestNew_UnsupportedClient(t *testing.T) {
+	_, err := New(nil, nil, &Config{Instances: []vclient.Config{
+		{Name: "x", Client: "unknown", URL: "http://localhost"},
+	}})
+	assert.Error(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectError bool
+	}{
+		{
+			name:    "valid config",
+			content: `{"instances":[{"name":"teku-1","client":"teku","url":"https://teku:5052","ca_file":"/etc/teku/ca.pem"}]}`,
+		},
+		{
+			name:        "missing name",
+			content:     `{"instances":[{"client":"teku","url":"https://teku:5052"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "duplicate name",
+			content:     `{"instances":[{"name":"a","url":"https://a"},{"name":"a","url":"https://b"}]}`,
+			expectError: true,
+		},
+		{
+			name: "with signers",
+			content: `{"instances":[{"name":"teku-1","client":"teku","url":"https://teku:5052"}],` +
+				`"signers":[{"name":"w3s-1","url":"https://web3signer:9000"}]}`,
+		},
+		{
+			name: "signer without url",
+			content: `{"instances":[{"name":"teku-1","client":"teku","url":"https://teku:5052"}],` +
+				`"signers":[{"name":"w3s-1"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid json",
+			content:     `{`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "clients.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			cfg, err := LoadConfig(path)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, cfg.Instances, 1)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package interchange reads and writes EIP-3076 slashing protection interchange files
+package interchange
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatVersion is the only interchange format version accepted and produced
+const FormatVersion = "5"
+
+// ErrInvalid is returned for an interchange that does not follow EIP-3076
+var ErrInvalid = errors.New("invalid slashing protection interchange")
+
+// Interchange is an EIP-3076 slashing protection interchange file
+type Interchange struct {
+	Metadata Metadata `json:"metadata"`
+	Data     []Record `json:"data"`
+}
+
+// Metadata identifies the format version and chain of an interchange
+type Metadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// Record is the signing history of one validator key
+type Record struct {
+	Pubkey             string              `json:"pubkey"`
+	SignedBlocks       []SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a block proposal signed by the key
+type SignedBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// SignedAttestation is an attestation signed by the key
+type SignedAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// Watermark is the highest block slot and attestation epochs a key has signed.
+// A nil field means the key never signed a message of that kind.
+type Watermark struct {
+	Pubkey      string
+	BlockSlot   *uint64
+	SourceEpoch *uint64
+	TargetEpoch *uint64
+}
+
+// Validate checks the interchange against EIP-3076 and returns its genesis
+// validators root in lower case
+func (i *Interchange) Validate() (string, error) {
+	if i.Metadata.InterchangeFormatVersion != FormatVersion {
+		return "", fmt.Errorf("%w: unsupported format version %q", ErrInvalid, i.Metadata.InterchangeFormatVersion)
+	}
+	root := strings.ToLower(i.Metadata.GenesisValidatorsRoot)
+	if !isHex(root, 32) {
+		return "", fmt.Errorf("%w: genesis_validators_root must be 32 hex bytes", ErrInvalid)
+	}
+	for _, r := range i.Data {
+		if !isHex(strings.ToLower(r.Pubkey), 48) {
+			return "", fmt.Errorf("%w: pubkey %q must be 48 hex bytes", ErrInvalid, r.Pubkey)
+		}
+	}
+	return root, nil
+}
+
+// Watermarks reduces every record to the highest slot and epochs it signed.
+// Records of the same key are merged, keeping the highest value of each.
+func (i *Interchange) Watermarks() ([]Watermark, error) {
+	var marks []Watermark
+	byPubkey := map[string]int{}
+	for _, r := range i.Data {
+		pubkey := strings.ToLower(r.Pubkey)
+		idx, ok := byPubkey[pubkey]
+		if !ok {
+			marks = append(marks, Watermark{Pubkey: pubkey})
+			idx = len(marks) - 1
+			byPubkey[pubkey] = idx
+		}
+		m := &marks[idx]
+
+		for _, b := range r.SignedBlocks {
+			slot, err := strconv.ParseUint(b.Slot, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: invalid slot %q", ErrInvalid, pubkey, b.Slot)
+			}
+			m.BlockSlot = maxOf(m.BlockSlot, slot)
+		}
+		for _, a := range r.SignedAttestations {
+			source, err := strconv.ParseUint(a.SourceEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: invalid source epoch %q", ErrInvalid, pubkey, a.SourceEpoch)
+			}
+			target, err := strconv.ParseUint(a.TargetEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: invalid target epoch %q", ErrInvalid, pubkey, a.TargetEpoch)
+			}
+			if source > target {
+				return nil, fmt.Errorf("%w: %s: source epoch %d is after target epoch %d", ErrInvalid, pubkey, source, target)
+			}
+			m.SourceEpoch = maxOf(m.SourceEpoch, source)
+			m.TargetEpoch = maxOf(m.TargetEpoch, target)
+		}
+	}
+	return marks, nil
+}
+
+// Minimal builds an interchange holding one block and one attestation per key at
+// its watermarks. Importing clients refuse to sign at or below them, which is the
+// minimal format EIP-3076 allows in place of the full signing history.
+func Minimal(genesisValidatorsRoot string, marks []Watermark) *Interchange {
+	i := &Interchange{
+		Metadata: Metadata{InterchangeFormatVersion: FormatVersion, GenesisValidatorsRoot: genesisValidatorsRoot},
+		Data:     make([]Record, 0, len(marks)),
+	}
+	for _, m := range marks {
+		r := Record{Pubkey: m.Pubkey, SignedBlocks: []SignedBlock{}, SignedAttestations: []SignedAttestation{}}
+		if m.BlockSlot != nil {
+			r.SignedBlocks = append(r.SignedBlocks, SignedBlock{Slot: strconv.FormatUint(*m.BlockSlot, 10)})
+		}
+		if m.SourceEpoch != nil && m.TargetEpoch != nil {
+			r.SignedAttestations = append(r.SignedAttestations, SignedAttestation{
+				SourceEpoch: strconv.FormatUint(*m.SourceEpoch, 10),
+				TargetEpoch: strconv.FormatUint(*m.TargetEpoch, 10),
+			})
+		}
+		i.Data = append(i.Data, r)
+	}
+	return i
+}
+
+func maxOf(current *uint64, v uint64) *uint64 {
+	if current != nil && *current >= v {
+		return current
+	}
+	return &v
+}
+
+// isHex reports whether s is a 0x-prefixed hex encoding of exactly size bytes
+func isHex(s string, size int) bool {
+	if !strings.HasPrefix(s, "0x") || len(s) != 2+2*size {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
@@ -0,0 +1,136 @@
+package interchange
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testRoot   = "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+	testPubkey = "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed"
+)
+
+// example is the interchange from EIP-3076, with an older second record of the same key in upper case
+var example = `{
+	"metadata": {
+		"interchange_format_version": "5",
+		"genesis_validators_root": "` + testRoot + `"
+	},
+	"data": [
+		{
+			"pubkey": "` + testPubkey + `",
+			"signed_blocks": [
+				{"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"},
+				{"slot": "81951"}
+			],
+			"signed_attestations": [
+				{"source_epoch": "2290", "target_epoch": "3007", "signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"},
+				{"source_epoch": "2290", "target_epoch": "3008"}
+			]
+		},
+		{
+			"pubkey": "0x` + strings.ToUpper(testPubkey[2:]) + `",
+			"signed_blocks": [{"slot": "5"}],
+			"signed_attestations": [{"source_epoch": "1", "target_epoch": "2"}]
+		}
+	]
+}`
+
+func TestInterchange_Watermarks(t *testing.T) {
+	var i Interchange
+	require.NoError(t, json.Unmarshal([]byte(example), &i))
+
+	root, err := i.Validate()
+	require.NoError(t, err)
+	assert.Equal(t, testRoot, root)
+
+	marks, err := i.Watermarks()
+	require.NoError(t, err)
+	require.Len(t, marks, 1)
+	assert.Equal(t, uint64(81952), *marks[0].BlockSlot)
+	assert.Equal(t, uint64(2290), *marks[0].SourceEpoch)
+	assert.Equal(t, uint64(3008), *marks[0].TargetEpoch)
+}
+
+func TestInterchange_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(*Interchange)
+		expectedError string
+	}{
+		{
+			name:          "old version",
+			mutate:        func(i *Interchange) { i.Metadata.InterchangeFormatVersion = "4" },
+			expectedError: `unsupported format version "4"`,
+		},
+		{
+			name:          "short root",
+			mutate:        func(i *Interchange) { i.Metadata.GenesisValidatorsRoot = "0x00" },
+			expectedError: "genesis_validators_root must be 32 hex bytes",
+		},
+		{
+			name:          "bad pubkey",
+			mutate:        func(i *Interchange) { i.Data[0].Pubkey = "0xzz" },
+			expectedError: `pubkey "0xzz" must be 48 hex bytes`,
+		},
+		{
+			name:          "bad slot",
+			mutate:        func(i *Interchange) { i.Data[0].SignedBlocks[0].Slot = "-1" },
+			expectedError: `invalid slot "-1"`,
+		},
+		{
+			name: "source after target",
+			mutate: func(i *Interchange) {
+				i.Data[0].SignedAttestations[0].SourceEpoch = "3010"
+			},
+			expectedError: "source epoch 3010 is after target epoch 3007",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := Interchange{
+				Metadata: Metadata{InterchangeFormatVersion: FormatVersion, GenesisValidatorsRoot: testRoot},
+				Data: []Record{{
+					Pubkey:             testPubkey,
+					SignedBlocks:       []SignedBlock{{Slot: "1"}},
+					SignedAttestations: []SignedAttestation{{SourceEpoch: "1", TargetEpoch: "3007"}},
+				}},
+			}
+			tt.mutate(&i)
+
+			_, err := i.Validate()
+			if err == nil {
+				_, err = i.Watermarks()
+			}
+			assert.ErrorIs(t, err, ErrInvalid)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestMinimal(t *testing.T) {
+	slot, source, target := uint64(81952), uint64(2290), uint64(3008)
+	i := Minimal(testRoot, []Watermark{
+		{Pubkey: testPubkey, BlockSlot: &slot, SourceEpoch: &source, TargetEpoch: &target},
+		{Pubkey: "0xaa"},
+	})
+
+	out, err := json.Marshal(i)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"metadata": {"interchange_format_version": "5", "genesis_validators_root": "`+testRoot+`"},
+		"data": [
+			{
+				"pubkey": "`+testPubkey+`",
+				"signed_blocks": [{"slot": "81952"}],
+				"signed_attestations": [{"source_epoch": "2290", "target_epoch": "3008"}]
+			},
+			{"pubkey": "0xaa", "signed_blocks": [], "signed_attestations": []}
+		]
+	}`, string(out))
+}
@@ -0,0 +1,232 @@
+// Package keystore reads and decrypts EIP-2335 BLS12-381 keystores
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/bls"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Version is the only keystore version accepted
+const Version = 4
+
+// Upper bounds on the key derivation work a keystore may ask for, so a hostile
+// keystore cannot tie up the server. The scrypt bounds are the EIP-2335 defaults
+// deposit tools use; scrypt needs 128·n·r bytes, 256 MiB at these values.
+const (
+	maxScryptN      = 1 << 18
+	maxScryptR      = 8
+	maxScryptP      = 1
+	maxPBKDF2Rounds = 1 << 22
+)
+
+// ErrInvalid is returned for a keystore that does not follow EIP-2335
+var ErrInvalid = errors.New("invalid keystore")
+
+// ErrWrongPassword is returned when the password does not decrypt the keystore
+var ErrWrongPassword = errors.New("wrong keystore password")
+
+// Keystore is an EIP-2335 keystore
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description,omitempty"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+// Crypto holds the modules that protect the secret key
+type Crypto struct {
+	KDF      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Module is a keystore crypto module with its function-specific parameters
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+// kdfParams are the parameters of the scrypt and pbkdf2 key derivation functions
+type kdfParams struct {
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+	// scrypt
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+	// pbkdf2
+	C   int    `json:"c"`
+	PRF string `json:"prf"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Parse decodes and validates a keystore. It does not need the password, so a
+// keystore that parses may still fail to decrypt.
+func Parse(data []byte) (*Keystore, error) {
+	var k Keystore
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Validate checks the structure of the keystore against EIP-2335 and normalizes
+// its pubkey to lower case hex without prefix
+func (k *Keystore) Validate() error {
+	if k.Version != Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalid, k.Version)
+	}
+	if k.UUID == "" {
+		return fmt.Errorf("%w: uuid is required", ErrInvalid)
+	}
+	k.Pubkey = strings.ToLower(strings.TrimPrefix(k.Pubkey, "0x"))
+	if b, err := hex.DecodeString(k.Pubkey); err != nil || len(b) != bls.PubkeySize {
+		return fmt.Errorf("%w: pubkey must be %d hex bytes", ErrInvalid, bls.PubkeySize)
+	}
+
+	if _, err := k.kdfParams(); err != nil {
+		return err
+	}
+	if k.Crypto.Checksum.Function != "sha256" {
+		return fmt.Errorf("%w: unsupported checksum function %q", ErrInvalid, k.Crypto.Checksum.Function)
+	}
+	if b, err := hex.DecodeString(k.Crypto.Checksum.Message); err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("%w: checksum message must be %d hex bytes", ErrInvalid, sha256.Size)
+	}
+	if k.Crypto.Cipher.Function != "aes-128-ctr" {
+		return fmt.Errorf("%w: unsupported cipher function %q", ErrInvalid, k.Crypto.Cipher.Function)
+	}
+	var params cipherParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &params); err != nil {
+		return fmt.Errorf("%w: cipher params: %v", ErrInvalid, err)
+	}
+	if b, err := hex.DecodeString(params.IV); err != nil || len(b) != aes.BlockSize {
+		return fmt.Errorf("%w: cipher iv must be %d hex bytes", ErrInvalid, aes.BlockSize)
+	}
+	if b, err := hex.DecodeString(k.Crypto.Cipher.Message); err != nil || len(b) != bls.SecretKeySize {
+		return fmt.Errorf("%w: cipher message must be %d hex bytes", ErrInvalid, bls.SecretKeySize)
+	}
+	return nil
+}
+
+// kdfParams checks and returns the key derivation parameters
+func (k *Keystore) kdfParams() (*kdfParams, error) {
+	kdf := k.Crypto.KDF
+	var p kdfParams
+	if err := json.Unmarshal(kdf.Params, &p); err != nil {
+		return nil, fmt.Errorf("%w: kdf params: %v", ErrInvalid, err)
+	}
+	switch kdf.Function {
+	case "scrypt":
+		if p.N < 2 || p.N&(p.N-1) != 0 || p.N > maxScryptN || p.R < 1 || p.R > maxScryptR || p.P < 1 || p.P > maxScryptP {
+			return nil, fmt.Errorf("%w: unsupported scrypt parameters n=%d r=%d p=%d", ErrInvalid, p.N, p.R, p.P)
+		}
+	case "pbkdf2":
+		if p.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: unsupported pbkdf2 prf %q", ErrInvalid, p.PRF)
+		}
+		if p.C < 1 || p.C > maxPBKDF2Rounds {
+			return nil, fmt.Errorf("%w: unsupported pbkdf2 round count %d", ErrInvalid, p.C)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported kdf function %q", ErrInvalid, kdf.Function)
+	}
+
+	// The first half of the key decrypts, the second half checks the password.
+	// EIP-2335 keystores always derive 32 bytes; a longer key only adds work.
+	if p.DKLen != 32 {
+		return nil, fmt.Errorf("%w: kdf dklen must be 32", ErrInvalid)
+	}
+	if _, err := hex.DecodeString(p.Salt); err != nil {
+		return nil, fmt.Errorf("%w: kdf salt must be hex", ErrInvalid)
+	}
+	return &p, nil
+}
+
+// Decrypt returns the secret key after checking that it belongs to the
+// keystore's pubkey. It returns ErrWrongPassword if the checksum does not match.
+func (k *Keystore) Decrypt(password string) ([]byte, error) {
+	key, err := k.deriveKey(normalizePassword(password))
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, _ := hex.DecodeString(k.Crypto.Checksum.Message)
+	message, _ := hex.DecodeString(k.Crypto.Cipher.Message)
+	sum := sha256.Sum256(append(append([]byte{}, key[16:32]...), message...))
+	if subtle.ConstantTimeCompare(sum[:], checksum) != 1 {
+		return nil, ErrWrongPassword
+	}
+
+	var params cipherParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &params); err != nil {
+		return nil, fmt.Errorf("%w: cipher params: %v", ErrInvalid, err)
+	}
+	iv, _ := hex.DecodeString(params.IV)
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	secret := make([]byte, len(message))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, message)
+
+	pubkey, err := bls.PublicKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if hex.EncodeToString(pubkey) != k.Pubkey {
+		return nil, fmt.Errorf("%w: secret key does not match pubkey", ErrInvalid)
+	}
+	return secret, nil
+}
+
+// deriveKey runs the keystore's key derivation function over the password
+func (k *Keystore) deriveKey(password []byte) ([]byte, error) {
+	p, err := k.kdfParams()
+	if err != nil {
+		return nil, err
+	}
+	salt, _ := hex.DecodeString(p.Salt)
+	if k.Crypto.KDF.Function == "pbkdf2" {
+		return pbkdf2.Key(password, salt, p.C, p.DKLen, sha256.New), nil
+	}
+	key, err := scrypt.Key(password, salt, p.N, p.R, p.P, p.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return key, nil
+}
+
+// normalizePassword applies the EIP-2335 password processing: NFKD normalization
+// and removal of the C0, C1 and Delete control codes
+func normalizePassword(password string) []byte {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(password) {
+		if !unicode.IsControl(r) {
+			b.WriteRune(r)
+		}
+	}
+	return []byte(b.String())
+}
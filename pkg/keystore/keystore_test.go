@@ -0,0 +1,130 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The EIP-2335 test vectors
+const (
+	testPassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+	testSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	testPubkey   = "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+
+	testScryptKeystore = `{
+		"crypto": {
+			"kdf": {
+				"function": "scrypt",
+				"params": {"dklen": 32, "n": 262144, "p": 1, "r": 8, "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},
+				"message": ""
+			},
+			"checksum": {"function": "sha256", "params": {}, "message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"},
+			"cipher": {
+				"function": "aes-128-ctr",
+				"params": {"iv": "264daa3f303d7259501c93d997d84fe6"},
+				"message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"
+			}
+		},
+		"description": "This is a test keystore that uses scrypt to secure the secret.",
+		"pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+		"path": "m/12381/60/3141592653/589793238",
+		"uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+		"version": 4
+	}`
+
+	testPBKDF2Keystore = `{
+		"crypto": {
+			"kdf": {
+				"function": "pbkdf2",
+				"params": {"dklen": 32, "c": 262144, "prf": "hmac-sha256", "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},
+				"message": ""
+			},
+			"checksum": {"function": "sha256", "params": {}, "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"},
+			"cipher": {
+				"function": "aes-128-ctr",
+				"params": {"iv": "264daa3f303d7259501c93d997d84fe6"},
+				"message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+			}
+		},
+		"description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+		"pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+		"path": "m/12381/60/0/0",
+		"uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+		"version": 4
+	}`
+)
+
+func TestKeystore_Decrypt(t *testing.T) {
+	for name, data := range map[string]string{"scrypt": testScryptKeystore, "pbkdf2": testPBKDF2Keystore} {
+		t.Run(name, func(t *testing.T) {
+			k, err := Parse([]byte(data))
+			require.NoError(t, err)
+			assert.Equal(t, testPubkey, k.Pubkey)
+
+			secret, err := k.Decrypt(testPassword)
+			require.NoError(t, err)
+			assert.Equal(t, testSecret, hex.EncodeToString(secret))
+
+			_, err = k.Decrypt("testpassword")
+			assert.ErrorIs(t, err, ErrWrongPassword)
+		})
+	}
+}
+
+func TestKeystore_DecryptOtherPubkey(t *testing.T) {
+	k, err := Parse([]byte(strings.Replace(testPBKDF2Keystore, `"pubkey": "96`, `"pubkey": "0x97`, 1)))
+	require.NoError(t, err)
+
+	_, err = k.Decrypt(testPassword)
+	assert.EqualError(t, err, "invalid keystore: secret key does not match pubkey")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		old           string
+		new           string
+		expectedError string
+	}{
+		{name: "version", old: `"version": 4`, new: `"version": 3`, expectedError: "invalid keystore: unsupported version 3"},
+		{name: "missing uuid", old: `"uuid": "64625def-3331-4eea-ab6f-782f3ed16a83"`, new: `"uuid": ""`, expectedError: "invalid keystore: uuid is required"},
+		{name: "short pubkey", old: `"pubkey": "9612`, new: `"pubkey": "`, expectedError: "invalid keystore: pubkey must be 48 hex bytes"},
+		{name: "kdf", old: `"function": "pbkdf2"`, new: `"function": "argon2"`, expectedError: `invalid keystore: unsupported kdf function "argon2"`},
+		{name: "prf", old: `"prf": "hmac-sha256"`, new: `"prf": "hmac-sha512"`, expectedError: `invalid keystore: unsupported pbkdf2 prf "hmac-sha512"`},
+		{name: "round count", old: `"c": 262144`, new: `"c": 1000000000`, expectedError: "invalid keystore: unsupported pbkdf2 round count 1000000000"},
+		{name: "short dklen", old: `"dklen": 32`, new: `"dklen": 16`, expectedError: "invalid keystore: kdf dklen must be 32"},
+		{name: "long dklen", old: `"dklen": 32`, new: `"dklen": 64`, expectedError: "invalid keystore: kdf dklen must be 32"},
+		{name: "huge dklen", old: `"dklen": 32`, new: `"dklen": 1000000`, expectedError: "invalid keystore: kdf dklen must be 32"},
+		{name: "cipher", old: `"function": "aes-128-ctr"`, new: `"function": "aes-256-gcm"`, expectedError: `invalid keystore: unsupported cipher function "aes-256-gcm"`},
+		{name: "iv", old: `"iv": "264daa3f303d7259501c93d997d84fe6"`, new: `"iv": "264d"`, expectedError: "invalid keystore: cipher iv must be 16 hex bytes"},
+		{name: "not json", old: `{`, new: `[`, expectedError: "invalid keystore: invalid character ':' after array element"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(strings.Replace(testPBKDF2Keystore, tt.old, tt.new, 1)))
+			assert.ErrorIs(t, err, ErrInvalid)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+
+	scryptTests := []struct {
+		old           string
+		new           string
+		expectedError string
+	}{
+		{old: `"n": 262144`, new: `"n": 1000`, expectedError: "invalid keystore: unsupported scrypt parameters n=1000 r=8 p=1"},
+		{old: `"n": 262144`, new: `"n": 524288`, expectedError: "invalid keystore: unsupported scrypt parameters n=524288 r=8 p=1"},
+		{old: `"r": 8`, new: `"r": 16`, expectedError: "invalid keystore: unsupported scrypt parameters n=262144 r=16 p=1"},
+		{old: `"p": 1`, new: `"p": 2`, expectedError: "invalid keystore: unsupported scrypt parameters n=262144 r=8 p=2"},
+		{old: `"dklen": 32`, new: `"dklen": 1000000000`, expectedError: "invalid keystore: kdf dklen must be 32"},
+	}
+	for _, tt := range scryptTests {
+		_, err := Parse([]byte(strings.Replace(testScryptKeystore, tt.old, tt.new, 1)))
+		assert.EqualError(t, err, tt.expectedError)
+	}
+}
@@ -0,0 +1,80 @@
+package lido
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// wordSize is the size of an ABI-encoded word in bytes
+const wordSize = 32
+
+// selector returns the 4-byte function selector for a Solidity function signature
+func selector(signature string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+// encodeCall ABI-encodes a call with uint256 arguments
+func encodeCall(sel []byte, args ...uint64) []byte {
+	data := make([]byte, 4+wordSize*len(args))
+	copy(data, sel)
+	for i, arg := range args {
+		binary.BigEndian.PutUint64(data[4+wordSize*(i+1)-8:], arg)
+	}
+	return data
+}
+
+// readUint64 decodes the uint256 word at offset, failing if it does not fit in 64 bits
+func readUint64(data []byte, offset uint64) (uint64, error) {
+	if offset+wordSize > uint64(len(data)) {
+		return 0, fmt.Errorf("abi: word at offset %d out of range", offset)
+	}
+	word := data[offset : offset+wordSize]
+	for _, b := range word[:wordSize-8] {
+		if b != 0 {
+			return 0, fmt.Errorf("abi: value at offset %d overflows uint64", offset)
+		}
+	}
+	return binary.BigEndian.Uint64(word[wordSize-8:]), nil
+}
+
+// readBytes decodes the dynamic bytes value whose head word is at offset
+func readBytes(data []byte, offset uint64) ([]byte, error) {
+	start, err := readUint64(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	length, err := readUint64(data, start)
+	if err != nil {
+		return nil, err
+	}
+	begin := start + wordSize
+	if begin+length > uint64(len(data)) {
+		return nil, fmt.Errorf("abi: bytes at offset %d out of range", start)
+	}
+	return data[begin : begin+length], nil
+}
+
+// readBoolArray decodes the dynamic bool[] value whose head word is at offset
+func readBoolArray(data []byte, offset uint64) ([]bool, error) {
+	start, err := readUint64(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	length, err := readUint64(data, start)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, length)
+	for i := range out {
+		v, err := readUint64(data, start+wordSize*uint64(i+1))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v != 0
+	}
+	return out, nil
+}
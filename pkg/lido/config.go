@@ -0,0 +1,115 @@
+// Package lido checks whether validator keys are uploaded to the Lido node operator registry.
+//
+// The number of keys an operator submitted is read from the totalAddedValidators
+// field of getNodeOperator rather than from getTotalSigningKeyCount. Both return
+// the operator's total key count, and getNodeOperator also returns the vetted and
+// deposited counters the key states need, so one call per operator covers both.
+package lido
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// registryAddresses are the NodeOperatorsRegistry (curated module) proxies per network
+var registryAddresses = map[string]string{
+	"mainnet": "0x55032650b14df07b85bF18A3a3eC8E0Af2e028d5",
+	"holesky": "0x595F64Ddc3856a3b5Ff4f4CC1d1fb4B46cFd2bAC",
+}
+
+// csmAddresses are the Community Staking Module proxies per network
+var csmAddresses = map[string]string{
+	"mainnet": "0xdA7dE2ECdDfccC6c3AF10108Db212ACBBf9EA83F",
+	"holesky": "0x4562c3e63c2e586cD1651B958C22F88135aCAd4f",
+}
+
+// NetworkConfig configures the registry lookups for one Ethereum network
+type NetworkConfig struct {
+	Network string
+	RPCURL  string
+	// RegistryAddress and OperatorIDs configure the curated module
+	RegistryAddress string
+	OperatorIDs     []uint64
+	// CSMAddress and CSMOperatorIDs configure the Community Staking Module
+	CSMAddress     string
+	CSMOperatorIDs []uint64
+}
+
+// Config holds the Lido registry configuration
+type Config struct {
+	Networks []NetworkConfig
+	CacheTTL time.Duration
+}
+
+// NewConfig creates a Lido configuration from environment variables.
+// A network is enabled when LIDO_<NETWORK>_RPC_URL is set. LIDO_<NETWORK>_OPERATOR_IDS
+// and LIDO_<NETWORK>_CSM_OPERATOR_IDS list our curated and CSM node operator IDs, and
+// LIDO_<NETWORK>_REGISTRY_ADDRESS and LIDO_<NETWORK>_CSM_ADDRESS override the contracts.
+func NewConfig() (*Config, error) {
+	cfg := &Config{CacheTTL: time.Hour}
+
+	if v := os.Getenv("LIDO_CACHE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LIDO_CACHE_TTL: %w", err)
+		}
+		cfg.CacheTTL = ttl
+	}
+
+	for _, network := range []string{"mainnet", "holesky"} {
+		prefix := "LIDO_" + strings.ToUpper(network) + "_"
+		rpcURL := os.Getenv(prefix + "RPC_URL")
+		if rpcURL == "" {
+			continue
+		}
+
+		ids, err := parseOperatorIDs(os.Getenv(prefix + "OPERATOR_IDS"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sOPERATOR_IDS: %w", prefix, err)
+		}
+		csmIDs, err := parseOperatorIDs(os.Getenv(prefix + "CSM_OPERATOR_IDS"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sCSM_OPERATOR_IDS: %w", prefix, err)
+		}
+		if len(ids) == 0 && len(csmIDs) == 0 {
+			return nil, fmt.Errorf("%sOPERATOR_IDS or %sCSM_OPERATOR_IDS is required", prefix, prefix)
+		}
+
+		cfg.Networks = append(cfg.Networks, NetworkConfig{
+			Network:         network,
+			RPCURL:          rpcURL,
+			RegistryAddress: envOrDefault(prefix+"REGISTRY_ADDRESS", registryAddresses[network]),
+			OperatorIDs:     ids,
+			CSMAddress:      envOrDefault(prefix+"CSM_ADDRESS", csmAddresses[network]),
+			CSMOperatorIDs:  csmIDs,
+		})
+	}
+
+	return cfg, nil
+}
+
+func parseOperatorIDs(s string) ([]uint64, error) {
+	var ids []uint64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
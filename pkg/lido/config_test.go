@@ -0,0 +1,66 @@
+package lido
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Setenv("LIDO_MAINNET_RPC_URL", "http://geth:8545")
+	t.Setenv("LIDO_MAINNET_OPERATOR_IDS", "12, 34")
+	t.Setenv("LIDO_MAINNET_CSM_OPERATOR_IDS", "5")
+	t.Setenv("LIDO_HOLESKY_RPC_URL", "")
+	t.Setenv("LIDO_CACHE_TTL", "10m")
+
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, cfg.CacheTTL)
+	require.Len(t, cfg.Networks, 1)
+	assert.Equal(t, NetworkConfig{
+		Network:         "mainnet",
+		RPCURL:          "http://geth:8545",
+		RegistryAddress: registryAddresses["mainnet"],
+		OperatorIDs:     []uint64{12, 34},
+		CSMAddress:      csmAddresses["mainnet"],
+		CSMOperatorIDs:  []uint64{5},
+	}, cfg.Networks[0])
+}
+
+func TestNewConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{
+			name: "missing operator ids",
+			env:  map[string]string{"LIDO_HOLESKY_RPC_URL": "http://geth:8545"},
+		},
+		{
+			name: "invalid operator id",
+			env:  map[string]string{"LIDO_HOLESKY_RPC_URL": "http://geth:8545", "LIDO_HOLESKY_OPERATOR_IDS": "x"},
+		},
+		{
+			name: "invalid csm operator id",
+			env:  map[string]string{"LIDO_HOLESKY_RPC_URL": "http://geth:8545", "LIDO_HOLESKY_CSM_OPERATOR_IDS": "-1"},
+		},
+		{
+			name: "invalid cache ttl",
+			env:  map[string]string{"LIDO_CACHE_TTL": "soon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LIDO_MAINNET_RPC_URL", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := NewConfig()
+			assert.Error(t, err)
+		})
+	}
+}
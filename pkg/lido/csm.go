@@ -0,0 +1,86 @@
+package lido
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+)
+
+var (
+	csmGetNodeOperatorSelector = selector("getNodeOperator(uint256)")
+	csmGetSigningKeysSelector  = selector("getSigningKeys(uint256,uint256,uint256)")
+)
+
+// CSModule reads the Lido Community Staking Module contract and implements Module.
+// Its ABI differs from the curated registry: getNodeOperator returns a struct of
+// counters and getSigningKeys returns only the concatenated pubkeys.
+type CSModule struct {
+	rpc     *rpcClient
+	address string
+}
+
+// NewCSModule creates a CSM reader calling the contract at address through rpcURL
+func NewCSModule(rpcURL, address string) *CSModule {
+	return &CSModule{rpc: newRPCClient(rpcURL), address: address}
+}
+
+// NodeOperator returns the key counters of a CSM node operator
+func (m *CSModule) NodeOperator(ctx context.Context, operatorID uint64) (NodeOperator, error) {
+	out, err := m.rpc.Call(ctx, m.address, encodeCall(csmGetNodeOperatorSelector, operatorID))
+	if err != nil {
+		return NodeOperator{}, fmt.Errorf("CSM getNodeOperator(%d): %w", operatorID, err)
+	}
+
+	// The NodeOperator struct is static, so its fields are encoded in place:
+	// totalAddedKeys, totalWithdrawnKeys, totalDepositedKeys, totalVettedKeys,
+	// stuckValidatorsCount, depositableValidatorsCount, targetLimit, targetLimitMode,
+	// totalExitedKeys, enqueuedCount, then addresses and flags
+	field := func(i int) (uint64, error) {
+		return readUint64(out, uint64(i*wordSize))
+	}
+
+	var op NodeOperator
+	for _, f := range []struct {
+		index int
+		dest  *uint64
+	}{
+		{0, &op.TotalAdded},
+		{2, &op.TotalDeposited},
+		{3, &op.TotalVetted},
+		{8, &op.TotalExited},
+	} {
+		if *f.dest, err = field(f.index); err != nil {
+			return NodeOperator{}, fmt.Errorf("CSM getNodeOperator(%d): %w", operatorID, err)
+		}
+	}
+	// CSM has no per-operator active flag; operators with keys are active
+	op.Active = op.TotalAdded > 0
+
+	return op, nil
+}
+
+// SigningKeys returns limit keys of a CSM node operator starting at offset
+func (m *CSModule) SigningKeys(ctx context.Context, operatorID, offset, limit uint64) ([]SigningKey, error) {
+	out, err := m.rpc.Call(ctx, m.address, encodeCall(csmGetSigningKeysSelector, operatorID, offset, limit))
+	if err != nil {
+		return nil, fmt.Errorf("CSM getSigningKeys(%d, %d, %d): %w", operatorID, offset, limit, err)
+	}
+
+	pubkeys, err := readBytes(out, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubkeys)%pubkeyLength != 0 {
+		return nil, fmt.Errorf("CSM getSigningKeys: pubkeys length %d is not a multiple of %d", len(pubkeys), pubkeyLength)
+	}
+
+	keys := make([]SigningKey, len(pubkeys)/pubkeyLength)
+	for i := range keys {
+		keys[i] = SigningKey{
+			Pubkey: "0x" + hex.EncodeToString(pubkeys[i*pubkeyLength:(i+1)*pubkeyLength]),
+			Index:  offset + uint64(i),
+		}
+	}
+
+	return keys, nil
+}
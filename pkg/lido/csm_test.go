@@ -0,0 +1,34 @@
+package lido
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestCSModule(t *testing.T) {
+	srv := newLidoStub(t, nil, map[uint64]operatorKeys{
+		2: {
+			pubkeys:   []string{testPubkey(7), testPubkey(8), testPubkey(9)},
+			vetted:    3,
+			deposited: 2,
+			exited:    1,
+		},
+	})
+	csm := NewCSModule(srv.URL, testCSMAddress)
+
+	op, err := csm.NodeOperator(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, NodeOperator{Active: true, TotalAdded: 3, TotalVetted: 3, TotalDeposited: 2, TotalExited: 1}, op)
+
+	keys, err := AllSigningKeys(t.Context(), csm, 2, op.TotalAdded, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []SigningKey{
+		{Pubkey: testPubkey(7), Index: 0},
+		{Pubkey: testPubkey(8), Index: 1},
+		{Pubkey: testPubkey(9), Index: 2},
+	}, keys)
+	assert.Equal(t, models.LidoKeyStateVetted, op.KeyState(2))
+}
@@ -0,0 +1,26 @@
+package lido
+
+import "context"
+
+// Module is a Lido staking module contract holding node operator keys
+type Module interface {
+	// NodeOperator returns the key counters of a node operator
+	NodeOperator(ctx context.Context, operatorID uint64) (NodeOperator, error)
+
+	// SigningKeys returns limit keys of a node operator starting at offset
+	SigningKeys(ctx context.Context, operatorID, offset, limit uint64) ([]SigningKey, error)
+}
+
+// AllSigningKeys returns the first total keys of a node operator, fetched in batches
+func AllSigningKeys(ctx context.Context, m Module, operatorID, total, batchSize uint64) ([]SigningKey, error) {
+	keys := make([]SigningKey, 0, total)
+	for offset := uint64(0); offset < total; offset += batchSize {
+		limit := min(batchSize, total-offset)
+		batch, err := m.SigningKeys(ctx, operatorID, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+	}
+	return keys, nil
+}
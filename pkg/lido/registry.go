@@ -0,0 +1,127 @@
+package lido
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// pubkeyLength is the size of a BLS public key in bytes
+const pubkeyLength = 48
+
+var (
+	getSigningKeysSelector  = selector("getSigningKeys(uint256,uint256,uint256)")
+	getNodeOperatorSelector = selector("getNodeOperator(uint256,bool)")
+)
+
+// NodeOperator holds a node operator's key counters from the registry.
+// Keys are used in index order, so the counters split the key list into ranges:
+// [0, TotalDeposited) are deposited, [TotalDeposited, TotalVetted) are vetted and
+// waiting for a deposit, and [TotalVetted, TotalAdded) are not yet vetted.
+type NodeOperator struct {
+	Active         bool
+	TotalVetted    uint64
+	TotalExited    uint64
+	TotalAdded     uint64
+	TotalDeposited uint64
+}
+
+// KeyState returns the lifecycle state of the key at index
+func (o NodeOperator) KeyState(index uint64) string {
+	switch {
+	case index < o.TotalDeposited:
+		return models.LidoKeyStateDeposited
+	case index < o.TotalVetted:
+		return models.LidoKeyStateVetted
+	default:
+		return models.LidoKeyStateUnvetted
+	}
+}
+
+// SigningKey is a validator key submitted to a staking module
+type SigningKey struct {
+	Pubkey string
+	Index  uint64
+	// Used is only reported by the curated registry
+	Used bool
+}
+
+// Registry reads the Lido curated NodeOperatorsRegistry contract and implements Module
+type Registry struct {
+	rpc     *rpcClient
+	address string
+}
+
+// NewRegistry creates a registry reader calling the contract at address through rpcURL
+func NewRegistry(rpcURL, address string) *Registry {
+	return &Registry{rpc: newRPCClient(rpcURL), address: address}
+}
+
+// NodeOperator returns the key counters of a node operator. TotalAdded is the
+// value getTotalSigningKeyCount returns.
+func (r *Registry) NodeOperator(ctx context.Context, operatorID uint64) (NodeOperator, error) {
+	out, err := r.rpc.Call(ctx, r.address, encodeCall(getNodeOperatorSelector, operatorID, 0))
+	if err != nil {
+		return NodeOperator{}, fmt.Errorf("getNodeOperator(%d): %w", operatorID, err)
+	}
+
+	// Returns (bool active, string name, address rewardAddress, uint64 totalVettedValidators,
+	// uint64 totalExitedValidators, uint64 totalAddedValidators, uint64 totalDepositedValidators)
+	var words [7]uint64
+	for i := range words {
+		if i == 1 || i == 2 {
+			// Skip the name offset and the reward address
+			continue
+		}
+		if words[i], err = readUint64(out, uint64(i*wordSize)); err != nil {
+			return NodeOperator{}, fmt.Errorf("getNodeOperator(%d): %w", operatorID, err)
+		}
+	}
+
+	return NodeOperator{
+		Active:         words[0] != 0,
+		TotalVetted:    words[3],
+		TotalExited:    words[4],
+		TotalAdded:     words[5],
+		TotalDeposited: words[6],
+	}, nil
+}
+
+// SigningKeys returns limit keys of a node operator starting at offset
+func (r *Registry) SigningKeys(ctx context.Context, operatorID, offset, limit uint64) ([]SigningKey, error) {
+	out, err := r.rpc.Call(ctx, r.address, encodeCall(getSigningKeysSelector, operatorID, offset, limit))
+	if err != nil {
+		return nil, fmt.Errorf("getSigningKeys(%d, %d, %d): %w", operatorID, offset, limit, err)
+	}
+
+	// Returns (bytes pubkeys, bytes signatures, bool[] used)
+	pubkeys, err := readBytes(out, 0)
+	if err != nil {
+		return nil, err
+	}
+	used, err := readBoolArray(out, 2*wordSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubkeys)%pubkeyLength != 0 {
+		return nil, fmt.Errorf("getSigningKeys: pubkeys length %d is not a multiple of %d", len(pubkeys), pubkeyLength)
+	}
+
+	count := len(pubkeys) / pubkeyLength
+	if len(used) != count {
+		return nil, fmt.Errorf("getSigningKeys: got %d pubkeys but %d used flags", count, len(used))
+	}
+
+	keys := make([]SigningKey, count)
+	for i := range keys {
+		keys[i] = SigningKey{
+			Pubkey: "0x" + hex.EncodeToString(pubkeys[i*pubkeyLength:(i+1)*pubkeyLength]),
+			Index:  offset + uint64(i),
+			Used:   used[i],
+		}
+	}
+
+	return keys, nil
+}
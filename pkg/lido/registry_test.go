@@ -0,0 +1,267 @@
+package lido
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+const (
+	testRegistryAddress = "0x55032650b14df07b85bF18A3a3eC8E0Af2e028d5"
+	testCSMAddress      = "0xdA7dE2ECdDfccC6c3AF10108Db212ACBBf9EA83F"
+)
+
+// testPubkey returns a deterministic 48-byte pubkey
+func testPubkey(b byte) string {
+	return "0x" + strings.Repeat(hex.EncodeToString([]byte{b}), pubkeyLength)
+}
+
+func word(v uint64) []byte {
+	w := make([]byte, wordSize)
+	binary.BigEndian.PutUint64(w[wordSize-8:], v)
+	return w
+}
+
+// padded right-pads b to a multiple of the word size
+func padded(b []byte) []byte {
+	if rem := len(b) % wordSize; rem != 0 {
+		b = append(b, make([]byte, wordSize-rem)...)
+	}
+	return b
+}
+
+// encodeSigningKeys ABI-encodes the (bytes, bytes, bool[]) result of getSigningKeys
+func encodeSigningKeys(pubkeys []string, used []bool) []byte {
+	var keyBytes []byte
+	for _, pk := range pubkeys {
+		b, _ := hex.DecodeString(strings.TrimPrefix(pk, "0x"))
+		keyBytes = append(keyBytes, b...)
+	}
+	sigBytes := make([]byte, 96*len(pubkeys))
+
+	keysPart := append(word(uint64(len(keyBytes))), padded(keyBytes)...)
+	sigsPart := append(word(uint64(len(sigBytes))), padded(sigBytes)...)
+	usedPart := word(uint64(len(used)))
+	for _, u := range used {
+		if u {
+			usedPart = append(usedPart, word(1)...)
+		} else {
+			usedPart = append(usedPart, word(0)...)
+		}
+	}
+
+	head := 3 * wordSize
+	var out bytes.Buffer
+	out.Write(word(uint64(head)))
+	out.Write(word(uint64(head + len(keysPart))))
+	out.Write(word(uint64(head + len(keysPart) + len(sigsPart))))
+	out.Write(keysPart)
+	out.Write(sigsPart)
+	out.Write(usedPart)
+	return out.Bytes()
+}
+
+// operatorKeys is a node operator's keys and counters in the registry stub
+type operatorKeys struct {
+	pubkeys   []string
+	used      []bool
+	vetted    uint64
+	deposited uint64
+	exited    uint64
+}
+
+// encodeNodeOperator ABI-encodes the getNodeOperator result with an empty name
+func encodeNodeOperator(op operatorKeys) []byte {
+	var out bytes.Buffer
+	out.Write(word(1))
+	out.Write(word(7 * wordSize))
+	out.Write(make([]byte, wordSize))
+	out.Write(word(op.vetted))
+	out.Write(word(op.exited))
+	out.Write(word(uint64(len(op.pubkeys))))
+	out.Write(word(op.deposited))
+	out.Write(word(0))
+	return out.Bytes()
+}
+
+// encodeCSMNodeOperator ABI-encodes the static NodeOperator struct returned by CSM
+func encodeCSMNodeOperator(op operatorKeys) []byte {
+	fields := make([]uint64, 15)
+	fields[0] = uint64(len(op.pubkeys))
+	fields[2] = op.deposited
+	fields[3] = op.vetted
+	fields[8] = op.exited
+
+	var out bytes.Buffer
+	for _, f := range fields {
+		out.Write(word(f))
+	}
+	return out.Bytes()
+}
+
+// encodeCSMSigningKeys ABI-encodes the bytes result of CSM getSigningKeys
+func encodeCSMSigningKeys(pubkeys []string) []byte {
+	var keyBytes []byte
+	for _, pk := range pubkeys {
+		b, _ := hex.DecodeString(strings.TrimPrefix(pk, "0x"))
+		keyBytes = append(keyBytes, b...)
+	}
+	out := word(wordSize)
+	out = append(out, word(uint64(len(keyBytes)))...)
+	return append(out, padded(keyBytes)...)
+}
+
+// newRegistryStub starts a JSON-RPC server answering eth_call for the curated registry
+func newRegistryStub(t *testing.T, operators map[uint64]operatorKeys) *httptest.Server {
+	t.Helper()
+	return newLidoStub(t, operators, nil)
+}
+
+// newLidoStub starts a JSON-RPC server answering eth_call for the curated registry and CSM
+func newLidoStub(t *testing.T, curated, csm map[uint64]operatorKeys) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+
+		var call struct {
+			To   string `json:"to"`
+			Data string `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &call))
+
+		data, err := hex.DecodeString(strings.TrimPrefix(call.Data, "0x"))
+		require.NoError(t, err)
+
+		reply := func(result []byte) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  "0x" + hex.EncodeToString(result),
+			})
+		}
+
+		operatorID, _ := readUint64(data[4:], 0)
+		offset, _ := readUint64(data[4:], wordSize)
+		limit, _ := readUint64(data[4:], 2*wordSize)
+		end := offset + limit
+
+		switch {
+		case call.To == testCSMAddress && bytes.Equal(data[:4], csmGetNodeOperatorSelector):
+			reply(encodeCSMNodeOperator(csm[operatorID]))
+		case call.To == testCSMAddress && bytes.Equal(data[:4], csmGetSigningKeysSelector):
+			reply(encodeCSMSigningKeys(csm[operatorID].pubkeys[offset:end]))
+		case call.To != testRegistryAddress:
+			t.Errorf("unexpected contract %s", call.To)
+		case bytes.Equal(data[:4], getNodeOperatorSelector):
+			reply(encodeNodeOperator(curated[operatorID]))
+		case bytes.Equal(data[:4], getSigningKeysSelector):
+			op := curated[operatorID]
+			reply(encodeSigningKeys(op.pubkeys[offset:end], op.used[offset:end]))
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"error":   map[string]interface{}{"code": -32000, "message": "execution reverted"},
+			})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// newCountingProxy forwards requests to target and counts them
+func newCountingProxy(t *testing.T, target string, calls *int) string {
+	t.Helper()
+
+	u, err := url.Parse(target)
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(u)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func TestSelector(t *testing.T) {
+	assert.Equal(t, "a9059cbb", hex.EncodeToString(selector("transfer(address,uint256)")))
+}
+
+func TestRegistry_AllSigningKeys(t *testing.T) {
+	srv := newRegistryStub(t, map[uint64]operatorKeys{
+		7: {
+			pubkeys: []string{testPubkey(1), testPubkey(2), testPubkey(3)},
+			used:    []bool{true, true, false},
+		},
+	})
+	registry := NewRegistry(srv.URL, testRegistryAddress)
+
+	op, err := registry.NodeOperator(t.Context(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), op.TotalAdded)
+
+	// A batch size of 2 forces two getSigningKeys calls
+	keys, err := AllSigningKeys(t.Context(), registry, 7, op.TotalAdded, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []SigningKey{
+		{Pubkey: testPubkey(1), Index: 0, Used: true},
+		{Pubkey: testPubkey(2), Index: 1, Used: true},
+		{Pubkey: testPubkey(3), Index: 2, Used: false},
+	}, keys)
+
+	keys, err = AllSigningKeys(t.Context(), registry, 8, 0, 2)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestRegistry_NodeOperator(t *testing.T) {
+	srv := newRegistryStub(t, map[uint64]operatorKeys{
+		7: {
+			pubkeys:   []string{testPubkey(1), testPubkey(2), testPubkey(3), testPubkey(4)},
+			used:      []bool{true, false, false, false},
+			vetted:    3,
+			deposited: 1,
+		},
+	})
+
+	op, err := NewRegistry(srv.URL, testRegistryAddress).NodeOperator(t.Context(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, NodeOperator{Active: true, TotalVetted: 3, TotalAdded: 4, TotalDeposited: 1}, op)
+
+	assert.Equal(t, models.LidoKeyStateDeposited, op.KeyState(0))
+	assert.Equal(t, models.LidoKeyStateVetted, op.KeyState(1))
+	assert.Equal(t, models.LidoKeyStateVetted, op.KeyState(2))
+	assert.Equal(t, models.LidoKeyStateUnvetted, op.KeyState(3))
+}
+
+func TestRegistry_RPCError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewRegistry(srv.URL, testRegistryAddress).NodeOperator(t.Context(), 1)
+	assert.ErrorContains(t, err, "execution reverted")
+}
@@ -0,0 +1,95 @@
+package lido
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// rpcClient is a minimal execution layer JSON-RPC client supporting eth_call
+type rpcClient struct {
+	url        string
+	httpClient *http.Client
+	nextID     atomic.Int64
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int64         `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newRPCClient(url string) *rpcClient {
+	return &rpcClient{
+		url:        url,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Call executes a read-only contract call against the latest block
+func (c *rpcClient) Call(ctx context.Context, to string, data []byte) ([]byte, error) {
+	call := map[string]string{
+		"to":   to,
+		"data": "0x" + hex.EncodeToString(data),
+	}
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.nextID.Add(1),
+		Method:  "eth_call",
+		Params:  []interface{}{call, "latest"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode eth_call: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("eth_call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("eth_call returned status %d", resp.StatusCode)
+	}
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode eth_call response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("eth_call error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+
+	var result string
+	if err := json.Unmarshal(rpcResp.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode eth_call result: %w", err)
+	}
+
+	out, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid eth_call result: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,276 @@
+package lido
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// keyBatchSize is the number of keys requested per getSigningKeys call
+const keyBatchSize = 100
+
+// OperatorSummary reports how a node operator's submitted keys are used
+type OperatorSummary struct {
+	Network        string `json:"network"`
+	Module         string `json:"module"`
+	OperatorID     uint64 `json:"operator_id"`
+	Active         bool   `json:"active"`
+	TotalAdded     uint64 `json:"total_added"`
+	TotalVetted    uint64 `json:"total_vetted"`
+	TotalDeposited uint64 `json:"total_deposited"`
+	TotalExited    uint64 `json:"total_exited"`
+	// Available is the number of vetted keys still waiting for a deposit
+	Available uint64 `json:"available"`
+	// Unvetted is the number of submitted keys above the operator's staking limit
+	Unvetted uint64 `json:"unvetted"`
+}
+
+// newOperatorSummary derives the key usage counts of a node operator
+func newOperatorSummary(networkName, module string, operatorID uint64, op NodeOperator) OperatorSummary {
+	return OperatorSummary{
+		Network:        networkName,
+		Module:         module,
+		OperatorID:     operatorID,
+		Active:         op.Active,
+		TotalAdded:     op.TotalAdded,
+		TotalVetted:    op.TotalVetted,
+		TotalDeposited: op.TotalDeposited,
+		TotalExited:    op.TotalExited,
+		Available:      saturatingSub(op.TotalVetted, op.TotalDeposited),
+		Unvetted:       saturatingSub(op.TotalAdded, op.TotalVetted),
+	}
+}
+
+// moduleOperators are our node operators within one staking module
+type moduleOperators struct {
+	name        string
+	module      Module
+	operatorIDs []uint64
+}
+
+// network caches the operator key sets of one network's staking modules
+type network struct {
+	name    string
+	modules []moduleOperators
+
+	mu        sync.Mutex
+	keys      map[string]models.LidoStatus
+	operators []OperatorSummary
+	fetchedAt time.Time
+}
+
+// Syncer stores the Lido registry status of every validator on the configured networks
+type Syncer struct {
+	repo          models.ValidatorRepo
+	networks      map[string]*network
+	ttl           time.Duration
+	completeHooks []func(ctx context.Context, err error)
+}
+
+// NewSyncer creates a syncer for the configured networks
+func NewSyncer(repo models.ValidatorRepo, cfg *Config) *Syncer {
+	s := &Syncer{repo: repo, networks: make(map[string]*network), ttl: cfg.CacheTTL}
+	for _, nc := range cfg.Networks {
+		n := &network{name: nc.Network}
+		if len(nc.OperatorIDs) > 0 {
+			n.modules = append(n.modules, moduleOperators{
+				name:        models.LidoModuleCurated,
+				module:      NewRegistry(nc.RPCURL, nc.RegistryAddress),
+				operatorIDs: nc.OperatorIDs,
+			})
+		}
+		if len(nc.CSMOperatorIDs) > 0 {
+			n.modules = append(n.modules, moduleOperators{
+				name:        models.LidoModuleCSM,
+				module:      NewCSModule(nc.RPCURL, nc.CSMAddress),
+				operatorIDs: nc.CSMOperatorIDs,
+			})
+		}
+		s.networks[nc.Network] = n
+	}
+	return s
+}
+
+// OnComplete registers a function called after every run started by Start, with
+// the error of the run or nil if it succeeded
+func (s *Syncer) OnComplete(f func(ctx context.Context, err error)) {
+	s.completeHooks = append(s.completeHooks, f)
+}
+
+// Lookup returns the registry status of a pubkey on the given network
+func (s *Syncer) Lookup(ctx context.Context, networkName, pubkey string) (models.LidoStatus, error) {
+	keys, err := s.Keys(ctx, networkName)
+	if err != nil {
+		return models.LidoStatus{}, err
+	}
+	return keys[pubkey], nil
+}
+
+// Networks returns the configured network names in sorted order
+func (s *Syncer) Networks() []string {
+	names := make([]string, 0, len(s.networks))
+	for name := range s.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Keys returns the registry status of every key our node operators uploaded on
+// the given network. The returned map is shared and must not be modified.
+func (s *Syncer) Keys(ctx context.Context, networkName string) (map[string]models.LidoStatus, error) {
+	n, ok := s.networks[networkName]
+	if !ok {
+		return nil, fmt.Errorf("lido is not configured for network %q", networkName)
+	}
+	return s.operatorKeys(ctx, n)
+}
+
+// Summaries returns the key usage of every configured node operator, ordered by network
+func (s *Syncer) Summaries(ctx context.Context) ([]OperatorSummary, error) {
+	var summaries []OperatorSummary
+	for _, name := range s.Networks() {
+		n := s.networks[name]
+		if _, err := s.operatorKeys(ctx, n); err != nil {
+			return nil, fmt.Errorf("lido %s: %w", name, err)
+		}
+		n.mu.Lock()
+		summaries = append(summaries, n.operators...)
+		n.mu.Unlock()
+	}
+	return summaries, nil
+}
+
+// Run refreshes the Lido fields of every stored Ethereum validator on the configured networks.
+// A failing network does not stop the others from being processed.
+func (s *Syncer) Run(ctx context.Context) error {
+	var errs []error
+	for name, n := range s.networks {
+		if err := s.syncNetwork(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("lido %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start runs the syncer immediately and then on every interval until ctx is done
+func (s *Syncer) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Run(ctx)
+		if err != nil {
+			log.Printf("Lido registry sync failed: %v", err)
+		}
+		for _, f := range s.completeHooks {
+			f(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Syncer) syncNetwork(ctx context.Context, n *network) error {
+	keys, err := s.operatorKeys(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	validators, err := s.repo.List(ctx, map[string]interface{}{
+		"blockchain":         "ethereum",
+		"blockchain_network": n.name,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range validators {
+		status := keys[v.Pubkey]
+		if sameLidoStatus(v, status) {
+			continue
+		}
+		err := s.repo.UpdateLido(ctx, v.Pubkey, status)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// operatorKeys returns the cached key set of our operators, refetching it once it expires
+func (s *Syncer) operatorKeys(ctx context.Context, n *network) (map[string]models.LidoStatus, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.keys != nil && time.Since(n.fetchedAt) < s.ttl {
+		return n.keys, nil
+	}
+
+	keys := make(map[string]models.LidoStatus)
+	var operators []OperatorSummary
+	for _, m := range n.modules {
+		for _, operatorID := range m.operatorIDs {
+			op, err := m.module.NodeOperator(ctx, operatorID)
+			if err != nil {
+				return nil, err
+			}
+			operators = append(operators, newOperatorSummary(n.name, m.name, operatorID, op))
+
+			signingKeys, err := AllSigningKeys(ctx, m.module, operatorID, op.TotalAdded, keyBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range signingKeys {
+				id, index := int64(operatorID), int64(k.Index)
+				keys[k.Pubkey] = models.LidoStatus{
+					Uploaded:   true,
+					Module:     m.name,
+					OperatorID: &id,
+					KeyIndex:   &index,
+					KeyState:   op.KeyState(k.Index),
+				}
+			}
+		}
+	}
+
+	n.keys = keys
+	n.operators = operators
+	n.fetchedAt = time.Now()
+	return keys, nil
+}
+
+// sameLidoStatus reports whether v already stores status
+func sameLidoStatus(v models.Validator, status models.LidoStatus) bool {
+	return v.LidoUploaded == status.Uploaded &&
+		v.LidoModule == status.Module &&
+		v.LidoKeyState == status.KeyState &&
+		equalInt64Ptr(v.LidoOperatorID, status.OperatorID) &&
+		equalInt64Ptr(v.LidoKeyIndex, status.KeyIndex)
+}
+
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func saturatingSub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,170 @@
+package lido
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestSyncer_Run(t *testing.T) {
+	srv := newRegistryStub(t, map[uint64]operatorKeys{
+		7: {pubkeys: []string{testPubkey(1), testPubkey(2)}, used: []bool{true, false}, vetted: 2, deposited: 1},
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), map[string]interface{}{
+		"blockchain":         "ethereum",
+		"blockchain_network": "holesky",
+	}).Return([]models.Validator{
+		// Newly uploaded key
+		{Pubkey: testPubkey(1)},
+		// Already up to date
+		{Pubkey: testPubkey(2), LidoUploaded: true, LidoOperatorID: int64Ptr(7), LidoKeyIndex: int64Ptr(1), LidoKeyState: "vetted",
+			LidoModule: models.LidoModuleCurated},
+		// Removed from the registry
+		{Pubkey: testPubkey(9), LidoUploaded: true, LidoOperatorID: int64Ptr(7), LidoKeyIndex: int64Ptr(5)},
+	}, nil)
+	mockRepo.EXPECT().UpdateLido(gomock.Any(), testPubkey(1), models.LidoStatus{
+		Uploaded:   true,
+		Module:     models.LidoModuleCurated,
+		OperatorID: int64Ptr(7),
+		KeyIndex:   int64Ptr(0),
+		KeyState:   models.LidoKeyStateDeposited,
+	}).Return(nil)
+	mockRepo.EXPECT().UpdateLido(gomock.Any(), testPubkey(9), models.LidoStatus{}).Return(nil)
+
+	syncer := NewSyncer(mockRepo, &Config{
+		CacheTTL: time.Hour,
+		Networks: []NetworkConfig{{
+			Network:         "holesky",
+			RPCURL:          srv.URL,
+			RegistryAddress: testRegistryAddress,
+			OperatorIDs:     []uint64{7},
+		}},
+	})
+	require.NoError(t, syncer.Run(t.Context()))
+}
+
+func TestSyncer_LookupUsesCache(t *testing.T) {
+	var calls int
+	stub := newRegistryStub(t, map[uint64]operatorKeys{
+		3: {pubkeys: []string{testPubkey(4)}, used: []bool{false}, vetted: 1},
+	})
+	counting := newCountingProxy(t, stub.URL, &calls)
+
+	syncer := NewSyncer(nil, &Config{
+		CacheTTL: time.Hour,
+		Networks: []NetworkConfig{{
+			Network:         "mainnet",
+			RPCURL:          counting,
+			RegistryAddress: testRegistryAddress,
+			OperatorIDs:     []uint64{3},
+		}},
+	})
+
+	status, err := syncer.Lookup(t.Context(), "mainnet", testPubkey(4))
+	require.NoError(t, err)
+	assert.True(t, status.Uploaded)
+	assert.Equal(t, int64(3), *status.OperatorID)
+	assert.Equal(t, int64(0), *status.KeyIndex)
+	assert.Equal(t, models.LidoKeyStateVetted, status.KeyState)
+
+	status, err = syncer.Lookup(t.Context(), "mainnet", testPubkey(5))
+	require.NoError(t, err)
+	assert.False(t, status.Uploaded)
+
+	// One getNodeOperator and one getSigningKeys call for both lookups and the key set
+	keys, err := syncer.Keys(t.Context(), "mainnet")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{"mainnet"}, syncer.Networks())
+
+	_, err = syncer.Lookup(t.Context(), "holesky", testPubkey(4))
+	assert.Error(t, err)
+}
+
+func TestSyncer_Summaries(t *testing.T) {
+	srv := newRegistryStub(t, map[uint64]operatorKeys{
+		7: {
+			pubkeys:   []string{testPubkey(1), testPubkey(2), testPubkey(3), testPubkey(4), testPubkey(5)},
+			used:      []bool{true, true, false, false, false},
+			vetted:    4,
+			deposited: 2,
+			exited:    1,
+		},
+	})
+
+	syncer := NewSyncer(nil, &Config{
+		CacheTTL: time.Hour,
+		Networks: []NetworkConfig{{
+			Network:         "mainnet",
+			RPCURL:          srv.URL,
+			RegistryAddress: testRegistryAddress,
+			OperatorIDs:     []uint64{7},
+		}},
+	})
+
+	summaries, err := syncer.Summaries(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []OperatorSummary{{
+		Network:        "mainnet",
+		Module:         models.LidoModuleCurated,
+		OperatorID:     7,
+		Active:         true,
+		TotalAdded:     5,
+		TotalVetted:    4,
+		TotalDeposited: 2,
+		TotalExited:    1,
+		Available:      2,
+		Unvetted:       1,
+	}}, summaries)
+}
+
+func TestSyncer_CuratedAndCSM(t *testing.T) {
+	// Operator IDs overlap between modules, so both use ID 1 here
+	srv := newLidoStub(t,
+		map[uint64]operatorKeys{1: {pubkeys: []string{testPubkey(1)}, used: []bool{true}, vetted: 1, deposited: 1}},
+		map[uint64]operatorKeys{1: {pubkeys: []string{testPubkey(2)}, vetted: 1}},
+	)
+
+	syncer := NewSyncer(nil, &Config{
+		CacheTTL: time.Hour,
+		Networks: []NetworkConfig{{
+			Network:         "mainnet",
+			RPCURL:          srv.URL,
+			RegistryAddress: testRegistryAddress,
+			OperatorIDs:     []uint64{1},
+			CSMAddress:      testCSMAddress,
+			CSMOperatorIDs:  []uint64{1},
+		}},
+	})
+
+	curated, err := syncer.Lookup(t.Context(), "mainnet", testPubkey(1))
+	require.NoError(t, err)
+	assert.Equal(t, models.LidoModuleCurated, curated.Module)
+	assert.Equal(t, models.LidoKeyStateDeposited, curated.KeyState)
+
+	csm, err := syncer.Lookup(t.Context(), "mainnet", testPubkey(2))
+	require.NoError(t, err)
+	assert.Equal(t, models.LidoModuleCSM, csm.Module)
+	assert.Equal(t, models.LidoKeyStateVetted, csm.KeyState)
+
+	summaries, err := syncer.Summaries(t.Context())
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, models.LidoModuleCurated, summaries[0].Module)
+	assert.Equal(t, models.LidoModuleCSM, summaries[1].Module)
+}
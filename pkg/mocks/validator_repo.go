@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/zheli/validator-key-manager-backend/pkg/models"
@@ -49,6 +50,21 @@ func (mr *MockValidatorRepoMockRecorder) Create(ctx, v interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockValidatorRepo)(nil).Create), ctx, v)
 }
 
+// GetByIndex mocks base method.
+func (m *MockValidatorRepo) GetByIndex(ctx context.Context, network string, index int64) (*models.Validator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIndex", ctx, network, index)
+	ret0, _ := ret[0].(*models.Validator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIndex indicates an expected call of GetByIndex.
+func (mr *MockValidatorRepoMockRecorder) GetByIndex(ctx, network, index interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIndex", reflect.TypeOf((*MockValidatorRepo)(nil).GetByIndex), ctx, network, index)
+}
+
 // GetByPubkey mocks base method.
 func (m *MockValidatorRepo) GetByPubkey(ctx context.Context, pubkey string) (*models.Validator, error) {
 	m.ctrl.T.Helper()
@@ -79,8 +95,94 @@ func (mr *MockValidatorRepoMockRecorder) List(ctx, filters interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockValidatorRepo)(nil).List), ctx, filters)
 }
 
+// ListCredentialChanges mocks base method.
+func (m *MockValidatorRepo) ListCredentialChanges(ctx context.Context, pubkey string) ([]models.WithdrawalCredentialChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCredentialChanges", ctx, pubkey)
+	ret0, _ := ret[0].([]models.WithdrawalCredentialChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCredentialChanges indicates an expected call of ListCredentialChanges.
+func (mr *MockValidatorRepoMockRecorder) ListCredentialChanges(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCredentialChanges", reflect.TypeOf((*MockValidatorRepo)(nil).ListCredentialChanges), ctx, pubkey)
+}
+
+// SearchByPubkeyPrefix mocks base method.
+func (m *MockValidatorRepo) SearchByPubkeyPrefix(ctx context.Context, prefix string, limit int) ([]models.Validator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByPubkeyPrefix", ctx, prefix, limit)
+	ret0, _ := ret[0].([]models.Validator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchByPubkeyPrefix indicates an expected call of SearchByPubkeyPrefix.
+func (mr *MockValidatorRepoMockRecorder) SearchByPubkeyPrefix(ctx, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByPubkeyPrefix", reflect.TypeOf((*MockValidatorRepo)(nil).SearchByPubkeyPrefix), ctx, prefix, limit)
+}
+
+// TransitionStatus mocks base method.
+func (m *MockValidatorRepo) TransitionStatus(ctx context.Context, pubkey string, status models.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionStatus", ctx, pubkey, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransitionStatus indicates an expected call of TransitionStatus.
+func (mr *MockValidatorRepoMockRecorder) TransitionStatus(ctx, pubkey, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionStatus", reflect.TypeOf((*MockValidatorRepo)(nil).TransitionStatus), ctx, pubkey, status)
+}
+
+// UpdateBeacon mocks base method.
+func (m *MockValidatorRepo) UpdateBeacon(ctx context.Context, pubkey string, meta models.BeaconMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBeacon", ctx, pubkey, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBeacon indicates an expected call of UpdateBeacon.
+func (mr *MockValidatorRepoMockRecorder) UpdateBeacon(ctx, pubkey, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBeacon", reflect.TypeOf((*MockValidatorRepo)(nil).UpdateBeacon), ctx, pubkey, meta)
+}
+
+// UpdateClient mocks base method.
+func (m *MockValidatorRepo) UpdateClient(ctx context.Context, pubkey, client, instance string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClient", ctx, pubkey, client, instance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateClient indicates an expected call of UpdateClient.
+func (mr *MockValidatorRepoMockRecorder) UpdateClient(ctx, pubkey, client, instance interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClient", reflect.TypeOf((*MockValidatorRepo)(nil).UpdateClient), ctx, pubkey, client, instance)
+}
+
+// UpdateLido mocks base method.
+func (m *MockValidatorRepo) UpdateLido(ctx context.Context, pubkey string, status models.LidoStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLido", ctx, pubkey, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLido indicates an expected call of UpdateLido.
+func (mr *MockValidatorRepoMockRecorder) UpdateLido(ctx, pubkey, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLido", reflect.TypeOf((*MockValidatorRepo)(nil).UpdateLido), ctx, pubkey, status)
+}
+
 // UpdateStatus mocks base method.
-func (m *MockValidatorRepo) UpdateStatus(ctx context.Context, pubkey, status string) error {
+func (m *MockValidatorRepo) UpdateStatus(ctx context.Context, pubkey string, status models.Status) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateStatus", ctx, pubkey, status)
 	ret0, _ := ret[0].(error)
@@ -92,3 +194,1325 @@ func (mr *MockValidatorRepoMockRecorder) UpdateStatus(ctx, pubkey, status interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockValidatorRepo)(nil).UpdateStatus), ctx, pubkey, status)
 }
+
+// UpdateWithdrawalCredentials mocks base method.
+func (m *MockValidatorRepo) UpdateWithdrawalCredentials(ctx context.Context, pubkey, creds, source string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithdrawalCredentials", ctx, pubkey, creds, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWithdrawalCredentials indicates an expected call of UpdateWithdrawalCredentials.
+func (mr *MockValidatorRepoMockRecorder) UpdateWithdrawalCredentials(ctx, pubkey, creds, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithdrawalCredentials", reflect.TypeOf((*MockValidatorRepo)(nil).UpdateWithdrawalCredentials), ctx, pubkey, creds, source)
+}
+
+// MockClientKeyRepo is a mock of ClientKeyRepo interface.
+type MockClientKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientKeyRepoMockRecorder
+}
+
+// MockClientKeyRepoMockRecorder is the mock recorder for MockClientKeyRepo.
+type MockClientKeyRepoMockRecorder struct {
+	mock *MockClientKeyRepo
+}
+
+// NewMockClientKeyRepo creates a new mock instance.
+func NewMockClientKeyRepo(ctrl *gomock.Controller) *MockClientKeyRepo {
+	mock := &MockClientKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockClientKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientKeyRepo) EXPECT() *MockClientKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// ListByPubkey mocks base method.
+func (m *MockClientKeyRepo) ListByPubkey(ctx context.Context, pubkey string) ([]models.ClientKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPubkey", ctx, pubkey)
+	ret0, _ := ret[0].([]models.ClientKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPubkey indicates an expected call of ListByPubkey.
+func (mr *MockClientKeyRepoMockRecorder) ListByPubkey(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPubkey", reflect.TypeOf((*MockClientKeyRepo)(nil).ListByPubkey), ctx, pubkey)
+}
+
+// ListDoubleLoaded mocks base method.
+func (m *MockClientKeyRepo) ListDoubleLoaded(ctx context.Context) ([]models.DoubleLoadedKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDoubleLoaded", ctx)
+	ret0, _ := ret[0].([]models.DoubleLoadedKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDoubleLoaded indicates an expected call of ListDoubleLoaded.
+func (mr *MockClientKeyRepoMockRecorder) ListDoubleLoaded(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDoubleLoaded", reflect.TypeOf((*MockClientKeyRepo)(nil).ListDoubleLoaded), ctx)
+}
+
+// ReplaceInstanceKeys mocks base method.
+func (m *MockClientKeyRepo) ReplaceInstanceKeys(ctx context.Context, instance string, keys []models.ClientKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceInstanceKeys", ctx, instance, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceInstanceKeys indicates an expected call of ReplaceInstanceKeys.
+func (mr *MockClientKeyRepoMockRecorder) ReplaceInstanceKeys(ctx, instance, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceInstanceKeys", reflect.TypeOf((*MockClientKeyRepo)(nil).ReplaceInstanceKeys), ctx, instance, keys)
+}
+
+// MockAlertRepo is a mock of AlertRepo interface.
+type MockAlertRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRepoMockRecorder
+}
+
+// MockAlertRepoMockRecorder is the mock recorder for MockAlertRepo.
+type MockAlertRepoMockRecorder struct {
+	mock *MockAlertRepo
+}
+
+// NewMockAlertRepo creates a new mock instance.
+func NewMockAlertRepo(ctrl *gomock.Controller) *MockAlertRepo {
+	mock := &MockAlertRepo{ctrl: ctrl}
+	mock.recorder = &MockAlertRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRepo) EXPECT() *MockAlertRepoMockRecorder {
+	return m.recorder
+}
+
+// ListOpen mocks base method.
+func (m *MockAlertRepo) ListOpen(ctx context.Context, alertType string) ([]models.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpen", ctx, alertType)
+	ret0, _ := ret[0].([]models.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpen indicates an expected call of ListOpen.
+func (mr *MockAlertRepoMockRecorder) ListOpen(ctx, alertType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpen", reflect.TypeOf((*MockAlertRepo)(nil).ListOpen), ctx, alertType)
+}
+
+// MarkNotified mocks base method.
+func (m *MockAlertRepo) MarkNotified(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotified", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotified indicates an expected call of MarkNotified.
+func (mr *MockAlertRepoMockRecorder) MarkNotified(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotified", reflect.TypeOf((*MockAlertRepo)(nil).MarkNotified), ctx, id)
+}
+
+// Raise mocks base method.
+func (m *MockAlertRepo) Raise(ctx context.Context, a *models.Alert) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Raise", ctx, a)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Raise indicates an expected call of Raise.
+func (mr *MockAlertRepoMockRecorder) Raise(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Raise", reflect.TypeOf((*MockAlertRepo)(nil).Raise), ctx, a)
+}
+
+// Resolve mocks base method.
+func (m *MockAlertRepo) Resolve(ctx context.Context, alertType, pubkey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, alertType, pubkey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockAlertRepoMockRecorder) Resolve(ctx, alertType, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockAlertRepo)(nil).Resolve), ctx, alertType, pubkey)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockAuditRepo) Record(ctx context.Context, entry *models.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditRepoMockRecorder) Record(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditRepo)(nil).Record), ctx, entry)
+}
+
+// MockBLSChangeRepo is a mock of BLSChangeRepo interface.
+type MockBLSChangeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBLSChangeRepoMockRecorder
+}
+
+// MockBLSChangeRepoMockRecorder is the mock recorder for MockBLSChangeRepo.
+type MockBLSChangeRepoMockRecorder struct {
+	mock *MockBLSChangeRepo
+}
+
+// NewMockBLSChangeRepo creates a new mock instance.
+func NewMockBLSChangeRepo(ctrl *gomock.Controller) *MockBLSChangeRepo {
+	mock := &MockBLSChangeRepo{ctrl: ctrl}
+	mock.recorder = &MockBLSChangeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBLSChangeRepo) EXPECT() *MockBLSChangeRepoMockRecorder {
+	return m.recorder
+}
+
+// ConfirmApplied mocks base method.
+func (m *MockBLSChangeRepo) ConfirmApplied(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmApplied", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmApplied indicates an expected call of ConfirmApplied.
+func (mr *MockBLSChangeRepoMockRecorder) ConfirmApplied(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmApplied", reflect.TypeOf((*MockBLSChangeRepo)(nil).ConfirmApplied), ctx)
+}
+
+// GetByPubkey mocks base method.
+func (m *MockBLSChangeRepo) GetByPubkey(ctx context.Context, pubkey string) (*models.BLSChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPubkey", ctx, pubkey)
+	ret0, _ := ret[0].(*models.BLSChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPubkey indicates an expected call of GetByPubkey.
+func (mr *MockBLSChangeRepoMockRecorder) GetByPubkey(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPubkey", reflect.TypeOf((*MockBLSChangeRepo)(nil).GetByPubkey), ctx, pubkey)
+}
+
+// List mocks base method.
+func (m *MockBLSChangeRepo) List(ctx context.Context, status string) ([]models.BLSChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status)
+	ret0, _ := ret[0].([]models.BLSChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBLSChangeRepoMockRecorder) List(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBLSChangeRepo)(nil).List), ctx, status)
+}
+
+// Save mocks base method.
+func (m *MockBLSChangeRepo) Save(ctx context.Context, c *models.BLSChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBLSChangeRepoMockRecorder) Save(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBLSChangeRepo)(nil).Save), ctx, c)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockBLSChangeRepo) UpdateStatus(ctx context.Context, pubkey, status, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, pubkey, status, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockBLSChangeRepoMockRecorder) UpdateStatus(ctx, pubkey, status, errMsg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockBLSChangeRepo)(nil).UpdateStatus), ctx, pubkey, status, errMsg)
+}
+
+// MockVoluntaryExitRepo is a mock of VoluntaryExitRepo interface.
+type MockVoluntaryExitRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoluntaryExitRepoMockRecorder
+}
+
+// MockVoluntaryExitRepoMockRecorder is the mock recorder for MockVoluntaryExitRepo.
+type MockVoluntaryExitRepoMockRecorder struct {
+	mock *MockVoluntaryExitRepo
+}
+
+// NewMockVoluntaryExitRepo creates a new mock instance.
+func NewMockVoluntaryExitRepo(ctrl *gomock.Controller) *MockVoluntaryExitRepo {
+	mock := &MockVoluntaryExitRepo{ctrl: ctrl}
+	mock.recorder = &MockVoluntaryExitRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoluntaryExitRepo) EXPECT() *MockVoluntaryExitRepoMockRecorder {
+	return m.recorder
+}
+
+// GetByPubkey mocks base method.
+func (m *MockVoluntaryExitRepo) GetByPubkey(ctx context.Context, pubkey string) (*models.VoluntaryExit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPubkey", ctx, pubkey)
+	ret0, _ := ret[0].(*models.VoluntaryExit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPubkey indicates an expected call of GetByPubkey.
+func (mr *MockVoluntaryExitRepoMockRecorder) GetByPubkey(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPubkey", reflect.TypeOf((*MockVoluntaryExitRepo)(nil).GetByPubkey), ctx, pubkey)
+}
+
+// List mocks base method.
+func (m *MockVoluntaryExitRepo) List(ctx context.Context) ([]models.VoluntaryExit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.VoluntaryExit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockVoluntaryExitRepoMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVoluntaryExitRepo)(nil).List), ctx)
+}
+
+// ListMissing mocks base method.
+func (m *MockVoluntaryExitRepo) ListMissing(ctx context.Context) ([]models.Validator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMissing", ctx)
+	ret0, _ := ret[0].([]models.Validator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMissing indicates an expected call of ListMissing.
+func (mr *MockVoluntaryExitRepoMockRecorder) ListMissing(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMissing", reflect.TypeOf((*MockVoluntaryExitRepo)(nil).ListMissing), ctx)
+}
+
+// Save mocks base method.
+func (m *MockVoluntaryExitRepo) Save(ctx context.Context, e *models.VoluntaryExit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockVoluntaryExitRepoMockRecorder) Save(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockVoluntaryExitRepo)(nil).Save), ctx, e)
+}
+
+// MockExitRequestRepo is a mock of ExitRequestRepo interface.
+type MockExitRequestRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExitRequestRepoMockRecorder
+}
+
+// MockExitRequestRepoMockRecorder is the mock recorder for MockExitRequestRepo.
+type MockExitRequestRepoMockRecorder struct {
+	mock *MockExitRequestRepo
+}
+
+// NewMockExitRequestRepo creates a new mock instance.
+func NewMockExitRequestRepo(ctrl *gomock.Controller) *MockExitRequestRepo {
+	mock := &MockExitRequestRepo{ctrl: ctrl}
+	mock.recorder = &MockExitRequestRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExitRequestRepo) EXPECT() *MockExitRequestRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExitRequestRepo) Create(ctx context.Context, req *models.ExitRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExitRequestRepoMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExitRequestRepo)(nil).Create), ctx, req)
+}
+
+// Get mocks base method.
+func (m *MockExitRequestRepo) Get(ctx context.Context, id int64) (*models.ExitRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*models.ExitRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExitRequestRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExitRequestRepo)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockExitRequestRepo) List(ctx context.Context, status string) ([]models.ExitRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status)
+	ret0, _ := ret[0].([]models.ExitRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockExitRequestRepoMockRecorder) List(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExitRequestRepo)(nil).List), ctx, status)
+}
+
+// ListItems mocks base method.
+func (m *MockExitRequestRepo) ListItems(ctx context.Context, status string) ([]models.ExitRequestItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", ctx, status)
+	ret0, _ := ret[0].([]models.ExitRequestItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockExitRequestRepoMockRecorder) ListItems(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockExitRequestRepo)(nil).ListItems), ctx, status)
+}
+
+// Review mocks base method.
+func (m *MockExitRequestRepo) Review(ctx context.Context, id int64, status, reviewer string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, id, status, reviewer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockExitRequestRepoMockRecorder) Review(ctx, id, status, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockExitRequestRepo)(nil).Review), ctx, id, status, reviewer)
+}
+
+// UpdateItem mocks base method.
+func (m *MockExitRequestRepo) UpdateItem(ctx context.Context, id int64, pubkey, status, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, id, pubkey, status, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockExitRequestRepoMockRecorder) UpdateItem(ctx, id, pubkey, status, errMsg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockExitRequestRepo)(nil).UpdateItem), ctx, id, pubkey, status, errMsg)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockExitRequestRepo) UpdateStatus(ctx context.Context, id int64, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockExitRequestRepoMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockExitRequestRepo)(nil).UpdateStatus), ctx, id, status)
+}
+
+// MockWithdrawalRequestRepo is a mock of WithdrawalRequestRepo interface.
+type MockWithdrawalRequestRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalRequestRepoMockRecorder
+}
+
+// MockWithdrawalRequestRepoMockRecorder is the mock recorder for MockWithdrawalRequestRepo.
+type MockWithdrawalRequestRepoMockRecorder struct {
+	mock *MockWithdrawalRequestRepo
+}
+
+// NewMockWithdrawalRequestRepo creates a new mock instance.
+func NewMockWithdrawalRequestRepo(ctrl *gomock.Controller) *MockWithdrawalRequestRepo {
+	mock := &MockWithdrawalRequestRepo{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalRequestRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalRequestRepo) EXPECT() *MockWithdrawalRequestRepoMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockWithdrawalRequestRepo) List(ctx context.Context, pubkey string) ([]models.WithdrawalRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, pubkey)
+	ret0, _ := ret[0].([]models.WithdrawalRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWithdrawalRequestRepoMockRecorder) List(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWithdrawalRequestRepo)(nil).List), ctx, pubkey)
+}
+
+// Record mocks base method.
+func (m *MockWithdrawalRequestRepo) Record(ctx context.Context, r *models.WithdrawalRequest) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, r)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockWithdrawalRequestRepoMockRecorder) Record(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockWithdrawalRequestRepo)(nil).Record), ctx, r)
+}
+
+// MockConsolidationRepo is a mock of ConsolidationRepo interface.
+type MockConsolidationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsolidationRepoMockRecorder
+}
+
+// MockConsolidationRepoMockRecorder is the mock recorder for MockConsolidationRepo.
+type MockConsolidationRepoMockRecorder struct {
+	mock *MockConsolidationRepo
+}
+
+// NewMockConsolidationRepo creates a new mock instance.
+func NewMockConsolidationRepo(ctrl *gomock.Controller) *MockConsolidationRepo {
+	mock := &MockConsolidationRepo{ctrl: ctrl}
+	mock.recorder = &MockConsolidationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsolidationRepo) EXPECT() *MockConsolidationRepoMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockConsolidationRepo) List(ctx context.Context, status string) ([]models.Consolidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status)
+	ret0, _ := ret[0].([]models.Consolidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockConsolidationRepoMockRecorder) List(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockConsolidationRepo)(nil).List), ctx, status)
+}
+
+// Plan mocks base method.
+func (m *MockConsolidationRepo) Plan(ctx context.Context, c *models.Consolidation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockConsolidationRepoMockRecorder) Plan(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockConsolidationRepo)(nil).Plan), ctx, c)
+}
+
+// SetPending mocks base method.
+func (m *MockConsolidationRepo) SetPending(ctx context.Context, source, target string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPending", ctx, source, target)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPending indicates an expected call of SetPending.
+func (mr *MockConsolidationRepoMockRecorder) SetPending(ctx, source, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPending", reflect.TypeOf((*MockConsolidationRepo)(nil).SetPending), ctx, source, target)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockConsolidationRepo) UpdateStatus(ctx context.Context, source, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, source, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockConsolidationRepoMockRecorder) UpdateStatus(ctx, source, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockConsolidationRepo)(nil).UpdateStatus), ctx, source, status)
+}
+
+// MockSlashingProtectionRepo is a mock of SlashingProtectionRepo interface.
+type MockSlashingProtectionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlashingProtectionRepoMockRecorder
+}
+
+// MockSlashingProtectionRepoMockRecorder is the mock recorder for MockSlashingProtectionRepo.
+type MockSlashingProtectionRepoMockRecorder struct {
+	mock *MockSlashingProtectionRepo
+}
+
+// NewMockSlashingProtectionRepo creates a new mock instance.
+func NewMockSlashingProtectionRepo(ctrl *gomock.Controller) *MockSlashingProtectionRepo {
+	mock := &MockSlashingProtectionRepo{ctrl: ctrl}
+	mock.recorder = &MockSlashingProtectionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSlashingProtectionRepo) EXPECT() *MockSlashingProtectionRepoMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockSlashingProtectionRepo) List(ctx context.Context, pubkeys []string) ([]models.SlashingProtection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, pubkeys)
+	ret0, _ := ret[0].([]models.SlashingProtection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSlashingProtectionRepoMockRecorder) List(ctx, pubkeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSlashingProtectionRepo)(nil).List), ctx, pubkeys)
+}
+
+// Merge mocks base method.
+func (m *MockSlashingProtectionRepo) Merge(ctx context.Context, p *models.SlashingProtection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockSlashingProtectionRepoMockRecorder) Merge(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockSlashingProtectionRepo)(nil).Merge), ctx, p)
+}
+
+// MockKeyMigrationRepo is a mock of KeyMigrationRepo interface.
+type MockKeyMigrationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyMigrationRepoMockRecorder
+}
+
+// MockKeyMigrationRepoMockRecorder is the mock recorder for MockKeyMigrationRepo.
+type MockKeyMigrationRepoMockRecorder struct {
+	mock *MockKeyMigrationRepo
+}
+
+// NewMockKeyMigrationRepo creates a new mock instance.
+func NewMockKeyMigrationRepo(ctrl *gomock.Controller) *MockKeyMigrationRepo {
+	mock := &MockKeyMigrationRepo{ctrl: ctrl}
+	mock.recorder = &MockKeyMigrationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyMigrationRepo) EXPECT() *MockKeyMigrationRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m_2 *MockKeyMigrationRepo) Create(ctx context.Context, m *models.KeyMigration) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Create", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockKeyMigrationRepoMockRecorder) Create(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeyMigrationRepo)(nil).Create), ctx, m)
+}
+
+// Get mocks base method.
+func (m *MockKeyMigrationRepo) Get(ctx context.Context, id int64) (*models.KeyMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*models.KeyMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockKeyMigrationRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyMigrationRepo)(nil).Get), ctx, id)
+}
+
+// Holding mocks base method.
+func (m *MockKeyMigrationRepo) Holding(ctx context.Context, pubkey string) (*models.KeyMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Holding", ctx, pubkey)
+	ret0, _ := ret[0].(*models.KeyMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Holding indicates an expected call of Holding.
+func (mr *MockKeyMigrationRepoMockRecorder) Holding(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Holding", reflect.TypeOf((*MockKeyMigrationRepo)(nil).Holding), ctx, pubkey)
+}
+
+// List mocks base method.
+func (m *MockKeyMigrationRepo) List(ctx context.Context, status string) ([]models.KeyMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status)
+	ret0, _ := ret[0].([]models.KeyMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockKeyMigrationRepoMockRecorder) List(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockKeyMigrationRepo)(nil).List), ctx, status)
+}
+
+// ListUnfinished mocks base method.
+func (m *MockKeyMigrationRepo) ListUnfinished(ctx context.Context) ([]models.KeyMigration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfinished", ctx)
+	ret0, _ := ret[0].([]models.KeyMigration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfinished indicates an expected call of ListUnfinished.
+func (mr *MockKeyMigrationRepoMockRecorder) ListUnfinished(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfinished", reflect.TypeOf((*MockKeyMigrationRepo)(nil).ListUnfinished), ctx)
+}
+
+// Update mocks base method.
+func (m_2 *MockKeyMigrationRepo) Update(ctx context.Context, m *models.KeyMigration) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Update", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockKeyMigrationRepoMockRecorder) Update(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockKeyMigrationRepo)(nil).Update), ctx, m)
+}
+
+// MockDoppelgangerCheckRepo is a mock of DoppelgangerCheckRepo interface.
+type MockDoppelgangerCheckRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDoppelgangerCheckRepoMockRecorder
+}
+
+// MockDoppelgangerCheckRepoMockRecorder is the mock recorder for MockDoppelgangerCheckRepo.
+type MockDoppelgangerCheckRepoMockRecorder struct {
+	mock *MockDoppelgangerCheckRepo
+}
+
+// NewMockDoppelgangerCheckRepo creates a new mock instance.
+func NewMockDoppelgangerCheckRepo(ctrl *gomock.Controller) *MockDoppelgangerCheckRepo {
+	mock := &MockDoppelgangerCheckRepo{ctrl: ctrl}
+	mock.recorder = &MockDoppelgangerCheckRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDoppelgangerCheckRepo) EXPECT() *MockDoppelgangerCheckRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDoppelgangerCheckRepo) Create(ctx context.Context, c *models.DoppelgangerCheck) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDoppelgangerCheckRepoMockRecorder) Create(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDoppelgangerCheckRepo)(nil).Create), ctx, c)
+}
+
+// Latest mocks base method.
+func (m *MockDoppelgangerCheckRepo) Latest(ctx context.Context, pubkey string) (*models.DoppelgangerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", ctx, pubkey)
+	ret0, _ := ret[0].(*models.DoppelgangerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockDoppelgangerCheckRepoMockRecorder) Latest(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockDoppelgangerCheckRepo)(nil).Latest), ctx, pubkey)
+}
+
+// List mocks base method.
+func (m *MockDoppelgangerCheckRepo) List(ctx context.Context, pubkey string) ([]models.DoppelgangerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, pubkey)
+	ret0, _ := ret[0].([]models.DoppelgangerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDoppelgangerCheckRepoMockRecorder) List(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDoppelgangerCheckRepo)(nil).List), ctx, pubkey)
+}
+
+// ListRunning mocks base method.
+func (m *MockDoppelgangerCheckRepo) ListRunning(ctx context.Context) ([]models.DoppelgangerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRunning", ctx)
+	ret0, _ := ret[0].([]models.DoppelgangerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRunning indicates an expected call of ListRunning.
+func (mr *MockDoppelgangerCheckRepoMockRecorder) ListRunning(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunning", reflect.TypeOf((*MockDoppelgangerCheckRepo)(nil).ListRunning), ctx)
+}
+
+// Update mocks base method.
+func (m *MockDoppelgangerCheckRepo) Update(ctx context.Context, c *models.DoppelgangerCheck) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDoppelgangerCheckRepoMockRecorder) Update(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDoppelgangerCheckRepo)(nil).Update), ctx, c)
+}
+
+// MockKeystoreRepo is a mock of KeystoreRepo interface.
+type MockKeystoreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeystoreRepoMockRecorder
+}
+
+// MockKeystoreRepoMockRecorder is the mock recorder for MockKeystoreRepo.
+type MockKeystoreRepoMockRecorder struct {
+	mock *MockKeystoreRepo
+}
+
+// NewMockKeystoreRepo creates a new mock instance.
+func NewMockKeystoreRepo(ctrl *gomock.Controller) *MockKeystoreRepo {
+	mock := &MockKeystoreRepo{ctrl: ctrl}
+	mock.recorder = &MockKeystoreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeystoreRepo) EXPECT() *MockKeystoreRepoMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockKeystoreRepo) Delete(ctx context.Context, pubkey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, pubkey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockKeystoreRepoMockRecorder) Delete(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockKeystoreRepo)(nil).Delete), ctx, pubkey)
+}
+
+// Get mocks base method.
+func (m *MockKeystoreRepo) Get(ctx context.Context, pubkey string) (*models.StoredKeystore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, pubkey)
+	ret0, _ := ret[0].(*models.StoredKeystore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockKeystoreRepoMockRecorder) Get(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeystoreRepo)(nil).Get), ctx, pubkey)
+}
+
+// List mocks base method.
+func (m *MockKeystoreRepo) List(ctx context.Context) ([]models.StoredKeystore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.StoredKeystore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockKeystoreRepoMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockKeystoreRepo)(nil).List), ctx)
+}
+
+// Save mocks base method.
+func (m *MockKeystoreRepo) Save(ctx context.Context, k *models.StoredKeystore) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, k)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockKeystoreRepoMockRecorder) Save(ctx, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockKeystoreRepo)(nil).Save), ctx, k)
+}
+
+// MockSignerKeyRepo is a mock of SignerKeyRepo interface.
+type MockSignerKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignerKeyRepoMockRecorder
+}
+
+// MockSignerKeyRepoMockRecorder is the mock recorder for MockSignerKeyRepo.
+type MockSignerKeyRepoMockRecorder struct {
+	mock *MockSignerKeyRepo
+}
+
+// NewMockSignerKeyRepo creates a new mock instance.
+func NewMockSignerKeyRepo(ctrl *gomock.Controller) *MockSignerKeyRepo {
+	mock := &MockSignerKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockSignerKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignerKeyRepo) EXPECT() *MockSignerKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// ListByPubkey mocks base method.
+func (m *MockSignerKeyRepo) ListByPubkey(ctx context.Context, pubkey string) ([]models.SignerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPubkey", ctx, pubkey)
+	ret0, _ := ret[0].([]models.SignerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPubkey indicates an expected call of ListByPubkey.
+func (mr *MockSignerKeyRepoMockRecorder) ListByPubkey(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPubkey", reflect.TypeOf((*MockSignerKeyRepo)(nil).ListByPubkey), ctx, pubkey)
+}
+
+// ReplaceSignerKeys mocks base method.
+func (m *MockSignerKeyRepo) ReplaceSignerKeys(ctx context.Context, signer string, keys []models.SignerKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSignerKeys", ctx, signer, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSignerKeys indicates an expected call of ReplaceSignerKeys.
+func (mr *MockSignerKeyRepoMockRecorder) ReplaceSignerKeys(ctx, signer, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSignerKeys", reflect.TypeOf((*MockSignerKeyRepo)(nil).ReplaceSignerKeys), ctx, signer, keys)
+}
+
+// MockWebhookDeliveryRepo is a mock of WebhookDeliveryRepo interface.
+type MockWebhookDeliveryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryRepoMockRecorder
+}
+
+// MockWebhookDeliveryRepoMockRecorder is the mock recorder for MockWebhookDeliveryRepo.
+type MockWebhookDeliveryRepoMockRecorder struct {
+	mock *MockWebhookDeliveryRepo
+}
+
+// NewMockWebhookDeliveryRepo creates a new mock instance.
+func NewMockWebhookDeliveryRepo(ctrl *gomock.Controller) *MockWebhookDeliveryRepo {
+	mock := &MockWebhookDeliveryRepo{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryRepo) EXPECT() *MockWebhookDeliveryRepoMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockWebhookDeliveryRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockWebhookDeliveryRepoMockRecorder) ClaimDue(ctx, now, leaseUntil, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).ClaimDue), ctx, now, leaseUntil, limit)
+}
+
+// Create mocks base method.
+func (m *MockWebhookDeliveryRepo) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookDeliveryRepoMockRecorder) Create(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).Create), ctx, d)
+}
+
+// Get mocks base method.
+func (m *MockWebhookDeliveryRepo) Get(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookDeliveryRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhookDeliveryRepo) List(ctx context.Context, filters map[string]interface{}) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filters)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookDeliveryRepoMockRecorder) List(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).List), ctx, filters)
+}
+
+// UpdateAttempt mocks base method.
+func (m *MockWebhookDeliveryRepo) UpdateAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAttempt", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAttempt indicates an expected call of UpdateAttempt.
+func (mr *MockWebhookDeliveryRepoMockRecorder) UpdateAttempt(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAttempt", reflect.TypeOf((*MockWebhookDeliveryRepo)(nil).UpdateAttempt), ctx, d)
+}
+
+// MockSyncRunRepo is a mock of SyncRunRepo interface.
+type MockSyncRunRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncRunRepoMockRecorder
+}
+
+// MockSyncRunRepoMockRecorder is the mock recorder for MockSyncRunRepo.
+type MockSyncRunRepoMockRecorder struct {
+	mock *MockSyncRunRepo
+}
+
+// NewMockSyncRunRepo creates a new mock instance.
+func NewMockSyncRunRepo(ctrl *gomock.Controller) *MockSyncRunRepo {
+	mock := &MockSyncRunRepo{ctrl: ctrl}
+	mock.recorder = &MockSyncRunRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncRunRepo) EXPECT() *MockSyncRunRepoMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockSyncRunRepo) List(ctx context.Context) ([]models.SyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.SyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSyncRunRepoMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSyncRunRepo)(nil).List), ctx)
+}
+
+// RecordFailure mocks base method.
+func (m *MockSyncRunRepo) RecordFailure(ctx context.Context, source string, at time.Time, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, source, at, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockSyncRunRepoMockRecorder) RecordFailure(ctx, source, at, errMsg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockSyncRunRepo)(nil).RecordFailure), ctx, source, at, errMsg)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockSyncRunRepo) RecordSuccess(ctx context.Context, source string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", ctx, source, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockSyncRunRepoMockRecorder) RecordSuccess(ctx, source, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockSyncRunRepo)(nil).RecordSuccess), ctx, source, at)
+}
+
+// MockAlertSilenceRepo is a mock of AlertSilenceRepo interface.
+type MockAlertSilenceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertSilenceRepoMockRecorder
+}
+
+// MockAlertSilenceRepoMockRecorder is the mock recorder for MockAlertSilenceRepo.
+type MockAlertSilenceRepoMockRecorder struct {
+	mock *MockAlertSilenceRepo
+}
+
+// NewMockAlertSilenceRepo creates a new mock instance.
+func NewMockAlertSilenceRepo(ctrl *gomock.Controller) *MockAlertSilenceRepo {
+	mock := &MockAlertSilenceRepo{ctrl: ctrl}
+	mock.recorder = &MockAlertSilenceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertSilenceRepo) EXPECT() *MockAlertSilenceRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAlertSilenceRepo) Create(ctx context.Context, s *models.AlertSilence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAlertSilenceRepoMockRecorder) Create(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAlertSilenceRepo)(nil).Create), ctx, s)
+}
+
+// Delete mocks base method.
+func (m *MockAlertSilenceRepo) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertSilenceRepoMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertSilenceRepo)(nil).Delete), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockAlertSilenceRepo) List(ctx context.Context, now time.Time) ([]models.AlertSilence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, now)
+	ret0, _ := ret[0].([]models.AlertSilence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAlertSilenceRepoMockRecorder) List(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAlertSilenceRepo)(nil).List), ctx, now)
+}
+
+// MockOutboxRepo is a mock of OutboxRepo interface.
+type MockOutboxRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepoMockRecorder
+}
+
+// MockOutboxRepoMockRecorder is the mock recorder for MockOutboxRepo.
+type MockOutboxRepoMockRecorder struct {
+	mock *MockOutboxRepo
+}
+
+// NewMockOutboxRepo creates a new mock instance.
+func NewMockOutboxRepo(ctrl *gomock.Controller) *MockOutboxRepo {
+	mock := &MockOutboxRepo{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepo) EXPECT() *MockOutboxRepoMockRecorder {
+	return m.recorder
+}
+
+// ListRange mocks base method.
+func (m *MockOutboxRepo) ListRange(ctx context.Context, from, to time.Time) ([]models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, from, to)
+	ret0, _ := ret[0].([]models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockOutboxRepoMockRecorder) ListRange(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockOutboxRepo)(nil).ListRange), ctx, from, to)
+}
+
+// ListUnpublished mocks base method.
+func (m *MockOutboxRepo) ListUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpublished", ctx, limit)
+	ret0, _ := ret[0].([]models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpublished indicates an expected call of ListUnpublished.
+func (mr *MockOutboxRepoMockRecorder) ListUnpublished(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpublished", reflect.TypeOf((*MockOutboxRepo)(nil).ListUnpublished), ctx, limit)
+}
+
+// MarkPublished mocks base method.
+func (m *MockOutboxRepo) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockOutboxRepoMockRecorder) MarkPublished(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockOutboxRepo)(nil).MarkPublished), ctx, id, at)
+}
@@ -12,6 +12,23 @@ import (
 func TestMockValidatorRepo(t *testing.T) {
 	// This test ensures that MockValidatorRepo implements ValidatorRepo interface
 	var _ models.ValidatorRepo = (*MockValidatorRepo)(nil)
+	var _ models.ClientKeyRepo = (*MockClientKeyRepo)(nil)
+	var _ models.AlertRepo = (*MockAlertRepo)(nil)
+	var _ models.AuditRepo = (*MockAuditRepo)(nil)
+	var _ models.BLSChangeRepo = (*MockBLSChangeRepo)(nil)
+	var _ models.VoluntaryExitRepo = (*MockVoluntaryExitRepo)(nil)
+	var _ models.ExitRequestRepo = (*MockExitRequestRepo)(nil)
+	var _ models.WithdrawalRequestRepo = (*MockWithdrawalRequestRepo)(nil)
+	var _ models.ConsolidationRepo = (*MockConsolidationRepo)(nil)
+	var _ models.SlashingProtectionRepo = (*MockSlashingProtectionRepo)(nil)
+	var _ models.KeyMigrationRepo = (*MockKeyMigrationRepo)(nil)
+	var _ models.DoppelgangerCheckRepo = (*MockDoppelgangerCheckRepo)(nil)
+	var _ models.KeystoreRepo = (*MockKeystoreRepo)(nil)
+	var _ models.SignerKeyRepo = (*MockSignerKeyRepo)(nil)
+	var _ models.WebhookDeliveryRepo = (*MockWebhookDeliveryRepo)(nil)
+	var _ models.SyncRunRepo = (*MockSyncRunRepo)(nil)
+	var _ models.AlertSilenceRepo = (*MockAlertSilenceRepo)(nil)
+	var _ models.OutboxRepo = (*MockOutboxRepo)(nil)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -39,7 +56,12 @@ func TestMockValidatorRepo(t *testing.T) {
 	assert.Equal(t, expectedValidators, validators)
 
 	// Test UpdateStatus
-	mock.EXPECT().UpdateStatus(ctx, "test", "active").Return(nil)
-	err = mock.UpdateStatus(ctx, "test", "active")
+	mock.EXPECT().UpdateStatus(ctx, "test", models.StatusActive).Return(nil)
+	err = mock.UpdateStatus(ctx, "test", models.StatusActive)
+	assert.NoError(t, err)
+
+	// Test UpdateClient
+	mock.EXPECT().UpdateClient(ctx, "test", "teku", "teku-1").Return(nil)
+	err = mock.UpdateClient(ctx, "test", "teku", "teku-1")
 	assert.NoError(t, err)
 }
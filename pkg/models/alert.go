@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Alert severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// AlertTypeDoubleLoaded is raised when a key is loaded on more than one client instance
+const AlertTypeDoubleLoaded = "double_loaded"
+
+// Alert is an operational problem that needs attention
+type Alert struct {
+	ID         int64      `json:"id" db:"id"`
+	Type       string     `json:"type" db:"type"`
+	Severity   string     `json:"severity" db:"severity"`
+	Pubkey     string     `json:"pubkey,omitempty" db:"pubkey"`
+	Message    string     `json:"message" db:"message"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	// NotifiedAt is when the alert was sent to the notifiers of its rule
+	NotifiedAt *time.Time `json:"notified_at,omitempty" db:"notified_at"`
+}
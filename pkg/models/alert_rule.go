@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// AlertTypeRulePrefix prefixes the type of alerts raised by alert rules; the rule name follows it
+const AlertTypeRulePrefix = "rule:"
+
+// Background syncs whose runs are recorded
+const (
+	// SyncSourceDetector is the validator client key detection
+	SyncSourceDetector = "detector"
+	// SyncSourceBeacon is the beacon chain metadata sync
+	SyncSourceBeacon = "beacon"
+	// SyncSourceLido is the Lido registry sync
+	SyncSourceLido = "lido"
+	// SyncSourceSigners is the remote signer key sync
+	SyncSourceSigners = "signers"
+)
+
+// SyncSources lists every background sync
+var SyncSources = []string{SyncSourceDetector, SyncSourceBeacon, SyncSourceLido, SyncSourceSigners}
+
+// SyncRun is the outcome of the latest runs of a background sync
+type SyncRun struct {
+	Source        string     `json:"source" db:"source"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty" db:"last_success_at"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty" db:"last_failure_at"`
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AlertSilence stops an alert rule, or every rule if Rule is empty, from
+// raising alerts between StartsAt and EndsAt
+type AlertSilence struct {
+	ID        int64     `json:"id" db:"id"`
+	Rule      string    `json:"rule,omitempty" db:"rule"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Silences reports whether the silence covers the named rule at t
+func (s AlertSilence) Silences(rule string, t time.Time) bool {
+	return (s.Rule == "" || s.Rule == rule) && !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AuditLog records an action taken by a user or by the service itself
+type AuditLog struct {
+	ID        int64     `json:"id" db:"id"`
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	Action    string    `json:"action" db:"action"`
+	SourceIP  string    `json:"source_ip,omitempty" db:"source_ip"`
+	Details   string    `json:"details,omitempty" db:"details"`
+}
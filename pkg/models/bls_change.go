@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAlreadySubmitted is returned when replacing a change that was already submitted
+var ErrAlreadySubmitted = errors.New("change was already submitted")
+
+// BLS-to-execution change statuses
+const (
+	// BLSChangeStatusUploaded changes are verified and waiting to be submitted
+	BLSChangeStatusUploaded = "uploaded"
+	// BLSChangeStatusSubmitted changes were accepted into the beacon node pool
+	BLSChangeStatusSubmitted = "submitted"
+	// BLSChangeStatusFailed changes were rejected by the beacon node and may be retried
+	BLSChangeStatusFailed = "failed"
+	// BLSChangeStatusConfirmed changes are reflected in the validator's withdrawal credentials
+	BLSChangeStatusConfirmed = "confirmed"
+)
+
+// BLSChange is a stored SignedBLSToExecutionChange. Message holds the encrypted signed message.
+type BLSChange struct {
+	ID                 int64      `json:"id" db:"id"`
+	Pubkey             string     `json:"pubkey" db:"pubkey"`
+	ValidatorIndex     int64      `json:"validator_index" db:"validator_index"`
+	FromBLSPubkey      string     `json:"from_bls_pubkey" db:"from_bls_pubkey"`
+	ToExecutionAddress string     `json:"to_execution_address" db:"to_execution_address"`
+	Message            []byte     `json:"-" db:"message"`
+	Status             string     `json:"status" db:"status"`
+	Error              string     `json:"error,omitempty" db:"error"`
+	SubmittedAt        *time.Time `json:"submitted_at,omitempty" db:"submitted_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
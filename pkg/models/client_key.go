@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ClientKey is a key reported as loaded by a validator client instance
+type ClientKey struct {
+	Pubkey         string    `json:"pubkey" db:"pubkey"`
+	Client         string    `json:"client" db:"client"`
+	ClientInstance string    `json:"client_instance" db:"client_instance"`
+	Remote         bool      `json:"remote" db:"remote"`
+	SignerURL      string    `json:"signer_url,omitempty" db:"signer_url"`
+	LastSeenAt     time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// DoubleLoadedKey is a pubkey loaded on more than one validator client instance
+type DoubleLoadedKey struct {
+	Pubkey    string      `json:"pubkey"`
+	Instances []ClientKey `json:"instances"`
+}
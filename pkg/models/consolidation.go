@@ -0,0 +1,43 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConsolidationInProgress is returned when replanning a consolidation the beacon chain already processes
+var ErrConsolidationInProgress = errors.New("consolidation of the source validator is already in progress")
+
+// MaxEffectiveBalanceElectra is the highest effective balance, in gwei, of a compounding validator
+const MaxEffectiveBalanceElectra = 2048_000_000_000
+
+// Consolidation statuses
+const (
+	// ConsolidationStatusPlanned consolidations are recorded but not yet seen on chain
+	ConsolidationStatusPlanned = "planned"
+	// ConsolidationStatusPending consolidations are queued in the beacon state
+	ConsolidationStatusPending = "pending"
+	// ConsolidationStatusCompleted consolidations have moved the source balance to the target
+	ConsolidationStatusCompleted = "completed"
+)
+
+// Consolidation moves the balance of a source validator into a compounding target validator (EIP-7251)
+type Consolidation struct {
+	ID           int64      `json:"id" db:"id"`
+	SourcePubkey string     `json:"source_pubkey" db:"source_pubkey"`
+	TargetPubkey string     `json:"target_pubkey" db:"target_pubkey"`
+	Status       string     `json:"status" db:"status"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ConsolidationTarget summarizes the balance a target validator holds and is about to receive
+type ConsolidationTarget struct {
+	Pubkey           string          `json:"pubkey"`
+	ValidatorIndex   *int64          `json:"validator_index,omitempty"`
+	EffectiveBalance int64           `json:"effective_balance"`
+	IncomingBalance  int64           `json:"incoming_balance"`
+	ProjectedBalance int64           `json:"projected_balance"`
+	Sources          []Consolidation `json:"sources"`
+}
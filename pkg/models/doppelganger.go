@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCheckInProgress is returned when starting a doppelganger check for a key that already has one running
+var ErrCheckInProgress = errors.New("doppelganger check is already running")
+
+// AlertTypeDoppelganger is raised when a key shows activity while it should not be signing anywhere
+const AlertTypeDoppelganger = "doppelganger"
+
+// Doppelganger check statuses
+const (
+	// DoppelgangerStatusRunning checks are still watching the beacon node
+	DoppelgangerStatusRunning = "running"
+	// DoppelgangerStatusPassed checks saw no activity for all their epochs
+	DoppelgangerStatusPassed = "passed"
+	// DoppelgangerStatusFailed checks saw the validator attest or propose
+	DoppelgangerStatusFailed = "failed"
+)
+
+// DoppelgangerCheck watches a validator's liveness for a number of epochs to make
+// sure no other instance of its key is signing before it is enabled
+type DoppelgangerCheck struct {
+	ID             int64  `json:"id" db:"id"`
+	Pubkey         string `json:"pubkey" db:"pubkey"`
+	ValidatorIndex int64  `json:"validator_index" db:"validator_index"`
+	// Epochs is the number of quiet epochs needed to pass
+	Epochs int64 `json:"epochs" db:"epochs"`
+	// StartEpoch is the head epoch when the check started; only later epochs are watched
+	StartEpoch       int64      `json:"start_epoch" db:"start_epoch"`
+	CheckedEpochs    int64      `json:"checked_epochs" db:"checked_epochs"`
+	LastCheckedEpoch *int64     `json:"last_checked_epoch,omitempty" db:"last_checked_epoch"`
+	Status           string     `json:"status" db:"status"`
+	DetectedEpoch    *int64     `json:"detected_epoch,omitempty" db:"detected_epoch"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Exit request statuses
+const (
+	// ExitRequestStatusPending requests wait for a second operator to approve them
+	ExitRequestStatusPending = "pending"
+	// ExitRequestStatusRejected requests were declined and will never be submitted
+	ExitRequestStatusRejected = "rejected"
+	// ExitRequestStatusSubmitted requests were approved and their exits sent to the beacon node
+	ExitRequestStatusSubmitted = "submitted"
+	// ExitRequestStatusCompleted requests have no exit left in flight
+	ExitRequestStatusCompleted = "completed"
+)
+
+// Exit request item statuses
+const (
+	// ExitItemStatusPending items belong to a request that is not yet approved
+	ExitItemStatusPending = "pending"
+	// ExitItemStatusSubmitted items were accepted into the beacon node pool
+	ExitItemStatusSubmitted = "submitted"
+	// ExitItemStatusFailed items could not be submitted
+	ExitItemStatusFailed = "failed"
+	// ExitItemStatusExited items are reported exited by the beacon node
+	ExitItemStatusExited = "exited"
+)
+
+// ExitRequest asks for a set of validators to be exited. It is only
+// submitted once an operator other than the requester approves it.
+type ExitRequest struct {
+	ID          int64             `json:"id" db:"id"`
+	RequestedBy string            `json:"requested_by" db:"requested_by"`
+	Reason      string            `json:"reason,omitempty" db:"reason"`
+	Status      string            `json:"status" db:"status"`
+	ReviewedBy  string            `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+	Items       []ExitRequestItem `json:"items,omitempty"`
+}
+
+// ExitRequestItem tracks the exit of one validator of a request
+type ExitRequestItem struct {
+	RequestID   int64      `json:"request_id" db:"request_id"`
+	Pubkey      string     `json:"pubkey" db:"pubkey"`
+	Status      string     `json:"status" db:"status"`
+	Error       string     `json:"error,omitempty" db:"error"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty" db:"submitted_at"`
+	ExitedAt    *time.Time `json:"exited_at,omitempty" db:"exited_at"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMigrationInProgress is returned when migrating a key that is already being migrated
+var ErrMigrationInProgress = errors.New("key is already being migrated")
+
+// Key migration statuses, in the order a migration moves through them
+const (
+	// KeyMigrationStatusPending migrations have not touched either client yet
+	KeyMigrationStatusPending = "pending"
+	// KeyMigrationStatusSourceDeleted migrations removed the key from the source client
+	// and wait for a doppelganger check to pass before importing it
+	KeyMigrationStatusSourceDeleted = "source_deleted"
+	// KeyMigrationStatusTargetImported migrations loaded the key on the target client
+	// and wait for the validator to attest again
+	KeyMigrationStatusTargetImported = "target_imported"
+	// KeyMigrationStatusCompleted migrations saw the validator attest from the target client
+	KeyMigrationStatusCompleted = "completed"
+	// KeyMigrationStatusFailed migrations stopped and need an operator
+	KeyMigrationStatusFailed = "failed"
+)
+
+// KeyMigration moves a validator key from one validator client instance to another.
+// Every step is stored before the next one starts so an interrupted migration resumes
+// where it stopped.
+type KeyMigration struct {
+	ID             int64  `json:"id" db:"id"`
+	Pubkey         string `json:"pubkey" db:"pubkey"`
+	SourceInstance string `json:"source_instance" db:"source_instance"`
+	TargetInstance string `json:"target_instance" db:"target_instance"`
+	RequestedBy    string `json:"requested_by" db:"requested_by"`
+	// WaitEpochs is the number of quiet epochs a doppelganger check must see
+	// between deleting the key and importing it
+	WaitEpochs    int64  `json:"wait_epochs" db:"wait_epochs"`
+	Status        string `json:"status" db:"status"`
+	Error         string `json:"error,omitempty" db:"error"`
+	DeletedEpoch  *int64 `json:"deleted_epoch,omitempty" db:"deleted_epoch"`
+	ImportedEpoch *int64 `json:"imported_epoch,omitempty" db:"imported_epoch"`
+	// Keystore is the encrypted keystore and password to import. It is cleared once the
+	// target has the key, or when a migration fails before the source deleted it.
+	Keystore []byte `json:"-" db:"keystore"`
+	// SlashingProtection is the interchange the source client returned on deletion
+	SlashingProtection []byte     `json:"-" db:"slashing_protection"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Finished reports whether the migration reached a final status
+func (m *KeyMigration) Finished() bool {
+	return m.Status == KeyMigrationStatusCompleted || m.Status == KeyMigrationStatusFailed
+}
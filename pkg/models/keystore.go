@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// StoredKeystore is an EIP-2335 keystore held by the manager together with its
+// password. Both are encrypted with a data key of their own, which is in turn
+// encrypted with the vault key, and are never returned by the API.
+type StoredKeystore struct {
+	ID     int64  `json:"id" db:"id"`
+	Pubkey string `json:"pubkey" db:"pubkey"`
+	Path   string `json:"path" db:"path"`
+	UUID   string `json:"uuid" db:"uuid"`
+	// KDF is the key derivation function protecting the keystore, scrypt or pbkdf2
+	KDF string `json:"kdf" db:"kdf"`
+	// PasswordVerified is set when the password decrypted the keystore on upload
+	PasswordVerified bool      `json:"password_verified" db:"password_verified"`
+	DataKey          []byte    `json:"-" db:"data_key"`
+	Keystore         []byte    `json:"-" db:"keystore"`
+	Password         []byte    `json:"-" db:"password"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outbox event types
+const (
+	// EventValidatorCreated is written when a validator is added; its payload is the Validator
+	EventValidatorCreated = "validator.created"
+	// EventValidatorStatusChanged is written when a validator changes status; its payload is a StatusChange
+	EventValidatorStatusChanged = "validator.status_changed"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// it describes, so it is published even if the process dies after the commit
+type OutboxEvent struct {
+	ID          int64           `json:"id" db:"id"`
+	Type        string          `json:"type" db:"type"`
+	Pubkey      string          `json:"pubkey" db:"pubkey"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty" db:"published_at"`
+}
+
+// StatusChange is the payload of an EventValidatorStatusChanged event
+type StatusChange struct {
+	Pubkey     string `json:"pubkey"`
+	Blockchain string `json:"blockchain"`
+	Network    string `json:"network"`
+	From       Status `json:"from"`
+	To         Status `json:"to"`
+}
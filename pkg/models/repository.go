@@ -1,6 +1,9 @@
 package models
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ValidatorRepo defines the interface for validator data access
 type ValidatorRepo interface {
@@ -10,9 +13,281 @@ type ValidatorRepo interface {
 	// GetByPubkey retrieves a validator by its public key
 	GetByPubkey(ctx context.Context, pubkey string) (*Validator, error)
 
+	// GetByIndex retrieves a validator by its beacon chain index on a network
+	GetByIndex(ctx context.Context, network string, index int64) (*Validator, error)
+
+	// SearchByPubkeyPrefix returns up to limit validators whose public key starts with prefix
+	SearchByPubkeyPrefix(ctx context.Context, prefix string, limit int) ([]Validator, error)
+
 	// List returns a list of validators based on the provided filters
 	List(ctx context.Context, filters map[string]interface{}) ([]Validator, error)
 
 	// UpdateStatus updates the status of a validator by its public key
-	UpdateStatus(ctx context.Context, pubkey, status string) error
+	UpdateStatus(ctx context.Context, pubkey string, status Status) error
+
+	// TransitionStatus moves a validator to status, checking the transition against
+	// the current status in the same transaction as the write
+	TransitionStatus(ctx context.Context, pubkey string, status Status) error
+
+	// UpdateClient records which validator client instance has the key loaded
+	UpdateClient(ctx context.Context, pubkey, client, instance string) error
+
+	// UpdateLido records the Lido registry status of a validator by its public key
+	UpdateLido(ctx context.Context, pubkey string, status LidoStatus) error
+
+	// UpdateBeacon records the beacon chain metadata of a validator by its public key
+	UpdateBeacon(ctx context.Context, pubkey string, meta BeaconMetadata) error
+
+	// UpdateWithdrawalCredentials records the withdrawal credentials of a validator from source
+	UpdateWithdrawalCredentials(ctx context.Context, pubkey, creds, source string) error
+
+	// ListCredentialChanges returns the withdrawal credential history of a validator
+	ListCredentialChanges(ctx context.Context, pubkey string) ([]WithdrawalCredentialChange, error)
+}
+
+// ClientKeyRepo defines the interface for validator client discovery data
+type ClientKeyRepo interface {
+	// ReplaceInstanceKeys replaces all keys recorded for a client instance
+	ReplaceInstanceKeys(ctx context.Context, instance string, keys []ClientKey) error
+
+	// ListDoubleLoaded returns the pubkeys loaded on more than one client instance
+	ListDoubleLoaded(ctx context.Context) ([]DoubleLoadedKey, error)
+
+	// ListByPubkey returns the client instances that have a key loaded
+	ListByPubkey(ctx context.Context, pubkey string) ([]ClientKey, error)
+}
+
+// AlertRepo defines the interface for alert data access
+type AlertRepo interface {
+	// Raise stores a new alert and reports whether it was created.
+	// It returns false if an unresolved alert of the same type already exists for the pubkey.
+	Raise(ctx context.Context, a *Alert) (bool, error)
+
+	// ListOpen returns the unresolved alerts of the given type
+	ListOpen(ctx context.Context, alertType string) ([]Alert, error)
+
+	// Resolve marks the unresolved alert of the given type for a pubkey as resolved
+	Resolve(ctx context.Context, alertType, pubkey string) error
+
+	// MarkNotified records that an alert was sent to its notifiers
+	MarkNotified(ctx context.Context, id int64) error
+}
+
+// AuditRepo defines the interface for audit log data access
+type AuditRepo interface {
+	// Record appends an entry to the audit log
+	Record(ctx context.Context, entry *AuditLog) error
+}
+
+// BLSChangeRepo defines the interface for stored BLS-to-execution changes
+type BLSChangeRepo interface {
+	// Save stores a verified change, replacing one for the same key that was not yet submitted
+	Save(ctx context.Context, c *BLSChange) error
+
+	// GetByPubkey retrieves the change of a validator
+	GetByPubkey(ctx context.Context, pubkey string) (*BLSChange, error)
+
+	// List returns the changes with the given status, or all changes if status is empty
+	List(ctx context.Context, status string) ([]BLSChange, error)
+
+	// UpdateStatus records the submission outcome of a change
+	UpdateStatus(ctx context.Context, pubkey, status, errMsg string) error
+
+	// ConfirmApplied marks submitted changes whose validators now have 0x01 credentials as confirmed
+	ConfirmApplied(ctx context.Context) (int64, error)
+}
+
+// VoluntaryExitRepo defines the interface for the pre-signed voluntary exit vault
+type VoluntaryExitRepo interface {
+	// Save stores a verified exit, replacing any earlier one for the same key
+	Save(ctx context.Context, e *VoluntaryExit) error
+
+	// GetByPubkey retrieves the pre-signed exit of a validator
+	GetByPubkey(ctx context.Context, pubkey string) (*VoluntaryExit, error)
+
+	// List returns every stored exit
+	List(ctx context.Context) ([]VoluntaryExit, error)
+
+	// ListMissing returns the active validators without a stored exit for their current index
+	ListMissing(ctx context.Context) ([]Validator, error)
+}
+
+// ExitRequestRepo defines the interface for exit requests and their approval
+type ExitRequestRepo interface {
+	// Create stores a new pending request together with its items
+	Create(ctx context.Context, req *ExitRequest) error
+
+	// Get retrieves a request with its items
+	Get(ctx context.Context, id int64) (*ExitRequest, error)
+
+	// List returns the requests with the given status, or all requests if status is empty
+	List(ctx context.Context, status string) ([]ExitRequest, error)
+
+	// Review moves a pending request to status on behalf of reviewer, who must not be its requester
+	Review(ctx context.Context, id int64, status, reviewer string) error
+
+	// UpdateStatus sets the status of a request
+	UpdateStatus(ctx context.Context, id int64, status string) error
+
+	// UpdateItem records the progress of one validator of a request
+	UpdateItem(ctx context.Context, id int64, pubkey, status, errMsg string) error
+
+	// ListItems returns the items with the given status across all requests
+	ListItems(ctx context.Context, status string) ([]ExitRequestItem, error)
+}
+
+// WithdrawalRequestRepo defines the interface for detected execution layer requests
+type WithdrawalRequestRepo interface {
+	// Record stores a detected request and reports whether it was new
+	Record(ctx context.Context, r *WithdrawalRequest) (bool, error)
+
+	// List returns the detected requests of a validator, or of all validators if pubkey is empty
+	List(ctx context.Context, pubkey string) ([]WithdrawalRequest, error)
+}
+
+// ConsolidationRepo defines the interface for consolidation tracking
+type ConsolidationRepo interface {
+	// Plan records a planned consolidation, replacing a planned one for the same source
+	Plan(ctx context.Context, c *Consolidation) error
+
+	// SetPending records a consolidation seen in the beacon state queue and reports whether it changed
+	SetPending(ctx context.Context, source, target string) (bool, error)
+
+	// UpdateStatus sets the status of the consolidation of a source validator
+	UpdateStatus(ctx context.Context, source, status string) error
+
+	// List returns the consolidations with the given status, or all consolidations if status is empty
+	List(ctx context.Context, status string) ([]Consolidation, error)
+}
+
+// SlashingProtectionRepo defines the interface for stored slashing protection watermarks
+type SlashingProtectionRepo interface {
+	// Merge raises the stored watermarks of a key to those of p, never lowering them.
+	// p is updated to the merged values.
+	Merge(ctx context.Context, p *SlashingProtection) error
+
+	// List returns the watermarks of the given keys that have any
+	List(ctx context.Context, pubkeys []string) ([]SlashingProtection, error)
+}
+
+// KeyMigrationRepo defines the interface for key migrations between validator clients
+type KeyMigrationRepo interface {
+	// Create stores a new pending migration. It returns ErrMigrationInProgress if
+	// the key already has an unfinished migration.
+	Create(ctx context.Context, m *KeyMigration) error
+
+	// Get retrieves a migration by ID
+	Get(ctx context.Context, id int64) (*KeyMigration, error)
+
+	// List returns the migrations with the given status, or all migrations if status is empty
+	List(ctx context.Context, status string) ([]KeyMigration, error)
+
+	// ListUnfinished returns the migrations that have neither completed nor failed, oldest first
+	ListUnfinished(ctx context.Context) ([]KeyMigration, error)
+
+	// Holding returns the latest migration that holds a key: one that has not
+	// finished, or one that failed after the key left its source and kept the keystore
+	Holding(ctx context.Context, pubkey string) (*KeyMigration, error)
+
+	// Update stores the progress of a migration
+	Update(ctx context.Context, m *KeyMigration) error
+}
+
+// DoppelgangerCheckRepo defines the interface for doppelganger checks
+type DoppelgangerCheckRepo interface {
+	// Create stores a new running check. It returns ErrCheckInProgress if the
+	// key already has a running check.
+	Create(ctx context.Context, c *DoppelgangerCheck) error
+
+	// Latest returns the most recent check of a key
+	Latest(ctx context.Context, pubkey string) (*DoppelgangerCheck, error)
+
+	// List returns the checks of a key, or of all keys if pubkey is empty, newest first
+	List(ctx context.Context, pubkey string) ([]DoppelgangerCheck, error)
+
+	// ListRunning returns the checks that are still running
+	ListRunning(ctx context.Context) ([]DoppelgangerCheck, error)
+
+	// Update stores the progress of a check
+	Update(ctx context.Context, c *DoppelgangerCheck) error
+}
+
+// KeystoreRepo defines the interface for stored keystores
+type KeystoreRepo interface {
+	// Save stores a keystore, replacing the one stored for the same key
+	Save(ctx context.Context, k *StoredKeystore) error
+
+	// Get retrieves the keystore of a key
+	Get(ctx context.Context, pubkey string) (*StoredKeystore, error)
+
+	// List returns all stored keystores
+	List(ctx context.Context) ([]StoredKeystore, error)
+
+	// Delete removes the keystore of a key
+	Delete(ctx context.Context, pubkey string) error
+}
+
+// SignerKeyRepo defines the interface for remote signer discovery data
+type SignerKeyRepo interface {
+	// ReplaceSignerKeys replaces all keys recorded for a remote signer
+	ReplaceSignerKeys(ctx context.Context, signer string, keys []SignerKey) error
+
+	// ListByPubkey returns the remote signers that hold a key
+	ListByPubkey(ctx context.Context, pubkey string) ([]SignerKey, error)
+}
+
+// WebhookDeliveryRepo defines the interface for the webhook delivery log
+type WebhookDeliveryRepo interface {
+	// Create stores a new pending delivery, due at d.NextAttemptAt or immediately if it is not set
+	Create(ctx context.Context, d *WebhookDelivery) error
+
+	// Get retrieves a delivery by its ID
+	Get(ctx context.Context, id int64) (*WebhookDelivery, error)
+
+	// List returns the deliveries matching the provided filters, newest first
+	List(ctx context.Context, filters map[string]interface{}) ([]WebhookDelivery, error)
+
+	// ClaimDue claims up to limit pending deliveries whose next attempt is due at
+	// now by moving their next attempt to leaseUntil, skipping rows claimed concurrently
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error)
+
+	// UpdateAttempt stores the outcome of a delivery attempt
+	UpdateAttempt(ctx context.Context, d *WebhookDelivery) error
+}
+
+// SyncRunRepo defines the interface for the outcome of background sync runs
+type SyncRunRepo interface {
+	// RecordSuccess stores a successful run of a sync at the given time
+	RecordSuccess(ctx context.Context, source string, at time.Time) error
+
+	// RecordFailure stores a failed run of a sync at the given time and its error
+	RecordFailure(ctx context.Context, source string, at time.Time, errMsg string) error
+
+	// List returns the recorded outcome of every sync that has run
+	List(ctx context.Context) ([]SyncRun, error)
+}
+
+// AlertSilenceRepo defines the interface for alert silencing windows
+type AlertSilenceRepo interface {
+	// Create stores a new silence
+	Create(ctx context.Context, s *AlertSilence) error
+
+	// List returns the silences that have not ended at now, by start time
+	List(ctx context.Context, now time.Time) ([]AlertSilence, error)
+
+	// Delete removes a silence
+	Delete(ctx context.Context, id int64) error
+}
+
+// OutboxRepo defines the interface for domain events awaiting publication.
+// Events are written by the repositories making the changes they describe.
+type OutboxRepo interface {
+	// ListUnpublished returns up to limit events that have not been published, oldest first
+	ListUnpublished(ctx context.Context, limit int) ([]OutboxEvent, error)
+
+	// MarkPublished records that an event was delivered to every subscriber
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+
+	// ListRange returns the events created in [from, to), oldest first
+	ListRange(ctx context.Context, from, to time.Time) ([]OutboxEvent, error)
 }
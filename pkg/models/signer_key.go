@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SignerKey is a key reported as loaded by a remote signer
+type SignerKey struct {
+	Pubkey     string    `json:"pubkey" db:"pubkey"`
+	Signer     string    `json:"signer" db:"signer"`
+	SignerURL  string    `json:"signer_url" db:"signer_url"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// KeyLocation lists the remote signers holding a key and the validator client
+// instances that sign for it through a remote signer
+type KeyLocation struct {
+	Pubkey  string      `json:"pubkey"`
+	Signers []SignerKey `json:"signers"`
+	Clients []ClientKey `json:"clients"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrGenesisMismatch is returned when slashing protection data of a key comes from another chain
+var ErrGenesisMismatch = errors.New("slashing protection data is for a different genesis validators root")
+
+// SlashingProtection holds the highest block slot and attestation epochs a key
+// is known to have signed, merged from every imported interchange. A nil field
+// means no message of that kind was ever recorded.
+type SlashingProtection struct {
+	Pubkey                string    `json:"pubkey" db:"pubkey"`
+	GenesisValidatorsRoot string    `json:"genesis_validators_root" db:"genesis_validators_root"`
+	BlockSlot             *int64    `json:"block_slot,omitempty" db:"block_slot"`
+	SourceEpoch           *int64    `json:"source_epoch,omitempty" db:"source_epoch"`
+	TargetEpoch           *int64    `json:"target_epoch,omitempty" db:"target_epoch"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
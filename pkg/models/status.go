@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidStatus is returned for a status that is not part of the lifecycle
+var ErrInvalidStatus = errors.New("invalid validator status")
+
+// ErrInvalidTransition is returned when a status change is not allowed
+var ErrInvalidTransition = errors.New("invalid validator status transition")
+
+// Status is the lifecycle state of a validator
+type Status string
+
+// Validator lifecycle states
+const (
+	StatusUnused    Status = "unused"
+	StatusPending   Status = "pending"
+	StatusActive    Status = "active"
+	StatusExiting   Status = "exiting"
+	StatusExited    Status = "exited"
+	StatusWithdrawn Status = "withdrawn"
+	StatusSlashed   Status = "slashed"
+)
+
+// allowedTransitions lists the states each state may move to.
+// Keys progress unused → pending → active → exiting → exited → withdrawn,
+// and can be slashed while active or exiting.
+var allowedTransitions = map[Status][]Status{
+	StatusUnused:    {StatusPending},
+	StatusPending:   {StatusActive},
+	StatusActive:    {StatusExiting, StatusSlashed},
+	StatusExiting:   {StatusExited, StatusSlashed},
+	StatusExited:    {StatusWithdrawn},
+	StatusSlashed:   {StatusExited, StatusWithdrawn},
+	StatusWithdrawn: {},
+}
+
+// TransitionError describes a rejected status change
+type TransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s to %s", ErrInvalidTransition, e.From, e.To)
+}
+
+// Is reports whether target is ErrInvalidTransition
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// ParseStatus converts s to a Status, rejecting unknown values
+func ParseStatus(s string) (Status, error) {
+	status := Status(s)
+	if !status.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, s)
+	}
+	return status, nil
+}
+
+// Valid reports whether s is a known lifecycle state
+func (s Status) Valid() bool {
+	_, ok := allowedTransitions[s]
+	return ok
+}
+
+// HasDuties reports whether validators in state s attest and propose, so a
+// second instance of their key could get them slashed
+func (s Status) HasDuties() bool {
+	return s == StatusActive || s == StatusExiting
+}
+
+// CanTransitionTo reports whether a validator in state s may move to next.
+// Staying in the same state is always allowed.
+func (s Status) CanTransitionTo(next Status) bool {
+	if s == next {
+		return next.Valid()
+	}
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns a *TransitionError if s may not move to next
+func (s Status) CheckTransition(next Status) error {
+	if !next.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, next)
+	}
+	if !s.CanTransitionTo(next) {
+		return &TransitionError{From: s, To: next}
+	}
+	return nil
+}
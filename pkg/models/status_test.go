@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatus_CheckTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        Status
+		to          Status
+		expectedErr error
+	}{
+		{name: "unused to pending", from: StatusUnused, to: StatusPending},
+		{name: "pending to active", from: StatusPending, to: StatusActive},
+		{name: "active to exiting", from: StatusActive, to: StatusExiting},
+		{name: "exiting to exited", from: StatusExiting, to: StatusExited},
+		{name: "exited to withdrawn", from: StatusExited, to: StatusWithdrawn},
+		{name: "active to slashed", from: StatusActive, to: StatusSlashed},
+		{name: "exiting to slashed", from: StatusExiting, to: StatusSlashed},
+		{name: "slashed to exited", from: StatusSlashed, to: StatusExited},
+		{name: "same status", from: StatusActive, to: StatusActive},
+		{name: "exited back to unused", from: StatusExited, to: StatusUnused, expectedErr: ErrInvalidTransition},
+		{name: "unused to active skips pending", from: StatusUnused, to: StatusActive, expectedErr: ErrInvalidTransition},
+		{name: "unused to slashed", from: StatusUnused, to: StatusSlashed, expectedErr: ErrInvalidTransition},
+		{name: "withdrawn is final", from: StatusWithdrawn, to: StatusActive, expectedErr: ErrInvalidTransition},
+		{name: "typo", from: StatusPending, to: Status("activ"), expectedErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.from.CheckTransition(tt.to)
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	status, err := ParseStatus("exiting")
+	if err != nil || status != StatusExiting {
+		t.Errorf("expected exiting, got %q, %v", status, err)
+	}
+
+	if _, err := ParseStatus("activ"); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestStatus_HasDuties(t *testing.T) {
+	for _, status := range []Status{StatusActive, StatusExiting} {
+		if !status.HasDuties() {
+			t.Errorf("expected %s to have duties", status)
+		}
+	}
+	for _, status := range []Status{StatusUnused, StatusPending, StatusExited, StatusWithdrawn, StatusSlashed} {
+		if status.HasDuties() {
+			t.Errorf("expected %s to have no duties", status)
+		}
+	}
+}
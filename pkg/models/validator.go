@@ -10,12 +10,65 @@ var ErrNotFound = errors.New("not found")
 
 // Validator represents a validator in the system
 type Validator struct {
-	ID                int64     `json:"id" db:"id"`
-	Pubkey            string    `json:"pubkey" db:"pubkey"`
-	Blockchain        string    `json:"blockchain" db:"blockchain"`
-	BlockchainNetwork string    `json:"blockchain_network" db:"blockchain_network"`
-	Status            string    `json:"status" db:"status"`
-	Client            string    `json:"client,omitempty" db:"client"`
-	CreatedAt         time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	ID                int64  `json:"id" db:"id"`
+	Pubkey            string `json:"pubkey" db:"pubkey"`
+	Blockchain        string `json:"blockchain" db:"blockchain"`
+	BlockchainNetwork string `json:"blockchain_network" db:"blockchain_network"`
+	Status            Status `json:"status" db:"status"`
+	Client            string `json:"client,omitempty" db:"client"`
+	ClientInstance    string `json:"client_instance,omitempty" db:"client_instance"`
+	LidoUploaded      bool   `json:"lido_uploaded" db:"lido_uploaded"`
+	LidoModule        string `json:"lido_module,omitempty" db:"lido_module"`
+	LidoOperatorID    *int64 `json:"lido_operator_id,omitempty" db:"lido_operator_id"`
+	LidoKeyIndex      *int64 `json:"lido_key_index,omitempty" db:"lido_key_index"`
+	LidoKeyState      string `json:"lido_key_state,omitempty" db:"lido_key_state"`
+	// Beacon chain metadata, set once the key has been seen by a beacon node.
+	// Epochs are nil while unknown or at FAR_FUTURE_EPOCH.
+	ValidatorIndex        *int64    `json:"validator_index,omitempty" db:"validator_index"`
+	EffectiveBalance      int64     `json:"effective_balance" db:"effective_balance"`
+	ActivationEpoch       *int64    `json:"activation_epoch,omitempty" db:"activation_epoch"`
+	ExitEpoch             *int64    `json:"exit_epoch,omitempty" db:"exit_epoch"`
+	WithdrawableEpoch     *int64    `json:"withdrawable_epoch,omitempty" db:"withdrawable_epoch"`
+	Slashed               bool      `json:"slashed" db:"slashed"`
+	WithdrawalCredentials string    `json:"withdrawal_credentials,omitempty" db:"withdrawal_credentials"`
+	CreatedAt             time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Lido staking modules a key can be uploaded to
+const (
+	// LidoModuleCurated is the curated NodeOperatorsRegistry module
+	LidoModuleCurated = "curated"
+	// LidoModuleCSM is the Community Staking Module
+	LidoModuleCSM = "csm"
+)
+
+// Lido key states, derived from a key's index within its node operator's key list
+const (
+	// LidoKeyStateDeposited keys have received a deposit
+	LidoKeyStateDeposited = "deposited"
+	// LidoKeyStateVetted keys are approved for deposit but not yet deposited
+	LidoKeyStateVetted = "vetted"
+	// LidoKeyStateUnvetted keys are submitted but above the operator's staking limit
+	LidoKeyStateUnvetted = "unvetted"
+)
+
+// LidoStatus describes whether a key is uploaded to a Lido node operator registry
+type LidoStatus struct {
+	Uploaded   bool   `json:"uploaded"`
+	Module     string `json:"module,omitempty"`
+	OperatorID *int64 `json:"operator_id,omitempty"`
+	KeyIndex   *int64 `json:"key_index,omitempty"`
+	KeyState   string `json:"key_state,omitempty"`
+}
+
+// BeaconMetadata is the beacon chain state of a validator. EffectiveBalance is in gwei.
+type BeaconMetadata struct {
+	Index                 *int64 `json:"index,omitempty"`
+	EffectiveBalance      int64  `json:"effective_balance"`
+	ActivationEpoch       *int64 `json:"activation_epoch,omitempty"`
+	ExitEpoch             *int64 `json:"exit_epoch,omitempty"`
+	WithdrawableEpoch     *int64 `json:"withdrawable_epoch,omitempty"`
+	Slashed               bool   `json:"slashed"`
+	WithdrawalCredentials string `json:"withdrawal_credentials,omitempty"`
 }
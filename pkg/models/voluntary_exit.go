@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// VoluntaryExit is a stored pre-signed SignedVoluntaryExit. Message holds the encrypted signed message.
+type VoluntaryExit struct {
+	ID             int64     `json:"id" db:"id"`
+	Pubkey         string    `json:"pubkey" db:"pubkey"`
+	ValidatorIndex int64     `json:"validator_index" db:"validator_index"`
+	Epoch          int64     `json:"epoch" db:"epoch"`
+	Message        []byte    `json:"-" db:"message"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	// WebhookDeliveryPending deliveries are waiting for their next attempt
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliveryDelivered deliveries were accepted by the receiver
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryFailed deliveries were given up after the last attempt
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookDelivery is one event payload sent, or to be sent, to one webhook
+type WebhookDelivery struct {
+	ID      int64           `json:"id" db:"id"`
+	Webhook string          `json:"webhook" db:"webhook"`
+	Event   string          `json:"event" db:"event"`
+	URL     string          `json:"url" db:"url"`
+	Payload json.RawMessage `json:"payload" db:"payload"`
+	Status  string          `json:"status" db:"status"`
+	// Attempts is the number of times the payload was sent
+	Attempts      int        `json:"attempts" db:"attempts"`
+	ResponseCode  *int       `json:"response_code,omitempty" db:"response_code"`
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	// RedeliveryOf is the delivery this one resends
+	RedeliveryOf *int64     `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Withdrawal credential types, named after their 0x prefix byte
+const (
+	// WithdrawalCredentialsBLS (0x00) credentials commit to a BLS withdrawal key
+	WithdrawalCredentialsBLS = "bls"
+	// WithdrawalCredentialsExecution (0x01) credentials withdraw to an execution address
+	WithdrawalCredentialsExecution = "execution"
+	// WithdrawalCredentialsCompounding (0x02) credentials withdraw to an execution address and compound rewards
+	WithdrawalCredentialsCompounding = "compounding"
+	// WithdrawalCredentialsUnknown covers missing or unrecognised credentials
+	WithdrawalCredentialsUnknown = "unknown"
+)
+
+// Sources a withdrawal credential change can be recorded from
+const (
+	CredentialsSourceDepositData = "deposit_data"
+	CredentialsSourceBeacon      = "beacon"
+)
+
+// WithdrawalCredentialsType returns the type of 0x-prefixed withdrawal credentials
+func WithdrawalCredentialsType(creds string) string {
+	if len(creds) != 66 {
+		return WithdrawalCredentialsUnknown
+	}
+	switch strings.ToLower(creds[:4]) {
+	case "0x00":
+		return WithdrawalCredentialsBLS
+	case "0x01":
+		return WithdrawalCredentialsExecution
+	case "0x02":
+		return WithdrawalCredentialsCompounding
+	default:
+		return WithdrawalCredentialsUnknown
+	}
+}
+
+// WithdrawalAddress returns the execution address of 0x01 or 0x02 credentials,
+// or an empty string for any other type
+func WithdrawalAddress(creds string) string {
+	switch WithdrawalCredentialsType(creds) {
+	case WithdrawalCredentialsExecution, WithdrawalCredentialsCompounding:
+		return "0x" + strings.ToLower(creds[26:])
+	default:
+		return ""
+	}
+}
+
+// WithdrawalCredentialChange records a change of a validator's withdrawal credentials
+type WithdrawalCredentialChange struct {
+	ID        int64     `json:"id" db:"id"`
+	Pubkey    string    `json:"pubkey" db:"pubkey"`
+	OldValue  string    `json:"old_credentials" db:"old_credentials"`
+	NewValue  string    `json:"new_credentials" db:"new_credentials"`
+	Source    string    `json:"source" db:"source"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// DepositData is one entry of a deposit_data-*.json file produced by the staking deposit CLI
+type DepositData struct {
+	Pubkey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	NetworkName           string `json:"network_name"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Alert types raised for execution layer requests
+const (
+	// AlertTypeUnexpectedExit is raised when a validator exits without an approved exit request
+	AlertTypeUnexpectedExit = "unexpected_exit"
+	// AlertTypeELWithdrawal is raised when a partial withdrawal is requested from the execution layer
+	AlertTypeELWithdrawal = "el_withdrawal"
+)
+
+// WithdrawalRequest is an exit or partial withdrawal of one of our validators
+// that was not initiated through this service. Following EIP-7002, an Amount of
+// zero is a full exit. SourceAddress is the withdrawal address, the only account
+// able to trigger a request from the execution layer; it is empty for validators
+// with 0x00 credentials, which can only have been exited with the validator key.
+type WithdrawalRequest struct {
+	ID             int64     `json:"id" db:"id"`
+	Pubkey         string    `json:"pubkey" db:"pubkey"`
+	ValidatorIndex int64     `json:"validator_index" db:"validator_index"`
+	Amount         int64     `json:"amount" db:"amount"`
+	Epoch          int64     `json:"epoch" db:"epoch"`
+	SourceAddress  string    `json:"source_address,omitempty" db:"source_address"`
+	DetectedAt     time.Time `json:"detected_at" db:"detected_at"`
+}
+
+// IsExit reports whether the request exits the validator
+func (r WithdrawalRequest) IsExit() bool {
+	return r.Amount == 0
+}
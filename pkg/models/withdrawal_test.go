@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithdrawalCredentialsType(t *testing.T) {
+	tests := []struct {
+		name            string
+		creds           string
+		expectedType    string
+		expectedAddress string
+	}{
+		{
+			name:         "bls",
+			creds:        "0x00" + strings.Repeat("ab", 31),
+			expectedType: WithdrawalCredentialsBLS,
+		},
+		{
+			name:            "execution",
+			creds:           "0x010000000000000000000000D8DA6BF26964AF9D7EED9E03E53415D37AA96045",
+			expectedType:    WithdrawalCredentialsExecution,
+			expectedAddress: "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+		},
+		{
+			name:            "compounding",
+			creds:           "0x020000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045",
+			expectedType:    WithdrawalCredentialsCompounding,
+			expectedAddress: "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+		},
+		{
+			name:         "empty",
+			creds:        "",
+			expectedType: WithdrawalCredentialsUnknown,
+		},
+		{
+			name:         "unknown prefix",
+			creds:        "0x03" + strings.Repeat("00", 31),
+			expectedType: WithdrawalCredentialsUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithdrawalCredentialsType(tt.creds); got != tt.expectedType {
+				t.Errorf("expected type %q, got %q", tt.expectedType, got)
+			}
+			if got := WithdrawalAddress(tt.creds); got != tt.expectedAddress {
+				t.Errorf("expected address %q, got %q", tt.expectedAddress, got)
+			}
+		})
+	}
+}
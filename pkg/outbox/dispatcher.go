@@ -0,0 +1,142 @@
+// Package outbox publishes the domain events written to the outbox table to
+// in-process subscribers
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ErrUnknownSubscriber is returned when replaying to a subscriber that is not registered
+var ErrUnknownSubscriber = errors.New("unknown outbox subscriber")
+
+// batchSize is the number of events read from the outbox at a time
+const batchSize = 100
+
+// Handler processes one event. Delivery is at least once: an event is handed
+// to a handler again after a crash or a failure of another subscriber, and
+// again on replay, so handlers must tolerate duplicates.
+type Handler func(ctx context.Context, e models.OutboxEvent) error
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Dispatcher publishes outbox events to the registered subscribers in the
+// order they were written
+type Dispatcher struct {
+	repo        models.OutboxRepo
+	subscribers []subscriber
+}
+
+// NewDispatcher creates a new outbox dispatcher
+func NewDispatcher(repo models.OutboxRepo) *Dispatcher {
+	return &Dispatcher{repo: repo}
+}
+
+// Subscribe registers a handler called for every event under a unique name.
+// Subscribers must be registered before the dispatcher is started.
+func (d *Dispatcher) Subscribe(name string, h Handler) {
+	d.subscribers = append(d.subscribers, subscriber{name: name, handle: h})
+}
+
+// Subscribers returns the names of the registered subscribers
+func (d *Dispatcher) Subscribers() []string {
+	names := make([]string, 0, len(d.subscribers))
+	for _, s := range d.subscribers {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// Run publishes every unpublished event. An event is marked published once
+// every subscriber has handled it. If any subscriber fails the run stops, so
+// the event and the ones after it are published again by the next run.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		events, err := d.repo.ListUnpublished(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := d.publish(ctx, e, ""); err != nil {
+				return err
+			}
+			if err := d.repo.MarkPublished(ctx, e.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
+
+// Start publishes pending events immediately and then on every interval until ctx is done
+func (d *Dispatcher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Run(ctx); err != nil {
+			log.Printf("Outbox dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replay publishes the events created in [from, to) again to the named
+// subscriber, or to every subscriber if name is empty, and returns the number
+// of events replayed. Whether an event was published before is ignored and
+// left unchanged. Replay stops at the first event a subscriber fails.
+func (d *Dispatcher) Replay(ctx context.Context, from, to time.Time, name string) (int, error) {
+	if name != "" && !d.subscribed(name) {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownSubscriber, name)
+	}
+
+	events, err := d.repo.ListRange(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range events {
+		if err := d.publish(ctx, e, name); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+func (d *Dispatcher) subscribed(name string) bool {
+	for _, s := range d.subscribers {
+		if s.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// publish hands an event to the named subscriber, or every subscriber if name
+// is empty. Every subscriber is called even if an earlier one fails.
+func (d *Dispatcher) publish(ctx context.Context, e models.OutboxEvent, name string) error {
+	var errs []error
+	for _, s := range d.subscribers {
+		if name != "" && s.name != name {
+			continue
+		}
+		if err := s.handle(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %q failed on event %d: %w", s.name, e.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// recorder is a subscriber that records the events it handles and fails on failOn
+type recorder struct {
+	handled []int64
+	failOn  int64
+}
+
+func (r *recorder) handle(_ context.Context, e models.OutboxEvent) error {
+	r.handled = append(r.handled, e.ID)
+	if e.ID == r.failOn {
+		return errors.New("subscriber unavailable")
+	}
+	return nil
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	events := []models.OutboxEvent{
+		{ID: 1, Type: models.EventValidatorCreated},
+		{ID: 2, Type: models.EventValidatorStatusChanged},
+		{ID: 3, Type: models.EventValidatorStatusChanged},
+	}
+
+	tests := []struct {
+		name          string
+		failOn        int64
+		published     []int64
+		expectedOther []int64
+		expectError   bool
+	}{
+		{name: "every event published", published: []int64{1, 2, 3}, expectedOther: []int64{1, 2, 3}},
+		{name: "failed subscriber stops the run", failOn: 2, published: []int64{1}, expectedOther: []int64{1, 2}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOutboxRepo(ctrl)
+			mockRepo.EXPECT().ListUnpublished(gomock.Any(), batchSize).Return(events, nil)
+			var published []int64
+			mockRepo.EXPECT().MarkPublished(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int64, _ time.Time) error {
+				published = append(published, id)
+				return nil
+			}).AnyTimes()
+
+			failing := &recorder{failOn: tt.failOn}
+			other := &recorder{}
+			d := NewDispatcher(mockRepo)
+			d.Subscribe("failing", failing.handle)
+			d.Subscribe("other", other.handle)
+
+			err := d.Run(context.Background())
+			if tt.expectError {
+				assert.ErrorContains(t, err, `subscriber "failing" failed on event 2`)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.published, published)
+			// Every subscriber sees the failed event, which is delivered again by the next run
+			assert.Equal(t, tt.expectedOther, other.handled)
+		})
+	}
+}
+
+func TestDispatcher_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOutboxRepo(ctrl)
+	ctx := context.Background()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	webhooks := &recorder{}
+	metrics := &recorder{}
+	d := NewDispatcher(mockRepo)
+	d.Subscribe("webhooks", webhooks.handle)
+	d.Subscribe("metrics", metrics.handle)
+	assert.Equal(t, []string{"webhooks", "metrics"}, d.Subscribers())
+
+	mockRepo.EXPECT().ListRange(ctx, from, to).Return([]models.OutboxEvent{{ID: 4}, {ID: 5}}, nil)
+	n, err := d.Replay(ctx, from, to, "webhooks")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []int64{4, 5}, webhooks.handled)
+	assert.Empty(t, metrics.handled)
+
+	_, err = d.Replay(ctx, from, to, "history")
+	assert.ErrorIs(t, err, ErrUnknownSubscriber)
+}
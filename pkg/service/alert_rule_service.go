@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/alerting"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ErrInvalidSilence is returned for an alert silence that cannot be created
+var ErrInvalidSilence = errors.New("invalid alert silence")
+
+// inactivity tracks the consecutive epochs each key of an inactive rule was not live
+type inactivity struct {
+	epoch  int64
+	missed map[string]int64
+}
+
+// AlertRuleService evaluates the configured alert rules after every sync and on
+// an interval. An alert is raised and sent to the rule's notifiers when its
+// condition starts to hold, unless the rule is silenced, and resolved once the
+// condition clears. An open alert is never raised twice; one whose notification
+// failed is sent again by every evaluation until it goes through.
+type AlertRuleService struct {
+	validators models.ValidatorRepo
+	alerts     models.AlertRepo
+	silences   models.AlertSilenceRepo
+	syncRuns   models.SyncRunRepo
+	audit      models.AuditRepo
+	nodes      beacon.Nodes
+	rules      []alerting.Rule
+	notifiers  []alerting.Notifier
+	started    time.Time
+
+	mu         sync.Mutex
+	inactivity map[string]*inactivity
+}
+
+// NewAlertRuleService creates a new alert rule service for the given rules and notifiers
+func NewAlertRuleService(validators models.ValidatorRepo, alerts models.AlertRepo, silences models.AlertSilenceRepo,
+	syncRuns models.SyncRunRepo, audit models.AuditRepo, nodes beacon.Nodes, rules []alerting.Rule, notifiers []alerting.Notifier) *AlertRuleService {
+	return &AlertRuleService{
+		validators: validators,
+		alerts:     alerts,
+		silences:   silences,
+		syncRuns:   syncRuns,
+		audit:      audit,
+		nodes:      nodes,
+		rules:      rules,
+		notifiers:  notifiers,
+		started:    time.Now(),
+		inactivity: make(map[string]*inactivity),
+	}
+}
+
+// Rules returns the configured alert rules
+func (s *AlertRuleService) Rules() []alerting.Rule {
+	return s.rules
+}
+
+// SyncCompleted returns a completion hook that records the outcome of every run
+// of the named background sync and evaluates the rules
+func (s *AlertRuleService) SyncCompleted(source string) func(ctx context.Context, err error) {
+	return func(ctx context.Context, err error) {
+		var recordErr error
+		if err != nil {
+			recordErr = s.syncRuns.RecordFailure(ctx, source, time.Now(), err.Error())
+		} else {
+			recordErr = s.syncRuns.RecordSuccess(ctx, source, time.Now())
+		}
+		if recordErr != nil {
+			log.Printf("Failed to record %s sync run: %v", source, recordErr)
+		}
+		if err := s.Evaluate(ctx); err != nil {
+			log.Printf("Alert rule evaluation failed: %v", err)
+		}
+	}
+}
+
+// SyncRuns returns the recorded outcome of every background sync
+func (s *AlertRuleService) SyncRuns(ctx context.Context) ([]models.SyncRun, error) {
+	return s.syncRuns.List(ctx)
+}
+
+// Start evaluates the rules immediately and then on every interval until ctx is
+// done. Inactive rules need an evaluation at least once per epoch.
+func (s *AlertRuleService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Evaluate(ctx); err != nil {
+			log.Printf("Alert rule evaluation failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate checks every rule once. A rule that cannot be checked keeps its open
+// alerts and does not stop the others.
+func (s *AlertRuleService) Evaluate(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	silences, err := s.silences.List(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rule := range s.rules {
+		silenced := slices.ContainsFunc(silences, func(silence models.AlertSilence) bool {
+			return silence.Silences(rule.Name, now)
+		})
+		if err := s.evaluate(ctx, rule, silenced, now); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *AlertRuleService) evaluate(ctx context.Context, rule alerting.Rule, silenced bool, now time.Time) error {
+	firing, err := s.check(ctx, rule, now)
+	if err != nil {
+		return err
+	}
+
+	if !silenced {
+		for _, pubkey := range sortedKeys(firing) {
+			_, err := s.alerts.Raise(ctx, &models.Alert{Type: rule.AlertType(), Severity: rule.Severity,
+				Pubkey: pubkey, Message: firing[pubkey]})
+			if err != nil {
+				return fmt.Errorf("failed to raise alert: %w", err)
+			}
+		}
+	}
+
+	open, err := s.alerts.ListOpen(ctx, rule.AlertType())
+	if err != nil {
+		return err
+	}
+	// Notifier failures are reported once every alert is stored
+	var errs []error
+	for _, a := range open {
+		if _, ok := firing[a.Pubkey]; ok {
+			if a.NotifiedAt == nil && !silenced {
+				errs = append(errs, s.notifyRaised(ctx, rule, a, now))
+			}
+			continue
+		}
+		if err := s.alerts.Resolve(ctx, rule.AlertType(), a.Pubkey); err != nil {
+			return fmt.Errorf("failed to resolve alert: %w", err)
+		}
+		// An alert that was never sent is not reported as resolved either
+		if a.NotifiedAt != nil {
+			errs = append(errs, s.notify(ctx, rule, a.Message, true, now))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifyRaised sends a raised alert to the rule's notifiers and records that it
+// was sent. If any notifier fails it is sent again, to all of them, by the next
+// evaluation.
+func (s *AlertRuleService) notifyRaised(ctx context.Context, rule alerting.Rule, a models.Alert, now time.Time) error {
+	if err := s.notify(ctx, rule, a.Message, false, now); err != nil {
+		return err
+	}
+	if err := s.alerts.MarkNotified(ctx, a.ID); err != nil {
+		return fmt.Errorf("failed to mark alert notified: %w", err)
+	}
+	return nil
+}
+
+// check returns the alert message of every pubkey the rule fires for. Rules
+// about a whole network or sync fire for the empty pubkey.
+func (s *AlertRuleService) check(ctx context.Context, rule alerting.Rule, now time.Time) (map[string]string, error) {
+	switch rule.Type {
+	case alerting.RuleStatus:
+		return s.checkStatus(ctx, rule)
+	case alerting.RuleInactive:
+		return s.checkInactive(ctx, rule)
+	case alerting.RuleSyncStale:
+		return s.checkSyncStale(ctx, rule, now)
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+func (s *AlertRuleService) checkStatus(ctx context.Context, rule alerting.Rule) (map[string]string, error) {
+	filters := map[string]interface{}{"status": string(rule.Status)}
+	if rule.Blockchain != "" {
+		filters["blockchain"] = rule.Blockchain
+	}
+	if rule.Network != "" {
+		filters["blockchain_network"] = rule.Network
+	}
+	validators, err := s.validators.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	firing := make(map[string]string, len(validators))
+	for _, v := range validators {
+		firing[v.Pubkey] = fmt.Sprintf("Validator %s on %s %s is %s", v.Pubkey, v.Blockchain, v.BlockchainNetwork, v.Status)
+	}
+	return firing, nil
+}
+
+// checkInactive counts the active keys that were not live in each of the last
+// rule.Epochs finished epochs. Beacon nodes only answer for the previous epoch,
+// so the missed epochs are counted across evaluations and start over when an
+// epoch goes unchecked.
+func (s *AlertRuleService) checkInactive(ctx context.Context, rule alerting.Rule) (map[string]string, error) {
+	node, ok := s.nodes.Get(rule.Blockchain, rule.Network)
+	if !ok {
+		return nil, fmt.Errorf("no beacon node configured for %s %s", rule.Blockchain, rule.Network)
+	}
+	head, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := s.inactivity[rule.Name]
+	epoch := int64(head) - 1
+	if state == nil || epoch > state.epoch {
+		validators, err := s.validators.List(ctx, map[string]interface{}{
+			"blockchain":         rule.Blockchain,
+			"blockchain_network": rule.Network,
+			"status":             string(models.StatusActive),
+		})
+		if err != nil {
+			return nil, err
+		}
+		indices := make(map[string]string, len(validators))
+		for _, v := range validators {
+			if v.ValidatorIndex != nil {
+				indices[strconv.FormatInt(*v.ValidatorIndex, 10)] = v.Pubkey
+			}
+		}
+
+		live := map[string]bool{}
+		if len(indices) > 0 {
+			if live, err = node.Liveness(ctx, uint64(epoch), sortedKeys(indices)); err != nil {
+				return nil, err
+			}
+		}
+
+		next := &inactivity{epoch: epoch, missed: make(map[string]int64)}
+		for index, pubkey := range indices {
+			if live[index] {
+				continue
+			}
+			next.missed[pubkey] = 1
+			if state != nil && state.epoch == epoch-1 {
+				next.missed[pubkey] += state.missed[pubkey]
+			}
+		}
+		state = next
+		s.inactivity[rule.Name] = state
+	}
+
+	inactive := 0
+	for _, missed := range state.missed {
+		if missed >= rule.Epochs {
+			inactive++
+		}
+	}
+	if inactive <= rule.Threshold {
+		return nil, nil
+	}
+	return map[string]string{"": fmt.Sprintf("%d %s %s keys were inactive for the last %d epochs up to epoch %d (threshold %d)",
+		inactive, rule.Blockchain, rule.Network, rule.Epochs, state.epoch, rule.Threshold)}, nil
+}
+
+// checkSyncStale fires when the sync has not succeeded within rule.MaxAge. A
+// sync that never succeeded is measured from the start of the service.
+func (s *AlertRuleService) checkSyncStale(ctx context.Context, rule alerting.Rule, now time.Time) (map[string]string, error) {
+	runs, err := s.syncRuns.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since, lastError := s.started, ""
+	for _, run := range runs {
+		if run.Source != rule.Source {
+			continue
+		}
+		if run.LastSuccessAt != nil {
+			since = *run.LastSuccessAt
+		}
+		lastError = run.LastError
+	}
+	if now.Sub(since) <= rule.MaxAge.Duration {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("The %s sync has not succeeded since %s", rule.Source, since.UTC().Format(time.RFC3339))
+	if lastError != "" {
+		message += ": " + lastError
+	}
+	return map[string]string{"": message}, nil
+}
+
+// notify sends an alert to the rule's notifiers. Every notifier is tried even if an earlier one fails.
+func (s *AlertRuleService) notify(ctx context.Context, rule alerting.Rule, message string, resolved bool, now time.Time) error {
+	n := alerting.Notification{Rule: rule.Name, Severity: rule.Severity, Message: message, Resolved: resolved, Time: now}
+	var errs []error
+	for _, notifier := range s.notifiers {
+		if len(rule.Notify) > 0 && !slices.Contains(rule.Notify, notifier.Name()) {
+			continue
+		}
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListSilences returns the silences that have not ended
+func (s *AlertRuleService) ListSilences(ctx context.Context) ([]models.AlertSilence, error) {
+	return s.silences.List(ctx, time.Now())
+}
+
+// CreateSilence stops a rule, or every rule if silence.Rule is empty, from
+// raising alerts until silence.EndsAt. A silence without a start begins now.
+func (s *AlertRuleService) CreateSilence(ctx context.Context, silence *models.AlertSilence, sourceIP string) error {
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if silence.Reason == "" {
+		return fmt.Errorf("%w: a reason is required", ErrInvalidSilence)
+	}
+	if !silence.EndsAt.After(silence.StartsAt) || !silence.EndsAt.After(now) {
+		return fmt.Errorf("%w: ends_at must be after starts_at and in the future", ErrInvalidSilence)
+	}
+	if silence.Rule != "" && !slices.ContainsFunc(s.rules, func(r alerting.Rule) bool { return r.Name == silence.Rule }) {
+		return fmt.Errorf("%w: unknown rule %q", ErrInvalidSilence, silence.Rule)
+	}
+
+	if err := s.silences.Create(ctx, silence); err != nil {
+		return err
+	}
+
+	rule := silence.Rule
+	if rule == "" {
+		rule = "all rules"
+	}
+	details := fmt.Sprintf("Silence %d for %s from %s to %s by %s: %s", silence.ID, rule,
+		silence.StartsAt.UTC().Format(time.RFC3339), silence.EndsAt.UTC().Format(time.RFC3339), silence.CreatedBy, silence.Reason)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "alert.silence_created", SourceIP: sourceIP, Details: details}); err != nil {
+		return fmt.Errorf("failed to record alert silence: %w", err)
+	}
+	return nil
+}
+
+// DeleteSilence removes a silence, ending it immediately
+func (s *AlertRuleService) DeleteSilence(ctx context.Context, id int64, sourceIP string) error {
+	if err := s.silences.Delete(ctx, id); err != nil {
+		return err
+	}
+	details := fmt.Sprintf("Silence %d deleted", id)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "alert.silence_deleted", SourceIP: sourceIP, Details: details}); err != nil {
+		return fmt.Errorf("failed to record alert silence deletion: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/alerting"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// fakeNotifier records the notifications it is sent
+type fakeNotifier struct {
+	name string
+	sent []alerting.Notification
+	err  error
+}
+
+func (f *fakeNotifier) Name() string { return f.name }
+
+func (f *fakeNotifier) Notify(_ context.Context, n alerting.Notification) error {
+	f.sent = append(f.sent, n)
+	return f.err
+}
+
+func TestAlertRuleService_StatusRule(t *testing.T) {
+	rule := alerting.Rule{Name: "mainnet-slashed", Type: alerting.RuleStatus, Severity: models.SeverityCritical,
+		Network: "mainnet", Status: models.StatusSlashed, Notify: []string{"ops"}}
+
+	tests := []struct {
+		name         string
+		silences     []models.AlertSilence
+		expectRaise  bool
+		expectedSent []string
+	}{
+		{
+			name:         "raises new alerts and resolves cleared ones",
+			expectRaise:  true,
+			expectedSent: []string{"[CRITICAL] mainnet-slashed", "[RESOLVED] mainnet-slashed"},
+		},
+		{
+			name: "silenced rule still resolves",
+			silences: []models.AlertSilence{
+				{Rule: "mainnet-slashed", StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(time.Hour)},
+			},
+			expectedSent: []string{"[RESOLVED] mainnet-slashed"},
+		},
+		{
+			name: "silence that has not started",
+			silences: []models.AlertSilence{
+				{StartsAt: time.Now().Add(time.Hour), EndsAt: time.Now().Add(2 * time.Hour)},
+			},
+			expectRaise:  true,
+			expectedSent: []string{"[CRITICAL] mainnet-slashed", "[RESOLVED] mainnet-slashed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockAlerts := mocks.NewMockAlertRepo(ctrl)
+			mockSilences := mocks.NewMockAlertSilenceRepo(ctrl)
+			ops, other := &fakeNotifier{name: "ops"}, &fakeNotifier{name: "other"}
+			service := NewAlertRuleService(mockValidators, mockAlerts, mockSilences, nil, nil, beacon.Nodes{},
+				[]alerting.Rule{rule}, []alerting.Notifier{ops, other})
+			ctx := context.Background()
+
+			mockSilences.EXPECT().List(ctx, gomock.Any()).Return(tt.silences, nil)
+			mockValidators.EXPECT().List(ctx, map[string]interface{}{"status": "slashed", "blockchain_network": "mainnet"}).
+				Return([]models.Validator{
+					{Pubkey: testKey1, Blockchain: "ethereum", BlockchainNetwork: "mainnet", Status: models.StatusSlashed},
+					{Pubkey: testKey2, Blockchain: "ethereum", BlockchainNetwork: "mainnet", Status: models.StatusSlashed},
+				}, nil)
+			// testKey1 was already notified, so only testKey2 is sent
+			notified := time.Now().Add(-time.Hour)
+			open := []models.Alert{
+				{ID: 1, Pubkey: testKey1, NotifiedAt: &notified},
+				{ID: 3, Pubkey: "0xgone", Message: "Validator 0xgone on ethereum mainnet is slashed", NotifiedAt: &notified},
+			}
+			if tt.expectRaise {
+				mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+					assert.Equal(t, "rule:mainnet-slashed", a.Type)
+					assert.Equal(t, models.SeverityCritical, a.Severity)
+					return a.Pubkey == testKey2, nil
+				}).Times(2)
+				open = append(open, models.Alert{ID: 2, Pubkey: testKey2, Message: "Validator " + testKey2 + " on ethereum mainnet is slashed"})
+				mockAlerts.EXPECT().MarkNotified(ctx, int64(2)).Return(nil)
+			}
+			mockAlerts.EXPECT().ListOpen(ctx, "rule:mainnet-slashed").Return(open, nil)
+			mockAlerts.EXPECT().Resolve(ctx, "rule:mainnet-slashed", "0xgone").Return(nil)
+
+			require.NoError(t, service.Evaluate(ctx))
+			var titles []string
+			for _, n := range ops.sent {
+				titles = append(titles, n.Title())
+			}
+			assert.ElementsMatch(t, tt.expectedSent, titles)
+			assert.Empty(t, other.sent)
+			for _, n := range ops.sent {
+				if !n.Resolved {
+					assert.Contains(t, n.Message, testKey2)
+				}
+			}
+		})
+	}
+}
+
+func TestAlertRuleService_InactiveRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := &chainStub{epoch: 11, live: map[string]bool{"3": true}}
+	srv := newChainStub(t, chain)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	rule := alerting.Rule{Name: "inactive", Type: alerting.RuleInactive, Severity: models.SeverityWarning,
+		Blockchain: "ethereum", Network: "mainnet", Threshold: 1, Epochs: 2}
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockAlerts := mocks.NewMockAlertRepo(ctrl)
+	mockSilences := mocks.NewMockAlertSilenceRepo(ctrl)
+	ops := &fakeNotifier{name: "ops"}
+	service := NewAlertRuleService(mockValidators, mockAlerts, mockSilences, nil, nil, nodes,
+		[]alerting.Rule{rule}, []alerting.Notifier{ops})
+	ctx := context.Background()
+
+	index1, index2, index3 := int64(1), int64(2), int64(3)
+	mockSilences.EXPECT().List(ctx, gomock.Any()).Return(nil, nil).AnyTimes()
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{
+		"blockchain": "ethereum", "blockchain_network": "mainnet", "status": "active",
+	}).Return([]models.Validator{
+		{Pubkey: testKey1, ValidatorIndex: &index1},
+		{Pubkey: testKey2, ValidatorIndex: &index2},
+		{Pubkey: "0x" + strings.Repeat("c3", 48), ValidatorIndex: &index3},
+		{Pubkey: "0x" + strings.Repeat("d4", 48)},
+	}, nil).Times(3)
+
+	// Two keys missed epoch 10, which is below the two epochs the rule needs
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:inactive").Return(nil, nil)
+	require.NoError(t, service.Evaluate(ctx))
+
+	// They missed epoch 11 as well
+	chain.epoch = 12
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+		assert.Equal(t, "", a.Pubkey)
+		assert.Equal(t, "2 ethereum mainnet keys were inactive for the last 2 epochs up to epoch 11 (threshold 1)", a.Message)
+		return true, nil
+	})
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:inactive").Return([]models.Alert{{ID: 1, Pubkey: ""}}, nil)
+	mockAlerts.EXPECT().MarkNotified(ctx, int64(1)).Return(nil)
+	require.NoError(t, service.Evaluate(ctx))
+	require.Len(t, ops.sent, 1)
+
+	// Evaluating again within the epoch reuses the last liveness check
+	notified := time.Now()
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).Return(false, nil)
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:inactive").Return([]models.Alert{{ID: 1, Pubkey: "", NotifiedAt: &notified}}, nil)
+	require.NoError(t, service.Evaluate(ctx))
+	require.Len(t, ops.sent, 1)
+
+	// One key is back, leaving one inactive key which the threshold tolerates
+	chain.epoch = 13
+	chain.live["1"] = true
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:inactive").Return([]models.Alert{{ID: 1, Pubkey: "", NotifiedAt: &notified}}, nil)
+	mockAlerts.EXPECT().Resolve(ctx, "rule:inactive", "").Return(nil)
+	require.NoError(t, service.Evaluate(ctx))
+	require.Len(t, ops.sent, 2)
+	assert.True(t, ops.sent[1].Resolved)
+}
+
+func TestAlertRuleService_SyncStaleRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rule := alerting.Rule{Name: "beacon-stale", Type: alerting.RuleSyncStale, Severity: models.SeverityCritical,
+		Source: models.SyncSourceBeacon, MaxAge: alerting.Duration{Duration: 26 * time.Hour}}
+	mockAlerts := mocks.NewMockAlertRepo(ctrl)
+	mockSilences := mocks.NewMockAlertSilenceRepo(ctrl)
+	mockRuns := mocks.NewMockSyncRunRepo(ctrl)
+	ops := &fakeNotifier{name: "ops", err: errors.New("smtp unavailable")}
+	service := NewAlertRuleService(nil, mockAlerts, mockSilences, mockRuns, nil, beacon.Nodes{},
+		[]alerting.Rule{rule}, []alerting.Notifier{ops})
+	ctx := context.Background()
+
+	lastSuccess := time.Now().Add(-30 * time.Hour)
+	mockRuns.EXPECT().RecordFailure(ctx, models.SyncSourceBeacon, gomock.Any(), "connection refused").Return(nil)
+	mockSilences.EXPECT().List(ctx, gomock.Any()).Return(nil, nil)
+	mockRuns.EXPECT().List(ctx).Return([]models.SyncRun{
+		{Source: models.SyncSourceLido},
+		{Source: models.SyncSourceBeacon, LastSuccessAt: &lastSuccess, LastError: "connection refused"},
+	}, nil)
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+		assert.Contains(t, a.Message, "The beacon sync has not succeeded since")
+		assert.Contains(t, a.Message, ": connection refused")
+		return true, nil
+	})
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:beacon-stale").Return([]models.Alert{{ID: 1, Pubkey: ""}}, nil)
+
+	// A failing notifier is logged rather than failing the sync, and the alert stays unsent
+	service.SyncCompleted(models.SyncSourceBeacon)(ctx, errors.New("connection refused"))
+	require.Len(t, ops.sent, 1)
+
+	// The next evaluation sends it again even though it was raised before
+	ops.err = nil
+	mockRuns.EXPECT().RecordFailure(ctx, models.SyncSourceBeacon, gomock.Any(), "connection refused").Return(nil)
+	mockSilences.EXPECT().List(ctx, gomock.Any()).Return(nil, nil)
+	mockRuns.EXPECT().List(ctx).Return([]models.SyncRun{
+		{Source: models.SyncSourceBeacon, LastSuccessAt: &lastSuccess, LastError: "connection refused"},
+	}, nil)
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).Return(false, nil)
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:beacon-stale").Return([]models.Alert{{ID: 1, Pubkey: ""}}, nil)
+	mockAlerts.EXPECT().MarkNotified(ctx, int64(1)).Return(nil)
+	service.SyncCompleted(models.SyncSourceBeacon)(ctx, errors.New("connection refused"))
+	require.Len(t, ops.sent, 2)
+	assert.False(t, ops.sent[1].Resolved)
+
+	// A successful run clears the alert
+	now := time.Now()
+	mockRuns.EXPECT().RecordSuccess(ctx, models.SyncSourceBeacon, gomock.Any()).Return(nil)
+	mockSilences.EXPECT().List(ctx, gomock.Any()).Return(nil, nil)
+	mockRuns.EXPECT().List(ctx).Return([]models.SyncRun{{Source: models.SyncSourceBeacon, LastSuccessAt: &now}}, nil)
+	mockAlerts.EXPECT().ListOpen(ctx, "rule:beacon-stale").Return([]models.Alert{{ID: 1, Pubkey: "", NotifiedAt: &now}}, nil)
+	mockAlerts.EXPECT().Resolve(ctx, "rule:beacon-stale", "").Return(nil)
+	service.SyncCompleted(models.SyncSourceBeacon)(ctx, nil)
+	require.Len(t, ops.sent, 3)
+	assert.True(t, ops.sent[2].Resolved)
+}
+
+func TestAlertRuleService_CreateSilence(t *testing.T) {
+	rules := []alerting.Rule{{Name: "inactive", Type: alerting.RuleInactive}}
+
+	tests := []struct {
+		name          string
+		silence       models.AlertSilence
+		expectedError error
+	}{
+		{name: "rule silence", silence: models.AlertSilence{Rule: "inactive", EndsAt: time.Now().Add(time.Hour), Reason: "client upgrade"}},
+		{name: "every rule", silence: models.AlertSilence{EndsAt: time.Now().Add(time.Hour), Reason: "maintenance"}},
+		{
+			name:          "unknown rule",
+			silence:       models.AlertSilence{Rule: "slashed", EndsAt: time.Now().Add(time.Hour), Reason: "maintenance"},
+			expectedError: ErrInvalidSilence,
+		},
+		{
+			name:          "ended",
+			silence:       models.AlertSilence{EndsAt: time.Now().Add(-time.Minute), Reason: "maintenance"},
+			expectedError: ErrInvalidSilence,
+		},
+		{
+			name:          "missing reason",
+			silence:       models.AlertSilence{EndsAt: time.Now().Add(time.Hour)},
+			expectedError: ErrInvalidSilence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSilences := mocks.NewMockAlertSilenceRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			service := NewAlertRuleService(nil, nil, mockSilences, nil, mockAudit, beacon.Nodes{}, rules, nil)
+			ctx := context.Background()
+
+			if tt.expectedError == nil {
+				mockSilences.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *models.AlertSilence) error {
+					assert.False(t, s.StartsAt.IsZero())
+					s.ID = 4
+					return nil
+				})
+				mockAudit.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, log *models.AuditLog) error {
+					assert.Equal(t, "alert.silence_created", log.Action)
+					assert.Equal(t, "10.0.0.1", log.SourceIP)
+					assert.Contains(t, log.Details, "Silence 4 for ")
+					return nil
+				})
+			}
+
+			err := service.CreateSilence(ctx, &tt.silence, "10.0.0.1")
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/bls"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+// BLSChangeUpload is one entry of a bls_to_execution_change-*.json file
+type BLSChangeUpload struct {
+	beacon.SignedBLSToExecutionChange
+	Metadata BLSChangeMetadata `json:"metadata"`
+}
+
+// BLSChangeMetadata is the metadata the deposit CLI adds to generated changes
+type BLSChangeMetadata struct {
+	NetworkName string `json:"network_name"`
+}
+
+// BLSChangeResult reports what happened to one change during upload or submission
+type BLSChangeResult struct {
+	ValidatorIndex string `json:"validator_index"`
+	Pubkey         string `json:"pubkey,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BLSChangeService verifies, stores and submits signed BLS-to-execution changes
+type BLSChangeService struct {
+	validators models.ValidatorRepo
+	changes    models.BLSChangeRepo
+	audit      models.AuditRepo
+	cipher     *vault.Cipher
+	nodes      beacon.Nodes
+}
+
+// NewBLSChangeService creates a new BLS-to-execution change service
+func NewBLSChangeService(validators models.ValidatorRepo, changes models.BLSChangeRepo, audit models.AuditRepo,
+	cipher *vault.Cipher, nodes beacon.Nodes) *BLSChangeService {
+	return &BLSChangeService{validators: validators, changes: changes, audit: audit, cipher: cipher, nodes: nodes}
+}
+
+// Upload verifies each change and stores the valid ones encrypted. A change must
+// be signed by the BLS withdrawal key committed to in the validator's 0x00
+// credentials. network names the chain, as in the deposit CLI, for entries
+// without metadata. Invalid entries are reported without stopping the others.
+func (s *BLSChangeService) Upload(ctx context.Context, network string, uploads []BLSChangeUpload, sourceIP string) ([]BLSChangeResult, error) {
+	results := make([]BLSChangeResult, 0, len(uploads))
+	for _, u := range uploads {
+		result := BLSChangeResult{ValidatorIndex: u.Message.ValidatorIndex}
+
+		networkName := u.Metadata.NetworkName
+		if networkName == "" {
+			networkName = network
+		}
+		change, err := s.verify(ctx, networkName, u.SignedBLSToExecutionChange)
+		var rejected *rejectionError
+		if errors.As(err, &rejected) {
+			result.Error = rejected.reason
+			results = append(results, result)
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		result.Pubkey = change.Pubkey
+
+		err = s.changes.Save(ctx, change)
+		if errors.Is(err, models.ErrAlreadySubmitted) {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+
+		details := fmt.Sprintf("BLS-to-execution change for %s to %s", change.Pubkey, change.ToExecutionAddress)
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "bls_change.uploaded", SourceIP: sourceIP, Details: details}); err != nil {
+			return results, fmt.Errorf("failed to record upload: %w", err)
+		}
+		result.Status = change.Status
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// rejectionError explains why an uploaded change failed verification
+type rejectionError struct {
+	reason string
+}
+
+func (e *rejectionError) Error() string {
+	return "change rejected: " + e.reason
+}
+
+func rejectf(format string, args ...interface{}) error {
+	return &rejectionError{reason: fmt.Sprintf(format, args...)}
+}
+
+// verify checks a signed change against the stored validator and returns it ready to save
+func (s *BLSChangeService) verify(ctx context.Context, networkName string, signed beacon.SignedBLSToExecutionChange) (*models.BLSChange, error) {
+	msg := signed.Message
+	msg.FromBLSPubkey = strings.ToLower(msg.FromBLSPubkey)
+	msg.ToExecutionAddress = strings.ToLower(msg.ToExecutionAddress)
+	signed.Message = msg
+	signed.Signature = strings.ToLower(signed.Signature)
+
+	index, err := msg.Index()
+	if err != nil {
+		return nil, rejectf("%v", err)
+	}
+	objectRoot, err := msg.HashTreeRoot()
+	if err != nil {
+		return nil, rejectf("%v", err)
+	}
+	chain, ok := depositNetworks[networkName]
+	if !ok {
+		return nil, rejectf("unsupported network %q", networkName)
+	}
+
+	validators, err := s.validators.List(ctx, map[string]interface{}{
+		"blockchain":         chain[0],
+		"blockchain_network": chain[1],
+		"validator_index":    int64(index),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) != 1 {
+		return nil, rejectf("validator %d is not managed on %s", index, networkName)
+	}
+	v := validators[0]
+
+	if models.WithdrawalCredentialsType(v.WithdrawalCredentials) != models.WithdrawalCredentialsBLS {
+		return nil, rejectf("validator %d does not have 0x00 withdrawal credentials", index)
+	}
+	fromPubkey, _ := hex.DecodeString(msg.FromBLSPubkey[2:])
+	hashed := sha256.Sum256(fromPubkey)
+	if hex.EncodeToString(hashed[1:]) != v.WithdrawalCredentials[4:] {
+		return nil, rejectf("from_bls_pubkey does not match the withdrawal credentials of validator %d", index)
+	}
+
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return nil, rejectf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+	genesis, err := node.Genesis(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis of %s %s: %w", v.Blockchain, v.BlockchainNetwork, err)
+	}
+
+	// BLS-to-execution changes are signed with the genesis fork version so they stay valid across forks
+	domain := beacon.ComputeDomain(beacon.DomainBLSToExecutionChange, genesis.ForkVersion, genesis.ValidatorsRoot)
+	signingRoot := beacon.SigningRoot(objectRoot, domain)
+	signature, err := hex.DecodeString(strings.TrimPrefix(signed.Signature, "0x"))
+	if err != nil {
+		return nil, rejectf("invalid signature encoding: %v", err)
+	}
+	if err := bls.Verify(fromPubkey, signingRoot[:], signature); err != nil {
+		return nil, rejectf("%v", err)
+	}
+
+	plaintext, err := json.Marshal(signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode change: %w", err)
+	}
+	sealed, err := s.cipher.Seal(plaintext, []byte(v.Pubkey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BLSChange{
+		Pubkey:             v.Pubkey,
+		ValidatorIndex:     int64(index),
+		FromBLSPubkey:      msg.FromBLSPubkey,
+		ToExecutionAddress: msg.ToExecutionAddress,
+		Message:            sealed,
+		Status:             models.BLSChangeStatusUploaded,
+	}, nil
+}
+
+// Submit sends every uploaded or previously failed change to its network's beacon
+// node pool and records the outcome of each
+func (s *BLSChangeService) Submit(ctx context.Context, sourceIP string) ([]BLSChangeResult, error) {
+	if _, err := s.changes.ConfirmApplied(ctx); err != nil {
+		return nil, err
+	}
+
+	var pending []models.BLSChange
+	for _, status := range []string{models.BLSChangeStatusUploaded, models.BLSChangeStatusFailed} {
+		changes, err := s.changes.List(ctx, status)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, changes...)
+	}
+
+	results := make([]BLSChangeResult, 0, len(pending))
+	for _, c := range pending {
+		result := BLSChangeResult{ValidatorIndex: fmt.Sprint(c.ValidatorIndex), Pubkey: c.Pubkey, Status: models.BLSChangeStatusSubmitted}
+		if err := s.submit(ctx, c); err != nil {
+			result.Status = models.BLSChangeStatusFailed
+			result.Error = err.Error()
+		}
+
+		if err := s.changes.UpdateStatus(ctx, c.Pubkey, result.Status, result.Error); err != nil {
+			return results, err
+		}
+		details := fmt.Sprintf("BLS-to-execution change for %s %s", c.Pubkey, result.Status)
+		if result.Error != "" {
+			details += ": " + result.Error
+		}
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "bls_change.submitted", SourceIP: sourceIP, Details: details}); err != nil {
+			return results, fmt.Errorf("failed to record submission: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *BLSChangeService) submit(ctx context.Context, c models.BLSChange) error {
+	v, err := s.validators.GetByPubkey(ctx, c.Pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to load validator: %w", err)
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+
+	plaintext, err := s.cipher.Open(c.Message, []byte(c.Pubkey))
+	if err != nil {
+		return err
+	}
+	var signed beacon.SignedBLSToExecutionChange
+	if err := json.Unmarshal(plaintext, &signed); err != nil {
+		return fmt.Errorf("failed to decode stored change: %w", err)
+	}
+
+	return node.SubmitBLSToExecutionChange(ctx, signed)
+}
+
+// List returns the stored changes with the given status, or all of them if status is empty
+func (s *BLSChangeService) List(ctx context.Context, status string) ([]models.BLSChange, error) {
+	if _, err := s.changes.ConfirmApplied(ctx); err != nil {
+		return nil, err
+	}
+	return s.changes.List(ctx, status)
+}
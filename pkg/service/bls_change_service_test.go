@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+var testGenesisRoot = strings.Repeat("4b", 32)
+
+// newPoolStub serves the genesis and BLS change pool endpoints, recording submitted changes
+func newPoolStub(t *testing.T, submitted *[]beacon.SignedBLSToExecutionChange) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			w.Write([]byte(`{"data":{"genesis_validators_root":"0x` + testGenesisRoot + `","genesis_fork_version":"0x00000000"}}`))
+		case "/eth/v1/beacon/pool/bls_to_execution_changes":
+			var changes []beacon.SignedBLSToExecutionChange
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&changes))
+			*submitted = append(*submitted, changes...)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// signChange signs a BLS-to-execution change with the withdrawal secret key sk
+func signChange(t *testing.T, sk int64, index, address string) (beacon.SignedBLSToExecutionChange, string) {
+	t.Helper()
+
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplicationBase(big.NewInt(sk))
+	pkBytes := pk.Bytes()
+
+	msg := beacon.BLSToExecutionChange{
+		ValidatorIndex:     index,
+		FromBLSPubkey:      "0x" + hex.EncodeToString(pkBytes[:]),
+		ToExecutionAddress: address,
+	}
+	root, err := msg.HashTreeRoot()
+	require.NoError(t, err)
+
+	var gvr [32]byte
+	g, _ := hex.DecodeString(testGenesisRoot)
+	copy(gvr[:], g)
+	signingRoot := beacon.SigningRoot(root, beacon.ComputeDomain(beacon.DomainBLSToExecutionChange, [4]byte{}, gvr))
+
+	h, err := bls12381.HashToG2(signingRoot[:], []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	require.NoError(t, err)
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&h, big.NewInt(sk))
+	sigBytes := sig.Bytes()
+
+	hashed := sha256.Sum256(pkBytes[:])
+	creds := "0x00" + hex.EncodeToString(hashed[1:])
+
+	return beacon.SignedBLSToExecutionChange{Message: msg, Signature: "0x" + hex.EncodeToString(sigBytes[:])}, creds
+}
+
+func TestBLSChangeService_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var submitted []beacon.SignedBLSToExecutionChange
+	srv := newPoolStub(t, &submitted)
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockChanges := mocks.NewMockBLSChangeRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewBLSChangeService(mockValidators, mockChanges, mockAudit, cipher, nodes)
+	ctx := context.Background()
+
+	address := "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+	valid, creds := signChange(t, 12345, "42", address)
+	forged, _ := signChange(t, 999, "43", address)
+	forged.Message.FromBLSPubkey = valid.Message.FromBLSPubkey
+
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{
+		"blockchain": "ethereum", "blockchain_network": "mainnet", "validator_index": int64(42),
+	}).Return([]models.Validator{{Pubkey: "0xaa", Blockchain: "ethereum", BlockchainNetwork: "mainnet", WithdrawalCredentials: creds}}, nil)
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{
+		"blockchain": "ethereum", "blockchain_network": "mainnet", "validator_index": int64(43),
+	}).Return([]models.Validator{{Pubkey: "0xbb", Blockchain: "ethereum", BlockchainNetwork: "mainnet", WithdrawalCredentials: creds}}, nil)
+
+	var saved *models.BLSChange
+	mockChanges.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.BLSChange) error {
+		saved = c
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	results, err := service.Upload(ctx, "mainnet", []BLSChangeUpload{
+		{SignedBLSToExecutionChange: valid},
+		{SignedBLSToExecutionChange: forged},
+	}, "10.0.0.1")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, BLSChangeResult{ValidatorIndex: "42", Pubkey: "0xaa", Status: models.BLSChangeStatusUploaded}, results[0])
+	assert.Equal(t, "invalid BLS signature", results[1].Error)
+
+	// The stored message is encrypted and bound to the validator
+	require.NotNil(t, saved)
+	assert.Equal(t, int64(42), saved.ValidatorIndex)
+	assert.Equal(t, address, saved.ToExecutionAddress)
+	assert.NotContains(t, string(saved.Message), address)
+	plaintext, err := cipher.Open(saved.Message, []byte("0xaa"))
+	require.NoError(t, err)
+	assert.Contains(t, string(plaintext), address)
+
+	// Submitting sends the decrypted message to the pool
+	mockChanges.EXPECT().ConfirmApplied(ctx).Return(int64(0), nil)
+	mockChanges.EXPECT().List(ctx, models.BLSChangeStatusUploaded).Return([]models.BLSChange{*saved}, nil)
+	mockChanges.EXPECT().List(ctx, models.BLSChangeStatusFailed).Return(nil, nil)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xaa").Return(&models.Validator{Pubkey: "0xaa", Blockchain: "ethereum", BlockchainNetwork: "mainnet"}, nil)
+	mockChanges.EXPECT().UpdateStatus(ctx, "0xaa", models.BLSChangeStatusSubmitted, "").Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	results, err = service.Submit(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, []BLSChangeResult{{ValidatorIndex: "42", Pubkey: "0xaa", Status: models.BLSChangeStatusSubmitted}}, results)
+	assert.Equal(t, []beacon.SignedBLSToExecutionChange{valid}, submitted)
+}
+
+func TestBLSChangeService_UploadRejects(t *testing.T) {
+	address := "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+	valid, creds := signChange(t, 12345, "42", address)
+
+	tests := []struct {
+		name          string
+		validators    []models.Validator
+		expectedError string
+	}{
+		{
+			name:          "unknown validator",
+			expectedError: "validator 42 is not managed on mainnet",
+		},
+		{
+			name:          "already 0x01",
+			validators:    []models.Validator{{Pubkey: "0xaa", WithdrawalCredentials: "0x01" + creds[4:]}},
+			expectedError: "validator 42 does not have 0x00 withdrawal credentials",
+		},
+		{
+			name:          "different withdrawal key",
+			validators:    []models.Validator{{Pubkey: "0xaa", WithdrawalCredentials: "0x00" + strings.Repeat("11", 31)}},
+			expectedError: "from_bls_pubkey does not match the withdrawal credentials of validator 42",
+		},
+		{
+			name:          "no beacon node",
+			validators:    []models.Validator{{Pubkey: "0xaa", Blockchain: "ethereum", BlockchainNetwork: "mainnet", WithdrawalCredentials: creds}},
+			expectedError: "no beacon node configured for ethereum mainnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockValidators.EXPECT().List(gomock.Any(), gomock.Any()).Return(tt.validators, nil)
+			service := NewBLSChangeService(mockValidators, mocks.NewMockBLSChangeRepo(ctrl), mocks.NewMockAuditRepo(ctrl), nil, beacon.Nodes{})
+
+			results, err := service.Upload(context.Background(), "", []BLSChangeUpload{{
+				SignedBLSToExecutionChange: valid,
+				Metadata:                   BLSChangeMetadata{NetworkName: "mainnet"},
+			}}, "")
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.expectedError, results[0].Error)
+		})
+	}
+}
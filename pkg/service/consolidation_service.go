@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ErrInvalidConsolidation is returned for a consolidation that cannot be planned
+var ErrInvalidConsolidation = errors.New("invalid consolidation")
+
+// ConsolidationService plans and tracks consolidations of validators into
+// compounding validators (EIP-7251)
+type ConsolidationService struct {
+	validators     *ValidatorService
+	consolidations models.ConsolidationRepo
+	audit          models.AuditRepo
+	nodes          beacon.Nodes
+}
+
+// NewConsolidationService creates a new consolidation service
+func NewConsolidationService(validators *ValidatorService, consolidations models.ConsolidationRepo, audit models.AuditRepo,
+	nodes beacon.Nodes) *ConsolidationService {
+	return &ConsolidationService{validators: validators, consolidations: consolidations, audit: audit, nodes: nodes}
+}
+
+// Plan records that source is to be consolidated into target. Both must be active
+// validators on the same network, and target must have 0x02 credentials.
+func (s *ConsolidationService) Plan(ctx context.Context, source, target, sourceIP string) (*models.Consolidation, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	target = strings.ToLower(strings.TrimSpace(target))
+	if source == "" || target == "" {
+		return nil, fmt.Errorf("%w: source_pubkey and target_pubkey are required", ErrInvalidConsolidation)
+	}
+	if source == target {
+		return nil, fmt.Errorf("%w: source and target are the same validator", ErrInvalidConsolidation)
+	}
+
+	src, err := s.activeValidator(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := s.activeValidator(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if src.Blockchain != dst.Blockchain || src.BlockchainNetwork != dst.BlockchainNetwork {
+		return nil, fmt.Errorf("%w: source and target are on different networks", ErrInvalidConsolidation)
+	}
+	if models.WithdrawalAddress(src.WithdrawalCredentials) == "" {
+		return nil, fmt.Errorf("%w: source %s has no execution withdrawal credentials", ErrInvalidConsolidation, source)
+	}
+	if models.WithdrawalCredentialsType(dst.WithdrawalCredentials) != models.WithdrawalCredentialsCompounding {
+		return nil, fmt.Errorf("%w: target %s does not have 0x02 withdrawal credentials", ErrInvalidConsolidation, target)
+	}
+
+	c := &models.Consolidation{SourcePubkey: source, TargetPubkey: target}
+	if err := s.consolidations.Plan(ctx, c); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Consolidation of %s into %s planned", source, target)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "consolidation.planned", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record consolidation: %w", err)
+	}
+	return c, nil
+}
+
+func (s *ConsolidationService) activeValidator(ctx context.Context, pubkey string) (*models.Validator, error) {
+	v, err := s.validators.GetValidatorByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: validator %s is not managed", ErrInvalidConsolidation, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.Status != models.StatusActive {
+		return nil, fmt.Errorf("%w: validator %s is %s, not active", ErrInvalidConsolidation, pubkey, v.Status)
+	}
+	return v, nil
+}
+
+// Check reads the consolidation queue of every network with managed validators.
+// Queued consolidations of our validators become pending and move their source
+// to exiting; pending ones that left the queue are completed and move their
+// source to exited. It runs after each beacon sync, before exits are checked,
+// so consolidating sources are not reported as unexpected exits.
+func (s *ConsolidationService) Check(ctx context.Context) error {
+	validators, err := s.validators.ListValidators(ctx, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	byChain := map[[2]string]map[int64]models.Validator{}
+	for _, v := range validators {
+		if v.ValidatorIndex == nil {
+			continue
+		}
+		chain := [2]string{v.Blockchain, v.BlockchainNetwork}
+		if byChain[chain] == nil {
+			byChain[chain] = map[int64]models.Validator{}
+		}
+		byChain[chain][*v.ValidatorIndex] = v
+	}
+
+	var errs []error
+	queued := map[string]bool{}
+	checked := map[[2]string]bool{}
+	for chain, byIndex := range byChain {
+		node, ok := s.nodes.Get(chain[0], chain[1])
+		if !ok {
+			continue
+		}
+		if err := s.checkQueue(ctx, node, byIndex, queued); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", chain[0], chain[1], err))
+			continue
+		}
+		checked[chain] = true
+	}
+
+	pending, err := s.consolidations.List(ctx, models.ConsolidationStatusPending)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, c := range pending {
+		if queued[c.SourcePubkey] {
+			continue
+		}
+		v, err := s.validators.GetValidatorByPubkey(ctx, c.SourcePubkey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.SourcePubkey, err))
+			continue
+		}
+		// Only complete consolidations on networks whose queue was read
+		if !checked[[2]string{v.Blockchain, v.BlockchainNetwork}] {
+			continue
+		}
+		if err := s.complete(ctx, c, v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.SourcePubkey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkQueue records the queued consolidations whose source is one of the given
+// validators. A failing entry does not stop the others; a source that could not
+// be moved to exiting is tried again by the next check.
+func (s *ConsolidationService) checkQueue(ctx context.Context, node *beacon.Client, byIndex map[int64]models.Validator, queued map[string]bool) error {
+	queue, err := node.PendingConsolidations(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range queue {
+		sourceIndex, err := strconv.ParseInt(p.SourceIndex, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid source index %q: %w", p.SourceIndex, err))
+			continue
+		}
+		source, ok := byIndex[sourceIndex]
+		if !ok {
+			continue
+		}
+		queued[source.Pubkey] = true
+
+		if err := s.setPending(ctx, node, byIndex, source, p.TargetIndex); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Pubkey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setPending records the queued consolidation of source and moves source to exiting
+func (s *ConsolidationService) setPending(ctx context.Context, node *beacon.Client, byIndex map[int64]models.Validator,
+	source models.Validator, targetIndex string) error {
+	target, err := s.targetPubkey(ctx, node, byIndex, targetIndex)
+	if err != nil {
+		return err
+	}
+	changed, err := s.consolidations.SetPending(ctx, source.Pubkey, target)
+	if err != nil {
+		return err
+	}
+	if changed {
+		details := fmt.Sprintf("Consolidation of %s into %s is pending on chain", source.Pubkey, target)
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "consolidation.pending", Details: details}); err != nil {
+			return fmt.Errorf("failed to record consolidation: %w", err)
+		}
+	}
+	if source.Status != models.StatusExiting {
+		if err := s.validators.UpdateValidatorStatus(ctx, source.Pubkey, models.StatusExiting); err != nil {
+			return fmt.Errorf("failed to mark exiting: %w", err)
+		}
+	}
+	return nil
+}
+
+// targetPubkey resolves a target index, asking the beacon node for targets we do not manage
+func (s *ConsolidationService) targetPubkey(ctx context.Context, node *beacon.Client, byIndex map[int64]models.Validator, index string) (string, error) {
+	targetIndex, err := strconv.ParseInt(index, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid target index %q: %w", index, err)
+	}
+	if v, ok := byIndex[targetIndex]; ok {
+		return v.Pubkey, nil
+	}
+
+	states, err := node.Validators(ctx, []string{index})
+	if err != nil {
+		return "", err
+	}
+	if len(states) == 0 {
+		return "", fmt.Errorf("target validator %d is unknown to the beacon node", targetIndex)
+	}
+	return strings.ToLower(states[0].Data.Pubkey), nil
+}
+
+// complete marks a consolidation that left the queue completed and its source exited
+func (s *ConsolidationService) complete(ctx context.Context, c models.Consolidation, source *models.Validator) error {
+	if source.Status == models.StatusActive {
+		if err := s.validators.UpdateValidatorStatus(ctx, c.SourcePubkey, models.StatusExiting); err != nil {
+			return err
+		}
+	}
+	if source.Status == models.StatusActive || source.Status == models.StatusExiting {
+		if err := s.validators.UpdateValidatorStatus(ctx, c.SourcePubkey, models.StatusExited); err != nil {
+			return err
+		}
+	}
+	if err := s.consolidations.UpdateStatus(ctx, c.SourcePubkey, models.ConsolidationStatusCompleted); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Consolidation of %s into %s completed", c.SourcePubkey, c.TargetPubkey)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "consolidation.completed", Details: details}); err != nil {
+		return fmt.Errorf("failed to record consolidation: %w", err)
+	}
+	return nil
+}
+
+// List returns the consolidations with the given status, or all of them if status is empty
+func (s *ConsolidationService) List(ctx context.Context, status string) ([]models.Consolidation, error) {
+	return s.consolidations.List(ctx, status)
+}
+
+// Targets returns, per target validator, its current effective balance and the
+// balance its planned and pending consolidations will add to it
+func (s *ConsolidationService) Targets(ctx context.Context) ([]models.ConsolidationTarget, error) {
+	consolidations, err := s.consolidations.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []models.ConsolidationTarget
+	byPubkey := map[string]int{}
+	for _, c := range consolidations {
+		i, ok := byPubkey[c.TargetPubkey]
+		if !ok {
+			target := models.ConsolidationTarget{Pubkey: c.TargetPubkey}
+			v, err := s.validators.GetValidatorByPubkey(ctx, c.TargetPubkey)
+			if err != nil && !isNotFound(err) {
+				return nil, err
+			}
+			if v != nil {
+				target.ValidatorIndex = v.ValidatorIndex
+				target.EffectiveBalance = v.EffectiveBalance
+			}
+			targets = append(targets, target)
+			i = len(targets) - 1
+			byPubkey[c.TargetPubkey] = i
+		}
+
+		target := &targets[i]
+		target.Sources = append(target.Sources, c)
+		if c.Status == models.ConsolidationStatusCompleted {
+			continue
+		}
+		source, err := s.validators.GetValidatorByPubkey(ctx, c.SourcePubkey)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if source != nil {
+			target.IncomingBalance += source.EffectiveBalance
+		}
+	}
+
+	for i := range targets {
+		targets[i].ProjectedBalance = min(targets[i].EffectiveBalance+targets[i].IncomingBalance, models.MaxEffectiveBalanceElectra)
+	}
+	return targets, nil
+}
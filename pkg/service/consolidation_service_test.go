@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+var (
+	executionCreds   = "0x010000000000000000000000" + strings.Repeat("d8", 20)
+	compoundingCreds = "0x020000000000000000000000" + strings.Repeat("d8", 20)
+)
+
+func TestConsolidationService_Plan(t *testing.T) {
+	source := &models.Validator{Pubkey: "0xaa", Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+		WithdrawalCredentials: executionCreds}
+	target := &models.Validator{Pubkey: "0xbb", Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+		WithdrawalCredentials: compoundingCreds}
+
+	tests := []struct {
+		name          string
+		source        string
+		target        string
+		mockSetup     func(*mocks.MockValidatorRepo, *mocks.MockConsolidationRepo, *mocks.MockAuditRepo)
+		expectedError string
+	}{
+		{
+			name:   "planned",
+			source: "0xAA",
+			target: "0xbb",
+			mockSetup: func(v *mocks.MockValidatorRepo, c *mocks.MockConsolidationRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(source, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xbb").Return(target, nil)
+				c.EXPECT().Plan(gomock.Any(), &models.Consolidation{SourcePubkey: "0xaa", TargetPubkey: "0xbb"}).Return(nil)
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:          "same validator",
+			source:        "0xaa",
+			target:        "0xAA",
+			mockSetup:     func(*mocks.MockValidatorRepo, *mocks.MockConsolidationRepo, *mocks.MockAuditRepo) {},
+			expectedError: "invalid consolidation: source and target are the same validator",
+		},
+		{
+			name:   "target without compounding credentials",
+			source: "0xbb",
+			target: "0xaa",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockConsolidationRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xbb").Return(target, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(source, nil)
+			},
+			expectedError: "invalid consolidation: target 0xaa does not have 0x02 withdrawal credentials",
+		},
+		{
+			name:   "different networks",
+			source: "0xaa",
+			target: "0xcc",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockConsolidationRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(source, nil)
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xcc").Return(&models.Validator{Pubkey: "0xcc", Status: models.StatusActive,
+					Blockchain: "gnosis", BlockchainNetwork: "mainnet", WithdrawalCredentials: compoundingCreds}, nil)
+			},
+			expectedError: "invalid consolidation: source and target are on different networks",
+		},
+		{
+			name:   "source exiting",
+			source: "0xaa",
+			target: "0xbb",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockConsolidationRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.Validator{Pubkey: "0xaa", Status: models.StatusExiting}, nil)
+			},
+			expectedError: "invalid consolidation: validator 0xaa is exiting, not active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockConsolidations := mocks.NewMockConsolidationRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockValidators, mockConsolidations, mockAudit)
+
+			service := NewConsolidationService(NewValidatorService(mockValidators, nil), mockConsolidations, mockAudit, beacon.Nodes{})
+			_, err := service.Plan(context.Background(), tt.source, tt.target, "10.0.0.1")
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.True(t, errors.Is(err, ErrInvalidConsolidation))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConsolidationService_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/pending_consolidations":
+			w.Write([]byte(`{"data":[{"source_index":"1","target_index":"900"},{"source_index":"500","target_index":"501"}]}`))
+		case "/eth/v1/beacon/states/head/validators":
+			assert.Equal(t, "900", r.URL.Query().Get("id"))
+			w.Write([]byte(`{"data":[{"index":"900","status":"active_ongoing","validator":{"pubkey":"0xDD"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockConsolidations := mocks.NewMockConsolidationRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewConsolidationService(NewValidatorService(mockValidators, nil), mockConsolidations, mockAudit, nodes)
+	ctx := context.Background()
+
+	index1, index2 := int64(1), int64(2)
+	queued := models.Validator{Pubkey: "0xaa", Status: models.StatusActive, ValidatorIndex: &index1, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	done := models.Validator{Pubkey: "0xbb", Status: models.StatusExiting, ValidatorIndex: &index2, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{}).Return([]models.Validator{queued, done}, nil)
+
+	// 0xaa entered the queue, targeting a validator we do not manage
+	mockConsolidations.EXPECT().SetPending(ctx, "0xaa", "0xdd").Return(true, nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	// 0xbb left the queue
+	mockConsolidations.EXPECT().List(ctx, models.ConsolidationStatusPending).Return([]models.Consolidation{
+		{SourcePubkey: "0xaa", TargetPubkey: "0xdd", Status: models.ConsolidationStatusPending},
+		{SourcePubkey: "0xbb", TargetPubkey: "0xdd", Status: models.ConsolidationStatusPending},
+	}, nil)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xbb").Return(&done, nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xbb", models.StatusExited).Return(nil)
+	mockConsolidations.EXPECT().UpdateStatus(ctx, "0xbb", models.ConsolidationStatusCompleted).Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, service.Check(ctx))
+}
+
+func TestConsolidationService_CheckFailingSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/pending_consolidations":
+			w.Write([]byte(`{"data":[{"source_index":"1","target_index":"3"},{"source_index":"2","target_index":"3"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockConsolidations := mocks.NewMockConsolidationRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewConsolidationService(NewValidatorService(mockValidators, nil), mockConsolidations, mockAudit, nodes)
+	ctx := context.Background()
+
+	index1, index2, index3 := int64(1), int64(2), int64(3)
+	slashed := models.Validator{Pubkey: "0xaa", Status: models.StatusSlashed, ValidatorIndex: &index1, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	active := models.Validator{Pubkey: "0xbb", Status: models.StatusActive, ValidatorIndex: &index2, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	target := models.Validator{Pubkey: "0xcc", Status: models.StatusActive, ValidatorIndex: &index3, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{}).Return([]models.Validator{slashed, active, target}, nil)
+
+	// The slashed source cannot move to exiting, which does not stop the next queue entry
+	mockConsolidations.EXPECT().SetPending(ctx, "0xaa", "0xcc").Return(false, nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).
+		Return(&models.TransitionError{From: models.StatusSlashed, To: models.StatusExiting})
+	mockConsolidations.EXPECT().SetPending(ctx, "0xbb", "0xcc").Return(true, nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xbb", models.StatusExiting).Return(nil)
+	mockConsolidations.EXPECT().List(ctx, models.ConsolidationStatusPending).Return(nil, nil)
+
+	err := service.Check(ctx)
+	assert.ErrorIs(t, err, models.ErrInvalidTransition)
+	assert.ErrorContains(t, err, "0xaa: failed to mark exiting")
+}
+
+func TestConsolidationService_Targets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockConsolidations := mocks.NewMockConsolidationRepo(ctrl)
+	service := NewConsolidationService(NewValidatorService(mockValidators, nil), mockConsolidations, nil, beacon.Nodes{})
+	ctx := context.Background()
+
+	consolidations := []models.Consolidation{
+		{SourcePubkey: "0xa1", TargetPubkey: "0xbb", Status: models.ConsolidationStatusCompleted},
+		{SourcePubkey: "0xa2", TargetPubkey: "0xbb", Status: models.ConsolidationStatusPending},
+		{SourcePubkey: "0xa3", TargetPubkey: "0xbb", Status: models.ConsolidationStatusPlanned},
+	}
+	mockConsolidations.EXPECT().List(ctx, "").Return(consolidations, nil)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xbb").Return(&models.Validator{Pubkey: "0xbb", EffectiveBalance: 2030_000_000_000}, nil)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xa2").Return(&models.Validator{Pubkey: "0xa2", EffectiveBalance: 32_000_000_000}, nil)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xa3").Return(&models.Validator{Pubkey: "0xa3", EffectiveBalance: 32_000_000_000}, nil)
+
+	targets, err := service.Targets(ctx)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, int64(2030_000_000_000), targets[0].EffectiveBalance)
+	assert.Equal(t, int64(64_000_000_000), targets[0].IncomingBalance)
+	// The projection is capped at the maximum effective balance
+	assert.Equal(t, int64(models.MaxEffectiveBalanceElectra), targets[0].ProjectedBalance)
+	assert.Len(t, targets[0].Sources, 3)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/interchange"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// ErrInvalidDeployment is returned for a deployment that cannot be carried out
+var ErrInvalidDeployment = errors.New("invalid deployment")
+
+// ErrAlreadyDeployed is returned when deploying a key that is loaded on a validator client
+var ErrAlreadyDeployed = errors.New("validator is already deployed")
+
+// ErrKeyMigrating is returned when deploying a key that a key migration holds
+var ErrKeyMigrating = errors.New("validator key is held by a key migration")
+
+// DeploymentService loads stored keystores on validator clients through the
+// keymanager API and removes them again, keeping the client of each validator
+// and its slashing protection up to date
+type DeploymentService struct {
+	validators   models.ValidatorRepo
+	migrations   models.KeyMigrationRepo
+	keystores    *KeystoreService
+	protection   *SlashingProtectionService
+	doppelganger *DoppelgangerService
+	audit        models.AuditRepo
+	clients      map[string]vclient.KeyManager
+}
+
+// NewDeploymentService creates a new deployment service for the given validator clients
+func NewDeploymentService(validators models.ValidatorRepo, migrations models.KeyMigrationRepo, keystores *KeystoreService,
+	protection *SlashingProtectionService, doppelganger *DoppelgangerService, audit models.AuditRepo, clients []vclient.KeyManager) *DeploymentService {
+	byName := make(map[string]vclient.KeyManager, len(clients))
+	for _, c := range clients {
+		byName[c.Name()] = c
+	}
+	return &DeploymentService{
+		validators:   validators,
+		migrations:   migrations,
+		keystores:    keystores,
+		protection:   protection,
+		doppelganger: doppelganger,
+		audit:        audit,
+		clients:      byName,
+	}
+}
+
+// Deploy imports the stored keystore of a validator on a validator client
+// instance, together with its stored slashing protection. The validator must not
+// be loaded anywhere or held by a key migration and must pass the doppelganger
+// requirements. Importing a key the client already has succeeds, so a failed
+// deployment can be retried.
+func (s *DeploymentService) Deploy(ctx context.Context, pubkey, instance, sourceIP string) (*models.Validator, error) {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	client, ok := s.clients[instance]
+	if !ok {
+		return nil, fmt.Errorf("%w: validator client instance %q is not configured", ErrInvalidDeployment, instance)
+	}
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if v.ClientInstance != "" {
+		return nil, fmt.Errorf("%w: %s is loaded on %s", ErrAlreadyDeployed, pubkey, v.ClientInstance)
+	}
+	if err := checkNotMigrating(ctx, s.migrations, pubkey); err != nil {
+		return nil, err
+	}
+	if err := s.doppelganger.Deployable(ctx, pubkey); err != nil {
+		return nil, err
+	}
+
+	protection, err := s.deployProtection(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	ks, password, err := s.keystores.Open(ctx, pubkey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: no keystore stored for %s", ErrInvalidDeployment, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := client.ImportKeystore(ctx, ks, password, protection); err != nil {
+		return nil, err
+	}
+
+	if err := s.validators.UpdateClient(ctx, pubkey, client.Type(), client.Name()); err != nil {
+		return nil, err
+	}
+	v.Client, v.ClientInstance = client.Type(), client.Name()
+
+	details := fmt.Sprintf("Validator %s deployed on %s", pubkey, instance)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "validator.deployed", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record deployment: %w", err)
+	}
+	return v, nil
+}
+
+// checkNotMigrating returns ErrKeyMigrating if a key migration holds pubkey. While
+// a migration runs, or after it failed with the key off its source, no client
+// reports the key, but the migration may still import it on its target.
+func checkNotMigrating(ctx context.Context, migrations models.KeyMigrationRepo, pubkey string) error {
+	m, err := migrations.Holding(ctx, pubkey)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s is held by migration %d (%s)", ErrKeyMigrating, pubkey, m.ID, m.Status)
+}
+
+// deployProtection returns the slashing protection interchange to import with
+// a key. Validators that may have signed before must have stored data; keys
+// that never had duties have nothing to protect yet.
+func (s *DeploymentService) deployProtection(ctx context.Context, v *models.Validator) ([]byte, error) {
+	i, err := s.protection.Export(ctx, []string{v.Pubkey}, "")
+	if errors.Is(err, ErrMissingSlashingProtection) {
+		if v.Status == models.StatusUnused || v.Status == models.StatusPending {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: no slashing protection stored for %s validator %s", ErrInvalidDeployment, v.Status, v.Pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(i)
+}
+
+// Undeploy deletes the keystore of a validator from a validator client instance,
+// or from the instance it is loaded on if instance is empty, and merges the
+// slashing protection the client returns into the stored data. Deleting a key
+// the client no longer has succeeds, so a failed undeployment can be retried.
+func (s *DeploymentService) Undeploy(ctx context.Context, pubkey, instance, sourceIP string) (*models.Validator, error) {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if instance == "" {
+		instance = v.ClientInstance
+	}
+	if instance == "" {
+		return nil, fmt.Errorf("%w: %s is not deployed", ErrInvalidDeployment, pubkey)
+	}
+	client, ok := s.clients[instance]
+	if !ok {
+		return nil, fmt.Errorf("%w: validator client instance %q is not configured", ErrInvalidDeployment, instance)
+	}
+
+	raw, err := client.DeleteKeystore(ctx, pubkey)
+	if err != nil && !errors.Is(err, vclient.ErrKeyNotFound) {
+		return nil, err
+	}
+	outcome := "deleted"
+	if raw != nil {
+		var i interchange.Interchange
+		if err := json.Unmarshal(raw, &i); err != nil {
+			return nil, fmt.Errorf("%s returned invalid slashing protection: %w", instance, err)
+		}
+		result, err := s.protection.Import(ctx, &i, instance, sourceIP)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.Rejected {
+			if r.Pubkey == pubkey {
+				return nil, fmt.Errorf("slashing protection from %s rejected: %s", instance, r.Error)
+			}
+		}
+	} else {
+		outcome = "not loaded"
+	}
+
+	// Removing a stray copy from another client leaves the recorded client alone
+	if v.ClientInstance == instance {
+		if err := s.validators.UpdateClient(ctx, pubkey, "", ""); err != nil {
+			return nil, err
+		}
+		v.Client, v.ClientInstance = "", ""
+	}
+
+	details := fmt.Sprintf("Validator %s undeployed from %s: %s", pubkey, instance, outcome)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "validator.undeployed", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record undeployment: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// notMigrating returns a key migration repository that holds no keys
+func notMigrating(ctrl *gomock.Controller) *mocks.MockKeyMigrationRepo {
+	m := mocks.NewMockKeyMigrationRepo(ctrl)
+	m.EXPECT().Holding(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+	return m
+}
+
+// newDeploymentService wires a deployment service to mocks and a keystore
+// service holding testKeystore
+func newDeploymentService(t *testing.T, ctrl *gomock.Controller, nodes beacon.Nodes, migrations models.KeyMigrationRepo,
+	clients ...vclient.KeyManager) (
+	*DeploymentService, *mocks.MockValidatorRepo, *mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo) {
+	t.Helper()
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+	mockProtection := mocks.NewMockSlashingProtectionRepo(ctrl)
+	mockKeystores := mocks.NewMockKeystoreRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	keystores := NewKeystoreService(mockKeystores, mockValidators, mockAudit, cipher)
+	service := NewDeploymentService(mockValidators, migrations, keystores, NewSlashingProtectionService(mockProtection, mockAudit),
+		NewDoppelgangerService(mockChecks, mockValidators, nil, mockAudit, nodes, 3), mockAudit, clients)
+	return service, mockValidators, mockChecks, mockProtection, mockKeystores
+}
+
+// storeTestKeystore stores testKeystore through the keystore service and returns the stored row
+func storeTestKeystore(t *testing.T, s *DeploymentService, validators *mocks.MockValidatorRepo, keystores *mocks.MockKeystoreRepo) *models.StoredKeystore {
+	t.Helper()
+
+	var stored models.StoredKeystore
+	validators.EXPECT().GetByPubkey(gomock.Any(), testKeystorePubkey).Return(&models.Validator{Pubkey: testKeystorePubkey}, nil)
+	keystores.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k *models.StoredKeystore) error {
+		stored = *k
+		return nil
+	})
+	_, err := s.keystores.Store(context.Background(), KeystoreRequest{Keystore: []byte(testKeystore), Password: testKeystorePassword}, "")
+	require.NoError(t, err)
+	return &stored
+}
+
+func TestDeploymentService_Deploy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	target := &fakeKeyManager{name: "teku-1"}
+	service, mockValidators, _, mockProtection, mockKeystores := newDeploymentService(t, ctrl, beacon.Nodes{}, notMigrating(ctrl), target)
+	stored := storeTestKeystore(t, service, mockValidators, mockKeystores)
+	ctx := context.Background()
+
+	// A validator that is not active yet needs neither a doppelganger check nor slashing protection
+	mockValidators.EXPECT().GetByPubkey(ctx, testKeystorePubkey).Return(&models.Validator{
+		Pubkey: testKeystorePubkey, Status: models.StatusPending,
+	}, nil).Times(2)
+	mockProtection.EXPECT().List(ctx, []string{testKeystorePubkey}).Return(nil, nil)
+	mockKeystores.EXPECT().Get(ctx, testKeystorePubkey).Return(stored, nil)
+	mockValidators.EXPECT().UpdateClient(ctx, testKeystorePubkey, vclient.TypeTeku, "teku-1").Return(nil)
+
+	v, err := service.Deploy(ctx, testKeystorePubkey, "teku-1", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "teku-1", v.ClientInstance)
+	assert.Equal(t, []string{testKeystore}, target.imported)
+	assert.Equal(t, []string{""}, target.importedSP)
+}
+
+func TestDeploymentService_DeployWithProtection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := newChainStub(t, &chainStub{epoch: 103})
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	target := &fakeKeyManager{name: "teku-1"}
+	service, mockValidators, mockChecks, mockProtection, mockKeystores := newDeploymentService(t, ctrl, nodes, notMigrating(ctrl), target)
+	stored := storeTestKeystore(t, service, mockValidators, mockKeystores)
+	ctx := context.Background()
+
+	passed := int64(102)
+	blockSlot := int64(3200)
+	mockValidators.EXPECT().GetByPubkey(ctx, testKeystorePubkey).Return(&models.Validator{
+		Pubkey: testKeystorePubkey, Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+	}, nil).Times(2)
+	mockChecks.EXPECT().Latest(ctx, testKeystorePubkey).Return(&models.DoppelgangerCheck{
+		ID: 1, Status: models.DoppelgangerStatusPassed, LastCheckedEpoch: &passed,
+	}, nil)
+	mockProtection.EXPECT().List(ctx, []string{testKeystorePubkey}).Return([]models.SlashingProtection{
+		{Pubkey: testKeystorePubkey, GenesisValidatorsRoot: testInterchangeRoot, BlockSlot: &blockSlot},
+	}, nil)
+	mockKeystores.EXPECT().Get(ctx, testKeystorePubkey).Return(stored, nil)
+	mockValidators.EXPECT().UpdateClient(ctx, testKeystorePubkey, vclient.TypeTeku, "teku-1").Return(nil)
+
+	_, err := service.Deploy(ctx, testKeystorePubkey, "teku-1", "")
+	require.NoError(t, err)
+	assert.Contains(t, target.importedSP[0], `"slot":"3200"`)
+}
+
+func TestDeploymentService_DeployRejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		instance      string
+		validator     *models.Validator
+		migration     *models.KeyMigration
+		mockSetup     func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo)
+		expectedError error
+	}{
+		{
+			name:          "unknown instance",
+			instance:      "prysm-1",
+			mockSetup:     func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo) {},
+			expectedError: ErrInvalidDeployment,
+		},
+		{
+			name:          "already deployed",
+			instance:      "teku-1",
+			validator:     &models.Validator{Pubkey: testKeystorePubkey, Status: models.StatusActive, ClientInstance: "lh-1"},
+			mockSetup:     func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo) {},
+			expectedError: ErrAlreadyDeployed,
+		},
+		{
+			// The migration deleted the key from its source, so no client reports it
+			name:          "unfinished migration",
+			instance:      "teku-1",
+			validator:     &models.Validator{Pubkey: testKeystorePubkey, Status: models.StatusActive},
+			migration:     &models.KeyMigration{ID: 2, Pubkey: testKeystorePubkey, Status: models.KeyMigrationStatusSourceDeleted},
+			mockSetup:     func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo) {},
+			expectedError: ErrKeyMigrating,
+		},
+		{
+			name:      "failed migration that kept the keystore",
+			instance:  "teku-1",
+			validator: &models.Validator{Pubkey: testKeystorePubkey, Status: models.StatusActive},
+			migration: &models.KeyMigration{ID: 3, Pubkey: testKeystorePubkey, Status: models.KeyMigrationStatusFailed,
+				Keystore: []byte("sealed")},
+			mockSetup:     func(*mocks.MockDoppelgangerCheckRepo, *mocks.MockSlashingProtectionRepo, *mocks.MockKeystoreRepo) {},
+			expectedError: ErrKeyMigrating,
+		},
+		{
+			name:      "no doppelganger check",
+			instance:  "teku-1",
+			validator: &models.Validator{Pubkey: testKeystorePubkey, Status: models.StatusActive},
+			mockSetup: func(c *mocks.MockDoppelgangerCheckRepo, _ *mocks.MockSlashingProtectionRepo, _ *mocks.MockKeystoreRepo) {
+				c.EXPECT().Latest(gomock.Any(), testKeystorePubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrNotDeployable,
+		},
+		{
+			name:      "no keystore",
+			instance:  "teku-1",
+			validator: &models.Validator{Pubkey: testKeystorePubkey, Status: models.StatusUnused},
+			mockSetup: func(_ *mocks.MockDoppelgangerCheckRepo, p *mocks.MockSlashingProtectionRepo, k *mocks.MockKeystoreRepo) {
+				p.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+				k.EXPECT().Get(gomock.Any(), testKeystorePubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidDeployment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			migrations := notMigrating(ctrl)
+			if tt.migration != nil {
+				migrations = mocks.NewMockKeyMigrationRepo(ctrl)
+				migrations.EXPECT().Holding(gomock.Any(), testKeystorePubkey).Return(tt.migration, nil)
+			}
+			target := &fakeKeyManager{name: "teku-1"}
+			service, mockValidators, mockChecks, mockProtection, mockKeystores := newDeploymentService(t, ctrl, beacon.Nodes{}, migrations, target)
+			if tt.validator != nil {
+				mockValidators.EXPECT().GetByPubkey(gomock.Any(), testKeystorePubkey).Return(tt.validator, nil).MinTimes(1)
+			}
+			tt.mockSetup(mockChecks, mockProtection, mockKeystores)
+
+			_, err := service.Deploy(context.Background(), testKeystorePubkey, tt.instance, "")
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Empty(t, target.imported)
+		})
+	}
+}
+
+func TestDeploymentService_Undeploy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := &fakeKeyManager{name: "lh-1", protection: []byte(`{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` +
+		testInterchangeRoot + `"},"data":[{"pubkey":"` + testKeystorePubkey + `","signed_blocks":[{"slot":"3200"}],"signed_attestations":[]}]}`)}
+	stray := &fakeKeyManager{name: "teku-1", deleteErr: fmt.Errorf("instance %q: %w", "teku-1", vclient.ErrKeyNotFound)}
+	service, mockValidators, _, mockProtection, _ := newDeploymentService(t, ctrl, beacon.Nodes{}, notMigrating(ctrl), source, stray)
+	ctx := context.Background()
+
+	mockValidators.EXPECT().GetByPubkey(ctx, testKeystorePubkey).Return(&models.Validator{
+		Pubkey: testKeystorePubkey, Status: models.StatusActive, Client: vclient.TypeLighthouse, ClientInstance: "lh-1",
+	}, nil).AnyTimes()
+
+	// Removing a key the client does not have leaves the recorded client alone
+	v, err := service.Undeploy(ctx, testKeystorePubkey, "teku-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "lh-1", v.ClientInstance)
+
+	// The slashing protection the client returns is stored before the client is cleared
+	gomock.InOrder(
+		mockProtection.EXPECT().Merge(ctx, gomock.Any()).Return(nil),
+		mockValidators.EXPECT().UpdateClient(ctx, testKeystorePubkey, "", "").Return(nil),
+	)
+	v, err = service.Undeploy(ctx, testKeystorePubkey, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, v.ClientInstance)
+}
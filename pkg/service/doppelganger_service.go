@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ErrInvalidDoppelgangerCheck is returned for a doppelganger check that cannot be started
+var ErrInvalidDoppelgangerCheck = errors.New("invalid doppelganger check")
+
+// ErrNotDeployable is returned when a validator may not be enabled on a validator client
+var ErrNotDeployable = errors.New("validator is not deployable")
+
+// doppelgangerValidEpochs is how many epochs after its last watched epoch a passed
+// check still allows enabling the key. Activity can start at any time, so a pass
+// only holds while the key is being enabled.
+const doppelgangerValidEpochs = 2
+
+// DoppelgangerService watches the beacon node for activity of validators that
+// should not be signing anywhere, so a key is never enabled while another
+// instance of it is live
+type DoppelgangerService struct {
+	checks     models.DoppelgangerCheckRepo
+	validators models.ValidatorRepo
+	alerts     models.AlertRepo
+	audit      models.AuditRepo
+	nodes      beacon.Nodes
+	epochs     int64
+}
+
+// NewDoppelgangerService creates a new doppelganger detection service. epochs is
+// the default number of quiet epochs a check needs to pass.
+func NewDoppelgangerService(checks models.DoppelgangerCheckRepo, validators models.ValidatorRepo, alerts models.AlertRepo,
+	audit models.AuditRepo, nodes beacon.Nodes, epochs int64) *DoppelgangerService {
+	return &DoppelgangerService{checks: checks, validators: validators, alerts: alerts, audit: audit, nodes: nodes, epochs: epochs}
+}
+
+// StartCheck starts watching a validator for the given number of epochs, or the
+// default if epochs is 0. Only epochs after the current head are watched. If the
+// key already has a running check, that check is returned instead.
+func (s *DoppelgangerService) StartCheck(ctx context.Context, pubkey string, epochs int64, sourceIP string) (*models.DoppelgangerCheck, error) {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if epochs == 0 {
+		epochs = s.epochs
+	}
+	if epochs < 1 {
+		return nil, fmt.Errorf("%w: epochs must be at least 1", ErrInvalidDoppelgangerCheck)
+	}
+
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: validator %s is not managed", ErrInvalidDoppelgangerCheck, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !v.Status.HasDuties() || v.ValidatorIndex == nil {
+		return nil, fmt.Errorf("%w: validator %s is %s and has no duties to watch", ErrInvalidDoppelgangerCheck, pubkey, v.Status)
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return nil, fmt.Errorf("%w: no beacon node configured for %s %s", ErrInvalidDoppelgangerCheck, v.Blockchain, v.BlockchainNetwork)
+	}
+	head, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.DoppelgangerCheck{Pubkey: pubkey, ValidatorIndex: *v.ValidatorIndex, Epochs: epochs, StartEpoch: int64(head)}
+	err = s.checks.Create(ctx, c)
+	if errors.Is(err, models.ErrCheckInProgress) {
+		return s.checks.Latest(ctx, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Doppelganger check %d of %s for %d epochs from epoch %d", c.ID, pubkey, epochs, head)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "doppelganger.started", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record doppelganger check: %w", err)
+	}
+	return c, nil
+}
+
+// Latest returns the most recent check of a validator
+func (s *DoppelgangerService) Latest(ctx context.Context, pubkey string) (*models.DoppelgangerCheck, error) {
+	return s.checks.Latest(ctx, strings.ToLower(pubkey))
+}
+
+// List returns the checks of a validator, or of all validators if pubkey is empty
+func (s *DoppelgangerService) List(ctx context.Context, pubkey string) ([]models.DoppelgangerCheck, error) {
+	return s.checks.List(ctx, strings.ToLower(pubkey))
+}
+
+// Deployable returns an error wrapping ErrNotDeployable unless the validator may
+// be enabled on a validator client now. Validators with duties need a recently
+// passed doppelganger check. Validators not yet active cannot be seen on chain
+// and need none; exited and slashed validators are never enabled again.
+func (s *DoppelgangerService) Deployable(ctx context.Context, pubkey string) error {
+	pubkey = strings.ToLower(pubkey)
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return fmt.Errorf("%w: validator %s is not managed", ErrNotDeployable, pubkey)
+	}
+	if err != nil {
+		return err
+	}
+	if v.Status == models.StatusUnused || v.Status == models.StatusPending {
+		return nil
+	}
+	if !v.Status.HasDuties() {
+		return fmt.Errorf("%w: validator %s is %s", ErrNotDeployable, pubkey, v.Status)
+	}
+
+	c, err := s.checks.Latest(ctx, pubkey)
+	if isNotFound(err) {
+		return fmt.Errorf("%w: no doppelganger check has run for %s", ErrNotDeployable, pubkey)
+	}
+	if err != nil {
+		return err
+	}
+	switch c.Status {
+	case models.DoppelgangerStatusRunning:
+		return fmt.Errorf("%w: doppelganger check %d is still running", ErrNotDeployable, c.ID)
+	case models.DoppelgangerStatusFailed:
+		return fmt.Errorf("%w: doppelganger check %d saw %s live in epoch %d", ErrNotDeployable, c.ID, pubkey, *c.DetectedEpoch)
+	}
+
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+	head, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	if int64(head) > *c.LastCheckedEpoch+1+doppelgangerValidEpochs {
+		return fmt.Errorf("%w: doppelganger check %d passed in epoch %d and has expired", ErrNotDeployable, c.ID, *c.LastCheckedEpoch)
+	}
+	return nil
+}
+
+// Advance watches the previous epoch for every running check. A validator seen
+// live fails its check and raises a critical alert. A failing check does not
+// stop the others.
+func (s *DoppelgangerService) Advance(ctx context.Context) error {
+	checks, err := s.checks.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range checks {
+		if err := s.watch(ctx, &checks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("doppelganger check %d: %w", checks[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watch checks the liveness of a validator in the last completed epoch, once per epoch
+func (s *DoppelgangerService) watch(ctx context.Context, c *models.DoppelgangerCheck) error {
+	v, err := s.validators.GetByPubkey(ctx, c.Pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to load validator: %w", err)
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+	head, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Beacon nodes only answer for the current and previous epoch, and the current one is incomplete
+	epoch := int64(head) - 1
+	if epoch <= c.StartEpoch || (c.LastCheckedEpoch != nil && epoch <= *c.LastCheckedEpoch) {
+		return nil
+	}
+	index := strconv.FormatInt(c.ValidatorIndex, 10)
+	live, err := node.Liveness(ctx, uint64(epoch), []string{index})
+	if err != nil {
+		return err
+	}
+
+	if live[index] {
+		c.Status = models.DoppelgangerStatusFailed
+		c.DetectedEpoch = &epoch
+		if err := s.checks.Update(ctx, c); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Validator %s was live in epoch %d while it should not be signing anywhere", c.Pubkey, epoch)
+		if _, err := s.alerts.Raise(ctx, &models.Alert{Type: models.AlertTypeDoppelganger, Severity: models.SeverityCritical,
+			Pubkey: c.Pubkey, Message: message}); err != nil {
+			return fmt.Errorf("failed to raise alert: %w", err)
+		}
+		log.Printf("CRITICAL: %s", message)
+		return s.record(ctx, c, fmt.Sprintf("failed: %s live in epoch %d", c.Pubkey, epoch))
+	}
+
+	c.CheckedEpochs++
+	c.LastCheckedEpoch = &epoch
+	if c.CheckedEpochs < c.Epochs {
+		return s.checks.Update(ctx, c)
+	}
+	c.Status = models.DoppelgangerStatusPassed
+	if err := s.checks.Update(ctx, c); err != nil {
+		return err
+	}
+	return s.record(ctx, c, fmt.Sprintf("passed: %s quiet for %d epochs", c.Pubkey, c.CheckedEpochs))
+}
+
+// record adds the outcome of a check to the audit log
+func (s *DoppelgangerService) record(ctx context.Context, c *models.DoppelgangerCheck, outcome string) error {
+	details := fmt.Sprintf("Doppelganger check %d %s", c.ID, outcome)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "doppelganger." + c.Status, Details: details}); err != nil {
+		return fmt.Errorf("failed to record doppelganger check: %w", err)
+	}
+	return nil
+}
+
+// Start advances running checks immediately and then on every interval until ctx is done
+func (s *DoppelgangerService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Advance(ctx); err != nil {
+			log.Printf("Doppelganger check failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
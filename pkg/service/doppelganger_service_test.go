@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestDoppelgangerService_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := &chainStub{epoch: 100, live: map[string]bool{}}
+	srv := newChainStub(t, chain)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockAlerts := mocks.NewMockAlertRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewDoppelgangerService(mockChecks, mockValidators, mockAlerts, mockAudit, nodes, 2)
+	ctx := context.Background()
+
+	index := int64(7)
+	mockValidators.EXPECT().GetByPubkey(ctx, testKey1).Return(&models.Validator{
+		Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+	}, nil).AnyTimes()
+
+	var stored models.DoppelgangerCheck
+	mockChecks.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.DoppelgangerCheck) error {
+		c.ID, c.Status = 1, models.DoppelgangerStatusRunning
+		stored = *c
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	c, err := service.StartCheck(ctx, testKey1, 0, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), c.Epochs)
+	assert.Equal(t, int64(100), c.StartEpoch)
+
+	mockChecks.EXPECT().ListRunning(ctx).DoAndReturn(func(context.Context) ([]models.DoppelgangerCheck, error) {
+		return []models.DoppelgangerCheck{stored}, nil
+	}).AnyTimes()
+	mockChecks.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.DoppelgangerCheck) error {
+		stored = *c
+		return nil
+	}).AnyTimes()
+
+	// Epochs up to the start epoch are not watched
+	chain.epoch = 101
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, int64(0), stored.CheckedEpochs)
+
+	chain.epoch = 102
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, int64(1), stored.CheckedEpochs)
+
+	// The same epoch is only counted once
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, int64(1), stored.CheckedEpochs)
+
+	chain.epoch = 103
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry *models.AuditLog) error {
+		assert.Equal(t, "doppelganger.passed", entry.Action)
+		return nil
+	})
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, models.DoppelgangerStatusPassed, stored.Status)
+	assert.Equal(t, int64(102), *stored.LastCheckedEpoch)
+}
+
+func TestDoppelgangerService_Detects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := &chainStub{epoch: 102, live: map[string]bool{"7": true}}
+	srv := newChainStub(t, chain)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockAlerts := mocks.NewMockAlertRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewDoppelgangerService(mockChecks, mockValidators, mockAlerts, mockAudit, nodes, 2)
+	ctx := context.Background()
+
+	mockValidators.EXPECT().GetByPubkey(ctx, testKey1).Return(&models.Validator{
+		Pubkey: testKey1, Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+	}, nil)
+	mockChecks.EXPECT().ListRunning(ctx).Return([]models.DoppelgangerCheck{{
+		ID: 1, Pubkey: testKey1, ValidatorIndex: 7, Epochs: 2, StartEpoch: 100, Status: models.DoppelgangerStatusRunning,
+	}}, nil)
+	mockChecks.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.DoppelgangerCheck) error {
+		assert.Equal(t, models.DoppelgangerStatusFailed, c.Status)
+		assert.Equal(t, int64(101), *c.DetectedEpoch)
+		return nil
+	})
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+		assert.Equal(t, models.AlertTypeDoppelganger, a.Type)
+		assert.Equal(t, models.SeverityCritical, a.Severity)
+		return true, nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, service.Advance(ctx))
+}
+
+func TestDoppelgangerService_Deployable(t *testing.T) {
+	index := int64(7)
+	passedAt := int64(100)
+	detected := int64(101)
+
+	tests := []struct {
+		name          string
+		status        models.Status
+		check         *models.DoppelgangerCheck
+		expectedError string
+	}{
+		{name: "not yet deposited", status: models.StatusUnused},
+		{name: "recently passed", status: models.StatusActive, check: &models.DoppelgangerCheck{ID: 1, Status: models.DoppelgangerStatusPassed, LastCheckedEpoch: &passedAt}},
+		{
+			name:          "exited",
+			status:        models.StatusExited,
+			expectedError: "validator is not deployable: validator " + testKey1 + " is exited",
+		},
+		{
+			name:          "never checked",
+			status:        models.StatusActive,
+			expectedError: "validator is not deployable: no doppelganger check has run for " + testKey1,
+		},
+		{
+			name:          "running",
+			status:        models.StatusActive,
+			check:         &models.DoppelgangerCheck{ID: 1, Status: models.DoppelgangerStatusRunning},
+			expectedError: "validator is not deployable: doppelganger check 1 is still running",
+		},
+		{
+			name:          "failed",
+			status:        models.StatusActive,
+			check:         &models.DoppelgangerCheck{ID: 1, Status: models.DoppelgangerStatusFailed, DetectedEpoch: &detected},
+			expectedError: "validator is not deployable: doppelganger check 1 saw " + testKey1 + " live in epoch 101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := newChainStub(t, &chainStub{epoch: 103})
+			nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+			mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			service := NewDoppelgangerService(mockChecks, mockValidators, nil, nil, nodes, 2)
+
+			mockValidators.EXPECT().GetByPubkey(gomock.Any(), testKey1).Return(&models.Validator{
+				Pubkey: testKey1, Status: tt.status, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+			}, nil)
+			if tt.status.HasDuties() {
+				if tt.check != nil {
+					mockChecks.EXPECT().Latest(gomock.Any(), testKey1).Return(tt.check, nil)
+				} else {
+					mockChecks.EXPECT().Latest(gomock.Any(), testKey1).Return(nil, sql.ErrNoRows)
+				}
+			}
+
+			err := service.Deployable(context.Background(), testKey1)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrNotDeployable)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
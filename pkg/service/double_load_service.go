@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// DoubleLoadService flags keys loaded on more than one validator client instance.
+// Running the same key on two clients gets it slashed, so every detection raises
+// a critical alert and is recorded in the audit log.
+type DoubleLoadService struct {
+	keys   models.ClientKeyRepo
+	alerts models.AlertRepo
+	audit  models.AuditRepo
+	hooks  []func(ctx context.Context, k models.DoubleLoadedKey) error
+}
+
+// NewDoubleLoadService creates a new double-loaded key service
+func NewDoubleLoadService(keys models.ClientKeyRepo, alerts models.AlertRepo, audit models.AuditRepo) *DoubleLoadService {
+	return &DoubleLoadService{keys: keys, alerts: alerts, audit: audit}
+}
+
+// OnDetect registers a function called for every newly double-loaded key
+func (s *DoubleLoadService) OnDetect(f func(ctx context.Context, k models.DoubleLoadedKey) error) {
+	s.hooks = append(s.hooks, f)
+}
+
+// Check raises an alert for every newly double-loaded key and resolves alerts
+// for keys that are no longer loaded more than once. It returns the keys that
+// are currently double-loaded.
+func (s *DoubleLoadService) Check(ctx context.Context) ([]models.DoubleLoadedKey, error) {
+	doubleLoaded, err := s.keys.ListDoubleLoaded(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(doubleLoaded))
+	for _, k := range doubleLoaded {
+		current[k.Pubkey] = true
+
+		message := fmt.Sprintf("key %s is loaded on %d validator client instances: %s",
+			k.Pubkey, len(k.Instances), describeInstances(k.Instances))
+		created, err := s.alerts.Raise(ctx, &models.Alert{
+			Type:     models.AlertTypeDoubleLoaded,
+			Severity: models.SeverityCritical,
+			Pubkey:   k.Pubkey,
+			Message:  message,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+
+		log.Printf("CRITICAL: %s", message)
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "alert.double_loaded", Details: message}); err != nil {
+			return nil, err
+		}
+		for _, hook := range s.hooks {
+			if err := hook(ctx, k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	open, err := s.alerts.ListOpen(ctx, models.AlertTypeDoubleLoaded)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range open {
+		if current[a.Pubkey] {
+			continue
+		}
+		if err := s.alerts.Resolve(ctx, models.AlertTypeDoubleLoaded, a.Pubkey); err != nil {
+			return nil, err
+		}
+		details := fmt.Sprintf("key %s is no longer double-loaded", a.Pubkey)
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "alert.resolved", Details: details}); err != nil {
+			return nil, err
+		}
+	}
+
+	return doubleLoaded, nil
+}
+
+// ListDoubleLoaded returns the keys currently loaded on more than one client instance
+func (s *DoubleLoadService) ListDoubleLoaded(ctx context.Context) ([]models.DoubleLoadedKey, error) {
+	return s.keys.ListDoubleLoaded(ctx)
+}
+
+// describeInstances formats instances as "name (client, remote)" for alert messages
+func describeInstances(instances []models.ClientKey) string {
+	parts := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		kind := "local"
+		if inst.Remote {
+			kind = "remote"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s, %s)", inst.ClientInstance, inst.Client, kind))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestDoubleLoadService_Check(t *testing.T) {
+	doubleLoaded := []models.DoubleLoadedKey{
+		{
+			Pubkey: "0xaa",
+			Instances: []models.ClientKey{
+				{Pubkey: "0xaa", Client: "lighthouse", ClientInstance: "lh-1"},
+				{Pubkey: "0xaa", Client: "teku", ClientInstance: "teku-1", Remote: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*mocks.MockClientKeyRepo, *mocks.MockAlertRepo, *mocks.MockAuditRepo)
+		expectedLen int
+		detected    []string
+		expectError bool
+	}{
+		{
+			name: "new double-loaded key raises alert and audit entry",
+			mockSetup: func(k *mocks.MockClientKeyRepo, a *mocks.MockAlertRepo, au *mocks.MockAuditRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(doubleLoaded, nil)
+				a.EXPECT().Raise(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, alert *models.Alert) (bool, error) {
+					assert.Equal(t, models.SeverityCritical, alert.Severity)
+					assert.Equal(t, "0xaa", alert.Pubkey)
+					assert.Contains(t, alert.Message, "lh-1 (lighthouse, local), teku-1 (teku, remote)")
+					return true, nil
+				})
+				au.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *models.AuditLog) error {
+					assert.Equal(t, "alert.double_loaded", entry.Action)
+					return nil
+				})
+				a.EXPECT().ListOpen(gomock.Any(), models.AlertTypeDoubleLoaded).
+					Return([]models.Alert{{Pubkey: "0xaa"}}, nil)
+			},
+			expectedLen: 1,
+			detected:    []string{"0xaa"},
+		},
+		{
+			name: "already alerted key is not audited again",
+			mockSetup: func(k *mocks.MockClientKeyRepo, a *mocks.MockAlertRepo, _ *mocks.MockAuditRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(doubleLoaded, nil)
+				a.EXPECT().Raise(gomock.Any(), gomock.Any()).Return(false, nil)
+				a.EXPECT().ListOpen(gomock.Any(), models.AlertTypeDoubleLoaded).
+					Return([]models.Alert{{Pubkey: "0xaa"}}, nil)
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "resolved key closes alert",
+			mockSetup: func(k *mocks.MockClientKeyRepo, a *mocks.MockAlertRepo, au *mocks.MockAuditRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(nil, nil)
+				a.EXPECT().ListOpen(gomock.Any(), models.AlertTypeDoubleLoaded).
+					Return([]models.Alert{{Pubkey: "0xbb"}}, nil)
+				a.EXPECT().Resolve(gomock.Any(), models.AlertTypeDoubleLoaded, "0xbb").Return(nil)
+				au.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedLen: 0,
+		},
+		{
+			name: "repository error",
+			mockSetup: func(k *mocks.MockClientKeyRepo, _ *mocks.MockAlertRepo, _ *mocks.MockAuditRepo) {
+				k.EXPECT().ListDoubleLoaded(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockKeys := mocks.NewMockClientKeyRepo(ctrl)
+			mockAlerts := mocks.NewMockAlertRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockKeys, mockAlerts, mockAudit)
+
+			service := NewDoubleLoadService(mockKeys, mockAlerts, mockAudit)
+			var detected []string
+			service.OnDetect(func(_ context.Context, k models.DoubleLoadedKey) error {
+				detected = append(detected, k.Pubkey)
+				return nil
+			})
+			keys, err := service.Check(context.Background())
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, keys, tt.expectedLen)
+			assert.Equal(t, tt.detected, detected)
+		})
+	}
+}
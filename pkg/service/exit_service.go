@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+// ErrInvalidExitRequest is returned for an exit request that cannot be created
+var ErrInvalidExitRequest = errors.New("invalid exit request")
+
+// ErrRequestNotPending is returned when reviewing an exit request that was already reviewed
+var ErrRequestNotPending = errors.New("exit request is not pending")
+
+// ErrSelfReview is returned when the requester of an exit tries to approve or reject it
+var ErrSelfReview = errors.New("exit request must be reviewed by an operator other than its requester")
+
+// ExitService orchestrates voluntary exits. Exits are irreversible, so a request
+// is only submitted to the beacon node once a second operator approves it.
+type ExitService struct {
+	requests   models.ExitRequestRepo
+	exits      models.VoluntaryExitRepo
+	validators *ValidatorService
+	audit      models.AuditRepo
+	cipher     *vault.Cipher
+	nodes      beacon.Nodes
+}
+
+// NewExitService creates a new exit orchestration service
+func NewExitService(requests models.ExitRequestRepo, exits models.VoluntaryExitRepo, validators *ValidatorService,
+	audit models.AuditRepo, cipher *vault.Cipher, nodes beacon.Nodes) *ExitService {
+	return &ExitService{requests: requests, exits: exits, validators: validators, audit: audit, cipher: cipher, nodes: nodes}
+}
+
+// Request creates a pending exit request for pubkeys. Every validator must be
+// active and have a pre-signed exit for its current index.
+func (s *ExitService) Request(ctx context.Context, pubkeys []string, requestedBy, reason, sourceIP string) (*models.ExitRequest, error) {
+	requestedBy = strings.TrimSpace(requestedBy)
+	if requestedBy == "" {
+		return nil, fmt.Errorf("%w: requested_by is required", ErrInvalidExitRequest)
+	}
+	if len(pubkeys) == 0 {
+		return nil, fmt.Errorf("%w: no pubkeys given", ErrInvalidExitRequest)
+	}
+
+	req := &models.ExitRequest{RequestedBy: requestedBy, Reason: reason}
+	seen := make(map[string]bool, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+		if seen[pubkey] {
+			continue
+		}
+		seen[pubkey] = true
+
+		if err := s.checkExitable(ctx, pubkey); err != nil {
+			return nil, err
+		}
+		req.Items = append(req.Items, models.ExitRequestItem{Pubkey: pubkey})
+	}
+
+	if err := s.requests.Create(ctx, req); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Exit request %d by %s for %d validators", req.ID, requestedBy, len(req.Items))
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "exit_request.created", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record exit request: %w", err)
+	}
+	return req, nil
+}
+
+// checkExitable returns an error wrapping ErrInvalidExitRequest unless the validator can be exited now
+func (s *ExitService) checkExitable(ctx context.Context, pubkey string) error {
+	v, err := s.validators.GetValidatorByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return fmt.Errorf("%w: validator %s is not managed", ErrInvalidExitRequest, pubkey)
+	}
+	if err != nil {
+		return err
+	}
+	if v.Status != models.StatusActive {
+		return fmt.Errorf("%w: validator %s is %s, not active", ErrInvalidExitRequest, pubkey, v.Status)
+	}
+
+	exit, err := s.exits.GetByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return fmt.Errorf("%w: validator %s has no pre-signed exit", ErrInvalidExitRequest, pubkey)
+	}
+	if err != nil {
+		return err
+	}
+	if v.ValidatorIndex == nil || *v.ValidatorIndex != exit.ValidatorIndex {
+		return fmt.Errorf("%w: pre-signed exit of validator %s does not match its index", ErrInvalidExitRequest, pubkey)
+	}
+	return nil
+}
+
+// Approve approves a pending request on behalf of approver and submits its
+// stored exits. Validators whose exit the beacon node accepts move to exiting.
+// Once an exit is broadcast its item is recorded as submitted so Track follows
+// it, even if the validator status or the audit log cannot be updated; such
+// errors are logged and the remaining items are still submitted. A request
+// whose exits all failed is completed straight away.
+func (s *ExitService) Approve(ctx context.Context, id int64, approver, sourceIP string) (*models.ExitRequest, error) {
+	req, err := s.review(ctx, id, models.ExitRequestStatusSubmitted, approver, sourceIP)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, item := range req.Items {
+		status, errMsg := models.ExitItemStatusSubmitted, ""
+		if err := s.submit(ctx, item.Pubkey); err != nil {
+			status, errMsg = models.ExitItemStatusFailed, err.Error()
+		} else if err := s.validators.UpdateValidatorStatus(ctx, item.Pubkey, models.StatusExiting); err != nil {
+			errs = append(errs, fmt.Errorf("failed to mark %s exiting: %w", item.Pubkey, err))
+		}
+
+		if err := s.requests.UpdateItem(ctx, id, item.Pubkey, status, errMsg); err != nil {
+			errs = append(errs, fmt.Errorf("failed to record exit of %s as %s: %w", item.Pubkey, status, err))
+		}
+		details := fmt.Sprintf("Voluntary exit of %s for request %d %s", item.Pubkey, id, status)
+		if errMsg != "" {
+			details += ": " + errMsg
+		}
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "exit_request.submitted", SourceIP: sourceIP, Details: details}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to record exit submission of %s: %w", item.Pubkey, err))
+		}
+	}
+	if err := s.complete(ctx, id); err != nil {
+		errs = append(errs, fmt.Errorf("failed to complete exit request: %w", err))
+	}
+	if err := errors.Join(errs...); err != nil {
+		log.Printf("Exit request %d was submitted with errors: %v", id, err)
+	}
+
+	return s.requests.Get(ctx, id)
+}
+
+// Reject declines a pending request on behalf of reviewer
+func (s *ExitService) Reject(ctx context.Context, id int64, reviewer, sourceIP string) (*models.ExitRequest, error) {
+	if _, err := s.review(ctx, id, models.ExitRequestStatusRejected, reviewer, sourceIP); err != nil {
+		return nil, err
+	}
+	return s.requests.Get(ctx, id)
+}
+
+// review moves a pending request to status after checking reviewer is not its requester
+func (s *ExitService) review(ctx context.Context, id int64, status, reviewer, sourceIP string) (*models.ExitRequest, error) {
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidExitRequest)
+	}
+
+	req, err := s.requests.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != models.ExitRequestStatusPending {
+		return nil, ErrRequestNotPending
+	}
+	if strings.EqualFold(req.RequestedBy, reviewer) {
+		return nil, ErrSelfReview
+	}
+
+	// The repository re-checks both conditions so concurrent reviews cannot both succeed
+	if err := s.requests.Review(ctx, id, status, reviewer); err != nil {
+		if isNotFound(err) {
+			return nil, ErrRequestNotPending
+		}
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Exit request %d by %s %s by %s", id, req.RequestedBy, status, reviewer)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "exit_request.reviewed", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record exit review: %w", err)
+	}
+	return req, nil
+}
+
+// submit sends the stored exit of a validator to its network's beacon node pool
+func (s *ExitService) submit(ctx context.Context, pubkey string) error {
+	v, err := s.validators.GetValidatorByPubkey(ctx, pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to load validator: %w", err)
+	}
+	// The validator may have changed since the request was created
+	if err := v.Status.CheckTransition(models.StatusExiting); err != nil {
+		return err
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+
+	stored, err := s.exits.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to load pre-signed exit: %w", err)
+	}
+	plaintext, err := s.cipher.Open(stored.Message, []byte(pubkey))
+	if err != nil {
+		return err
+	}
+	var signed beacon.SignedVoluntaryExit
+	if err := json.Unmarshal(plaintext, &signed); err != nil {
+		return fmt.Errorf("failed to decode stored exit: %w", err)
+	}
+
+	return node.SubmitVoluntaryExit(ctx, signed)
+}
+
+// Get returns a request with the progress of each of its validators
+func (s *ExitService) Get(ctx context.Context, id int64) (*models.ExitRequest, error) {
+	return s.requests.Get(ctx, id)
+}
+
+// List returns the requests with the given status, or all of them if status is empty
+func (s *ExitService) List(ctx context.Context, status string) ([]models.ExitRequest, error) {
+	return s.requests.List(ctx, status)
+}
+
+// Track checks the submitted exits against the beacon node, moving validators
+// the beacon node reports exited to exited. Submitted requests with no exit
+// left in flight are completed. A failing validator does not stop the others.
+func (s *ExitService) Track(ctx context.Context) error {
+	items, err := s.requests.ListItems(ctx, models.ExitItemStatusSubmitted)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, item := range items {
+		exited, err := s.exited(ctx, item.Pubkey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Pubkey, err))
+			continue
+		}
+		if !exited {
+			continue
+		}
+
+		if err := s.validators.UpdateValidatorStatus(ctx, item.Pubkey, models.StatusExited); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Pubkey, err))
+			continue
+		}
+		if err := s.requests.UpdateItem(ctx, item.RequestID, item.Pubkey, models.ExitItemStatusExited, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Pubkey, err))
+		}
+	}
+
+	// Also covers requests left submitted with every exit failed
+	requests, err := s.requests.List(ctx, models.ExitRequestStatusSubmitted)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, req := range requests {
+		if err := s.complete(ctx, req.ID); err != nil {
+			errs = append(errs, fmt.Errorf("exit request %d: %w", req.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exited reports whether the beacon node has the validator past its exit epoch
+func (s *ExitService) exited(ctx context.Context, pubkey string) (bool, error) {
+	v, err := s.validators.GetValidatorByPubkey(ctx, pubkey)
+	if err != nil {
+		return false, err
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return false, fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+
+	states, err := node.Validators(ctx, []string{pubkey})
+	if err != nil {
+		return false, err
+	}
+	for _, state := range states {
+		if strings.HasPrefix(state.Status, "exited_") || strings.HasPrefix(state.Status, "withdrawal_") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// complete marks a request completed once none of its exits are in flight
+func (s *ExitService) complete(ctx context.Context, id int64) error {
+	req, err := s.requests.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, item := range req.Items {
+		if item.Status == models.ExitItemStatusPending || item.Status == models.ExitItemStatusSubmitted {
+			return nil
+		}
+	}
+	return s.requests.UpdateStatus(ctx, id, models.ExitRequestStatusCompleted)
+}
+
+// Start tracks submitted exits immediately and then on every interval until ctx is done
+func (s *ExitService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Track(ctx); err != nil {
+			log.Printf("Exit tracking failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+// newExitPoolStub serves the voluntary exit pool, recording submitted exits, and
+// reports every validator with the given beacon status
+func newExitPoolStub(t *testing.T, submitted *[]beacon.SignedVoluntaryExit, status string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/pool/voluntary_exits":
+			var exit beacon.SignedVoluntaryExit
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&exit))
+			*submitted = append(*submitted, exit)
+		case "/eth/v1/beacon/states/head/validators":
+			w.Write([]byte(`{"data":[{"index":"42","status":"` + status + `","validator":{"pubkey":"` + r.URL.Query().Get("id") + `"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestExitService_Request(t *testing.T) {
+	index := int64(42)
+	active := &models.Validator{Pubkey: "0xaa", Status: models.StatusActive, ValidatorIndex: &index}
+
+	tests := []struct {
+		name          string
+		pubkeys       []string
+		requestedBy   string
+		mockSetup     func(*mocks.MockValidatorRepo, *mocks.MockVoluntaryExitRepo, *mocks.MockExitRequestRepo, *mocks.MockAuditRepo)
+		expectedError string
+	}{
+		{
+			name:        "created",
+			pubkeys:     []string{"0xAA", "0xaa"},
+			requestedBy: "alice",
+			mockSetup: func(v *mocks.MockValidatorRepo, e *mocks.MockVoluntaryExitRepo, r *mocks.MockExitRequestRepo, a *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(active, nil)
+				e.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.VoluntaryExit{ValidatorIndex: 42}, nil)
+				r.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *models.ExitRequest) error {
+					assert.Equal(t, []models.ExitRequestItem{{Pubkey: "0xaa"}}, req.Items)
+					return nil
+				})
+				a.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "no requester",
+			pubkeys: []string{"0xaa"},
+			mockSetup: func(*mocks.MockValidatorRepo, *mocks.MockVoluntaryExitRepo, *mocks.MockExitRequestRepo, *mocks.MockAuditRepo) {
+			},
+			expectedError: "invalid exit request: requested_by is required",
+		},
+		{
+			name:        "not active",
+			pubkeys:     []string{"0xaa"},
+			requestedBy: "alice",
+			mockSetup: func(v *mocks.MockValidatorRepo, _ *mocks.MockVoluntaryExitRepo, _ *mocks.MockExitRequestRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.Validator{Pubkey: "0xaa", Status: models.StatusExiting}, nil)
+			},
+			expectedError: "invalid exit request: validator 0xaa is exiting, not active",
+		},
+		{
+			name:        "no pre-signed exit",
+			pubkeys:     []string{"0xaa"},
+			requestedBy: "alice",
+			mockSetup: func(v *mocks.MockValidatorRepo, e *mocks.MockVoluntaryExitRepo, _ *mocks.MockExitRequestRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(active, nil)
+				e.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(nil, sql.ErrNoRows)
+			},
+			expectedError: "invalid exit request: validator 0xaa has no pre-signed exit",
+		},
+		{
+			name:        "stale pre-signed exit",
+			pubkeys:     []string{"0xaa"},
+			requestedBy: "alice",
+			mockSetup: func(v *mocks.MockValidatorRepo, e *mocks.MockVoluntaryExitRepo, _ *mocks.MockExitRequestRepo, _ *mocks.MockAuditRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(active, nil)
+				e.EXPECT().GetByPubkey(gomock.Any(), "0xaa").Return(&models.VoluntaryExit{ValidatorIndex: 7}, nil)
+			},
+			expectedError: "invalid exit request: pre-signed exit of validator 0xaa does not match its index",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockExits := mocks.NewMockVoluntaryExitRepo(ctrl)
+			mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			tt.mockSetup(mockValidators, mockExits, mockRequests, mockAudit)
+
+			service := NewExitService(mockRequests, mockExits, NewValidatorService(mockValidators, nil), mockAudit, nil, beacon.Nodes{})
+			_, err := service.Request(context.Background(), tt.pubkeys, tt.requestedBy, "", "10.0.0.1")
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.True(t, errors.Is(err, ErrInvalidExitRequest))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExitService_Review(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *models.ExitRequest
+		reviewErr     error
+		reviewer      string
+		expectedError error
+	}{
+		{
+			name:          "self approval",
+			request:       &models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusPending},
+			reviewer:      "Alice",
+			expectedError: ErrSelfReview,
+		},
+		{
+			name:          "already reviewed",
+			request:       &models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusRejected},
+			reviewer:      "bob",
+			expectedError: ErrRequestNotPending,
+		},
+		{
+			name:          "concurrent review",
+			request:       &models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusPending},
+			reviewErr:     sql.ErrNoRows,
+			reviewer:      "bob",
+			expectedError: ErrRequestNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+			mockRequests.EXPECT().Get(gomock.Any(), int64(1)).Return(tt.request, nil)
+			if tt.reviewErr != nil {
+				mockRequests.EXPECT().Review(gomock.Any(), int64(1), models.ExitRequestStatusSubmitted, tt.reviewer).Return(tt.reviewErr)
+			}
+
+			service := NewExitService(mockRequests, nil, nil, nil, nil, beacon.Nodes{})
+			_, err := service.Approve(context.Background(), 1, tt.reviewer, "")
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestExitService_ApproveAndTrack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var submitted []beacon.SignedVoluntaryExit
+	srv := newExitPoolStub(t, &submitted, "exited_unslashed")
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockExits := mocks.NewMockVoluntaryExitRepo(ctrl)
+	mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewExitService(mockRequests, mockExits, NewValidatorService(mockValidators, nil), mockAudit, cipher, nodes)
+	ctx := context.Background()
+
+	signed := beacon.SignedVoluntaryExit{Message: beacon.VoluntaryExit{Epoch: "194048", ValidatorIndex: "42"}, Signature: "0x01"}
+	plaintext, _ := json.Marshal(signed)
+	sealed, err := cipher.Seal(plaintext, []byte("0xaa"))
+	require.NoError(t, err)
+
+	validator := &models.Validator{Pubkey: "0xaa", Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	pending := &models.ExitRequest{
+		ID:          1,
+		RequestedBy: "alice",
+		Status:      models.ExitRequestStatusPending,
+		Items:       []models.ExitRequestItem{{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusPending}},
+	}
+	submittedReq := &models.ExitRequest{
+		ID:          1,
+		RequestedBy: "alice",
+		Status:      models.ExitRequestStatusSubmitted,
+		Items:       []models.ExitRequestItem{{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusSubmitted}},
+	}
+
+	gomock.InOrder(
+		mockRequests.EXPECT().Get(ctx, int64(1)).Return(pending, nil),
+		mockRequests.EXPECT().Review(ctx, int64(1), models.ExitRequestStatusSubmitted, "bob").Return(nil),
+		mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil),
+		mockValidators.EXPECT().GetByPubkey(ctx, "0xaa").Return(validator, nil),
+		mockExits.EXPECT().GetByPubkey(ctx, "0xaa").Return(&models.VoluntaryExit{Pubkey: "0xaa", Message: sealed}, nil),
+		mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).Return(nil),
+		mockRequests.EXPECT().UpdateItem(ctx, int64(1), "0xaa", models.ExitItemStatusSubmitted, "").Return(nil),
+		mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil),
+		mockRequests.EXPECT().Get(ctx, int64(1)).Return(submittedReq, nil).Times(2),
+	)
+
+	req, err := service.Approve(ctx, 1, "bob", "10.0.0.2")
+	require.NoError(t, err)
+	assert.Equal(t, models.ExitRequestStatusSubmitted, req.Status)
+	assert.Equal(t, []beacon.SignedVoluntaryExit{signed}, submitted)
+
+	// Once the beacon node reports the validator exited, so is the request
+	exiting := &models.Validator{Pubkey: "0xaa", Status: models.StatusExiting, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	exited := *submittedReq
+	exited.Items = []models.ExitRequestItem{{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusExited}}
+	gomock.InOrder(
+		mockRequests.EXPECT().ListItems(ctx, models.ExitItemStatusSubmitted).Return(submittedReq.Items, nil),
+		mockValidators.EXPECT().GetByPubkey(ctx, "0xaa").Return(exiting, nil),
+		mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExited).Return(nil),
+		mockRequests.EXPECT().UpdateItem(ctx, int64(1), "0xaa", models.ExitItemStatusExited, "").Return(nil),
+		mockRequests.EXPECT().List(ctx, models.ExitRequestStatusSubmitted).Return([]models.ExitRequest{*submittedReq}, nil),
+		mockRequests.EXPECT().Get(ctx, int64(1)).Return(&exited, nil),
+		mockRequests.EXPECT().UpdateStatus(ctx, int64(1), models.ExitRequestStatusCompleted).Return(nil),
+	)
+	require.NoError(t, service.Track(ctx))
+}
+
+func TestExitService_ApproveSubmitFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewExitService(mockRequests, mocks.NewMockVoluntaryExitRepo(ctrl), NewValidatorService(mockValidators, nil), mockAudit, nil, beacon.Nodes{})
+	ctx := context.Background()
+
+	pending := &models.ExitRequest{
+		ID:          1,
+		RequestedBy: "alice",
+		Status:      models.ExitRequestStatusPending,
+		Items:       []models.ExitRequestItem{{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusPending}},
+	}
+
+	failed := &models.ExitRequest{
+		ID:          1,
+		RequestedBy: "alice",
+		Status:      models.ExitRequestStatusSubmitted,
+		Items:       []models.ExitRequestItem{{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusFailed}},
+	}
+
+	// The validator was slashed after the request was created; its status is left alone
+	mockRequests.EXPECT().Get(ctx, int64(1)).Return(pending, nil)
+	mockRequests.EXPECT().Review(ctx, int64(1), models.ExitRequestStatusSubmitted, "bob").Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil).Times(2)
+	mockValidators.EXPECT().GetByPubkey(ctx, "0xaa").Return(&models.Validator{Pubkey: "0xaa", Status: models.StatusSlashed}, nil)
+	mockRequests.EXPECT().UpdateItem(ctx, int64(1), "0xaa", models.ExitItemStatusFailed,
+		"invalid validator status transition: slashed to exiting").Return(nil)
+
+	// With no exit in flight the request is completed instead of staying submitted
+	mockRequests.EXPECT().Get(ctx, int64(1)).Return(failed, nil).Times(2)
+	mockRequests.EXPECT().UpdateStatus(ctx, int64(1), models.ExitRequestStatusCompleted).Return(nil)
+
+	_, err := service.Approve(ctx, 1, "bob", "")
+	require.NoError(t, err)
+}
+
+func TestExitService_ApproveAfterBroadcastFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var submitted []beacon.SignedVoluntaryExit
+	srv := newExitPoolStub(t, &submitted, "active_exiting")
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockExits := mocks.NewMockVoluntaryExitRepo(ctrl)
+	mockRequests := mocks.NewMockExitRequestRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewExitService(mockRequests, mockExits, NewValidatorService(mockValidators, nil), mockAudit, cipher, nodes)
+	ctx := context.Background()
+
+	pending := &models.ExitRequest{ID: 1, RequestedBy: "alice", Status: models.ExitRequestStatusPending}
+	for _, pubkey := range []string{"0xaa", "0xbb"} {
+		signed := beacon.SignedVoluntaryExit{Message: beacon.VoluntaryExit{Epoch: "194048", ValidatorIndex: "42"}, Signature: "0x01"}
+		plaintext, _ := json.Marshal(signed)
+		sealed, err := cipher.Seal(plaintext, []byte(pubkey))
+		require.NoError(t, err)
+
+		pending.Items = append(pending.Items, models.ExitRequestItem{RequestID: 1, Pubkey: pubkey, Status: models.ExitItemStatusPending})
+		mockValidators.EXPECT().GetByPubkey(ctx, pubkey).
+			Return(&models.Validator{Pubkey: pubkey, Status: models.StatusActive, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}, nil)
+		mockExits.EXPECT().GetByPubkey(ctx, pubkey).Return(&models.VoluntaryExit{Pubkey: pubkey, Message: sealed}, nil)
+	}
+
+	mockRequests.EXPECT().Get(ctx, int64(1)).Return(pending, nil)
+	mockRequests.EXPECT().Review(ctx, int64(1), models.ExitRequestStatusSubmitted, "bob").Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	// 0xaa changed status concurrently and the audit log is down, yet both
+	// broadcast exits are still recorded as submitted so Track follows them
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).
+		Return(&models.TransitionError{From: models.StatusSlashed, To: models.StatusExiting})
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xbb", models.StatusExiting).Return(nil)
+	mockRequests.EXPECT().UpdateItem(ctx, int64(1), "0xaa", models.ExitItemStatusSubmitted, "").Return(nil)
+	mockRequests.EXPECT().UpdateItem(ctx, int64(1), "0xbb", models.ExitItemStatusSubmitted, "").Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(errors.New("database error")).Times(2)
+
+	submittedReq := *pending
+	submittedReq.Status = models.ExitRequestStatusSubmitted
+	submittedReq.Items = []models.ExitRequestItem{
+		{RequestID: 1, Pubkey: "0xaa", Status: models.ExitItemStatusSubmitted},
+		{RequestID: 1, Pubkey: "0xbb", Status: models.ExitItemStatusSubmitted},
+	}
+	mockRequests.EXPECT().Get(ctx, int64(1)).Return(&submittedReq, nil).Times(2)
+
+	req, err := service.Approve(ctx, 1, "bob", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.ExitRequestStatusSubmitted, req.Status)
+	assert.Len(t, submitted, 2)
+}
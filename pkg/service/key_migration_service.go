@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/interchange"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// ErrInvalidKeyMigration is returned for a key migration that cannot be started
+var ErrInvalidKeyMigration = errors.New("invalid key migration")
+
+// migrationVerifyEpochs is how long a migrated validator may take to attest from
+// its new client. Clients running doppelganger protection stay silent for a few
+// epochs after an import, so this is generous.
+const migrationVerifyEpochs = 8
+
+// KeyMigrationRequest asks for a key to be moved between validator client instances
+type KeyMigrationRequest struct {
+	Pubkey         string `json:"pubkey"`
+	SourceInstance string `json:"source_instance"`
+	TargetInstance string `json:"target_instance"`
+	RequestedBy    string `json:"requested_by"`
+	// Keystore is the EIP-2335 keystore the target client imports
+	Keystore json.RawMessage `json:"keystore"`
+	Password string          `json:"password"`
+	// WaitEpochs overrides the service default when set
+	WaitEpochs *int64 `json:"wait_epochs,omitempty"`
+}
+
+// migrationSecret is the keystore and password stored encrypted until the target imports them
+type migrationSecret struct {
+	Keystore string `json:"keystore"`
+	Password string `json:"password"`
+}
+
+// KeyMigrationService moves keys between validator clients without risking a
+// slashing. The key is deleted from the source client first, capturing its
+// slashing protection, and only imported on the target once a doppelganger
+// check has seen the validator quiet for the configured number of epochs. Each step is
+// stored before the next starts, and every step can be repeated safely, so a
+// migration interrupted by a crash resumes where it stopped.
+type KeyMigrationService struct {
+	migrations   models.KeyMigrationRepo
+	validators   models.ValidatorRepo
+	protection   *SlashingProtectionService
+	doppelganger *DoppelgangerService
+	audit        models.AuditRepo
+	cipher       *vault.Cipher
+	nodes        beacon.Nodes
+	clients      map[string]vclient.KeyManager
+	waitEpochs   int64
+}
+
+// NewKeyMigrationService creates a new key migration service. waitEpochs is the
+// default number of quiet epochs between deleting a key and importing it.
+func NewKeyMigrationService(migrations models.KeyMigrationRepo, validators models.ValidatorRepo, protection *SlashingProtectionService,
+	doppelganger *DoppelgangerService, audit models.AuditRepo, cipher *vault.Cipher, nodes beacon.Nodes, clients []vclient.KeyManager, waitEpochs int64) *KeyMigrationService {
+	byName := make(map[string]vclient.KeyManager, len(clients))
+	for _, c := range clients {
+		byName[c.Name()] = c
+	}
+	return &KeyMigrationService{
+		migrations:   migrations,
+		validators:   validators,
+		protection:   protection,
+		doppelganger: doppelganger,
+		audit:        audit,
+		cipher:       cipher,
+		nodes:        nodes,
+		clients:      byName,
+		waitEpochs:   waitEpochs,
+	}
+}
+
+// Create starts a migration. The validator must be active, so its attestations
+// can be watched, and the keystore must be for its key.
+func (s *KeyMigrationService) Create(ctx context.Context, req KeyMigrationRequest, sourceIP string) (*models.KeyMigration, error) {
+	req.Pubkey = strings.ToLower(strings.TrimSpace(req.Pubkey))
+	req.RequestedBy = strings.TrimSpace(req.RequestedBy)
+	if req.RequestedBy == "" {
+		return nil, fmt.Errorf("%w: requested_by is required", ErrInvalidKeyMigration)
+	}
+	if req.SourceInstance == req.TargetInstance {
+		return nil, fmt.Errorf("%w: source and target instance must differ", ErrInvalidKeyMigration)
+	}
+	for _, name := range []string{req.SourceInstance, req.TargetInstance} {
+		if _, ok := s.clients[name]; !ok {
+			return nil, fmt.Errorf("%w: validator client instance %q is not configured", ErrInvalidKeyMigration, name)
+		}
+	}
+	waitEpochs := s.waitEpochs
+	if req.WaitEpochs != nil {
+		waitEpochs = *req.WaitEpochs
+	}
+	if waitEpochs < 1 {
+		return nil, fmt.Errorf("%w: wait_epochs must be at least 1", ErrInvalidKeyMigration)
+	}
+
+	v, err := s.validators.GetByPubkey(ctx, req.Pubkey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: validator %s is not managed", ErrInvalidKeyMigration, req.Pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.Status != models.StatusActive || v.ValidatorIndex == nil {
+		return nil, fmt.Errorf("%w: validator %s is %s, not active", ErrInvalidKeyMigration, req.Pubkey, v.Status)
+	}
+	if v.ClientInstance != "" && v.ClientInstance != req.SourceInstance {
+		return nil, fmt.Errorf("%w: validator %s is loaded on %s, not %s", ErrInvalidKeyMigration, req.Pubkey, v.ClientInstance, req.SourceInstance)
+	}
+	if _, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork); !ok {
+		return nil, fmt.Errorf("%w: no beacon node configured for %s %s", ErrInvalidKeyMigration, v.Blockchain, v.BlockchainNetwork)
+	}
+
+	var keystore struct {
+		Pubkey string `json:"pubkey"`
+	}
+	if err := json.Unmarshal(req.Keystore, &keystore); err != nil {
+		return nil, fmt.Errorf("%w: invalid keystore: %v", ErrInvalidKeyMigration, err)
+	}
+	if vclient.NormalizePubkey(keystore.Pubkey) != req.Pubkey {
+		return nil, fmt.Errorf("%w: keystore is not for validator %s", ErrInvalidKeyMigration, req.Pubkey)
+	}
+	plaintext, err := json.Marshal(migrationSecret{Keystore: string(req.Keystore), Password: req.Password})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode keystore: %w", err)
+	}
+	sealed, err := s.cipher.Seal(plaintext, []byte(req.Pubkey))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &models.KeyMigration{
+		Pubkey:         req.Pubkey,
+		SourceInstance: req.SourceInstance,
+		TargetInstance: req.TargetInstance,
+		RequestedBy:    req.RequestedBy,
+		WaitEpochs:     waitEpochs,
+		Keystore:       sealed,
+	}
+	if err := s.migrations.Create(ctx, m); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Migration %d of %s from %s to %s by %s", m.ID, m.Pubkey, m.SourceInstance, m.TargetInstance, m.RequestedBy)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "key_migration.created", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record key migration: %w", err)
+	}
+	return m, nil
+}
+
+// Get returns a migration
+func (s *KeyMigrationService) Get(ctx context.Context, id int64) (*models.KeyMigration, error) {
+	return s.migrations.Get(ctx, id)
+}
+
+// List returns the migrations with the given status, or all of them if status is empty
+func (s *KeyMigrationService) List(ctx context.Context, status string) ([]models.KeyMigration, error) {
+	return s.migrations.List(ctx, status)
+}
+
+// Release discards the keystore a failed migration kept after the key left its
+// source, once an operator has loaded the key again or decided not to. Until
+// then the key cannot be deployed or registered anywhere.
+func (s *KeyMigrationService) Release(ctx context.Context, id int64, sourceIP string) (*models.KeyMigration, error) {
+	m, err := s.migrations.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != models.KeyMigrationStatusFailed || m.Keystore == nil {
+		return nil, fmt.Errorf("%w: migration %d does not hold a keystore", ErrInvalidKeyMigration, id)
+	}
+
+	m.Keystore = nil
+	if err := s.migrations.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	details := fmt.Sprintf("Migration %d of %s released its keystore", m.ID, m.Pubkey)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "key_migration.released", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record key migration release: %w", err)
+	}
+	return m, nil
+}
+
+// Advance moves every unfinished migration as far as it can go now. Errors that
+// may be temporary leave a migration where it is to be retried on the next run;
+// a failing migration does not stop the others.
+func (s *KeyMigrationService) Advance(ctx context.Context) error {
+	migrations, err := s.migrations.ListUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range migrations {
+		m := &migrations[i]
+		for {
+			progressed, err := s.step(ctx, m)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("key migration %d: %w", m.ID, err))
+			}
+			if err != nil || !progressed || m.Finished() {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// step runs the next step of a migration and reports whether it moved on
+func (s *KeyMigrationService) step(ctx context.Context, m *models.KeyMigration) (bool, error) {
+	source, ok := s.clients[m.SourceInstance]
+	if !ok {
+		return false, fmt.Errorf("validator client instance %q is not configured", m.SourceInstance)
+	}
+	target, ok := s.clients[m.TargetInstance]
+	if !ok {
+		return false, fmt.Errorf("validator client instance %q is not configured", m.TargetInstance)
+	}
+	v, err := s.validators.GetByPubkey(ctx, m.Pubkey)
+	if err != nil {
+		return false, fmt.Errorf("failed to load validator: %w", err)
+	}
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return false, fmt.Errorf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+	if v.ValidatorIndex == nil {
+		return false, fmt.Errorf("validator %s has no index", m.Pubkey)
+	}
+	index := strconv.FormatInt(*v.ValidatorIndex, 10)
+
+	switch m.Status {
+	case models.KeyMigrationStatusPending:
+		return s.deleteFromSource(ctx, m, source, node)
+	case models.KeyMigrationStatusSourceDeleted:
+		return s.importToTarget(ctx, m, target, node)
+	case models.KeyMigrationStatusTargetImported:
+		return s.verify(ctx, m, target, node, index)
+	default:
+		return false, nil
+	}
+}
+
+// deleteFromSource removes the key from the source client, merges the slashing
+// protection it returns into the stored data and starts a doppelganger check.
+// A repeated deletion returns the same slashing protection and the running
+// check is reused, so a crash before the step is stored is harmless.
+func (s *KeyMigrationService) deleteFromSource(ctx context.Context, m *models.KeyMigration, source vclient.KeyManager, node *beacon.Client) (bool, error) {
+	raw, err := source.DeleteKeystore(ctx, m.Pubkey)
+	if errors.Is(err, vclient.ErrKeyNotFound) || errors.Is(err, vclient.ErrRejected) {
+		return false, s.fail(ctx, m, err.Error())
+	}
+	if err != nil {
+		return false, err
+	}
+	epoch, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var i interchange.Interchange
+	if err := json.Unmarshal(raw, &i); err != nil {
+		return false, s.halt(ctx, m, fmt.Sprintf("%s returned invalid slashing protection: %v", m.SourceInstance, err))
+	}
+	result, err := s.protection.Import(ctx, &i, m.SourceInstance, "")
+	if errors.Is(err, interchange.ErrInvalid) {
+		return false, s.halt(ctx, m, fmt.Sprintf("%s returned invalid slashing protection: %v", m.SourceInstance, err))
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, r := range result.Rejected {
+		if r.Pubkey == m.Pubkey {
+			return false, s.halt(ctx, m, "slashing protection rejected: "+r.Error)
+		}
+	}
+	if ok, err := s.checkDoppelganger(ctx, m); !ok {
+		return false, err
+	}
+
+	deleted := int64(epoch)
+	m.DeletedEpoch = &deleted
+	m.SlashingProtection = raw
+	m.Status = models.KeyMigrationStatusSourceDeleted
+	return true, s.save(ctx, m, fmt.Sprintf("deleted from %s in epoch %d", m.SourceInstance, epoch))
+}
+
+// importToTarget imports the key on the target client once the doppelganger
+// check passed. Importing a key the target already has succeeds, so a crash
+// before the step is stored is harmless.
+func (s *KeyMigrationService) importToTarget(ctx context.Context, m *models.KeyMigration, target vclient.KeyManager, node *beacon.Client) (bool, error) {
+	err := s.doppelganger.Deployable(ctx, m.Pubkey)
+	if errors.Is(err, ErrNotDeployable) {
+		_, err := s.checkDoppelganger(ctx, m)
+		return false, err
+	}
+	if err != nil {
+		return false, err
+	}
+	epoch, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	protection, err := s.targetProtection(ctx, m)
+	if err != nil {
+		return false, err
+	}
+	plaintext, err := s.cipher.Open(m.Keystore, []byte(m.Pubkey))
+	if err != nil {
+		return false, err
+	}
+	var secret migrationSecret
+	if err := json.Unmarshal(plaintext, &secret); err != nil {
+		return false, fmt.Errorf("failed to decode stored keystore: %w", err)
+	}
+
+	err = target.ImportKeystore(ctx, secret.Keystore, secret.Password, protection)
+	if errors.Is(err, vclient.ErrRejected) {
+		return false, s.halt(ctx, m, err.Error())
+	}
+	if err != nil {
+		return false, err
+	}
+
+	imported := int64(epoch)
+	m.ImportedEpoch = &imported
+	m.Keystore = nil
+	m.Status = models.KeyMigrationStatusTargetImported
+	return true, s.save(ctx, m, fmt.Sprintf("imported on %s in epoch %d", m.TargetInstance, epoch))
+}
+
+// checkDoppelganger makes sure a doppelganger check watches the key and reports
+// whether the migration can go on. A failed check fails the migration; a check
+// that passed too long ago to deploy is replaced by a new one.
+func (s *KeyMigrationService) checkDoppelganger(ctx context.Context, m *models.KeyMigration) (bool, error) {
+	c, err := s.doppelganger.Latest(ctx, m.Pubkey)
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+	if c != nil && c.Status == models.DoppelgangerStatusFailed && c.CreatedAt.After(m.CreatedAt) {
+		return false, s.halt(ctx, m, fmt.Sprintf("doppelganger check %d saw the validator live in epoch %d", c.ID, *c.DetectedEpoch))
+	}
+	if c != nil && c.Status == models.DoppelgangerStatusRunning {
+		return true, nil
+	}
+
+	_, err = s.doppelganger.StartCheck(ctx, m.Pubkey, m.WaitEpochs, "")
+	if errors.Is(err, ErrInvalidDoppelgangerCheck) {
+		return false, s.halt(ctx, m, err.Error())
+	}
+	return err == nil, err
+}
+
+// targetProtection returns the interchange to import with the key. It holds all
+// slashing protection stored for the key, which includes what the source client
+// returned; if the source returned none, its empty interchange is passed on.
+func (s *KeyMigrationService) targetProtection(ctx context.Context, m *models.KeyMigration) ([]byte, error) {
+	i, err := s.protection.Export(ctx, []string{m.Pubkey}, "")
+	if errors.Is(err, ErrMissingSlashingProtection) {
+		return m.SlashingProtection, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(i)
+}
+
+// verify completes the migration once the validator attests from the target client
+func (s *KeyMigrationService) verify(ctx context.Context, m *models.KeyMigration, target vclient.KeyManager, node *beacon.Client, index string) (bool, error) {
+	epoch, err := node.HeadEpoch(ctx)
+	if err != nil {
+		return false, err
+	}
+	imported := uint64(*m.ImportedEpoch)
+	if epoch <= imported+1 {
+		return false, nil
+	}
+
+	live, err := node.Liveness(ctx, epoch-1, []string{index})
+	if err != nil {
+		return false, err
+	}
+	if !live[index] {
+		if epoch > imported+migrationVerifyEpochs {
+			return false, s.fail(ctx, m, fmt.Sprintf("validator did not attest within %d epochs of the import", migrationVerifyEpochs))
+		}
+		return false, nil
+	}
+
+	if err := s.validators.UpdateClient(ctx, m.Pubkey, target.Type(), target.Name()); err != nil {
+		return false, err
+	}
+	m.Status = models.KeyMigrationStatusCompleted
+	return true, s.save(ctx, m, fmt.Sprintf("attested from %s in epoch %d", m.TargetInstance, epoch-1))
+}
+
+// fail stops a migration for an operator to look at, discarding its keystore.
+// It is only used while the key is still loaded on a client.
+func (s *KeyMigrationService) fail(ctx context.Context, m *models.KeyMigration, reason string) error {
+	m.Status = models.KeyMigrationStatusFailed
+	m.Error = reason
+	m.Keystore = nil
+	return s.save(ctx, m, "failed: "+reason)
+}
+
+// halt stops a migration after the key left the source client. The key is then
+// loaded on no client, so the sealed keystore is kept for an operator to import
+// it again once the cause is fixed. The migration holds the key until it is
+// released.
+func (s *KeyMigrationService) halt(ctx context.Context, m *models.KeyMigration, reason string) error {
+	m.Status = models.KeyMigrationStatusFailed
+	m.Error = reason + "; the key is not loaded on any client and its keystore is kept"
+	return s.save(ctx, m, "failed: "+m.Error)
+}
+
+// save stores the progress of a migration and records it in the audit log
+func (s *KeyMigrationService) save(ctx context.Context, m *models.KeyMigration, details string) error {
+	if err := s.migrations.Update(ctx, m); err != nil {
+		return err
+	}
+
+	details = fmt.Sprintf("Migration %d of %s %s", m.ID, m.Pubkey, details)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "key_migration." + m.Status, Details: details}); err != nil {
+		return fmt.Errorf("failed to record key migration progress: %w", err)
+	}
+	return nil
+}
+
+// Start advances migrations immediately and then on every interval until ctx is done
+func (s *KeyMigrationService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Advance(ctx); err != nil {
+			log.Printf("Key migration failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// chainStub is a beacon node whose head epoch and validator liveness tests can change
+type chainStub struct {
+	epoch uint64
+	live  map[string]bool
+}
+
+// newChainStub serves the head and liveness endpoints from chain
+func newChainStub(t *testing.T, chain *chainStub) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/eth/v1/config/spec":
+			w.Write([]byte(`{"data":{"SLOTS_PER_EPOCH":"32"}}`))
+		case r.URL.Path == "/eth/v1/beacon/headers/head":
+			slot := strconv.FormatUint(chain.epoch*32, 10)
+			w.Write([]byte(`{"data":{"header":{"message":{"slot":"` + slot + `"}}}}`))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/validator/liveness/"):
+			var indices []string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&indices))
+			var data []map[string]interface{}
+			for _, i := range indices {
+				data = append(data, map[string]interface{}{"index": i, "is_live": chain.live[i]})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// fakeKeyManager is a validator client that records imported keystores
+type fakeKeyManager struct {
+	name       string
+	protection []byte
+	deleteErr  error
+	imported   []string
+	importedSP []string
+	remote     []string
+}
+
+func (f *fakeKeyManager) Name() string { return f.name }
+func (f *fakeKeyManager) Type() string { return vclient.TypeTeku }
+
+func (f *fakeKeyManager) ListKeys(context.Context) ([]vclient.Key, error) { return nil, nil }
+
+func (f *fakeKeyManager) ImportKeystore(_ context.Context, keystore, _ string, slashingProtection []byte) error {
+	f.imported = append(f.imported, keystore)
+	f.importedSP = append(f.importedSP, string(slashingProtection))
+	return nil
+}
+
+func (f *fakeKeyManager) DeleteKeystore(context.Context, string) ([]byte, error) {
+	return f.protection, f.deleteErr
+}
+
+func (f *fakeKeyManager) ImportRemoteKey(_ context.Context, pubkey, signerURL string) error {
+	f.remote = append(f.remote, pubkey+"@"+signerURL)
+	return nil
+}
+
+func (f *fakeKeyManager) DeleteRemoteKey(context.Context, string) error { return f.deleteErr }
+
+func TestKeyMigrationService_Migrate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := &chainStub{epoch: 100, live: map[string]bool{}}
+	srv := newChainStub(t, chain)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockProtection := mocks.NewMockSlashingProtectionRepo(ctrl)
+	mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	source := &fakeKeyManager{name: "lh-1", protection: []byte(`{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` +
+		testInterchangeRoot + `"},"data":[{"pubkey":"` + testKey1 + `","signed_blocks":[{"slot":"3200"}],"signed_attestations":[]}]}`)}
+	target := &fakeKeyManager{name: "teku-1"}
+	doppelganger := NewDoppelgangerService(mockChecks, mockValidators, mocks.NewMockAlertRepo(ctrl), mockAudit, nodes, 3)
+	service := NewKeyMigrationService(mockMigrations, mockValidators, NewSlashingProtectionService(mockProtection, mockAudit),
+		doppelganger, mockAudit, cipher, nodes, []vclient.KeyManager{source, target}, 2)
+	ctx := context.Background()
+
+	index := int64(7)
+	v := &models.Validator{Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index, ClientInstance: "lh-1",
+		Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	mockValidators.EXPECT().GetByPubkey(gomock.Any(), testKey1).Return(v, nil).AnyTimes()
+
+	var m models.KeyMigration
+	mockMigrations.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, created *models.KeyMigration) error {
+		created.ID, created.Status = 1, models.KeyMigrationStatusPending
+		m = *created
+		return nil
+	})
+	keystore := `{"pubkey":"` + testKey1[2:] + `","crypto":{}}`
+	created, err := service.Create(ctx, KeyMigrationRequest{
+		Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", RequestedBy: "alice",
+		Keystore: json.RawMessage(keystore), Password: "pw",
+	}, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), created.WaitEpochs)
+	assert.NotContains(t, string(created.Keystore), "pw")
+
+	mockMigrations.EXPECT().ListUnfinished(ctx).DoAndReturn(func(context.Context) ([]models.KeyMigration, error) {
+		return []models.KeyMigration{m}, nil
+	}).AnyTimes()
+	mockMigrations.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, updated *models.KeyMigration) error {
+		m = *updated
+		return nil
+	}).AnyTimes()
+
+	var check *models.DoppelgangerCheck
+	mockChecks.EXPECT().Latest(ctx, testKey1).DoAndReturn(func(context.Context, string) (*models.DoppelgangerCheck, error) {
+		if check == nil {
+			return nil, sql.ErrNoRows
+		}
+		c := *check
+		return &c, nil
+	}).AnyTimes()
+	mockChecks.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.DoppelgangerCheck) error {
+		c.ID, c.Status = 1, models.DoppelgangerStatusRunning
+		check = c
+		return nil
+	})
+	mockChecks.EXPECT().ListRunning(ctx).DoAndReturn(func(context.Context) ([]models.DoppelgangerCheck, error) {
+		return []models.DoppelgangerCheck{*check}, nil
+	}).AnyTimes()
+	mockChecks.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *models.DoppelgangerCheck) error {
+		check = c
+		return nil
+	}).AnyTimes()
+
+	// The key is deleted, its slashing protection stored and a doppelganger check started
+	mockProtection.EXPECT().Merge(ctx, gomock.Any()).Return(nil)
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, models.KeyMigrationStatusSourceDeleted, m.Status)
+	assert.Equal(t, int64(100), *m.DeletedEpoch)
+	assert.Equal(t, int64(2), check.Epochs)
+	assert.Empty(t, target.imported)
+
+	// Nothing is imported while the check runs
+	chain.epoch = 102
+	require.NoError(t, doppelganger.Advance(ctx))
+	require.NoError(t, service.Advance(ctx))
+	assert.Empty(t, target.imported)
+
+	// Once the check passes the key is imported with the merged slashing protection
+	chain.epoch = 103
+	require.NoError(t, doppelganger.Advance(ctx))
+	assert.Equal(t, models.DoppelgangerStatusPassed, check.Status)
+	blockSlot := int64(3200)
+	mockProtection.EXPECT().List(ctx, []string{testKey1}).Return([]models.SlashingProtection{
+		{Pubkey: testKey1, GenesisValidatorsRoot: testInterchangeRoot, BlockSlot: &blockSlot},
+	}, nil)
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, models.KeyMigrationStatusTargetImported, m.Status)
+	assert.Equal(t, []string{keystore}, target.imported)
+	assert.Contains(t, target.importedSP[0], `"slot":"3200"`)
+	assert.Nil(t, m.Keystore)
+
+	// Attestations from the target complete it
+	chain.epoch = 105
+	chain.live["7"] = true
+	mockValidators.EXPECT().UpdateClient(ctx, testKey1, vclient.TypeTeku, "teku-1").Return(nil)
+	require.NoError(t, service.Advance(ctx))
+	assert.Equal(t, models.KeyMigrationStatusCompleted, m.Status)
+}
+
+func TestKeyMigrationService_FailsOnDoppelganger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := newChainStub(t, &chainStub{epoch: 103})
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockChecks := mocks.NewMockDoppelgangerCheckRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	target := &fakeKeyManager{name: "teku-1"}
+	doppelganger := NewDoppelgangerService(mockChecks, mockValidators, nil, mockAudit, nodes, 3)
+	service := NewKeyMigrationService(mockMigrations, mockValidators, nil, doppelganger, mockAudit, nil, nodes,
+		[]vclient.KeyManager{&fakeKeyManager{name: "lh-1"}, target}, 2)
+	ctx := context.Background()
+
+	index := int64(7)
+	deleted := int64(100)
+	detected := int64(101)
+	started := time.Now()
+	mockValidators.EXPECT().GetByPubkey(ctx, testKey1).Return(&models.Validator{
+		Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+	}, nil).Times(2)
+	mockMigrations.EXPECT().ListUnfinished(ctx).Return([]models.KeyMigration{{
+		ID: 1, Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", WaitEpochs: 2,
+		Status: models.KeyMigrationStatusSourceDeleted, DeletedEpoch: &deleted, Keystore: []byte("sealed"), CreatedAt: started,
+	}}, nil)
+	mockChecks.EXPECT().Latest(ctx, testKey1).Return(&models.DoppelgangerCheck{
+		ID: 3, Pubkey: testKey1, Status: models.DoppelgangerStatusFailed, DetectedEpoch: &detected, CreatedAt: started.Add(time.Minute),
+	}, nil).Times(2)
+
+	// The key is still signing somewhere, so it is not imported. It already left
+	// the source, so the keystore is kept.
+	mockMigrations.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *models.KeyMigration) error {
+		assert.Equal(t, models.KeyMigrationStatusFailed, m.Status)
+		assert.Contains(t, m.Error, "doppelganger check 3 saw the validator live in epoch 101")
+		assert.Equal(t, []byte("sealed"), m.Keystore)
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, service.Advance(ctx))
+	assert.Empty(t, target.imported)
+}
+
+func TestKeyMigrationService_FailsBeforeDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := newChainStub(t, &chainStub{epoch: 100})
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	mockMigrations := mocks.NewMockKeyMigrationRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	source := &fakeKeyManager{name: "lh-1", deleteErr: vclient.ErrKeyNotFound}
+	service := NewKeyMigrationService(mockMigrations, mockValidators, nil, nil, mockAudit, nil, nodes,
+		[]vclient.KeyManager{source, &fakeKeyManager{name: "teku-1"}}, 2)
+	ctx := context.Background()
+
+	index := int64(7)
+	mockValidators.EXPECT().GetByPubkey(ctx, testKey1).Return(&models.Validator{
+		Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+	}, nil)
+	mockMigrations.EXPECT().ListUnfinished(ctx).Return([]models.KeyMigration{{
+		ID: 1, Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", WaitEpochs: 2,
+		Status: models.KeyMigrationStatusPending, Keystore: []byte("sealed"),
+	}}, nil)
+
+	// The source never deleted the key, so the keystore is discarded
+	mockMigrations.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *models.KeyMigration) error {
+		assert.Equal(t, models.KeyMigrationStatusFailed, m.Status)
+		assert.Nil(t, m.Keystore)
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, service.Advance(ctx))
+}
+
+func TestKeyMigrationService_CreateRejects(t *testing.T) {
+	index := int64(7)
+	active := &models.Validator{Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index,
+		Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	keystore := json.RawMessage(`{"pubkey":"` + testKey1[2:] + `"}`)
+
+	tests := []struct {
+		name          string
+		req           KeyMigrationRequest
+		validator     *models.Validator
+		expectedError string
+	}{
+		{
+			name:          "unknown instance",
+			req:           KeyMigrationRequest{Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "prysm-1", RequestedBy: "alice"},
+			expectedError: `invalid key migration: validator client instance "prysm-1" is not configured`,
+		},
+		{
+			name:          "same instance",
+			req:           KeyMigrationRequest{Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "lh-1", RequestedBy: "alice"},
+			expectedError: "invalid key migration: source and target instance must differ",
+		},
+		{
+			name: "not active",
+			req:  KeyMigrationRequest{Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", RequestedBy: "alice", Keystore: keystore},
+			validator: &models.Validator{Pubkey: testKey1, Status: models.StatusExited, ValidatorIndex: &index,
+				Blockchain: "ethereum", BlockchainNetwork: "mainnet"},
+			expectedError: "invalid key migration: validator " + testKey1 + " is exited, not active",
+		},
+		{
+			name: "loaded elsewhere",
+			req:  KeyMigrationRequest{Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", RequestedBy: "alice", Keystore: keystore},
+			validator: &models.Validator{Pubkey: testKey1, Status: models.StatusActive, ValidatorIndex: &index, ClientInstance: "teku-1",
+				Blockchain: "ethereum", BlockchainNetwork: "mainnet"},
+			expectedError: "invalid key migration: validator " + testKey1 + " is loaded on teku-1, not lh-1",
+		},
+		{
+			name: "keystore of another key",
+			req: KeyMigrationRequest{Pubkey: testKey1, SourceInstance: "lh-1", TargetInstance: "teku-1", RequestedBy: "alice",
+				Keystore: json.RawMessage(`{"pubkey":"` + testKey2[2:] + `"}`)},
+			validator:     active,
+			expectedError: "invalid key migration: keystore is not for validator " + testKey1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			if tt.validator != nil {
+				mockValidators.EXPECT().GetByPubkey(gomock.Any(), testKey1).Return(tt.validator, nil)
+			}
+			nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: "http://localhost"}}})
+			service := NewKeyMigrationService(mocks.NewMockKeyMigrationRepo(ctrl), mockValidators, nil, nil, mocks.NewMockAuditRepo(ctrl), nil, nodes,
+				[]vclient.KeyManager{&fakeKeyManager{name: "lh-1"}, &fakeKeyManager{name: "teku-1"}}, 2)
+
+			_, err := service.Create(context.Background(), tt.req, "")
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/keystore"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// ErrInvalidKeystore is returned for a keystore that cannot be stored
+var ErrInvalidKeystore = errors.New("invalid keystore upload")
+
+// KeystoreRequest uploads an EIP-2335 keystore and its password
+type KeystoreRequest struct {
+	Keystore json.RawMessage `json:"keystore"`
+	Password string          `json:"password"`
+	// VerifyPassword decrypts the keystore before storing it, which takes a
+	// second or more for the usual scrypt parameters
+	VerifyPassword bool `json:"verify_password"`
+}
+
+// KeystoreService holds encrypted EIP-2335 keystores of managed validators so
+// they can be loaded on validator clients. Keystores and passwords are sealed
+// with a data key per keystore, which is sealed with the vault key; neither
+// ever leaves the service in plaintext except towards a validator client.
+type KeystoreService struct {
+	keystores  models.KeystoreRepo
+	validators models.ValidatorRepo
+	audit      models.AuditRepo
+	cipher     *vault.Cipher
+}
+
+// NewKeystoreService creates a new keystore service
+func NewKeystoreService(keystores models.KeystoreRepo, validators models.ValidatorRepo, audit models.AuditRepo, cipher *vault.Cipher) *KeystoreService {
+	return &KeystoreService{keystores: keystores, validators: validators, audit: audit, cipher: cipher}
+}
+
+// Store validates a keystore against its validator and stores it with its
+// password, replacing a keystore stored earlier for the same key
+func (s *KeystoreService) Store(ctx context.Context, req KeystoreRequest, sourceIP string) (*models.StoredKeystore, error) {
+	k, err := keystore.Parse(req.Keystore)
+	if err != nil {
+		return nil, err
+	}
+	if req.Password == "" {
+		return nil, fmt.Errorf("%w: password is required", ErrInvalidKeystore)
+	}
+	pubkey := vclient.NormalizePubkey(k.Pubkey)
+
+	_, err = s.validators.GetByPubkey(ctx, pubkey)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: validator %s is not managed", ErrInvalidKeystore, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VerifyPassword {
+		secret, err := k.Decrypt(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		clear(secret)
+	}
+
+	dataKey, sealedKey, err := s.cipher.NewDataKey([]byte(pubkey))
+	if err != nil {
+		return nil, err
+	}
+	sealedKeystore, err := dataKey.Seal(req.Keystore, []byte(pubkey))
+	if err != nil {
+		return nil, err
+	}
+	sealedPassword, err := dataKey.Seal([]byte(req.Password), []byte(pubkey))
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &models.StoredKeystore{
+		Pubkey:           pubkey,
+		Path:             k.Path,
+		UUID:             k.UUID,
+		KDF:              k.Crypto.KDF.Function,
+		PasswordVerified: req.VerifyPassword,
+		DataKey:          sealedKey,
+		Keystore:         sealedKeystore,
+		Password:         sealedPassword,
+	}
+	if err := s.keystores.Save(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Keystore %s of %s stored, password verified: %t", stored.UUID, pubkey, stored.PasswordVerified)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "keystore.stored", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record keystore: %w", err)
+	}
+	return stored, nil
+}
+
+// Get returns the stored keystore of a validator, still encrypted
+func (s *KeystoreService) Get(ctx context.Context, pubkey string) (*models.StoredKeystore, error) {
+	return s.keystores.Get(ctx, vclient.NormalizePubkey(pubkey))
+}
+
+// List returns all stored keystores, still encrypted
+func (s *KeystoreService) List(ctx context.Context) ([]models.StoredKeystore, error) {
+	return s.keystores.List(ctx)
+}
+
+// Delete removes the stored keystore of a validator
+func (s *KeystoreService) Delete(ctx context.Context, pubkey, sourceIP string) error {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	if err := s.keystores.Delete(ctx, pubkey); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Keystore of %s deleted", pubkey)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "keystore.deleted", SourceIP: sourceIP, Details: details}); err != nil {
+		return fmt.Errorf("failed to record keystore deletion: %w", err)
+	}
+	return nil
+}
+
+// Open decrypts the stored keystore and password of a validator for loading on
+// a validator client. They must never be returned over the API.
+func (s *KeystoreService) Open(ctx context.Context, pubkey string) (string, string, error) {
+	stored, err := s.Get(ctx, pubkey)
+	if err != nil {
+		return "", "", err
+	}
+	dataKey, err := s.cipher.OpenDataKey(stored.DataKey, []byte(stored.Pubkey))
+	if err != nil {
+		return "", "", err
+	}
+	ks, err := dataKey.Open(stored.Keystore, []byte(stored.Pubkey))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open keystore: %w", err)
+	}
+	password, err := dataKey.Open(stored.Password, []byte(stored.Pubkey))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open keystore password: %w", err)
+	}
+	return string(ks), string(password), nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/keystore"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+// The PBKDF2 keystore of the EIP-2335 test vectors and its password
+const (
+	testKeystorePubkey   = "0x9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+	testKeystorePassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+	testKeystore         = `{"crypto":{` +
+		`"kdf":{"function":"pbkdf2","params":{"dklen":32,"c":262144,"prf":"hmac-sha256","salt":"d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},"message":""},` +
+		`"checksum":{"function":"sha256","params":{},"message":"8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"},` +
+		`"cipher":{"function":"aes-128-ctr","params":{"iv":"264daa3f303d7259501c93d997d84fe6"},"message":"cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"}},` +
+		`"pubkey":"9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",` +
+		`"path":"m/12381/60/0/0","uuid":"64625def-3331-4eea-ab6f-782f3ed16a83","version":4}`
+)
+
+func TestKeystoreService_StoreOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+	mockKeystores := mocks.NewMockKeystoreRepo(ctrl)
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewKeystoreService(mockKeystores, mockValidators, mockAudit, cipher)
+	ctx := context.Background()
+
+	var stored models.StoredKeystore
+	mockValidators.EXPECT().GetByPubkey(ctx, testKeystorePubkey).Return(&models.Validator{Pubkey: testKeystorePubkey}, nil)
+	mockKeystores.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, k *models.StoredKeystore) error {
+		stored = *k
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	k, err := service.Store(ctx, KeystoreRequest{Keystore: []byte(testKeystore), Password: testKeystorePassword, VerifyPassword: true}, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, testKeystorePubkey, k.Pubkey)
+	assert.Equal(t, "pbkdf2", k.KDF)
+	assert.True(t, k.PasswordVerified)
+	assert.NotContains(t, string(stored.Keystore), "cee03fde")
+	assert.NotContains(t, string(stored.Password), testKeystorePassword)
+
+	mockKeystores.EXPECT().Get(ctx, testKeystorePubkey).Return(&stored, nil)
+	ks, password, err := service.Open(ctx, testKeystorePubkey)
+	require.NoError(t, err)
+	assert.Equal(t, testKeystore, ks)
+	assert.Equal(t, testKeystorePassword, password)
+}
+
+func TestKeystoreService_StoreRejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           KeystoreRequest
+		mockSetup     func(*mocks.MockValidatorRepo)
+		expectedError error
+	}{
+		{
+			name:          "not a keystore",
+			req:           KeystoreRequest{Keystore: []byte(`{"version":3}`), Password: "pw"},
+			mockSetup:     func(*mocks.MockValidatorRepo) {},
+			expectedError: keystore.ErrInvalid,
+		},
+		{
+			name:          "missing password",
+			req:           KeystoreRequest{Keystore: []byte(testKeystore)},
+			mockSetup:     func(*mocks.MockValidatorRepo) {},
+			expectedError: ErrInvalidKeystore,
+		},
+		{
+			name: "unmanaged validator",
+			req:  KeystoreRequest{Keystore: []byte(testKeystore), Password: "pw"},
+			mockSetup: func(v *mocks.MockValidatorRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), testKeystorePubkey).Return(nil, sql.ErrNoRows)
+			},
+			expectedError: ErrInvalidKeystore,
+		},
+		{
+			name: "wrong password",
+			req:  KeystoreRequest{Keystore: []byte(testKeystore), Password: "testpassword", VerifyPassword: true},
+			mockSetup: func(v *mocks.MockValidatorRepo) {
+				v.EXPECT().GetByPubkey(gomock.Any(), testKeystorePubkey).Return(&models.Validator{Pubkey: testKeystorePubkey}, nil)
+			},
+			expectedError: keystore.ErrWrongPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			tt.mockSetup(mockValidators)
+			service := NewKeystoreService(mocks.NewMockKeystoreRepo(ctrl), mockValidators, mocks.NewMockAuditRepo(ctrl), nil)
+
+			_, err := service.Store(context.Background(), tt.req, "")
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
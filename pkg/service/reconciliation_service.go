@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// reconcileBatchSize is the number of pubkeys requested per beacon node call
+const reconcileBatchSize = 50
+
+// lidoKeySource lists the keys our Lido node operators uploaded
+type lidoKeySource interface {
+	Networks() []string
+	Keys(ctx context.Context, network string) (map[string]models.LidoStatus, error)
+}
+
+// ReconciliationEntry is a key that one source reports differently from the others
+type ReconciliationEntry struct {
+	Pubkey  string `json:"pubkey"`
+	Network string `json:"network,omitempty"`
+	// Status is the stored status, or the beacon chain status for keys active on chain
+	Status string `json:"status,omitempty"`
+	// Instances are the validator client instances that have the key loaded
+	Instances []string           `json:"instances,omitempty"`
+	Lido      *models.LidoStatus `json:"lido,omitempty"`
+}
+
+// ReconciliationReport lists the discrepancies between the database, the
+// validator clients, the beacon chain and the Lido registries
+type ReconciliationReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// NotLoaded are stored keys expected to sign that no client has loaded
+	NotLoaded []ReconciliationEntry `json:"not_loaded"`
+	// UnknownLoaded are keys loaded on a client that are not stored
+	UnknownLoaded []ReconciliationEntry `json:"unknown_loaded"`
+	// ActiveWithoutClient are stored keys active on chain that no client has loaded
+	ActiveWithoutClient []ReconciliationEntry `json:"active_without_client"`
+	// LidoUnknown are keys uploaded by our Lido node operators that are not stored
+	LidoUnknown []ReconciliationEntry `json:"lido_unknown"`
+	// Errors are the sources that could not be read. Categories that depend on
+	// a failed source are left empty rather than reported wrongly.
+	Errors []string `json:"errors"`
+}
+
+// Discrepancies returns the number of keys in all categories
+func (r *ReconciliationReport) Discrepancies() int {
+	return len(r.NotLoaded) + len(r.UnknownLoaded) + len(r.ActiveWithoutClient) + len(r.LidoUnknown)
+}
+
+// ReconciliationService compares the keys known to every source and reports where they disagree
+type ReconciliationService struct {
+	validators models.ValidatorRepo
+	clients    []vclient.ValidatorClient
+	nodes      beacon.Nodes
+	lido       lidoKeySource
+}
+
+// NewReconciliationService creates a new reconciliation service. lido may be
+// nil when no Lido network is configured.
+func NewReconciliationService(validators models.ValidatorRepo, clients []vclient.ValidatorClient, nodes beacon.Nodes,
+	lido lidoKeySource) *ReconciliationService {
+	return &ReconciliationService{validators: validators, clients: clients, nodes: nodes, lido: lido}
+}
+
+// Reconcile reads every source and builds the reconciliation report. Sources
+// that fail are listed in the report; only a failure to read the stored
+// validators is returned as an error.
+func (s *ReconciliationService) Reconcile(ctx context.Context) (*ReconciliationReport, error) {
+	validators, err := s.validators.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(validators))
+	for _, v := range validators {
+		known[v.Pubkey] = true
+	}
+
+	report := &ReconciliationReport{
+		GeneratedAt:         time.Now().UTC(),
+		NotLoaded:           []ReconciliationEntry{},
+		UnknownLoaded:       []ReconciliationEntry{},
+		ActiveWithoutClient: []ReconciliationEntry{},
+		LidoUnknown:         []ReconciliationEntry{},
+		Errors:              []string{},
+	}
+
+	loaded, clientsComplete := s.loadedKeys(ctx, report)
+	for _, pubkey := range sortedKeys(loaded) {
+		if !known[pubkey] {
+			report.UnknownLoaded = append(report.UnknownLoaded, ReconciliationEntry{Pubkey: pubkey, Instances: loaded[pubkey]})
+		}
+	}
+
+	// A client that could not be read may have any key loaded
+	if clientsComplete {
+		for _, v := range validators {
+			if expectedLoaded(v.Status) && len(loaded[v.Pubkey]) == 0 {
+				report.NotLoaded = append(report.NotLoaded, ReconciliationEntry{
+					Pubkey: v.Pubkey, Network: v.BlockchainNetwork, Status: string(v.Status),
+				})
+			}
+		}
+		s.activeWithoutClient(ctx, validators, loaded, report)
+	}
+
+	s.lidoUnknown(ctx, known, report)
+	return report, nil
+}
+
+// loadedKeys returns the instances each key is loaded on and whether every client answered
+func (s *ReconciliationService) loadedKeys(ctx context.Context, report *ReconciliationReport) (map[string][]string, bool) {
+	loaded := make(map[string][]string)
+	complete := true
+	for _, c := range s.clients {
+		keys, err := c.ListKeys(ctx)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			complete = false
+			continue
+		}
+		for _, k := range keys {
+			loaded[k.Pubkey] = append(loaded[k.Pubkey], c.Name())
+		}
+	}
+	return loaded, complete
+}
+
+// activeWithoutClient reports the stored keys the beacon chain considers active
+// that no client has loaded. Networks without a configured beacon node are skipped.
+func (s *ReconciliationService) activeWithoutClient(ctx context.Context, validators []models.Validator, loaded map[string][]string,
+	report *ReconciliationReport) {
+	byNetwork := make(map[string][]models.Validator)
+	var networks []string
+	for _, v := range validators {
+		if len(loaded[v.Pubkey]) > 0 {
+			continue
+		}
+		key := v.Blockchain + "/" + v.BlockchainNetwork
+		if _, ok := byNetwork[key]; !ok {
+			networks = append(networks, key)
+		}
+		byNetwork[key] = append(byNetwork[key], v)
+	}
+
+	for _, key := range networks {
+		batch := byNetwork[key]
+		node, ok := s.nodes.Get(batch[0].Blockchain, batch[0].BlockchainNetwork)
+		if !ok {
+			continue
+		}
+		if err := s.checkActive(ctx, node, batch, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("beacon %s: %v", key, err))
+		}
+	}
+}
+
+func (s *ReconciliationService) checkActive(ctx context.Context, node *beacon.Client, validators []models.Validator,
+	report *ReconciliationReport) error {
+	for start := 0; start < len(validators); start += reconcileBatchSize {
+		batch := validators[start:min(start+reconcileBatchSize, len(validators))]
+		pubkeys := make([]string, len(batch))
+		for i, v := range batch {
+			pubkeys[i] = v.Pubkey
+		}
+		states, err := node.Validators(ctx, pubkeys)
+		if err != nil {
+			return err
+		}
+
+		active := make(map[string]string, len(states))
+		for _, st := range states {
+			if strings.HasPrefix(st.Status, "active_") {
+				active[vclient.NormalizePubkey(st.Data.Pubkey)] = st.Status
+			}
+		}
+		for _, v := range batch {
+			if status, ok := active[v.Pubkey]; ok {
+				report.ActiveWithoutClient = append(report.ActiveWithoutClient, ReconciliationEntry{
+					Pubkey: v.Pubkey, Network: v.BlockchainNetwork, Status: status,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// lidoUnknown reports the keys our Lido node operators uploaded that are not stored
+func (s *ReconciliationService) lidoUnknown(ctx context.Context, known map[string]bool, report *ReconciliationReport) {
+	if s.lido == nil {
+		return
+	}
+	for _, network := range s.lido.Networks() {
+		keys, err := s.lido.Keys(ctx, network)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("lido %s: %v", network, err))
+			continue
+		}
+		for _, pubkey := range sortedKeys(keys) {
+			if known[pubkey] {
+				continue
+			}
+			status := keys[pubkey]
+			report.LidoUnknown = append(report.LidoUnknown, ReconciliationEntry{Pubkey: pubkey, Network: network, Lido: &status})
+		}
+	}
+}
+
+// expectedLoaded reports whether a validator in status should be loaded on a client.
+// Unused keys are not deposited yet and exited keys no longer sign.
+func expectedLoaded(status models.Status) bool {
+	return status == models.StatusPending || status == models.StatusActive || status == models.StatusExiting
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// fakeLister is a validator client reporting a fixed key set
+type fakeLister struct {
+	name string
+	keys []vclient.Key
+	err  error
+}
+
+func (f *fakeLister) Name() string { return f.name }
+func (f *fakeLister) Type() string { return vclient.TypeLighthouse }
+
+func (f *fakeLister) ListKeys(context.Context) ([]vclient.Key, error) { return f.keys, f.err }
+
+// fakeLido is a Lido registry with one network
+type fakeLido struct {
+	keys map[string]models.LidoStatus
+	err  error
+}
+
+func (f *fakeLido) Networks() []string { return []string{"mainnet"} }
+
+func (f *fakeLido) Keys(context.Context, string) (map[string]models.LidoStatus, error) {
+	return f.keys, f.err
+}
+
+func TestReconciliationService_Reconcile(t *testing.T) {
+	testKey3 := "0x" + strings.Repeat("c3", 48)
+	testKey4 := "0x" + strings.Repeat("d4", 48)
+
+	// The beacon node considers testKey2 active and testKey3 pending
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/beacon/states/head/validators", r.URL.Path)
+		w.Write([]byte(`{"data":[` +
+			`{"index":"2","status":"active_ongoing","validator":{"pubkey":"` + testKey2 + `"}},` +
+			`{"index":"3","status":"pending_queued","validator":{"pubkey":"` + testKey3 + `"}}]}`))
+	}))
+	defer srv.Close()
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+
+	validators := []models.Validator{
+		{Pubkey: testKey1, Blockchain: "ethereum", BlockchainNetwork: "mainnet", Status: models.StatusActive},
+		{Pubkey: testKey2, Blockchain: "ethereum", BlockchainNetwork: "mainnet", Status: models.StatusExited},
+		{Pubkey: testKey3, Blockchain: "ethereum", BlockchainNetwork: "mainnet", Status: models.StatusPending},
+	}
+	operator := int64(7)
+
+	tests := []struct {
+		name             string
+		clients          []vclient.ValidatorClient
+		lido             *fakeLido
+		notLoaded        []string
+		unknownLoaded    []string
+		activeNoClient   []string
+		lidoUnknown      []string
+		expectedErrors   int
+		expectedProblems int
+	}{
+		{
+			name: "reports every category",
+			clients: []vclient.ValidatorClient{
+				&fakeLister{name: "lh-1", keys: []vclient.Key{{Pubkey: testKey1}, {Pubkey: testKey4}}},
+			},
+			lido: &fakeLido{keys: map[string]models.LidoStatus{
+				testKey1: {Uploaded: true, OperatorID: &operator},
+				testKey4: {Uploaded: true, OperatorID: &operator},
+			}},
+			notLoaded:        []string{testKey3},
+			unknownLoaded:    []string{testKey4},
+			activeNoClient:   []string{testKey2},
+			lidoUnknown:      []string{testKey4},
+			expectedProblems: 4,
+		},
+		{
+			name: "failing client suppresses the categories that need every client",
+			clients: []vclient.ValidatorClient{
+				&fakeLister{name: "lh-1", keys: []vclient.Key{{Pubkey: testKey4}}},
+				&fakeLister{name: "teku-1", err: errors.New("connection refused")},
+			},
+			lido:             &fakeLido{err: errors.New("rpc unavailable")},
+			unknownLoaded:    []string{testKey4},
+			expectedErrors:   2,
+			expectedProblems: 1,
+		},
+	}
+
+	pubkeys := func(entries []ReconciliationEntry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.Pubkey)
+		}
+		return result
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			mockValidators.EXPECT().List(gomock.Any(), gomock.Any()).Return(validators, nil)
+			service := NewReconciliationService(mockValidators, tt.clients, nodes, tt.lido)
+
+			report, err := service.Reconcile(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.notLoaded, pubkeys(report.NotLoaded))
+			assert.Equal(t, tt.unknownLoaded, pubkeys(report.UnknownLoaded))
+			assert.Equal(t, tt.activeNoClient, pubkeys(report.ActiveWithoutClient))
+			assert.Equal(t, tt.lidoUnknown, pubkeys(report.LidoUnknown))
+			assert.Len(t, report.Errors, tt.expectedErrors)
+			assert.Equal(t, tt.expectedProblems, report.Discrepancies())
+		})
+	}
+}
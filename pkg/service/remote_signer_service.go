@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+	"github.com/zheli/validator-key-manager-backend/pkg/web3signer"
+)
+
+// SignerHealth is the reachability and health report of a remote signer
+type SignerHealth struct {
+	Name   string             `json:"name"`
+	URL    string             `json:"url"`
+	Up     bool               `json:"up"`
+	Status string             `json:"status,omitempty"`
+	Checks []web3signer.Check `json:"checks,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// RemoteSignerService tracks which Web3Signer instance holds each key and
+// registers remote keys on validator clients, so a key can be located on both
+// its signer and the clients that sign through it
+type RemoteSignerService struct {
+	validators    models.ValidatorRepo
+	migrations    models.KeyMigrationRepo
+	signerKeys    models.SignerKeyRepo
+	clientKeys    models.ClientKeyRepo
+	doppelganger  *DoppelgangerService
+	audit         models.AuditRepo
+	signers       []*web3signer.Client
+	clients       map[string]vclient.KeyManager
+	completeHooks []func(ctx context.Context, err error)
+}
+
+// NewRemoteSignerService creates a new remote signer service for the given signers and validator clients
+func NewRemoteSignerService(validators models.ValidatorRepo, migrations models.KeyMigrationRepo, signerKeys models.SignerKeyRepo,
+	clientKeys models.ClientKeyRepo, doppelganger *DoppelgangerService, audit models.AuditRepo, signers []*web3signer.Client,
+	clients []vclient.KeyManager) *RemoteSignerService {
+	byName := make(map[string]vclient.KeyManager, len(clients))
+	for _, c := range clients {
+		byName[c.Name()] = c
+	}
+	return &RemoteSignerService{
+		validators:   validators,
+		migrations:   migrations,
+		signerKeys:   signerKeys,
+		clientKeys:   clientKeys,
+		doppelganger: doppelganger,
+		audit:        audit,
+		signers:      signers,
+		clients:      byName,
+	}
+}
+
+// Sync stores the public keys loaded on every signer. A signer that cannot be
+// reached does not stop the others; its previously stored keys are kept.
+func (s *RemoteSignerService) Sync(ctx context.Context) error {
+	var errs []error
+	for _, signer := range s.signers {
+		pubkeys, err := signer.PublicKeys(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		keys := make([]models.SignerKey, 0, len(pubkeys))
+		for _, pubkey := range pubkeys {
+			keys = append(keys, models.SignerKey{Pubkey: pubkey, Signer: signer.Name(), SignerURL: signer.URL()})
+		}
+		if err := s.signerKeys.ReplaceSignerKeys(ctx, signer.Name(), keys); err != nil {
+			errs = append(errs, fmt.Errorf("signer %q: %w", signer.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnComplete registers a function called after every sync started by Start, with
+// the error of the sync or nil if it succeeded
+func (s *RemoteSignerService) OnComplete(f func(ctx context.Context, err error)) {
+	s.completeHooks = append(s.completeHooks, f)
+}
+
+// Start syncs the signer keys immediately and then on every interval until ctx is done
+func (s *RemoteSignerService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Sync(ctx)
+		if err != nil {
+			log.Printf("Remote signer sync failed: %v", err)
+		}
+		for _, f := range s.completeHooks {
+			f(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Health checks every signer's upcheck and health endpoints
+func (s *RemoteSignerService) Health(ctx context.Context) []SignerHealth {
+	result := make([]SignerHealth, 0, len(s.signers))
+	for _, signer := range s.signers {
+		h := SignerHealth{Name: signer.Name(), URL: signer.URL()}
+		if err := signer.Upcheck(ctx); err != nil {
+			h.Error = err.Error()
+			result = append(result, h)
+			continue
+		}
+		h.Up = true
+		report, err := signer.Health(ctx)
+		if err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Status, h.Checks = report.Status, report.Checks
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+// Locate returns the signers holding a key, as of their last sync, and the
+// validator client instances that sign for it through a remote signer
+func (s *RemoteSignerService) Locate(ctx context.Context, pubkey string) (*models.KeyLocation, error) {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	signers, err := s.signerKeys.ListByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	clientKeys, err := s.clientKeys.ListByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := &models.KeyLocation{Pubkey: pubkey, Signers: signers, Clients: []models.ClientKey{}}
+	if loc.Signers == nil {
+		loc.Signers = []models.SignerKey{}
+	}
+	for _, k := range clientKeys {
+		if k.Remote {
+			loc.Clients = append(loc.Clients, k)
+		}
+	}
+	return loc, nil
+}
+
+// Register makes a validator client instance sign for a key through the named
+// signer. The signer must have the key loaded, and the validator must not be
+// loaded anywhere or held by a key migration and must pass the doppelganger
+// requirements. Registering a key
+// the client already has succeeds, so a failed registration can be retried.
+func (s *RemoteSignerService) Register(ctx context.Context, pubkey, signerName, instance, sourceIP string) (*models.Validator, error) {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	client, ok := s.clients[instance]
+	if !ok {
+		return nil, fmt.Errorf("%w: validator client instance %q is not configured", ErrInvalidDeployment, instance)
+	}
+	signer := s.signer(signerName)
+	if signer == nil {
+		return nil, fmt.Errorf("%w: signer %q is not configured", ErrInvalidDeployment, signerName)
+	}
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if v.ClientInstance != "" {
+		return nil, fmt.Errorf("%w: %s is loaded on %s", ErrAlreadyDeployed, pubkey, v.ClientInstance)
+	}
+	if err := checkNotMigrating(ctx, s.migrations, pubkey); err != nil {
+		return nil, err
+	}
+	if err := s.doppelganger.Deployable(ctx, pubkey); err != nil {
+		return nil, err
+	}
+
+	// Check the signer itself rather than the last sync, so a key removed since is never registered
+	pubkeys, err := signer.PublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(pubkeys, pubkey) {
+		return nil, fmt.Errorf("%w: signer %q does not hold %s", ErrInvalidDeployment, signerName, pubkey)
+	}
+	if err := client.ImportRemoteKey(ctx, pubkey, signer.URL()); err != nil {
+		return nil, err
+	}
+
+	if err := s.validators.UpdateClient(ctx, pubkey, client.Type(), client.Name()); err != nil {
+		return nil, err
+	}
+	v.Client, v.ClientInstance = client.Type(), client.Name()
+
+	details := fmt.Sprintf("Validator %s registered on %s through signer %s", pubkey, instance, signerName)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "validator.remote_key_registered", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record remote key registration: %w", err)
+	}
+	return v, nil
+}
+
+// Unregister removes the remote key of a validator from a validator client
+// instance, or from the instance it is loaded on if instance is empty. Removing
+// a key the client no longer has succeeds, so a failed removal can be retried.
+func (s *RemoteSignerService) Unregister(ctx context.Context, pubkey, instance, sourceIP string) (*models.Validator, error) {
+	pubkey = vclient.NormalizePubkey(pubkey)
+	v, err := s.validators.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if instance == "" {
+		instance = v.ClientInstance
+	}
+	if instance == "" {
+		return nil, fmt.Errorf("%w: %s is not deployed", ErrInvalidDeployment, pubkey)
+	}
+	client, ok := s.clients[instance]
+	if !ok {
+		return nil, fmt.Errorf("%w: validator client instance %q is not configured", ErrInvalidDeployment, instance)
+	}
+
+	outcome := "deleted"
+	if err := client.DeleteRemoteKey(ctx, pubkey); errors.Is(err, vclient.ErrKeyNotFound) {
+		outcome = "not loaded"
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Removing a stray copy from another client leaves the recorded client alone
+	if v.ClientInstance == instance {
+		if err := s.validators.UpdateClient(ctx, pubkey, "", ""); err != nil {
+			return nil, err
+		}
+		v.Client, v.ClientInstance = "", ""
+	}
+
+	details := fmt.Sprintf("Validator %s unregistered from %s: %s", pubkey, instance, outcome)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "validator.remote_key_unregistered", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record remote key removal: %w", err)
+	}
+	return v, nil
+}
+
+func (s *RemoteSignerService) signer(name string) *web3signer.Client {
+	for _, signer := range s.signers {
+		if signer.Name() == name {
+			return signer
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+	"github.com/zheli/validator-key-manager-backend/pkg/web3signer"
+)
+
+// newSignerStub serves the Web3Signer endpoints with the given loaded keys, or
+// reports the signer down if up is false
+func newSignerStub(t *testing.T, name string, up bool, pubkeys ...string) *web3signer.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/eth2/publicKeys":
+			json.NewEncoder(w).Encode(append([]string{}, pubkeys...))
+		case "/upcheck":
+			w.Write([]byte("OK"))
+		case "/healthcheck":
+			w.Write([]byte(`{"status":"UP","checks":[{"id":"default-check","status":"UP"}],"outcome":"UP"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := web3signer.New(web3signer.Config{Name: name, URL: srv.URL, ClientURL: "http://" + name + ":9000"})
+	require.NoError(t, err)
+	return c
+}
+
+func newRemoteSignerService(ctrl *gomock.Controller, migrations models.KeyMigrationRepo, signers []*web3signer.Client,
+	clients ...vclient.KeyManager) (*RemoteSignerService, *mocks.MockValidatorRepo, *mocks.MockSignerKeyRepo, *mocks.MockClientKeyRepo) {
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockSignerKeys := mocks.NewMockSignerKeyRepo(ctrl)
+	mockClientKeys := mocks.NewMockClientKeyRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	doppelganger := NewDoppelgangerService(mocks.NewMockDoppelgangerCheckRepo(ctrl), mockValidators, nil, mockAudit, beacon.Nodes{}, 3)
+	service := NewRemoteSignerService(mockValidators, migrations, mockSignerKeys, mockClientKeys, doppelganger, mockAudit, signers, clients)
+	return service, mockValidators, mockSignerKeys, mockClientKeys
+}
+
+func TestRemoteSignerService_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signers := []*web3signer.Client{newSignerStub(t, "w3s-1", false), newSignerStub(t, "w3s-2", true, testKey1, testKey2)}
+	service, _, mockSignerKeys, _ := newRemoteSignerService(ctrl, nil, signers)
+
+	// An unreachable signer keeps its stored keys and does not stop the others
+	mockSignerKeys.EXPECT().ReplaceSignerKeys(gomock.Any(), "w3s-2", []models.SignerKey{
+		{Pubkey: testKey1, Signer: "w3s-2", SignerURL: "http://w3s-2:9000"},
+		{Pubkey: testKey2, Signer: "w3s-2", SignerURL: "http://w3s-2:9000"},
+	}).Return(nil)
+
+	err := service.Sync(context.Background())
+	assert.ErrorContains(t, err, `signer "w3s-1"`)
+}
+
+func TestRemoteSignerService_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signers := []*web3signer.Client{newSignerStub(t, "w3s-1", true), newSignerStub(t, "w3s-2", false)}
+	service, _, _, _ := newRemoteSignerService(ctrl, nil, signers)
+
+	health := service.Health(context.Background())
+	require.Len(t, health, 2)
+	assert.True(t, health[0].Up)
+	assert.Equal(t, web3signer.StatusUp, health[0].Status)
+	assert.False(t, health[1].Up)
+	assert.Contains(t, health[1].Error, "returned status 503")
+}
+
+func TestRemoteSignerService_Locate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockSignerKeys, mockClientKeys := newRemoteSignerService(ctrl, nil, nil)
+	ctx := context.Background()
+
+	mockSignerKeys.EXPECT().ListByPubkey(ctx, testKey1).Return([]models.SignerKey{{Pubkey: testKey1, Signer: "w3s-1"}}, nil)
+	mockClientKeys.EXPECT().ListByPubkey(ctx, testKey1).Return([]models.ClientKey{
+		{Pubkey: testKey1, ClientInstance: "lh-1"},
+		{Pubkey: testKey1, ClientInstance: "teku-1", Remote: true, SignerURL: "http://w3s-1:9000"},
+	}, nil)
+
+	loc, err := service.Locate(ctx, testKey1)
+	require.NoError(t, err)
+	assert.Len(t, loc.Signers, 1)
+	assert.Equal(t, []models.ClientKey{{Pubkey: testKey1, ClientInstance: "teku-1", Remote: true, SignerURL: "http://w3s-1:9000"}}, loc.Clients)
+}
+
+func TestRemoteSignerService_Register(t *testing.T) {
+	tests := []struct {
+		name          string
+		signer        string
+		instance      string
+		validator     *models.Validator
+		migration     *models.KeyMigration
+		expectedError error
+	}{
+		{
+			name:      "registers key held by signer",
+			signer:    "w3s-1",
+			instance:  "teku-1",
+			validator: &models.Validator{Pubkey: testKey1, Status: models.StatusPending},
+		},
+		{
+			name:          "unknown signer",
+			signer:        "w3s-9",
+			instance:      "teku-1",
+			expectedError: ErrInvalidDeployment,
+		},
+		{
+			name:          "already deployed",
+			signer:        "w3s-1",
+			instance:      "teku-1",
+			validator:     &models.Validator{Pubkey: testKey1, Status: models.StatusPending, ClientInstance: "lh-1"},
+			expectedError: ErrAlreadyDeployed,
+		},
+		{
+			name:          "key held by a migration",
+			signer:        "w3s-1",
+			instance:      "teku-1",
+			validator:     &models.Validator{Pubkey: testKey1, Status: models.StatusActive},
+			migration:     &models.KeyMigration{ID: 2, Pubkey: testKey1, Status: models.KeyMigrationStatusSourceDeleted},
+			expectedError: ErrKeyMigrating,
+		},
+		{
+			name:          "signer does not hold key",
+			signer:        "w3s-2",
+			instance:      "teku-1",
+			validator:     &models.Validator{Pubkey: testKey1, Status: models.StatusPending},
+			expectedError: ErrInvalidDeployment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			target := &fakeKeyManager{name: "teku-1"}
+			signers := []*web3signer.Client{newSignerStub(t, "w3s-1", true, testKey1), newSignerStub(t, "w3s-2", true, testKey2)}
+			migrations := notMigrating(ctrl)
+			if tt.migration != nil {
+				migrations = mocks.NewMockKeyMigrationRepo(ctrl)
+				migrations.EXPECT().Holding(gomock.Any(), testKey1).Return(tt.migration, nil)
+			}
+			service, mockValidators, _, _ := newRemoteSignerService(ctrl, migrations, signers, target)
+			if tt.validator != nil {
+				mockValidators.EXPECT().GetByPubkey(gomock.Any(), testKey1).Return(tt.validator, nil).MinTimes(1)
+			}
+			if tt.expectedError == nil {
+				mockValidators.EXPECT().UpdateClient(gomock.Any(), testKey1, vclient.TypeTeku, "teku-1").Return(nil)
+			}
+
+			v, err := service.Register(context.Background(), testKey1, tt.signer, tt.instance, "")
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, target.remote)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "teku-1", v.ClientInstance)
+			assert.Equal(t, []string{testKey1 + "@http://w3s-1:9000"}, target.remote)
+		})
+	}
+}
+
+func TestRemoteSignerService_Unregister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := &fakeKeyManager{name: "teku-1"}
+	stray := &fakeKeyManager{name: "lh-1", deleteErr: fmt.Errorf("instance %q: %w", "lh-1", vclient.ErrKeyNotFound)}
+	service, mockValidators, _, _ := newRemoteSignerService(ctrl, nil, nil, source, stray)
+	ctx := context.Background()
+
+	mockValidators.EXPECT().GetByPubkey(ctx, testKey1).Return(&models.Validator{
+		Pubkey: testKey1, Client: vclient.TypeTeku, ClientInstance: "teku-1",
+	}, nil).AnyTimes()
+
+	// Removing a key the client does not have leaves the recorded client alone
+	v, err := service.Unregister(ctx, testKey1, "lh-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "teku-1", v.ClientInstance)
+
+	mockValidators.EXPECT().UpdateClient(ctx, testKey1, "", "").Return(nil)
+	v, err = service.Unregister(ctx, testKey1, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, v.ClientInstance)
+}
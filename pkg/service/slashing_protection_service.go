@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/interchange"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// ErrMissingSlashingProtection is returned when exporting keys without stored slashing protection data
+var ErrMissingSlashingProtection = errors.New("no slashing protection data stored")
+
+// ErrMixedGenesis is returned when exporting keys of different chains in one interchange
+var ErrMixedGenesis = errors.New("keys belong to chains with different genesis validators roots")
+
+// SlashingProtectionImportResult reports the outcome of an interchange import
+type SlashingProtectionImportResult struct {
+	Imported int                           `json:"imported"`
+	Rejected []SlashingProtectionRejection `json:"rejected"`
+}
+
+// SlashingProtectionRejection explains why the data of one key was not imported
+type SlashingProtectionRejection struct {
+	Pubkey string `json:"pubkey"`
+	Error  string `json:"error"`
+}
+
+// SlashingProtectionService merges EIP-3076 interchanges exported by validator
+// clients and exports the combined data when keys move between clients
+type SlashingProtectionService struct {
+	repo  models.SlashingProtectionRepo
+	audit models.AuditRepo
+}
+
+// NewSlashingProtectionService creates a new slashing protection service
+func NewSlashingProtectionService(repo models.SlashingProtectionRepo, audit models.AuditRepo) *SlashingProtectionService {
+	return &SlashingProtectionService{repo: repo, audit: audit}
+}
+
+// Import merges an interchange into the stored data. Only the highest block slot
+// and attestation epochs of each key are kept, and stored values are never
+// lowered, so importing several clients' interchanges in any order is safe.
+// source names the client the interchange came from, for the audit log.
+func (s *SlashingProtectionService) Import(ctx context.Context, i *interchange.Interchange, source, sourceIP string) (*SlashingProtectionImportResult, error) {
+	root, err := i.Validate()
+	if err != nil {
+		return nil, err
+	}
+	marks, err := i.Watermarks()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SlashingProtectionImportResult{Rejected: []SlashingProtectionRejection{}}
+	for _, m := range marks {
+		p := &models.SlashingProtection{Pubkey: m.Pubkey, GenesisValidatorsRoot: root}
+		if p.BlockSlot, err = toInt64(m.BlockSlot); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", interchange.ErrInvalid, m.Pubkey, err)
+		}
+		if p.SourceEpoch, err = toInt64(m.SourceEpoch); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", interchange.ErrInvalid, m.Pubkey, err)
+		}
+		if p.TargetEpoch, err = toInt64(m.TargetEpoch); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", interchange.ErrInvalid, m.Pubkey, err)
+		}
+
+		err := s.repo.Merge(ctx, p)
+		if errors.Is(err, models.ErrGenesisMismatch) {
+			result.Rejected = append(result.Rejected, SlashingProtectionRejection{Pubkey: m.Pubkey, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Imported++
+	}
+
+	details := fmt.Sprintf("Slashing protection interchange from %q: %d keys imported, %d rejected",
+		source, result.Imported, len(result.Rejected))
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "slashing_protection.imported", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record import: %w", err)
+	}
+	return result, nil
+}
+
+// Export builds a minimal interchange of the stored data of pubkeys. Every key
+// must have stored data, and all keys must belong to the same chain.
+func (s *SlashingProtectionService) Export(ctx context.Context, pubkeys []string, sourceIP string) (*interchange.Interchange, error) {
+	seen := map[string]bool{}
+	var keys []string
+	for _, pubkey := range pubkeys {
+		pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+		if pubkey == "" || seen[pubkey] {
+			continue
+		}
+		seen[pubkey] = true
+		keys = append(keys, pubkey)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no pubkeys given", ErrMissingSlashingProtection)
+	}
+
+	stored, err := s.repo.List(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := make([]interchange.Watermark, 0, len(stored))
+	root := ""
+	for _, p := range stored {
+		if root != "" && p.GenesisValidatorsRoot != root {
+			return nil, ErrMixedGenesis
+		}
+		root = p.GenesisValidatorsRoot
+		delete(seen, p.Pubkey)
+		marks = append(marks, interchange.Watermark{
+			Pubkey:      p.Pubkey,
+			BlockSlot:   toUint64(p.BlockSlot),
+			SourceEpoch: toUint64(p.SourceEpoch),
+			TargetEpoch: toUint64(p.TargetEpoch),
+		})
+	}
+	if len(seen) > 0 {
+		missing := make([]string, 0, len(seen))
+		for _, pubkey := range keys {
+			if seen[pubkey] {
+				missing = append(missing, pubkey)
+			}
+		}
+		return nil, fmt.Errorf("%w for %s", ErrMissingSlashingProtection, strings.Join(missing, ", "))
+	}
+
+	details := fmt.Sprintf("Slashing protection exported for %d keys", len(marks))
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "slashing_protection.exported", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record export: %w", err)
+	}
+	return interchange.Minimal(root, marks), nil
+}
+
+// toInt64 converts an interchange value to its stored form
+func toInt64(v *uint64) (*int64, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if *v > math.MaxInt64 {
+		return nil, fmt.Errorf("value %d out of range", *v)
+	}
+	i := int64(*v)
+	return &i, nil
+}
+
+// toUint64 converts a stored value to its interchange form
+func toUint64(v *int64) *uint64 {
+	if v == nil {
+		return nil
+	}
+	u := uint64(*v)
+	return &u
+}
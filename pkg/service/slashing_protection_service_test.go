@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/interchange"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+var (
+	testInterchangeRoot = "0x" + strings.Repeat("4b", 32)
+	testKey1            = "0x" + strings.Repeat("a1", 48)
+	testKey2            = "0x" + strings.Repeat("a2", 48)
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestSlashingProtectionService_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSlashingProtectionRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewSlashingProtectionService(mockRepo, mockAudit)
+	ctx := context.Background()
+
+	i := &interchange.Interchange{
+		Metadata: interchange.Metadata{InterchangeFormatVersion: "5", GenesisValidatorsRoot: testInterchangeRoot},
+		Data: []interchange.Record{
+			{
+				Pubkey:             strings.ToUpper(testKey1),
+				SignedBlocks:       []interchange.SignedBlock{{Slot: "100"}, {Slot: "90"}},
+				SignedAttestations: []interchange.SignedAttestation{{SourceEpoch: "5", TargetEpoch: "6"}},
+			},
+			{Pubkey: testKey2},
+		},
+	}
+	i.Data[0].Pubkey = "0x" + strings.ToUpper(testKey1[2:])
+
+	mockRepo.EXPECT().Merge(ctx, &models.SlashingProtection{
+		Pubkey: testKey1, GenesisValidatorsRoot: testInterchangeRoot,
+		BlockSlot: int64Ptr(100), SourceEpoch: int64Ptr(5), TargetEpoch: int64Ptr(6),
+	}).Return(nil)
+	mockRepo.EXPECT().Merge(ctx, &models.SlashingProtection{Pubkey: testKey2, GenesisValidatorsRoot: testInterchangeRoot}).
+		Return(models.ErrGenesisMismatch)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry *models.AuditLog) error {
+		assert.Equal(t, `Slashing protection interchange from "teku-1": 1 keys imported, 1 rejected`, entry.Details)
+		return nil
+	})
+
+	result, err := service.Import(ctx, i, "teku-1", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []SlashingProtectionRejection{{Pubkey: testKey2, Error: models.ErrGenesisMismatch.Error()}}, result.Rejected)
+
+	// Invalid interchanges are refused before anything is stored
+	i.Metadata.InterchangeFormatVersion = "4"
+	_, err = service.Import(ctx, i, "teku-1", "10.0.0.1")
+	assert.ErrorIs(t, err, interchange.ErrInvalid)
+}
+
+func TestSlashingProtectionService_Export(t *testing.T) {
+	tests := []struct {
+		name          string
+		pubkeys       []string
+		stored        []models.SlashingProtection
+		expectedError error
+	}{
+		{
+			name:    "exported",
+			pubkeys: []string{testKey1, strings.ToUpper(testKey1[:2]) + testKey1[2:]},
+			stored: []models.SlashingProtection{
+				{Pubkey: testKey1, GenesisValidatorsRoot: testInterchangeRoot, BlockSlot: int64Ptr(100), SourceEpoch: int64Ptr(5), TargetEpoch: int64Ptr(6)},
+			},
+		},
+		{
+			name:          "missing key",
+			pubkeys:       []string{testKey1, testKey2},
+			stored:        []models.SlashingProtection{{Pubkey: testKey1, GenesisValidatorsRoot: testInterchangeRoot}},
+			expectedError: ErrMissingSlashingProtection,
+		},
+		{
+			name:    "mixed chains",
+			pubkeys: []string{testKey1, testKey2},
+			stored: []models.SlashingProtection{
+				{Pubkey: testKey1, GenesisValidatorsRoot: testInterchangeRoot},
+				{Pubkey: testKey2, GenesisValidatorsRoot: "0x" + strings.Repeat("00", 32)},
+			},
+			expectedError: ErrMixedGenesis,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockSlashingProtectionRepo(ctrl)
+			mockAudit := mocks.NewMockAuditRepo(ctrl)
+			service := NewSlashingProtectionService(mockRepo, mockAudit)
+
+			mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(tt.stored, nil)
+			if tt.expectedError == nil {
+				mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			i, err := service.Export(context.Background(), tt.pubkeys, "")
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testInterchangeRoot, i.Metadata.GenesisValidatorsRoot)
+			require.Len(t, i.Data, 1)
+			assert.Equal(t, []interchange.SignedBlock{{Slot: "100"}}, i.Data[0].SignedBlocks)
+			assert.Equal(t, []interchange.SignedAttestation{{SourceEpoch: "5", TargetEpoch: "6"}}, i.Data[0].SignedAttestations)
+		})
+	}
+}
@@ -2,23 +2,80 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/validator"
 )
 
+// ErrReasonRequired is returned when a status override has no reason
+var ErrReasonRequired = errors.New("a reason is required to override validator status")
+
+// ErrInvalidQuery is returned for a search query that is neither an index nor a pubkey prefix
+var ErrInvalidQuery = errors.New("query must be a validator index or a hex pubkey prefix")
+
+// ErrInvalidDepositData is returned for a deposit data entry that cannot be imported
+var ErrInvalidDepositData = errors.New("invalid deposit data")
+
+// depositNetworks maps deposit CLI network names to a blockchain and network
+var depositNetworks = map[string][2]string{
+	"mainnet": {"ethereum", "mainnet"},
+	"holesky": {"ethereum", "holesky"},
+	"hoodi":   {"ethereum", "hoodi"},
+	"sepolia": {"ethereum", "sepolia"},
+	"gnosis":  {"gnosis", "mainnet"},
+	"chiado":  {"gnosis", "chiado"},
+}
+
+// DepositImportResult counts the outcome of a deposit data import
+type DepositImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// Kinds of validator search query
+const (
+	SearchKindIndex  = "index"
+	SearchKindPubkey = "pubkey"
+	SearchKindPrefix = "prefix"
+)
+
+const (
+	// minPrefixLength is the shortest pubkey prefix searched, in hex characters
+	minPrefixLength = 4
+	// searchLimit caps the number of validators returned by a prefix search
+	searchLimit = 50
+)
+
+// SearchResult holds the validators matching a search query and how the query was read
+type SearchResult struct {
+	Kind       string             `json:"kind"`
+	Validators []models.Validator `json:"validators"`
+}
+
 // ValidatorService provides business logic for validator operations
 type ValidatorService struct {
-	repo models.ValidatorRepo
+	repo  models.ValidatorRepo
+	audit models.AuditRepo
 }
 
 // NewValidatorService creates a new validator service
-func NewValidatorService(repo models.ValidatorRepo) *ValidatorService {
-	return &ValidatorService{repo: repo}
+func NewValidatorService(repo models.ValidatorRepo, audit models.AuditRepo) *ValidatorService {
+	return &ValidatorService{repo: repo, audit: audit}
 }
 
-// CreateValidator creates a new validator
+// CreateValidator creates a new validator. An empty status defaults to unused.
 func (s *ValidatorService) CreateValidator(ctx context.Context, v *models.Validator) error {
+	if v.Status == "" {
+		v.Status = models.StatusUnused
+	}
+	if !v.Status.Valid() {
+		return fmt.Errorf("%w: %q", models.ErrInvalidStatus, v.Status)
+	}
 	return s.repo.Create(ctx, v)
 }
 
@@ -27,14 +84,168 @@ func (s *ValidatorService) GetValidatorByPubkey(ctx context.Context, pubkey stri
 	return s.repo.GetByPubkey(ctx, pubkey)
 }
 
+// GetValidatorByIndex retrieves a validator by its beacon chain index on a network
+func (s *ValidatorService) GetValidatorByIndex(ctx context.Context, network string, index int64) (*models.Validator, error) {
+	return s.repo.GetByIndex(ctx, network, index)
+}
+
+// SearchValidators finds validators by a pasted validator index or pubkey prefix.
+// A decimal query is read as an index; anything else must be hex, with or
+// without 0x, and is matched as a pubkey prefix or, at full length, exactly.
+// Index lookups are restricted to network when it is given.
+func (s *ValidatorService) SearchValidators(ctx context.Context, query, network string) (*SearchResult, error) {
+	query = strings.TrimSpace(query)
+
+	if index, err := strconv.ParseInt(query, 10, 64); err == nil && index >= 0 {
+		result := &SearchResult{Kind: SearchKindIndex}
+		if network == "" {
+			validators, err := s.repo.List(ctx, map[string]interface{}{"validator_index": index})
+			if err != nil {
+				return nil, err
+			}
+			result.Validators = validators
+			return result, nil
+		}
+		v, err := s.repo.GetByIndex(ctx, network, index)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if v != nil {
+			result.Validators = []models.Validator{*v}
+		}
+		return result, nil
+	}
+
+	hexPart := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(query, "0x"), "0X"))
+	if len(hexPart) < minPrefixLength || len(hexPart) > 96 || !isHex(hexPart) {
+		return nil, ErrInvalidQuery
+	}
+	pubkey := "0x" + hexPart
+
+	if len(hexPart) == 96 {
+		result := &SearchResult{Kind: SearchKindPubkey}
+		v, err := s.repo.GetByPubkey(ctx, pubkey)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if v != nil {
+			result.Validators = []models.Validator{*v}
+		}
+		return result, nil
+	}
+
+	validators, err := s.repo.SearchByPubkeyPrefix(ctx, pubkey, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResult{Kind: SearchKindPrefix, Validators: validators}, nil
+}
+
 // ListValidators retrieves a list of validators based on filters
 func (s *ValidatorService) ListValidators(ctx context.Context, filters map[string]interface{}) ([]models.Validator, error) {
 	return s.repo.List(ctx, filters)
 }
 
-// UpdateValidatorStatus updates the status of a validator
-func (s *ValidatorService) UpdateValidatorStatus(ctx context.Context, pubkey, status string) error {
-	return s.repo.UpdateStatus(ctx, pubkey, status)
+// UpdateValidatorStatus moves a validator to a new status. Transitions not
+// allowed by the lifecycle return an error wrapping models.ErrInvalidTransition.
+// The transition is checked against the stored status when it is written, so
+// concurrent status changes cannot combine into a forbidden one.
+func (s *ValidatorService) UpdateValidatorStatus(ctx context.Context, pubkey string, status models.Status) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w: %q", models.ErrInvalidStatus, status)
+	}
+	return s.repo.TransitionStatus(ctx, pubkey, status)
+}
+
+// OverrideValidatorStatus sets a validator's status without checking the
+// transition table. It is meant for operators correcting bad state, so a
+// reason is required and the change is recorded in the audit log.
+func (s *ValidatorService) OverrideValidatorStatus(ctx context.Context, pubkey string, status models.Status, reason, sourceIP string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	if !status.Valid() {
+		return fmt.Errorf("%w: %q", models.ErrInvalidStatus, status)
+	}
+
+	v, err := s.repo.GetByPubkey(ctx, pubkey)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateStatus(ctx, pubkey, status); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("validator %s status overridden from %s to %s: %s", pubkey, v.Status, status, reason)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "validator.status_override", SourceIP: sourceIP, Details: details}); err != nil {
+		return fmt.Errorf("failed to record status override: %w", err)
+	}
+	return nil
+}
+
+// ImportDepositData records the withdrawal credentials of deposit data entries.
+// Unknown keys are added as unused validators. All entries are validated
+// before anything is written.
+func (s *ValidatorService) ImportDepositData(ctx context.Context, entries []models.DepositData) (*DepositImportResult, error) {
+	for i := range entries {
+		if err := normalizeDepositData(&entries[i]); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidDepositData, i, err)
+		}
+	}
+
+	result := &DepositImportResult{}
+	for _, d := range entries {
+		chain := depositNetworks[d.NetworkName]
+
+		v, err := s.repo.GetByPubkey(ctx, d.Pubkey)
+		switch {
+		case err == nil:
+			if v.Blockchain != chain[0] || v.BlockchainNetwork != chain[1] {
+				return result, fmt.Errorf("%w: %s is registered on %s %s, not %s", ErrInvalidDepositData,
+					d.Pubkey, v.Blockchain, v.BlockchainNetwork, d.NetworkName)
+			}
+			result.Updated++
+		case isNotFound(err):
+			err := s.CreateValidator(ctx, &models.Validator{
+				Pubkey:            d.Pubkey,
+				Blockchain:        chain[0],
+				BlockchainNetwork: chain[1],
+				Status:            models.StatusUnused,
+			})
+			if err != nil {
+				return result, fmt.Errorf("failed to create validator %s: %w", d.Pubkey, err)
+			}
+			result.Created++
+		default:
+			return result, err
+		}
+
+		err = s.repo.UpdateWithdrawalCredentials(ctx, d.Pubkey, d.WithdrawalCredentials, models.CredentialsSourceDepositData)
+		if err != nil {
+			return result, fmt.Errorf("failed to record withdrawal credentials of %s: %w", d.Pubkey, err)
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeDepositData validates an entry and lower-cases its 0x-prefixed hex fields
+func normalizeDepositData(d *models.DepositData) error {
+	d.Pubkey = "0x" + strings.ToLower(strings.TrimPrefix(d.Pubkey, "0x"))
+	if err := validator.ValidatePubkeyFormat(d.Pubkey); err != nil {
+		return err
+	}
+
+	creds := strings.ToLower(strings.TrimPrefix(d.WithdrawalCredentials, "0x"))
+	if len(creds) != 64 || !isHex(creds) {
+		return errors.New("withdrawal credentials must be 32 bytes of hex")
+	}
+	d.WithdrawalCredentials = "0x" + creds
+
+	if _, ok := depositNetworks[d.NetworkName]; !ok {
+		return fmt.Errorf("unsupported network %q", d.NetworkName)
+	}
+	return nil
 }
 
 // CheckDuplicate checks if a pubkey already exists in the database
@@ -50,3 +261,17 @@ func (s *ValidatorService) CheckDuplicate(ctx context.Context, pubkey string) er
 	}
 	return nil
 }
+
+// isNotFound reports whether err means the validator does not exist
+func isNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, models.ErrNotFound)
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
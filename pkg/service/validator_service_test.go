@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,7 +19,7 @@ func TestValidatorService_CreateValidator(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockValidatorRepo(ctrl)
-	service := NewValidatorService(mockRepo)
+	service := NewValidatorService(mockRepo, nil)
 	ctx := context.Background()
 
 	validator := &models.Validator{
@@ -39,7 +41,7 @@ func TestValidatorService_GetValidatorByPubkey(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockValidatorRepo(ctrl)
-	service := NewValidatorService(mockRepo)
+	service := NewValidatorService(mockRepo, nil)
 	ctx := context.Background()
 
 	expectedValidator := &models.Validator{
@@ -65,7 +67,7 @@ func TestValidatorService_ListValidators(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockValidatorRepo(ctrl)
-	service := NewValidatorService(mockRepo)
+	service := NewValidatorService(mockRepo, nil)
 	ctx := context.Background()
 
 	filters := map[string]interface{}{
@@ -93,17 +95,85 @@ func TestValidatorService_ListValidators(t *testing.T) {
 	assert.Equal(t, expectedValidators, validators)
 }
 
+func TestValidatorService_CreateValidator_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	service := NewValidatorService(mockRepo, nil)
+
+	err := service.CreateValidator(context.Background(), &models.Validator{Pubkey: "0x123", Status: "activ"})
+	assert.ErrorIs(t, err, models.ErrInvalidStatus)
+
+	v := &models.Validator{Pubkey: "0x456"}
+	mockRepo.EXPECT().Create(gomock.Any(), v).Return(nil)
+	assert.NoError(t, service.CreateValidator(context.Background(), v))
+	assert.Equal(t, models.StatusUnused, v.Status)
+}
+
 func TestValidatorService_UpdateValidatorStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        models.Status
+		expectUpdate  bool
+		updateError   error
+		expectedError error
+	}{
+		{name: "allowed transition", status: models.StatusExiting, expectUpdate: true},
+		{
+			name: "rejected transition", status: models.StatusUnused, expectUpdate: true,
+			updateError:   &models.TransitionError{From: models.StatusExited, To: models.StatusUnused},
+			expectedError: models.ErrInvalidTransition,
+		},
+		{name: "unknown status", status: "activ", expectedError: models.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockValidatorRepo(ctrl)
+			service := NewValidatorService(mockRepo, nil)
+			ctx := context.Background()
+
+			// The transition is checked by the repository when the row is locked
+			if tt.expectUpdate {
+				mockRepo.EXPECT().TransitionStatus(ctx, "0x123", tt.status).Return(tt.updateError)
+			}
+
+			err := service.UpdateValidatorStatus(ctx, "0x123", tt.status)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatorService_OverrideValidatorStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockValidatorRepo(ctrl)
-	service := NewValidatorService(mockRepo)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	service := NewValidatorService(mockRepo, mockAudit)
 	ctx := context.Background()
 
-	mockRepo.EXPECT().UpdateStatus(ctx, "0x123", "inactive").Return(nil)
+	err := service.OverrideValidatorStatus(ctx, "0x123", models.StatusUnused, "", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrReasonRequired)
+
+	mockRepo.EXPECT().GetByPubkey(ctx, "0x123").Return(&models.Validator{Pubkey: "0x123", Status: models.StatusExited}, nil)
+	mockRepo.EXPECT().UpdateStatus(ctx, "0x123", models.StatusUnused).Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, log *models.AuditLog) error {
+		assert.Equal(t, "validator.status_override", log.Action)
+		assert.Equal(t, "10.0.0.1", log.SourceIP)
+		assert.Contains(t, log.Details, "from exited to unused: key was never deposited")
+		return nil
+	})
 
-	err := service.UpdateValidatorStatus(ctx, "0x123", "inactive")
+	err = service.OverrideValidatorStatus(ctx, "0x123", models.StatusUnused, "key was never deposited", "10.0.0.1")
 	assert.NoError(t, err)
 }
 
@@ -152,7 +222,7 @@ func TestCheckDuplicate(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := tt.mockSetup(ctrl)
-			service := NewValidatorService(mockRepo)
+			service := NewValidatorService(mockRepo, nil)
 
 			err := service.CheckDuplicate(context.Background(), tt.pubkey)
 			if tt.expectedError != nil {
@@ -164,3 +234,69 @@ func TestCheckDuplicate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorService_ImportDepositData(t *testing.T) {
+	pubkey1 := "0x" + strings.Repeat("a1", 48)
+	pubkey2 := "0x" + strings.Repeat("b2", 48)
+	blsCreds := "00" + strings.Repeat("cd", 31)
+	execCreds := "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+
+	t.Run("creates and updates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockValidatorRepo(ctrl)
+		service := NewValidatorService(mockRepo, nil)
+		ctx := context.Background()
+
+		mockRepo.EXPECT().GetByPubkey(ctx, pubkey1).Return(nil, sql.ErrNoRows)
+		mockRepo.EXPECT().Create(ctx, &models.Validator{
+			Pubkey:            pubkey1,
+			Blockchain:        "gnosis",
+			BlockchainNetwork: "chiado",
+			Status:            models.StatusUnused,
+		}).Return(nil)
+		mockRepo.EXPECT().UpdateWithdrawalCredentials(ctx, pubkey1, "0x"+blsCreds, models.CredentialsSourceDepositData).Return(nil)
+		mockRepo.EXPECT().GetByPubkey(ctx, pubkey2).Return(&models.Validator{
+			Pubkey: pubkey2, Blockchain: "gnosis", BlockchainNetwork: "chiado",
+		}, nil)
+		mockRepo.EXPECT().UpdateWithdrawalCredentials(ctx, pubkey2, execCreds, models.CredentialsSourceDepositData).Return(nil)
+
+		result, err := service.ImportDepositData(ctx, []models.DepositData{
+			// The deposit CLI writes hex without 0x
+			{Pubkey: strings.TrimPrefix(pubkey1, "0x"), WithdrawalCredentials: blsCreds, NetworkName: "chiado"},
+			{Pubkey: pubkey2, WithdrawalCredentials: strings.ToUpper(execCreds[2:]), NetworkName: "chiado"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &DepositImportResult{Created: 1, Updated: 1}, result)
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewValidatorService(mocks.NewMockValidatorRepo(ctrl), nil)
+		_, err := service.ImportDepositData(context.Background(), []models.DepositData{
+			{Pubkey: pubkey1, WithdrawalCredentials: blsCreds, NetworkName: "mainnet"},
+			{Pubkey: pubkey2, WithdrawalCredentials: "0x01", NetworkName: "mainnet"},
+		})
+		assert.ErrorIs(t, err, ErrInvalidDepositData)
+		assert.ErrorContains(t, err, "entry 1")
+	})
+
+	t.Run("network mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockValidatorRepo(ctrl)
+		service := NewValidatorService(mockRepo, nil)
+
+		mockRepo.EXPECT().GetByPubkey(gomock.Any(), pubkey1).Return(&models.Validator{
+			Pubkey: pubkey1, Blockchain: "ethereum", BlockchainNetwork: "holesky",
+		}, nil)
+		_, err := service.ImportDepositData(context.Background(), []models.DepositData{
+			{Pubkey: pubkey1, WithdrawalCredentials: blsCreds, NetworkName: "mainnet"},
+		})
+		assert.ErrorIs(t, err, ErrInvalidDepositData)
+	})
+}
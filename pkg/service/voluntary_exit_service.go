@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/bls"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+// VoluntaryExitResult reports what happened to one uploaded exit
+type VoluntaryExitResult struct {
+	ValidatorIndex string `json:"validator_index"`
+	Pubkey         string `json:"pubkey,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// VoluntaryExitService verifies and stores pre-signed voluntary exits
+type VoluntaryExitService struct {
+	validators models.ValidatorRepo
+	exits      models.VoluntaryExitRepo
+	audit      models.AuditRepo
+	cipher     *vault.Cipher
+	nodes      beacon.Nodes
+}
+
+// NewVoluntaryExitService creates a new voluntary exit service
+func NewVoluntaryExitService(validators models.ValidatorRepo, exits models.VoluntaryExitRepo, audit models.AuditRepo,
+	cipher *vault.Cipher, nodes beacon.Nodes) *VoluntaryExitService {
+	return &VoluntaryExitService{validators: validators, exits: exits, audit: audit, cipher: cipher, nodes: nodes}
+}
+
+// Upload verifies each exit against the validator it names on network and stores
+// the valid ones encrypted, replacing any earlier exit of the same validator.
+// Invalid entries are reported without stopping the others.
+func (s *VoluntaryExitService) Upload(ctx context.Context, network string, uploads []beacon.SignedVoluntaryExit, sourceIP string) ([]VoluntaryExitResult, error) {
+	results := make([]VoluntaryExitResult, 0, len(uploads))
+	for _, u := range uploads {
+		result := VoluntaryExitResult{ValidatorIndex: u.Message.ValidatorIndex}
+
+		exit, err := s.verify(ctx, network, u)
+		var rejected *rejectionError
+		if errors.As(err, &rejected) {
+			result.Error = rejected.reason
+			results = append(results, result)
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		result.Pubkey = exit.Pubkey
+
+		if err := s.exits.Save(ctx, exit); err != nil {
+			return results, err
+		}
+
+		details := fmt.Sprintf("Pre-signed voluntary exit for %s at epoch %d", exit.Pubkey, exit.Epoch)
+		if err := s.audit.Record(ctx, &models.AuditLog{Action: "voluntary_exit.uploaded", SourceIP: sourceIP, Details: details}); err != nil {
+			return results, fmt.Errorf("failed to record upload: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// verify checks a signed exit against the stored validator and returns it ready to save
+func (s *VoluntaryExitService) verify(ctx context.Context, networkName string, signed beacon.SignedVoluntaryExit) (*models.VoluntaryExit, error) {
+	signed.Signature = strings.ToLower(signed.Signature)
+
+	index, err := signed.Message.Index()
+	if err != nil {
+		return nil, rejectf("%v", err)
+	}
+	epoch, err := signed.Message.ExitEpoch()
+	if err != nil {
+		return nil, rejectf("%v", err)
+	}
+	objectRoot, err := signed.Message.HashTreeRoot()
+	if err != nil {
+		return nil, rejectf("%v", err)
+	}
+	chain, ok := depositNetworks[networkName]
+	if !ok {
+		return nil, rejectf("unsupported network %q", networkName)
+	}
+
+	validators, err := s.validators.List(ctx, map[string]interface{}{
+		"blockchain":         chain[0],
+		"blockchain_network": chain[1],
+		"validator_index":    int64(index),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) != 1 {
+		return nil, rejectf("validator %d is not managed on %s", index, networkName)
+	}
+	v := validators[0]
+
+	pubkey, err := hex.DecodeString(strings.TrimPrefix(v.Pubkey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored pubkey %s: %w", v.Pubkey, err)
+	}
+
+	node, ok := s.nodes.Get(v.Blockchain, v.BlockchainNetwork)
+	if !ok {
+		return nil, rejectf("no beacon node configured for %s %s", v.Blockchain, v.BlockchainNetwork)
+	}
+	genesis, err := node.Genesis(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis of %s %s: %w", v.Blockchain, v.BlockchainNetwork, err)
+	}
+	forkVersion, err := node.CapellaForkVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fork versions of %s %s: %w", v.Blockchain, v.BlockchainNetwork, err)
+	}
+
+	// Since Deneb voluntary exits are verified against the Capella fork version only (EIP-7044)
+	domain := beacon.ComputeDomain(beacon.DomainVoluntaryExit, forkVersion, genesis.ValidatorsRoot)
+	signingRoot := beacon.SigningRoot(objectRoot, domain)
+	signature, err := hex.DecodeString(strings.TrimPrefix(signed.Signature, "0x"))
+	if err != nil {
+		return nil, rejectf("invalid signature encoding: %v", err)
+	}
+	if err := bls.Verify(pubkey, signingRoot[:], signature); err != nil {
+		return nil, rejectf("%v", err)
+	}
+
+	plaintext, err := json.Marshal(signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exit: %w", err)
+	}
+	sealed, err := s.cipher.Seal(plaintext, []byte(v.Pubkey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VoluntaryExit{
+		Pubkey:         v.Pubkey,
+		ValidatorIndex: int64(index),
+		Epoch:          int64(epoch),
+		Message:        sealed,
+	}, nil
+}
+
+// List returns every stored pre-signed exit
+func (s *VoluntaryExitService) List(ctx context.Context) ([]models.VoluntaryExit, error) {
+	return s.exits.List(ctx)
+}
+
+// Missing returns the active validators that have no valid pre-signed exit for their current index
+func (s *VoluntaryExitService) Missing(ctx context.Context) ([]models.Validator, error) {
+	return s.exits.ListMissing(ctx)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/vault"
+)
+
+var testCapellaForkVersion = [4]byte{0x03, 0x00, 0x00, 0x00}
+
+// newSpecStub serves the genesis and spec endpoints
+func newSpecStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			w.Write([]byte(`{"data":{"genesis_validators_root":"0x` + testGenesisRoot + `","genesis_fork_version":"0x00000000"}}`))
+		case "/eth/v1/config/spec":
+			w.Write([]byte(`{"data":{"CAPELLA_FORK_VERSION":"0x03000000","DENEB_FORK_VERSION":"0x04000000","SECONDS_PER_SLOT":"12"}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// signExit signs a voluntary exit with the validator secret key sk under forkVersion
+func signExit(t *testing.T, sk int64, index, epoch string, forkVersion [4]byte) (beacon.SignedVoluntaryExit, string) {
+	t.Helper()
+
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplicationBase(big.NewInt(sk))
+	pkBytes := pk.Bytes()
+
+	msg := beacon.VoluntaryExit{Epoch: epoch, ValidatorIndex: index}
+	root, err := msg.HashTreeRoot()
+	require.NoError(t, err)
+
+	var gvr [32]byte
+	g, _ := hex.DecodeString(testGenesisRoot)
+	copy(gvr[:], g)
+	signingRoot := beacon.SigningRoot(root, beacon.ComputeDomain(beacon.DomainVoluntaryExit, forkVersion, gvr))
+
+	h, err := bls12381.HashToG2(signingRoot[:], []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	require.NoError(t, err)
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&h, big.NewInt(sk))
+	sigBytes := sig.Bytes()
+
+	return beacon.SignedVoluntaryExit{Message: msg, Signature: "0x" + hex.EncodeToString(sigBytes[:])}, "0x" + hex.EncodeToString(pkBytes[:])
+}
+
+func TestVoluntaryExitService_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := newSpecStub(t)
+
+	cipher, err := vault.NewCipher(bytes.Repeat([]byte{1}, vault.KeySize))
+	require.NoError(t, err)
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockExits := mocks.NewMockVoluntaryExitRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewVoluntaryExitService(mockValidators, mockExits, mockAudit, cipher, nodes)
+	ctx := context.Background()
+
+	valid, pubkey := signExit(t, 12345, "42", "194048", testCapellaForkVersion)
+	// Signed with the Deneb fork version, which EIP-7044 no longer accepts
+	deneb, _ := signExit(t, 12345, "42", "194048", [4]byte{0x04, 0x00, 0x00, 0x00})
+	// Signed for another validator index
+	otherIndex, _ := signExit(t, 12345, "43", "194048", testCapellaForkVersion)
+	otherIndex.Message.ValidatorIndex = "42"
+
+	validator := models.Validator{Pubkey: pubkey, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{
+		"blockchain": "ethereum", "blockchain_network": "mainnet", "validator_index": int64(42),
+	}).Return([]models.Validator{validator}, nil).Times(3)
+
+	var saved *models.VoluntaryExit
+	mockExits.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *models.VoluntaryExit) error {
+		saved = e
+		return nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	results, err := service.Upload(ctx, "mainnet", []beacon.SignedVoluntaryExit{valid, deneb, otherIndex}, "10.0.0.1")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, VoluntaryExitResult{ValidatorIndex: "42", Pubkey: pubkey}, results[0])
+	assert.Equal(t, "invalid BLS signature", results[1].Error)
+	assert.Equal(t, "invalid BLS signature", results[2].Error)
+
+	// The stored message is encrypted and bound to the validator
+	require.NotNil(t, saved)
+	assert.Equal(t, int64(42), saved.ValidatorIndex)
+	assert.Equal(t, int64(194048), saved.Epoch)
+	assert.NotContains(t, string(saved.Message), valid.Signature)
+	plaintext, err := cipher.Open(saved.Message, []byte(pubkey))
+	require.NoError(t, err)
+	assert.Contains(t, string(plaintext), valid.Signature)
+}
+
+func TestVoluntaryExitService_UploadRejects(t *testing.T) {
+	valid, pubkey := signExit(t, 12345, "42", "194048", testCapellaForkVersion)
+
+	tests := []struct {
+		name          string
+		network       string
+		exit          beacon.SignedVoluntaryExit
+		validators    []models.Validator
+		expectedError string
+	}{
+		{
+			name:          "unsupported network",
+			network:       "ropsten",
+			exit:          valid,
+			expectedError: `unsupported network "ropsten"`,
+		},
+		{
+			name:          "invalid epoch",
+			network:       "mainnet",
+			exit:          beacon.SignedVoluntaryExit{Message: beacon.VoluntaryExit{Epoch: "soon", ValidatorIndex: "42"}},
+			expectedError: `invalid epoch: strconv.ParseUint: parsing "soon": invalid syntax`,
+		},
+		{
+			name:          "unknown validator",
+			network:       "mainnet",
+			exit:          valid,
+			validators:    []models.Validator{},
+			expectedError: "validator 42 is not managed on mainnet",
+		},
+		{
+			name:          "no beacon node",
+			network:       "mainnet",
+			exit:          valid,
+			validators:    []models.Validator{{Pubkey: pubkey, Blockchain: "ethereum", BlockchainNetwork: "mainnet"}},
+			expectedError: "no beacon node configured for ethereum mainnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockValidators := mocks.NewMockValidatorRepo(ctrl)
+			if tt.validators != nil {
+				mockValidators.EXPECT().List(gomock.Any(), gomock.Any()).Return(tt.validators, nil)
+			}
+			service := NewVoluntaryExitService(mockValidators, mocks.NewMockVoluntaryExitRepo(ctrl), mocks.NewMockAuditRepo(ctrl), nil, beacon.Nodes{})
+
+			results, err := service.Upload(context.Background(), tt.network, []beacon.SignedVoluntaryExit{tt.exit}, "")
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.expectedError, results[0].Error)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/webhook"
+)
+
+// ErrWebhookNotConfigured is returned when redelivering to a webhook that has been removed from the configuration
+var ErrWebhookNotConfigured = errors.New("webhook is no longer configured")
+
+// webhookDispatchBatch is the number of due deliveries attempted per dispatch run
+const webhookDispatchBatch = 100
+
+// webhookClaimLease is how long a claimed delivery is kept from other dispatchers.
+// It outlasts a full batch of attempts at the client timeout, so only a delivery
+// whose attempt was never stored, e.g. after a crash, is claimed again.
+const webhookClaimLease = 30 * time.Minute
+
+// WebhookService records a delivery for every event a configured webhook
+// subscribes to and sends the due deliveries, retrying failed ones with backoff
+type WebhookService struct {
+	deliveries models.WebhookDeliveryRepo
+	audit      models.AuditRepo
+	endpoints  map[string]webhook.Endpoint
+	order      []string
+	client     *webhook.Client
+}
+
+// NewWebhookService creates a new webhook service for the given endpoints
+func NewWebhookService(deliveries models.WebhookDeliveryRepo, audit models.AuditRepo, endpoints []webhook.Endpoint, client *webhook.Client) *WebhookService {
+	s := &WebhookService{
+		deliveries: deliveries,
+		audit:      audit,
+		endpoints:  make(map[string]webhook.Endpoint, len(endpoints)),
+		client:     client,
+	}
+	for _, e := range endpoints {
+		s.endpoints[e.Name] = e
+		s.order = append(s.order, e.Name)
+	}
+	return s
+}
+
+// Notify records a pending delivery of event to every webhook subscribed to it.
+// The deliveries are sent by the next dispatch run.
+func (s *WebhookService) Notify(ctx context.Context, event string, data interface{}) error {
+	payload, err := json.Marshal(webhook.Payload{Event: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	for _, name := range s.order {
+		e := s.endpoints[name]
+		if !e.Subscribed(event) {
+			continue
+		}
+		d := &models.WebhookDelivery{Webhook: e.Name, Event: event, URL: e.URL, Payload: payload}
+		if err := s.deliveries.Create(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleEvent is the outbox subscriber notifying validator status changes. A
+// move to slashed or exited is also sent as its own event. The outbox event id
+// is included so receivers can drop the duplicates at-least-once delivery allows.
+func (s *WebhookService) HandleEvent(ctx context.Context, e models.OutboxEvent) error {
+	if e.Type != models.EventValidatorStatusChanged {
+		return nil
+	}
+	var change models.StatusChange
+	if err := json.Unmarshal(e.Payload, &change); err != nil {
+		return fmt.Errorf("failed to decode status change: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"event_id": e.ID,
+		"pubkey":   change.Pubkey,
+		"network":  change.Network,
+		"from":     change.From,
+		"to":       change.To,
+	}
+	if err := s.Notify(ctx, webhook.EventStatusChanged, data); err != nil {
+		return err
+	}
+	switch change.To {
+	case models.StatusSlashed:
+		return s.Notify(ctx, webhook.EventSlashed, data)
+	case models.StatusExited:
+		return s.Notify(ctx, webhook.EventExited, data)
+	}
+	return nil
+}
+
+// DoubleLoaded notifies a key found loaded on more than one validator client instance
+func (s *WebhookService) DoubleLoaded(ctx context.Context, k models.DoubleLoadedKey) error {
+	return s.Notify(ctx, webhook.EventDoubleLoaded, k)
+}
+
+// SyncFailed returns a completion hook that notifies every failed run of the named background sync
+func (s *WebhookService) SyncFailed(source string) func(ctx context.Context, err error) {
+	return func(ctx context.Context, err error) {
+		if err == nil {
+			return
+		}
+		data := map[string]string{"source": source, "error": err.Error()}
+		if err := s.Notify(ctx, webhook.EventSyncFailed, data); err != nil {
+			log.Printf("Failed to record %s sync failure webhook: %v", source, err)
+		}
+	}
+}
+
+// Dispatch claims and attempts every delivery that is due. A failed attempt is
+// scheduled again with backoff until webhook.MaxAttempts is reached.
+func (s *WebhookService) Dispatch(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.deliveries.ClaimDue(ctx, now, now.Add(webhookClaimLease), webhookDispatchBatch)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		if err := s.deliver(ctx, &due[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start dispatches due deliveries immediately and then on every interval until ctx is done
+func (s *WebhookService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Dispatch(ctx); err != nil {
+			log.Printf("Webhook dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListDeliveries returns the deliveries matching the provided filters, newest first
+func (s *WebhookService) ListDeliveries(ctx context.Context, filters map[string]interface{}) ([]models.WebhookDelivery, error) {
+	return s.deliveries.List(ctx, filters)
+}
+
+// GetDelivery retrieves a delivery by its ID
+func (s *WebhookService) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	return s.deliveries.Get(ctx, id)
+}
+
+// Redeliver sends the payload of a delivery again as a new delivery, which is
+// attempted immediately and retried like any other if it fails. The new delivery
+// is created already claimed, so a concurrent dispatch run does not send it too.
+// The original delivery is left unchanged.
+func (s *WebhookService) Redeliver(ctx context.Context, id int64, sourceIP string) (*models.WebhookDelivery, error) {
+	original, err := s.deliveries.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := s.endpoints[original.Webhook]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookNotConfigured, original.Webhook)
+	}
+
+	lease := time.Now().Add(webhookClaimLease)
+	d := &models.WebhookDelivery{
+		Webhook:       original.Webhook,
+		Event:         original.Event,
+		URL:           e.URL,
+		Payload:       original.Payload,
+		RedeliveryOf:  &original.ID,
+		NextAttemptAt: &lease,
+	}
+	if err := s.deliveries.Create(ctx, d); err != nil {
+		return nil, err
+	}
+	if err := s.deliver(ctx, d); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Webhook delivery %d redelivered to %s as delivery %d: %s", original.ID, d.Webhook, d.ID, d.Status)
+	if err := s.audit.Record(ctx, &models.AuditLog{Action: "webhook.redelivered", SourceIP: sourceIP, Details: details}); err != nil {
+		return nil, fmt.Errorf("failed to record webhook redelivery: %w", err)
+	}
+	return d, nil
+}
+
+// deliver makes one attempt at a delivery and stores its outcome
+func (s *WebhookService) deliver(ctx context.Context, d *models.WebhookDelivery) error {
+	d.Attempts++
+	d.ResponseCode, d.LastError, d.NextAttemptAt = nil, "", nil
+
+	e, ok := s.endpoints[d.Webhook]
+	if !ok {
+		d.Status, d.LastError = models.WebhookDeliveryFailed, ErrWebhookNotConfigured.Error()
+		return s.deliveries.UpdateAttempt(ctx, d)
+	}
+
+	d.URL = e.URL
+	code, err := s.client.Send(ctx, e, d.ID, d.Event, d.Payload)
+	if code != 0 {
+		d.ResponseCode = &code
+	}
+	now := time.Now()
+	switch {
+	case err == nil:
+		d.Status, d.DeliveredAt = models.WebhookDeliveryDelivered, &now
+	case d.Attempts >= webhook.MaxAttempts:
+		d.Status, d.LastError = models.WebhookDeliveryFailed, err.Error()
+	default:
+		next := now.Add(webhook.Backoff(d.Attempts))
+		d.Status, d.LastError, d.NextAttemptAt = models.WebhookDeliveryPending, err.Error(), &next
+	}
+	return s.deliveries.UpdateAttempt(ctx, d)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+	"github.com/zheli/validator-key-manager-backend/pkg/webhook"
+)
+
+func TestWebhookService_HandleEvent(t *testing.T) {
+	endpoints := []webhook.Endpoint{
+		{Name: "all", URL: "https://all.example", Secret: "s", Events: []string{webhook.EventStatusChanged, webhook.EventSlashed}},
+		{Name: "exits", URL: "https://exits.example", Secret: "s", Events: []string{webhook.EventExited}},
+	}
+
+	tests := []struct {
+		name     string
+		status   models.Status
+		expected []string
+	}{
+		{name: "status change", status: models.StatusExiting, expected: []string{"all:" + webhook.EventStatusChanged}},
+		{name: "slashed", status: models.StatusSlashed, expected: []string{"all:" + webhook.EventStatusChanged, "all:" + webhook.EventSlashed}},
+		{name: "exited", status: models.StatusExited, expected: []string{"all:" + webhook.EventStatusChanged, "exits:" + webhook.EventExited}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var created []string
+			mockDeliveries := mocks.NewMockWebhookDeliveryRepo(ctrl)
+			mockDeliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d *models.WebhookDelivery) error {
+				var payload struct {
+					Event string                 `json:"event"`
+					Data  map[string]interface{} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(d.Payload, &payload))
+				assert.Equal(t, d.Event, payload.Event)
+				assert.Equal(t, float64(42), payload.Data["event_id"])
+				assert.Equal(t, "active", payload.Data["from"])
+				assert.Equal(t, string(tt.status), payload.Data["to"])
+				created = append(created, d.Webhook+":"+d.Event)
+				return nil
+			}).AnyTimes()
+
+			service := NewWebhookService(mockDeliveries, nil, endpoints, webhook.NewClient())
+			change, err := json.Marshal(models.StatusChange{Pubkey: testKey1, Network: "mainnet", From: models.StatusActive, To: tt.status})
+			require.NoError(t, err)
+			e := models.OutboxEvent{ID: 42, Type: models.EventValidatorStatusChanged, Pubkey: testKey1, Payload: change}
+			require.NoError(t, service.HandleEvent(context.Background(), e))
+			assert.Equal(t, tt.expected, created)
+
+			// Other events are not sent to webhooks
+			require.NoError(t, service.HandleEvent(context.Background(), models.OutboxEvent{ID: 43, Type: models.EventValidatorCreated}))
+			assert.Equal(t, tt.expected, created)
+		})
+	}
+}
+
+func TestWebhookService_Dispatch(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	endpoints := []webhook.Endpoint{{Name: "ops", URL: srv.URL, Secret: "s", Events: webhook.Events}}
+
+	tests := []struct {
+		name           string
+		status         int
+		delivery       models.WebhookDelivery
+		expectedStatus string
+		expectRetry    bool
+	}{
+		{
+			name:           "delivered",
+			status:         http.StatusOK,
+			delivery:       models.WebhookDelivery{ID: 1, Webhook: "ops", Event: webhook.EventSyncFailed},
+			expectedStatus: models.WebhookDeliveryDelivered,
+		},
+		{
+			name:           "failed attempt is retried",
+			status:         http.StatusInternalServerError,
+			delivery:       models.WebhookDelivery{ID: 2, Webhook: "ops", Event: webhook.EventSyncFailed, Attempts: 1},
+			expectedStatus: models.WebhookDeliveryPending,
+			expectRetry:    true,
+		},
+		{
+			name:           "last attempt gives up",
+			status:         http.StatusInternalServerError,
+			delivery:       models.WebhookDelivery{ID: 3, Webhook: "ops", Event: webhook.EventSyncFailed, Attempts: webhook.MaxAttempts - 1},
+			expectedStatus: models.WebhookDeliveryFailed,
+		},
+		{
+			name:           "removed webhook fails",
+			delivery:       models.WebhookDelivery{ID: 4, Webhook: "gone", Event: webhook.EventSyncFailed},
+			expectedStatus: models.WebhookDeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			status = tt.status
+			tt.delivery.Payload = json.RawMessage(`{}`)
+			attempts := tt.delivery.Attempts
+
+			mockDeliveries := mocks.NewMockWebhookDeliveryRepo(ctrl)
+			mockDeliveries.EXPECT().ClaimDue(gomock.Any(), gomock.Any(), gomock.Any(), webhookDispatchBatch).
+				DoAndReturn(func(_ context.Context, now, leaseUntil time.Time, _ int) ([]models.WebhookDelivery, error) {
+					assert.Equal(t, now.Add(webhookClaimLease), leaseUntil)
+					return []models.WebhookDelivery{tt.delivery}, nil
+				})
+			mockDeliveries.EXPECT().UpdateAttempt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d *models.WebhookDelivery) error {
+				assert.Equal(t, tt.expectedStatus, d.Status)
+				assert.Equal(t, attempts+1, d.Attempts)
+				assert.Equal(t, tt.expectRetry, d.NextAttemptAt != nil)
+				assert.Equal(t, tt.expectedStatus == models.WebhookDeliveryDelivered, d.DeliveredAt != nil)
+				if tt.expectedStatus != models.WebhookDeliveryDelivered {
+					assert.NotEmpty(t, d.LastError)
+				}
+				if tt.expectRetry {
+					assert.WithinDuration(t, time.Now().Add(webhook.Backoff(d.Attempts)), *d.NextAttemptAt, 5*time.Second)
+				}
+				return nil
+			})
+
+			service := NewWebhookService(mockDeliveries, nil, endpoints, webhook.NewClient())
+			assert.NoError(t, service.Dispatch(context.Background()))
+		})
+	}
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeliveries := mocks.NewMockWebhookDeliveryRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	endpoints := []webhook.Endpoint{{Name: "ops", URL: srv.URL, Secret: "s", Events: webhook.Events}}
+	service := NewWebhookService(mockDeliveries, mockAudit, endpoints, webhook.NewClient())
+	ctx := context.Background()
+
+	original := &models.WebhookDelivery{ID: 5, Webhook: "ops", Event: webhook.EventSlashed, URL: "https://old.example",
+		Payload: json.RawMessage(`{"event":"validator.slashed"}`), Status: models.WebhookDeliveryFailed, Attempts: webhook.MaxAttempts}
+	mockDeliveries.EXPECT().Get(ctx, int64(5)).Return(original, nil)
+	mockDeliveries.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *models.WebhookDelivery) error {
+		assert.Equal(t, int64(5), *d.RedeliveryOf)
+		assert.Equal(t, srv.URL, d.URL)
+		assert.JSONEq(t, string(original.Payload), string(d.Payload))
+		// It is created claimed so no dispatch run sends it while it is attempted here
+		require.NotNil(t, d.NextAttemptAt)
+		assert.WithinDuration(t, time.Now().Add(webhookClaimLease), *d.NextAttemptAt, 5*time.Second)
+		d.ID, d.Status = 6, models.WebhookDeliveryPending
+		return nil
+	})
+	mockDeliveries.EXPECT().UpdateAttempt(ctx, gomock.Any()).Return(nil)
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, log *models.AuditLog) error {
+		assert.Equal(t, "webhook.redelivered", log.Action)
+		assert.Equal(t, "10.0.0.1", log.SourceIP)
+		assert.Contains(t, log.Details, "delivery 5 redelivered to ops as delivery 6: delivered")
+		return nil
+	})
+
+	d, err := service.Redeliver(ctx, 5, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryDelivered, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, models.WebhookDeliveryFailed, original.Status)
+
+	// A webhook removed from the configuration cannot be redelivered to
+	mockDeliveries.EXPECT().Get(ctx, int64(7)).Return(&models.WebhookDelivery{ID: 7, Webhook: "gone"}, nil)
+	_, err = service.Redeliver(ctx, 7, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrWebhookNotConfigured)
+}
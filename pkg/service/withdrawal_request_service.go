@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// WithdrawalRequestService detects exits and partial withdrawals of our validators
+// that were not initiated through this service. Since Pectra (EIP-7002) anyone
+// controlling a withdrawal address can trigger them from the execution layer.
+type WithdrawalRequestService struct {
+	validators *ValidatorService
+	requests   models.WithdrawalRequestRepo
+	alerts     models.AlertRepo
+	audit      models.AuditRepo
+	nodes      beacon.Nodes
+}
+
+// NewWithdrawalRequestService creates a new execution layer request detection service
+func NewWithdrawalRequestService(validators *ValidatorService, requests models.WithdrawalRequestRepo, alerts models.AlertRepo,
+	audit models.AuditRepo, nodes beacon.Nodes) *WithdrawalRequestService {
+	return &WithdrawalRequestService{validators: validators, requests: requests, alerts: alerts, audit: audit, nodes: nodes}
+}
+
+// Check flags active validators the beacon chain has scheduled to exit, which
+// approved exit requests would already have moved to exiting, and compounding
+// validators with a partial withdrawal in the beacon state's pending queue. It
+// relies on the beacon metadata being up to date, so it runs after each beacon sync.
+func (s *WithdrawalRequestService) Check(ctx context.Context) error {
+	active, err := s.validators.ListValidators(ctx, map[string]interface{}{"status": string(models.StatusActive)})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	compounding := map[[2]string]map[int64]models.Validator{}
+	for _, v := range active {
+		// Slashing sets the exit epoch too, but is reported by the slashing checks
+		if v.ExitEpoch != nil && !v.Slashed {
+			if err := s.flagExit(ctx, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", v.Pubkey, err))
+			}
+			continue
+		}
+		// Only validators with 0x02 credentials can withdraw part of their balance on request
+		if v.ValidatorIndex == nil || models.WithdrawalCredentialsType(v.WithdrawalCredentials) != models.WithdrawalCredentialsCompounding {
+			continue
+		}
+		chain := [2]string{v.Blockchain, v.BlockchainNetwork}
+		if compounding[chain] == nil {
+			compounding[chain] = map[int64]models.Validator{}
+		}
+		compounding[chain][*v.ValidatorIndex] = v
+	}
+
+	for chain, byIndex := range compounding {
+		if err := s.checkPartialWithdrawals(ctx, chain[0], chain[1], byIndex); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", chain[0], chain[1], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flagExit records and alerts on an exit that was not requested through this service
+func (s *WithdrawalRequestService) flagExit(ctx context.Context, v models.Validator) error {
+	req := &models.WithdrawalRequest{
+		Pubkey:        v.Pubkey,
+		Epoch:         *v.ExitEpoch,
+		SourceAddress: models.WithdrawalAddress(v.WithdrawalCredentials),
+	}
+	if v.ValidatorIndex != nil {
+		req.ValidatorIndex = *v.ValidatorIndex
+	}
+
+	message := fmt.Sprintf("validator %s is exiting at epoch %d without an approved exit request", v.Pubkey, req.Epoch)
+	if req.SourceAddress != "" {
+		message += fmt.Sprintf("; it was triggered from withdrawal address %s or signed with the validator key", req.SourceAddress)
+	} else {
+		message += "; it was signed with the validator key"
+	}
+	if err := s.flag(ctx, req, models.AlertTypeUnexpectedExit, models.SeverityCritical, message); err != nil {
+		return err
+	}
+
+	// Keep the lifecycle in line with the chain so the exit is tracked like any other
+	return s.validators.UpdateValidatorStatus(ctx, v.Pubkey, models.StatusExiting)
+}
+
+// checkPartialWithdrawals flags the queued partial withdrawals of the given validators on one network
+func (s *WithdrawalRequestService) checkPartialWithdrawals(ctx context.Context, blockchain, network string, byIndex map[int64]models.Validator) error {
+	node, ok := s.nodes.Get(blockchain, network)
+	if !ok {
+		return nil
+	}
+	pending, err := node.PendingPartialWithdrawals(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		index, err := strconv.ParseInt(p.ValidatorIndex, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid validator index %q: %w", p.ValidatorIndex, err)
+		}
+		v, ok := byIndex[index]
+		if !ok {
+			continue
+		}
+		amount, err := strconv.ParseInt(p.Amount, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q: %w", p.Amount, err)
+		}
+		epoch, err := strconv.ParseInt(p.WithdrawableEpoch, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid withdrawable epoch %q: %w", p.WithdrawableEpoch, err)
+		}
+
+		req := &models.WithdrawalRequest{
+			Pubkey:         v.Pubkey,
+			ValidatorIndex: index,
+			Amount:         amount,
+			Epoch:          epoch,
+			SourceAddress:  models.WithdrawalAddress(v.WithdrawalCredentials),
+		}
+		message := fmt.Sprintf("withdrawal address %s requested a partial withdrawal of %d gwei from validator %s, withdrawable at epoch %d",
+			req.SourceAddress, amount, v.Pubkey, epoch)
+		if err := s.flag(ctx, req, models.AlertTypeELWithdrawal, models.SeverityWarning, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flag records a request and, the first time it is seen, raises an alert for it
+func (s *WithdrawalRequestService) flag(ctx context.Context, req *models.WithdrawalRequest, alertType, severity, message string) error {
+	created, err := s.requests.Record(ctx, req)
+	if err != nil || !created {
+		return err
+	}
+
+	if _, err := s.alerts.Raise(ctx, &models.Alert{Type: alertType, Severity: severity, Pubkey: req.Pubkey, Message: message}); err != nil {
+		return err
+	}
+	log.Printf("%s: %s", strings.ToUpper(severity), message)
+	return s.audit.Record(ctx, &models.AuditLog{Action: "alert." + alertType, Details: message})
+}
+
+// List returns the detected requests of a validator, or of all validators if pubkey is empty
+func (s *WithdrawalRequestService) List(ctx context.Context, pubkey string) ([]models.WithdrawalRequest, error) {
+	return s.requests.List(ctx, pubkey)
+}
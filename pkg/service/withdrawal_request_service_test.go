@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/beacon"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestWithdrawalRequestService_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/beacon/states/head/pending_partial_withdrawals", r.URL.Path)
+		w.Write([]byte(`{"data":[
+			{"validator_index":"3","amount":"1000000000","withdrawable_epoch":"364000"},
+			{"validator_index":"99","amount":"5","withdrawable_epoch":"364000"}]}`))
+	}))
+	defer srv.Close()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockRequests := mocks.NewMockWithdrawalRequestRepo(ctrl)
+	mockAlerts := mocks.NewMockAlertRepo(ctrl)
+	mockAudit := mocks.NewMockAuditRepo(ctrl)
+	nodes := beacon.NewNodes(&beacon.Config{Nodes: []beacon.NodeConfig{{Blockchain: "ethereum", Network: "mainnet", URL: srv.URL}}})
+	service := NewWithdrawalRequestService(NewValidatorService(mockValidators, nil), mockRequests, mockAlerts, mockAudit, nodes)
+	ctx := context.Background()
+
+	address := strings.Repeat("d8", 20)
+	index1, index2, index3, exitEpoch := int64(1), int64(2), int64(3), int64(300)
+	exiting := models.Validator{
+		Pubkey: "0xaa", Status: models.StatusActive, ValidatorIndex: &index1, ExitEpoch: &exitEpoch,
+		WithdrawalCredentials: "0x010000000000000000000000" + address,
+	}
+	slashed := models.Validator{Pubkey: "0xbb", Status: models.StatusActive, ValidatorIndex: &index2, ExitEpoch: &exitEpoch, Slashed: true}
+	compounding := models.Validator{
+		Pubkey: "0xcc", Status: models.StatusActive, ValidatorIndex: &index3, Blockchain: "ethereum", BlockchainNetwork: "mainnet",
+		WithdrawalCredentials: "0x020000000000000000000000" + address,
+	}
+
+	mockValidators.EXPECT().List(ctx, map[string]interface{}{"status": "active"}).
+		Return([]models.Validator{exiting, slashed, compounding}, nil)
+
+	// The exit is flagged critical and the validator moved to exiting
+	mockRequests.EXPECT().Record(ctx, &models.WithdrawalRequest{Pubkey: "0xaa", ValidatorIndex: 1, Epoch: 300, SourceAddress: "0x" + address}).
+		Return(true, nil)
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+		assert.Equal(t, models.AlertTypeUnexpectedExit, a.Type)
+		assert.Equal(t, models.SeverityCritical, a.Severity)
+		assert.Contains(t, a.Message, "triggered from withdrawal address 0x"+address)
+		return true, nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).Return(nil)
+
+	// The queued partial withdrawal is flagged as a warning; other validators' entries are ignored
+	mockRequests.EXPECT().Record(ctx, &models.WithdrawalRequest{
+		Pubkey: "0xcc", ValidatorIndex: 3, Amount: 1000000000, Epoch: 364000, SourceAddress: "0x" + address,
+	}).Return(true, nil)
+	mockAlerts.EXPECT().Raise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *models.Alert) (bool, error) {
+		assert.Equal(t, models.AlertTypeELWithdrawal, a.Type)
+		assert.Equal(t, models.SeverityWarning, a.Severity)
+		return true, nil
+	})
+	mockAudit.EXPECT().Record(ctx, gomock.Any()).Return(nil)
+
+	require.NoError(t, service.Check(ctx))
+}
+
+func TestWithdrawalRequestService_CheckAlreadyFlagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidators := mocks.NewMockValidatorRepo(ctrl)
+	mockRequests := mocks.NewMockWithdrawalRequestRepo(ctrl)
+	service := NewWithdrawalRequestService(NewValidatorService(mockValidators, nil), mockRequests,
+		mocks.NewMockAlertRepo(ctrl), mocks.NewMockAuditRepo(ctrl), beacon.Nodes{})
+	ctx := context.Background()
+
+	exitEpoch := int64(300)
+	v := models.Validator{Pubkey: "0xaa", Status: models.StatusActive, ExitEpoch: &exitEpoch}
+	mockValidators.EXPECT().List(ctx, gomock.Any()).Return([]models.Validator{v}, nil)
+	mockRequests.EXPECT().Record(ctx, gomock.Any()).Return(false, nil)
+	mockValidators.EXPECT().TransitionStatus(ctx, "0xaa", models.StatusExiting).Return(nil)
+
+	require.NoError(t, service.Check(ctx))
+}
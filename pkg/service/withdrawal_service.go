@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+// credentialTypeOrder is the order report groups are listed in, most urgent to rotate first
+var credentialTypeOrder = map[string]int{
+	models.WithdrawalCredentialsBLS:         0,
+	models.WithdrawalCredentialsUnknown:     1,
+	models.WithdrawalCredentialsExecution:   2,
+	models.WithdrawalCredentialsCompounding: 3,
+}
+
+// WithdrawalCredentialsGroup lists the keys sharing a credential type and withdrawal address
+type WithdrawalCredentialsGroup struct {
+	Type              string   `json:"type"`
+	WithdrawalAddress string   `json:"withdrawal_address,omitempty"`
+	Count             int      `json:"count"`
+	Pubkeys           []string `json:"pubkeys"`
+}
+
+// WithdrawalCredentialsReport summarises the withdrawal credentials of our keys
+type WithdrawalCredentialsReport struct {
+	Totals map[string]int               `json:"totals"`
+	Groups []WithdrawalCredentialsGroup `json:"groups"`
+}
+
+// WithdrawalService reports on validator withdrawal credentials
+type WithdrawalService struct {
+	repo models.ValidatorRepo
+}
+
+// NewWithdrawalService creates a new withdrawal credentials service
+func NewWithdrawalService(repo models.ValidatorRepo) *WithdrawalService {
+	return &WithdrawalService{repo: repo}
+}
+
+// Report groups the validators matching filters by credential type and withdrawal address
+func (s *WithdrawalService) Report(ctx context.Context, filters map[string]interface{}) (*WithdrawalCredentialsReport, error) {
+	validators, err := s.repo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WithdrawalCredentialsReport{
+		Totals: map[string]int{
+			models.WithdrawalCredentialsBLS:         0,
+			models.WithdrawalCredentialsExecution:   0,
+			models.WithdrawalCredentialsCompounding: 0,
+			models.WithdrawalCredentialsUnknown:     0,
+		},
+		Groups: []WithdrawalCredentialsGroup{},
+	}
+
+	groups := make(map[[2]string]*WithdrawalCredentialsGroup)
+	for _, v := range validators {
+		credsType := models.WithdrawalCredentialsType(v.WithdrawalCredentials)
+		address := models.WithdrawalAddress(v.WithdrawalCredentials)
+		report.Totals[credsType]++
+
+		key := [2]string{credsType, address}
+		g, ok := groups[key]
+		if !ok {
+			g = &WithdrawalCredentialsGroup{Type: credsType, WithdrawalAddress: address}
+			groups[key] = g
+		}
+		g.Count++
+		g.Pubkeys = append(g.Pubkeys, v.Pubkey)
+	}
+
+	for _, g := range groups {
+		sort.Strings(g.Pubkeys)
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Type != b.Type {
+			return credentialTypeOrder[a.Type] < credentialTypeOrder[b.Type]
+		}
+		return a.WithdrawalAddress < b.WithdrawalAddress
+	})
+
+	return report, nil
+}
+
+// History returns the withdrawal credential changes recorded for a validator
+func (s *WithdrawalService) History(ctx context.Context, pubkey string) ([]models.WithdrawalCredentialChange, error) {
+	return s.repo.ListCredentialChanges(ctx, pubkey)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zheli/validator-key-manager-backend/pkg/mocks"
+	"github.com/zheli/validator-key-manager-backend/pkg/models"
+)
+
+func TestWithdrawalService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockValidatorRepo(ctrl)
+	service := NewWithdrawalService(mockRepo)
+	ctx := context.Background()
+
+	bls := "0x00" + strings.Repeat("ab", 31)
+	exec1 := "0x010000000000000000000000" + strings.Repeat("11", 20)
+	exec2 := "0x010000000000000000000000" + strings.Repeat("22", 20)
+	compounding1 := "0x020000000000000000000000" + strings.Repeat("11", 20)
+
+	filters := map[string]interface{}{"blockchain_network": "mainnet"}
+	mockRepo.EXPECT().List(ctx, filters).Return([]models.Validator{
+		{Pubkey: "0xd", WithdrawalCredentials: exec2},
+		{Pubkey: "0xc", WithdrawalCredentials: exec1},
+		{Pubkey: "0xb", WithdrawalCredentials: compounding1},
+		{Pubkey: "0xa", WithdrawalCredentials: exec1},
+		{Pubkey: "0xe", WithdrawalCredentials: bls},
+		{Pubkey: "0xf"},
+	}, nil)
+
+	report, err := service.Report(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		models.WithdrawalCredentialsBLS:         1,
+		models.WithdrawalCredentialsExecution:   3,
+		models.WithdrawalCredentialsCompounding: 1,
+		models.WithdrawalCredentialsUnknown:     1,
+	}, report.Totals)
+	assert.Equal(t, []WithdrawalCredentialsGroup{
+		{Type: models.WithdrawalCredentialsBLS, Count: 1, Pubkeys: []string{"0xe"}},
+		{Type: models.WithdrawalCredentialsUnknown, Count: 1, Pubkeys: []string{"0xf"}},
+		{Type: models.WithdrawalCredentialsExecution, WithdrawalAddress: "0x" + strings.Repeat("11", 20), Count: 2,
+			Pubkeys: []string{"0xa", "0xc"}},
+		{Type: models.WithdrawalCredentialsExecution, WithdrawalAddress: "0x" + strings.Repeat("22", 20), Count: 1,
+			Pubkeys: []string{"0xd"}},
+		{Type: models.WithdrawalCredentialsCompounding, WithdrawalAddress: "0x" + strings.Repeat("11", 20), Count: 1,
+			Pubkeys: []string{"0xb"}},
+	}, report.Groups)
+}
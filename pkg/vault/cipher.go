@@ -0,0 +1,102 @@
+// Package vault encrypts sensitive material before it is written to the database
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeySize is the length of the AES-256 encryption key in bytes
+const KeySize = 32
+
+// ErrNotConfigured is returned by NewCipherFromEnv when no key is set
+var ErrNotConfigured = errors.New("VAULT_ENCRYPTION_KEY is not set")
+
+// Cipher seals and opens data with AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// NewCipherFromEnv creates a cipher from the hex-encoded VAULT_ENCRYPTION_KEY
+func NewCipherFromEnv() (*Cipher, error) {
+	v := os.Getenv("VAULT_ENCRYPTION_KEY")
+	if v == "" {
+		return nil, ErrNotConfigured
+	}
+	key, err := hex.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAULT_ENCRYPTION_KEY: %w", err)
+	}
+	return NewCipher(key)
+}
+
+// Seal encrypts plaintext, binding it to associatedData. The nonce is prepended to the result.
+func (c *Cipher) Seal(plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+// Open decrypts data produced by Seal with the same associatedData
+func (c *Cipher) Open(sealed, associatedData []byte) ([]byte, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, errors.New("sealed data is too short")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NewDataKey creates a cipher with a fresh random key for one record and returns
+// it with the key sealed by c, bound to associatedData. Only the sealed key is
+// stored next to the record, so data sealed with it stays readable as long as c is.
+func (c *Cipher) NewDataKey(associatedData []byte) (*Cipher, []byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	dataKey, err := NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	sealed, err := c.Seal(key, associatedData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, sealed, nil
+}
+
+// OpenDataKey returns the cipher of a data key sealed by NewDataKey
+func (c *Cipher) OpenDataKey(sealed, associatedData []byte) (*Cipher, error) {
+	key, err := c.Open(sealed, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data key: %w", err)
+	}
+	return NewCipher(key)
+}
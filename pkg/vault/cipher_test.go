@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_SealOpen(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{7}, KeySize))
+	require.NoError(t, err)
+
+	sealed, err := c.Seal([]byte("signed message"), []byte("0xaa"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "signed message")
+
+	plaintext, err := c.Open(sealed, []byte("0xaa"))
+	require.NoError(t, err)
+	assert.Equal(t, "signed message", string(plaintext))
+
+	// Ciphertext moved to another row does not decrypt
+	_, err = c.Open(sealed, []byte("0xbb"))
+	assert.Error(t, err)
+
+	_, err = c.Open(sealed[:4], []byte("0xaa"))
+	assert.Error(t, err)
+}
+
+func TestNewCipherFromEnv(t *testing.T) {
+	t.Setenv("VAULT_ENCRYPTION_KEY", "")
+	_, err := NewCipherFromEnv()
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	t.Setenv("VAULT_ENCRYPTION_KEY", strings.Repeat("ab", 16))
+	_, err = NewCipherFromEnv()
+	assert.ErrorContains(t, err, "must be 32 bytes")
+
+	t.Setenv("VAULT_ENCRYPTION_KEY", strings.Repeat("ab", KeySize))
+	_, err = NewCipherFromEnv()
+	assert.NoError(t, err)
+}
+
+func TestCipher_DataKey(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{7}, KeySize))
+	require.NoError(t, err)
+
+	dataKey, sealedKey, err := c.NewDataKey([]byte("0xaa"))
+	require.NoError(t, err)
+	sealed, err := dataKey.Seal([]byte("keystore"), []byte("0xaa"))
+	require.NoError(t, err)
+
+	opened, err := c.OpenDataKey(sealedKey, []byte("0xaa"))
+	require.NoError(t, err)
+	plaintext, err := opened.Open(sealed, []byte("0xaa"))
+	require.NoError(t, err)
+	assert.Equal(t, "keystore", string(plaintext))
+
+	// The master key cannot open the data directly
+	_, err = c.Open(sealed, []byte("0xaa"))
+	assert.Error(t, err)
+
+	_, err = c.OpenDataKey(sealedKey, []byte("0xbb"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,323 @@
+package vclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// APIError is a non-200 response from a keymanager API
+type APIError struct {
+	Instance   string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("instance %q: %s returned status %d: %s", e.Instance, e.Path, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("instance %q: %s returned status %d", e.Instance, e.Path, e.StatusCode)
+}
+
+type keystoreResponse struct {
+	Data []struct {
+		ValidatingPubkey string `json:"validating_pubkey"`
+		DerivationPath   string `json:"derivation_path,omitempty"`
+		Readonly         bool   `json:"readonly"`
+	} `json:"data"`
+}
+
+// keystoreStatus is the per-key outcome of a keystore import or deletion
+type keystoreStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type importKeystoresRequest struct {
+	Keystores          []string `json:"keystores"`
+	Passwords          []string `json:"passwords"`
+	SlashingProtection string   `json:"slashing_protection,omitempty"`
+}
+
+type deleteKeystoresRequest struct {
+	Pubkeys []string `json:"pubkeys"`
+}
+
+type deleteKeystoresResponse struct {
+	Data               []keystoreStatus `json:"data"`
+	SlashingProtection string           `json:"slashing_protection"`
+}
+
+type remoteKey struct {
+	Pubkey string `json:"pubkey"`
+	URL    string `json:"url"`
+}
+
+type importRemoteKeysRequest struct {
+	RemoteKeys []remoteKey `json:"remote_keys"`
+}
+
+type remoteKeyResponse struct {
+	Data []struct {
+		Pubkey   string `json:"pubkey"`
+		URL      string `json:"url"`
+		Readonly bool   `json:"readonly"`
+	} `json:"data"`
+}
+
+// keymanagerClient implements the standard Ethereum keymanager API shared by all clients.
+// Adapters wrap it to add their client-specific quirks.
+type keymanagerClient struct {
+	name       string
+	clientType string
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newKeymanagerClient(clientType string, cfg Config) (*keymanagerClient, error) {
+	token, err := cfg.bearerToken()
+	if err != nil {
+		return nil, fmt.Errorf("instance %q: %w", cfg.Name, err)
+	}
+	return newKeymanagerClientWithToken(clientType, cfg, token)
+}
+
+func newKeymanagerClientWithToken(clientType string, cfg Config, token string) (*keymanagerClient, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("instance %q: failed to read CA file: %w", cfg.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("instance %q: no certificates found in CA file", cfg.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &keymanagerClient{
+		name:       cfg.Name,
+		clientType: clientType,
+		baseURL:    strings.TrimSuffix(cfg.URL, "/"),
+		token:      token,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Name returns the configured instance name
+func (c *keymanagerClient) Name() string {
+	return c.name
+}
+
+// Type returns the client implementation
+func (c *keymanagerClient) Type() string {
+	return c.clientType
+}
+
+// ListKeys returns both local keystores and remote-signer keys
+func (c *keymanagerClient) ListKeys(ctx context.Context) ([]Key, error) {
+	local, err := c.listKeystores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := c.listRemoteKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(local, remote...), nil
+}
+
+func (c *keymanagerClient) listKeystores(ctx context.Context) ([]Key, error) {
+	var resp keystoreResponse
+	if err := c.do(ctx, http.MethodGet, "/eth/v1/keystores", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(resp.Data))
+	for _, k := range resp.Data {
+		keys = append(keys, Key{Pubkey: NormalizePubkey(k.ValidatingPubkey), Readonly: k.Readonly})
+	}
+	return keys, nil
+}
+
+func (c *keymanagerClient) listRemoteKeys(ctx context.Context) ([]Key, error) {
+	var resp remoteKeyResponse
+	if err := c.do(ctx, http.MethodGet, "/eth/v1/remotekeys", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(resp.Data))
+	for _, k := range resp.Data {
+		keys = append(keys, Key{Pubkey: NormalizePubkey(k.Pubkey), Remote: true, SignerURL: k.URL, Readonly: k.Readonly})
+	}
+	return keys, nil
+}
+
+// ImportKeystore loads an EIP-2335 keystore together with the slashing protection
+// interchange to apply before it signs. Importing a key the client already has
+// loaded succeeds, so an interrupted import can be retried.
+func (c *keymanagerClient) ImportKeystore(ctx context.Context, keystore, password string, slashingProtection []byte) error {
+	body := importKeystoresRequest{
+		Keystores:          []string{keystore},
+		Passwords:          []string{password},
+		SlashingProtection: string(slashingProtection),
+	}
+	var resp struct {
+		Data []keystoreStatus `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/eth/v1/keystores", body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) != 1 {
+		return fmt.Errorf("instance %q: expected 1 import status, got %d", c.name, len(resp.Data))
+	}
+
+	switch s := resp.Data[0]; s.Status {
+	case "imported", "duplicate":
+		return nil
+	default:
+		return fmt.Errorf("instance %q: %w: import %s: %s", c.name, ErrRejected, s.Status, s.Message)
+	}
+}
+
+// DeleteKeystore removes a local keystore and returns the client's slashing
+// protection interchange for it. Deleting a key that is no longer loaded
+// still returns its slashing protection, so an interrupted deletion can be
+// retried. It returns ErrKeyNotFound if the client has no record of the key.
+func (c *keymanagerClient) DeleteKeystore(ctx context.Context, pubkey string) ([]byte, error) {
+	var resp deleteKeystoresResponse
+	if err := c.do(ctx, http.MethodDelete, "/eth/v1/keystores", deleteKeystoresRequest{Pubkeys: []string{pubkey}}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != 1 {
+		return nil, fmt.Errorf("instance %q: expected 1 deletion status, got %d", c.name, len(resp.Data))
+	}
+
+	switch s := resp.Data[0]; s.Status {
+	case "deleted", "not_active":
+		return []byte(resp.SlashingProtection), nil
+	case "not_found":
+		return nil, fmt.Errorf("instance %q: %w: %s", c.name, ErrKeyNotFound, pubkey)
+	default:
+		return nil, fmt.Errorf("instance %q: %w: deletion %s: %s", c.name, ErrRejected, s.Status, s.Message)
+	}
+}
+
+// ImportRemoteKey makes the client sign for pubkey through the remote signer at
+// signerURL. Registering a key the client already has succeeds, so an interrupted
+// registration can be retried.
+func (c *keymanagerClient) ImportRemoteKey(ctx context.Context, pubkey, signerURL string) error {
+	body := importRemoteKeysRequest{RemoteKeys: []remoteKey{{Pubkey: pubkey, URL: signerURL}}}
+	var resp struct {
+		Data []keystoreStatus `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/eth/v1/remotekeys", body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) != 1 {
+		return fmt.Errorf("instance %q: expected 1 import status, got %d", c.name, len(resp.Data))
+	}
+
+	switch s := resp.Data[0]; s.Status {
+	case "imported", "duplicate":
+		return nil
+	default:
+		return fmt.Errorf("instance %q: %w: remote key import %s: %s", c.name, ErrRejected, s.Status, s.Message)
+	}
+}
+
+// DeleteRemoteKey removes a remote-signer key. It returns ErrKeyNotFound if the
+// client has no remote key for pubkey.
+func (c *keymanagerClient) DeleteRemoteKey(ctx context.Context, pubkey string) error {
+	var resp struct {
+		Data []keystoreStatus `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/eth/v1/remotekeys", deleteKeystoresRequest{Pubkeys: []string{pubkey}}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) != 1 {
+		return fmt.Errorf("instance %q: expected 1 deletion status, got %d", c.name, len(resp.Data))
+	}
+
+	switch s := resp.Data[0]; s.Status {
+	case "deleted":
+		return nil
+	case "not_found":
+		return fmt.Errorf("instance %q: %w: %s", c.name, ErrKeyNotFound, pubkey)
+	default:
+		return fmt.Errorf("instance %q: %w: remote key deletion %s: %s", c.name, ErrRejected, s.Status, s.Message)
+	}
+}
+
+// do sends a request with in, if not nil, as its JSON body and decodes the response into out
+func (c *keymanagerClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("instance %q: request to %s failed: %w", c.name, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Instance: c.name, Path: path, StatusCode: resp.StatusCode}
+		var body struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(data, &body) == nil {
+			apiErr.Message = body.Message
+		}
+		return apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("instance %q: failed to decode %s response: %w", c.name, path, err)
+	}
+
+	return nil
+}
+
+func (c *keymanagerClient) requireToken() error {
+	if c.token == "" {
+		return fmt.Errorf("instance %q: %s requires a keymanager API token", c.name, c.clientType)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package vclient
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKeymanagerHandler serves fixed keystores and remote keys behind bearer auth
+func newKeymanagerHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/keystores", func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[{"validating_pubkey":"0xAA","derivation_path":"m/12381/3600/0/0/0","readonly":false}]}`))
+	})
+	mux.HandleFunc("/eth/v1/remotekeys", func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[{"pubkey":"bb","url":"https://signer:9000","readonly":true}]}`))
+	})
+	return mux
+}
+
+// newTLSKeymanagerServer starts a TLS keymanager stub and writes its self-signed certificate to a CA file
+func newTLSKeymanagerServer(t *testing.T, token string) (*httptest.Server, string) {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(newKeymanagerHandler(token))
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+
+	return srv, caFile
+}
+
+func TestTeku_ListKeys(t *testing.T) {
+	srv, caFile := newTLSKeymanagerServer(t, "secret")
+
+	tokenFile := filepath.Join(t.TempDir(), "validator-api-bearer")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	client, err := New(Config{
+		Name:      "teku-1",
+		Client:    TypeTeku,
+		URL:       srv.URL,
+		TokenFile: tokenFile,
+		CAFile:    caFile,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "teku-1", client.Name())
+	assert.Equal(t, TypeTeku, client.Type())
+
+	keys, err := client.ListKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Key{
+		{Pubkey: "0xaa"},
+		{Pubkey: "0xbb", Remote: true, SignerURL: "https://signer:9000", Readonly: true},
+	}, keys)
+}
+
+func TestTeku_Errors(t *testing.T) {
+	srv, caFile := newTLSKeymanagerServer(t, "secret")
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			name: "untrusted certificate",
+			cfg:  Config{Name: "teku-1", Client: TypeTeku, URL: srv.URL, Token: "secret"},
+		},
+		{
+			name: "wrong token",
+			cfg:  Config{Name: "teku-1", Client: TypeTeku, URL: srv.URL, Token: "wrong", CAFile: caFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.cfg)
+			require.NoError(t, err)
+
+			_, err = client.ListKeys(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNew_InvalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	_, err := New(Config{Name: "teku-1", Client: TypeTeku, URL: "https://localhost", Token: "secret", CAFile: caFile})
+	assert.Error(t, err)
+}
+
+func TestKeymanager_ImportDeleteKeystore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/keystores", r.URL.Path)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.Method {
+		case http.MethodPost:
+			assert.Equal(t, []interface{}{"pw"}, body["passwords"])
+			assert.Equal(t, `{"data":[]}`, body["slashing_protection"])
+			w.Write([]byte(`{"data":[{"status":"duplicate"}]}`))
+		case http.MethodDelete:
+			switch body["pubkeys"].([]interface{})[0] {
+			case "0xaa":
+				w.Write([]byte(`{"data":[{"status":"not_active"}],"slashing_protection":"{\"data\":[]}"}`))
+			case "0xbb":
+				w.Write([]byte(`{"data":[{"status":"not_found"}]}`))
+			default:
+				w.Write([]byte(`{"data":[{"status":"error","message":"disk full"}]}`))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{Name: "lh-1", Client: TypeLighthouse, URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	km, ok := client.(KeyManager)
+	require.True(t, ok)
+	ctx := context.Background()
+
+	// A key the client already has loaded counts as imported
+	require.NoError(t, km.ImportKeystore(ctx, `{"pubkey":"aa"}`, "pw", []byte(`{"data":[]}`)))
+
+	// A key deleted earlier still returns its slashing protection
+	protection, err := km.DeleteKeystore(ctx, "0xaa")
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":[]}`, string(protection))
+
+	_, err = km.DeleteKeystore(ctx, "0xbb")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = km.DeleteKeystore(ctx, "0xcc")
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.EqualError(t, err, `instance "lh-1": keystore operation rejected: deletion error: disk full`)
+}
+
+func TestKeymanager_ImportDeleteRemoteKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/remotekeys", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.Method {
+		case http.MethodPost:
+			assert.Equal(t, []interface{}{map[string]interface{}{"pubkey": "0xaa", "url": "https://signer:9000"}}, body["remote_keys"])
+			w.Write([]byte(`{"data":[{"status":"imported"}]}`))
+		case http.MethodDelete:
+			switch body["pubkeys"].([]interface{})[0] {
+			case "0xaa":
+				w.Write([]byte(`{"data":[{"status":"deleted"}]}`))
+			case "0xbb":
+				w.Write([]byte(`{"data":[{"status":"not_found"}]}`))
+			default:
+				w.Write([]byte(`{"data":[{"status":"error","message":"signer unreachable"}]}`))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{Name: "teku-1", Client: TypeTeku, URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	km := client.(KeyManager)
+	ctx := context.Background()
+
+	require.NoError(t, km.ImportRemoteKey(ctx, "0xaa", "https://signer:9000"))
+	require.NoError(t, km.DeleteRemoteKey(ctx, "0xaa"))
+	assert.ErrorIs(t, km.DeleteRemoteKey(ctx, "0xbb"), ErrKeyNotFound)
+
+	err = km.DeleteRemoteKey(ctx, "0xcc")
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.EqualError(t, err, `instance "teku-1": keystore operation rejected: remote key deletion error: signer unreachable`)
+}
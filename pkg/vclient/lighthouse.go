@@ -0,0 +1,18 @@
+package vclient
+
+func init() {
+	Register(TypeLighthouse, newLighthouse)
+}
+
+// newLighthouse creates a Lighthouse adapter. Lighthouse serves the keymanager API
+// over plain HTTP by default and always requires the token from its api-token.txt.
+func newLighthouse(cfg Config) (ValidatorClient, error) {
+	c, err := newKeymanagerClient(TypeLighthouse, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
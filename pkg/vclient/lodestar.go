@@ -0,0 +1,12 @@
+package vclient
+
+func init() {
+	Register(TypeLodestar, newLodestar)
+}
+
+// newLodestar creates a Lodestar adapter. Unlike the other clients, Lodestar can run
+// its keymanager API with authentication disabled (--keymanager.auth=false), so the
+// token is optional.
+func newLodestar(cfg Config) (ValidatorClient, error) {
+	return newKeymanagerClient(TypeLodestar, cfg)
+}
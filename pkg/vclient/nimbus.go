@@ -0,0 +1,18 @@
+package vclient
+
+func init() {
+	Register(TypeNimbus, newNimbus)
+}
+
+// newNimbus creates a Nimbus adapter. Nimbus serves the keymanager API on its REST
+// port once --keymanager is enabled and requires the token from --keymanager-token-file.
+func newNimbus(cfg Config) (ValidatorClient, error) {
+	c, err := newKeymanagerClient(TypeNimbus, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
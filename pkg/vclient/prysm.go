@@ -0,0 +1,76 @@
+package vclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func init() {
+	Register(TypePrysm, newPrysm)
+}
+
+// prysmClient adapts the Prysm keymanager API
+type prysmClient struct {
+	*keymanagerClient
+}
+
+// newPrysm creates a Prysm adapter. Older Prysm releases write the validator web URL
+// on the first line of the auth-token file and the token on the second, so the token
+// is taken from the last non-empty line.
+func newPrysm(cfg Config) (ValidatorClient, error) {
+	token := cfg.Token
+	if token == "" && cfg.TokenFile != "" {
+		data, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("instance %q: failed to read token file: %w", cfg.Name, err)
+		}
+		token = lastNonEmptyLine(string(data))
+	}
+
+	c, err := newKeymanagerClientWithToken(TypePrysm, cfg, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	return &prysmClient{keymanagerClient: c}, nil
+}
+
+// ListKeys returns both local keystores and remote-signer keys. A Prysm wallet is
+// either local or Web3Signer-backed, and the endpoint for the other kind answers
+// with a wallet type error rather than an empty list.
+func (c *prysmClient) ListKeys(ctx context.Context) ([]Key, error) {
+	local, err := c.listKeystores(ctx)
+	if err != nil && !isPrysmWalletTypeError(err) {
+		return nil, err
+	}
+	remote, err := c.listRemoteKeys(ctx)
+	if err != nil && !isPrysmWalletTypeError(err) {
+		return nil, err
+	}
+	return append(local, remote...), nil
+}
+
+func isPrysmWalletTypeError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusInternalServerError &&
+		strings.Contains(apiErr.Message, "Wallet is not of type")
+}
+
+func lastNonEmptyLine(s string) string {
+	lines := strings.Split(s, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
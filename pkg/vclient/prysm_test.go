@@ -0,0 +1,82 @@
+package vclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrysm_TokenFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "token only",
+			content: "secret\n",
+		},
+		{
+			name:    "legacy url and token",
+			content: "http://localhost:7500/initialize?token=secret\nsecret\n\n",
+		},
+	}
+
+	srv := httptest.NewServer(newKeymanagerHandler("secret"))
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenFile := filepath.Join(t.TempDir(), "auth-token")
+			require.NoError(t, os.WriteFile(tokenFile, []byte(tt.content), 0o600))
+
+			client, err := New(Config{Name: "prysm-1", Client: TypePrysm, URL: srv.URL, TokenFile: tokenFile})
+			require.NoError(t, err)
+
+			keys, err := client.ListKeys(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, keys, 2)
+		})
+	}
+}
+
+func TestPrysm_WalletTypeErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/keystores", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":500,"message":"Prysm Wallet is not of type Derived or Imported"}`))
+	})
+	mux.HandleFunc("/eth/v1/remotekeys", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"data":[{"pubkey":"0xbb","url":"https://signer:9000","readonly":false}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := New(Config{Name: "prysm-1", Client: TypePrysm, URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	keys, err := client.ListKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Pubkey: "0xbb", Remote: true, SignerURL: "https://signer:9000"}}, keys)
+}
+
+func TestPrysm_OtherErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":500,"message":"internal error"}`))
+	}))
+	defer srv.Close()
+
+	client, err := New(Config{Name: "prysm-1", Client: TypePrysm, URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	_, err = client.ListKeys(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "internal error", apiErr.Message)
+}
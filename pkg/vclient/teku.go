@@ -0,0 +1,19 @@
+package vclient
+
+func init() {
+	Register(TypeTeku, newTeku)
+}
+
+// newTeku creates a Teku adapter. Teku serves the keymanager API over TLS using the
+// certificate from its validator-api keystore, which is usually self-signed, so
+// CAFile can pin it. The bearer token is read from Teku's validator-api-bearer file.
+func newTeku(cfg Config) (ValidatorClient, error) {
+	c, err := newKeymanagerClient(TypeTeku, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,142 @@
+// Package vclient provides adapters for the keymanager APIs of Ethereum validator clients
+package vclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Supported validator client types
+const (
+	TypeLighthouse = "lighthouse"
+	TypeTeku       = "teku"
+	TypePrysm      = "prysm"
+	TypeNimbus     = "nimbus"
+	TypeLodestar   = "lodestar"
+)
+
+// ErrKeyNotFound is returned when deleting a key a client has no record of
+var ErrKeyNotFound = errors.New("key not found on validator client")
+
+// ErrRejected is returned when a validator client refuses to import or delete a key
+var ErrRejected = errors.New("keystore operation rejected")
+
+// Key is a validator key loaded on a client
+type Key struct {
+	// Pubkey is the lowercase 0x-prefixed BLS public key
+	Pubkey string
+	// Remote is true when the client signs through a remote signer
+	Remote bool
+	// SignerURL is the remote signer URL for remote keys
+	SignerURL string
+	// Readonly is true when the key cannot be removed through the API
+	Readonly bool
+}
+
+// ValidatorClient is a single validator client instance that can report its loaded keys
+type ValidatorClient interface {
+	// Name returns the configured instance name
+	Name() string
+
+	// Type returns the client implementation, e.g. "teku"
+	Type() string
+
+	// ListKeys returns both local keystores and remote-signer keys
+	ListKeys(ctx context.Context) ([]Key, error)
+}
+
+// KeyManager is a validator client whose local keystores and remote-signer keys can be
+// imported and deleted. Every adapter implements it through the standard keymanager API.
+type KeyManager interface {
+	ValidatorClient
+
+	// ImportKeystore loads an EIP-2335 keystore with its slashing protection interchange
+	ImportKeystore(ctx context.Context, keystore, password string, slashingProtection []byte) error
+
+	// DeleteKeystore removes a local keystore and returns its slashing protection interchange
+	DeleteKeystore(ctx context.Context, pubkey string) ([]byte, error)
+
+	// ImportRemoteKey makes the client sign for pubkey through the remote signer at signerURL
+	ImportRemoteKey(ctx context.Context, pubkey, signerURL string) error
+
+	// DeleteRemoteKey removes a remote-signer key
+	DeleteRemoteKey(ctx context.Context, pubkey string) error
+}
+
+// Config describes a single validator client keymanager API endpoint
+type Config struct {
+	// Name uniquely identifies the instance and is stored as the validator's client_instance
+	Name string `json:"name"`
+	// Client is the validator client implementation, e.g. "teku"
+	Client string `json:"client"`
+	// URL is the base URL of the keymanager API
+	URL string `json:"url"`
+	// Token is the keymanager API bearer token
+	Token string `json:"token,omitempty"`
+	// TokenFile is read for the bearer token when Token is empty
+	TokenFile string `json:"token_file,omitempty"`
+	// CAFile is a PEM file pinned as the only trusted root for the API certificate
+	CAFile string `json:"ca_file,omitempty"`
+	// ServerName overrides the hostname checked against the API certificate
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// Factory creates an adapter for one client type
+type Factory func(cfg Config) (ValidatorClient, error)
+
+var factories = map[string]Factory{}
+
+// Register makes an adapter available under the given client type.
+// Adapters call it from init, so supporting a new client only needs a new file.
+func Register(clientType string, f Factory) {
+	if _, ok := factories[clientType]; ok {
+		panic(fmt.Sprintf("vclient: adapter %q registered twice", clientType))
+	}
+	factories[clientType] = f
+}
+
+// Types returns the registered client types in sorted order
+func Types() []string {
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the adapter matching cfg.Client
+func New(cfg Config) (ValidatorClient, error) {
+	f, ok := factories[cfg.Client]
+	if !ok {
+		return nil, fmt.Errorf("instance %q: unsupported client %q", cfg.Name, cfg.Client)
+	}
+	return f(cfg)
+}
+
+// NormalizePubkey lowercases a pubkey and ensures it carries the 0x prefix
+func NormalizePubkey(pubkey string) string {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if !strings.HasPrefix(pubkey, "0x") {
+		pubkey = "0x" + pubkey
+	}
+	return pubkey
+}
+
+// bearerToken returns the configured token, reading it from TokenFile if necessary
+func (c Config) bearerToken() (string, error) {
+	if c.Token != "" || c.TokenFile == "" {
+		return c.Token, nil
+	}
+
+	data, err := os.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,81 @@
+package vclient
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypes(t *testing.T) {
+	assert.Equal(t, []string{TypeLighthouse, TypeLodestar, TypeNimbus, TypePrysm, TypeTeku}, Types())
+}
+
+func TestNew(t *testing.T) {
+	srv := httptest.NewServer(newKeymanagerHandler("secret"))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectError bool
+	}{
+		{
+			name: "lighthouse",
+			cfg:  Config{Name: "lh-1", Client: TypeLighthouse, URL: srv.URL, Token: "secret"},
+		},
+		{
+			name: "nimbus",
+			cfg:  Config{Name: "nimbus-1", Client: TypeNimbus, URL: srv.URL, Token: "secret"},
+		},
+		{
+			name: "lodestar",
+			cfg:  Config{Name: "lodestar-1", Client: TypeLodestar, URL: srv.URL, Token: "secret"},
+		},
+		{
+			name:        "lighthouse without token",
+			cfg:         Config{Name: "lh-1", Client: TypeLighthouse, URL: srv.URL},
+			expectError: true,
+		},
+		{
+			name:        "unsupported client",
+			cfg:         Config{Name: "x", Client: "unknown", URL: srv.URL},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.cfg)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.cfg.Client, client.Type())
+
+			keys, err := client.ListKeys(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, keys, 2)
+		})
+	}
+}
+
+func TestLodestar_AuthDisabled(t *testing.T) {
+	srv := httptest.NewServer(newKeymanagerHandler(""))
+	defer srv.Close()
+
+	client, err := New(Config{Name: "lodestar-1", Client: TypeLodestar, URL: srv.URL})
+	require.NoError(t, err)
+
+	keys, err := client.ListKeys(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
+
+func TestNormalizePubkey(t *testing.T) {
+	assert.Equal(t, "0xabcd", NormalizePubkey(" 0xABCD "))
+	assert.Equal(t, "0xabcd", NormalizePubkey("abcd"))
+}
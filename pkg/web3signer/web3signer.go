@@ -0,0 +1,178 @@
+// Package web3signer is a client for the Web3Signer remote signing service
+package web3signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zheli/validator-key-manager-backend/pkg/vclient"
+)
+
+// Health status values reported by Web3Signer
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Config describes a single Web3Signer instance
+type Config struct {
+	// Name uniquely identifies the signer
+	Name string `json:"name"`
+	// URL is the base URL the manager uses to reach the signer
+	URL string `json:"url"`
+	// ClientURL is the signer URL registered on validator clients. It defaults
+	// to URL and only needs to be set when clients reach the signer differently.
+	ClientURL string `json:"client_url,omitempty"`
+	// CAFile is a PEM file pinned as the only trusted root for the signer certificate
+	CAFile string `json:"ca_file,omitempty"`
+	// ServerName overrides the hostname checked against the signer certificate
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// Check is a single component check of a health report
+type Check struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// Health is the report returned by the healthcheck endpoint
+type Health struct {
+	Status  string  `json:"status"`
+	Checks  []Check `json:"checks"`
+	Outcome string  `json:"outcome"`
+}
+
+// Client talks to one Web3Signer instance
+type Client struct {
+	name       string
+	baseURL    string
+	clientURL  string
+	httpClient *http.Client
+}
+
+// New creates a client for the signer described by cfg
+func New(cfg Config) (*Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("signer %q: failed to read CA file: %w", cfg.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("signer %q: no certificates found in CA file", cfg.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientURL := cfg.ClientURL
+	if clientURL == "" {
+		clientURL = cfg.URL
+	}
+	return &Client{
+		name:      cfg.Name,
+		baseURL:   strings.TrimSuffix(cfg.URL, "/"),
+		clientURL: strings.TrimSuffix(clientURL, "/"),
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Name returns the configured signer name
+func (c *Client) Name() string {
+	return c.name
+}
+
+// URL returns the signer URL to register on validator clients
+func (c *Client) URL() string {
+	return c.clientURL
+}
+
+// PublicKeys returns the lowercase 0x-prefixed BLS public keys the signer has loaded
+func (c *Client) PublicKeys(ctx context.Context) ([]string, error) {
+	resp, err := c.get(ctx, "/api/v1/eth2/publicKeys")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.statusError("/api/v1/eth2/publicKeys", resp)
+	}
+
+	var keys []string
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("signer %q: failed to decode public keys: %w", c.name, err)
+	}
+	for i, k := range keys {
+		keys[i] = vclient.NormalizePubkey(k)
+	}
+	return keys, nil
+}
+
+// Upcheck returns an error unless the signer is up
+func (c *Client) Upcheck(ctx context.Context) error {
+	resp, err := c.get(ctx, "/upcheck")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return c.statusError("/upcheck", resp)
+	}
+	return nil
+}
+
+// Health returns the signer's health report. A signer that is down answers with
+// status 503 and still describes which checks failed, so the report is returned
+// for both.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	resp, err := c.get(ctx, "/healthcheck")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, c.statusError("/healthcheck", resp)
+	}
+
+	var h Health
+	if err := json.NewDecoder(resp.Body).Decode(&h); err != nil {
+		return nil, fmt.Errorf("signer %q: failed to decode health report: %w", c.name, err)
+	}
+	return &h, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("signer %q: request to %s failed: %w", c.name, path, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) statusError(path string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Errorf("signer %q: %s returned status %d: %s", c.name, path, resp.StatusCode, msg)
+	}
+	return fmt.Errorf("signer %q: %s returned status %d", c.name, path, resp.StatusCode)
+}
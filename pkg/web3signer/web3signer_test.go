@@ -0,0 +1,69 @@
+package web3signer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	down := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/eth2/publicKeys":
+			w.Write([]byte(`["0xAA","bb"]`))
+		case "/upcheck":
+			if down {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("OK"))
+		case "/healthcheck":
+			if down {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"status":"DOWN","checks":[{"id":"keys-check","status":"DOWN"}],"outcome":"DOWN"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"UP","checks":[{"id":"default-check","status":"UP"}],"outcome":"UP"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{Name: "w3s-1", URL: srv.URL + "/", ClientURL: "http://web3signer:9000"})
+	require.NoError(t, err)
+	assert.Equal(t, "w3s-1", c.Name())
+	assert.Equal(t, "http://web3signer:9000", c.URL())
+	ctx := context.Background()
+
+	keys, err := c.PublicKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xaa", "0xbb"}, keys)
+
+	require.NoError(t, c.Upcheck(ctx))
+	h, err := c.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUp, h.Outcome)
+
+	// A signer that is down still reports its failing checks
+	down = true
+	assert.EqualError(t, c.Upcheck(ctx), `signer "w3s-1": /upcheck returned status 503`)
+	h, err = c.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDown, h.Status)
+	assert.Equal(t, []Check{{ID: "keys-check", Status: StatusDown}}, h.Checks)
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(Config{Name: "w3s-1", URL: "https://web3signer:9000"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://web3signer:9000", c.URL())
+
+	_, err = New(Config{Name: "w3s-1", URL: "https://web3signer:9000", CAFile: "/nonexistent/ca.pem"})
+	assert.Error(t, err)
+}
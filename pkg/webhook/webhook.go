@@ -0,0 +1,197 @@
+// Package webhook sends HMAC-signed event notifications to configured HTTP endpoints
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event types a webhook can subscribe to
+const (
+	// EventStatusChanged is sent for every validator status change
+	EventStatusChanged = "validator.status_changed"
+	// EventSlashed is sent when a validator moves to slashed
+	EventSlashed = "validator.slashed"
+	// EventExited is sent when a validator moves to exited
+	EventExited = "validator.exited"
+	// EventDoubleLoaded is sent when a key is found loaded on more than one client instance
+	EventDoubleLoaded = "validator.double_loaded"
+	// EventSyncFailed is sent when a background sync run fails
+	EventSyncFailed = "sync.failed"
+)
+
+// Events lists every event type
+var Events = []string{EventStatusChanged, EventSlashed, EventExited, EventDoubleLoaded, EventSyncFailed}
+
+// Headers set on every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// MaxAttempts is the number of delivery attempts before a delivery is given up
+const MaxAttempts = 8
+
+// Endpoint is a webhook receiver and the events it subscribes to
+type Endpoint struct {
+	// Name uniquely identifies the webhook in the delivery log
+	Name string `json:"name"`
+	// URL receives the event payloads as POST requests
+	URL string `json:"url"`
+	// Secret is the HMAC-SHA256 key used to sign payloads
+	Secret string `json:"secret,omitempty"`
+	// SecretFile is read for the secret when Secret is empty
+	SecretFile string `json:"secret_file,omitempty"`
+	// Events are the event types sent to the webhook
+	Events []string `json:"events"`
+}
+
+// Subscribed reports whether the webhook receives event
+func (e Endpoint) Subscribed(event string) bool {
+	for _, ev := range e.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Config holds the configured webhooks
+type Config struct {
+	Webhooks []Endpoint `json:"webhooks"`
+}
+
+// LoadConfig reads a JSON webhook configuration from path and resolves the secret of every webhook
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook config: %w", err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Webhooks))
+	for i := range cfg.Webhooks {
+		e := &cfg.Webhooks[i]
+		if e.Name == "" {
+			return nil, fmt.Errorf("webhook name is required")
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("duplicate webhook name %q", e.Name)
+		}
+		seen[e.Name] = true
+		if e.URL == "" {
+			return nil, fmt.Errorf("webhook %q: url is required", e.Name)
+		}
+		if len(e.Events) == 0 {
+			return nil, fmt.Errorf("webhook %q: at least one event is required", e.Name)
+		}
+		for _, ev := range e.Events {
+			if !validEvent(ev) {
+				return nil, fmt.Errorf("webhook %q: unknown event %q", e.Name, ev)
+			}
+		}
+		if e.Secret == "" && e.SecretFile != "" {
+			secret, err := os.ReadFile(e.SecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %q: failed to read secret file: %w", e.Name, err)
+			}
+			e.Secret = strings.TrimSpace(string(secret))
+		}
+		if e.Secret == "" {
+			return nil, fmt.Errorf("webhook %q: secret is required", e.Name)
+		}
+	}
+
+	return &cfg, nil
+}
+
+func validEvent(event string) bool {
+	for _, ev := range Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Sign returns the signature header value of body sent at timestamp. Receivers
+// recompute the HMAC-SHA256 of "<timestamp>.<body>" with the shared secret and
+// compare it with the X-Webhook-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// failed attempts: 30 seconds, doubling up to one hour
+func Backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
+
+// Client sends deliveries to webhook endpoints
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a webhook client
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send posts a signed payload to the endpoint. It returns the response status
+// code, if any, and an error unless the endpoint answered with a 2xx status.
+func (c *Client) Send(ctx context.Context, e Endpoint, deliveryID int64, event string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(e.Secret, timestamp, payload))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook %q: request failed: %w", e.Name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook %q: returned status %d", e.Name, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
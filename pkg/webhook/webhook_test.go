@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("from-file\n"), 0o600))
+
+	tests := []struct {
+		name           string
+		content        string
+		expectedSecret string
+		expectError    bool
+	}{
+		{
+			name:           "valid config",
+			content:        `{"webhooks":[{"name":"ops","url":"https://hooks.example","secret":"s3cret","events":["validator.slashed"]}]}`,
+			expectedSecret: "s3cret",
+		},
+		{
+			name:           "secret file",
+			content:        `{"webhooks":[{"name":"ops","url":"https://hooks.example","secret_file":"` + secretFile + `","events":["sync.failed"]}]}`,
+			expectedSecret: "from-file",
+		},
+		{
+			name:        "missing secret",
+			content:     `{"webhooks":[{"name":"ops","url":"https://hooks.example","events":["sync.failed"]}]}`,
+			expectError: true,
+		},
+		{
+			name:        "unknown event",
+			content:     `{"webhooks":[{"name":"ops","url":"https://hooks.example","secret":"s","events":["validator.created"]}]}`,
+			expectError: true,
+		},
+		{
+			name:        "no events",
+			content:     `{"webhooks":[{"name":"ops","url":"https://hooks.example","secret":"s"}]}`,
+			expectError: true,
+		},
+		{
+			name: "duplicate name",
+			content: `{"webhooks":[{"name":"ops","url":"https://a","secret":"s","events":["sync.failed"]},` +
+				`{"name":"ops","url":"https://b","secret":"s","events":["sync.failed"]}]}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "webhooks.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			cfg, err := LoadConfig(path)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.Webhooks, 1)
+			assert.Equal(t, tt.expectedSecret, cfg.Webhooks[0].Secret)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.{}" keyed with "secret"
+	assert.Equal(t, "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", Sign("secret", 1700000000, []byte("{}")))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 8*time.Minute, Backoff(5))
+	assert.Equal(t, time.Hour, Backoff(MaxAttempts))
+}
+
+func TestClient_Send(t *testing.T) {
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+
+		assert.Equal(t, EventSlashed, r.Header.Get(HeaderEvent))
+		assert.Equal(t, "42", r.Header.Get(HeaderDelivery))
+		assert.Equal(t, Sign("s3cret", timestamp, body), r.Header.Get(HeaderSignature))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	e := Endpoint{Name: "ops", URL: srv.URL, Secret: "s3cret"}
+
+	code, err := c.Send(context.Background(), e, 42, EventSlashed, []byte(`{"event":"validator.slashed"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	status = http.StatusBadGateway
+	code, err = c.Send(context.Background(), e, 42, EventSlashed, []byte(`{}`))
+	assert.EqualError(t, err, `webhook "ops": returned status 502`)
+	assert.Equal(t, http.StatusBadGateway, code)
+}